@@ -0,0 +1,92 @@
+package gocosmosdb
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/intwinelabs/logger"
+)
+
+// aadRefreshMargin - how long before it expires an Azure AD token is replaced
+const aadRefreshMargin = 5 * time.Minute
+
+// AccessToken - an Azure AD (Entra ID) access token and when it expires
+type AccessToken struct {
+	Token     string
+	ExpiresOn time.Time
+}
+
+// TokenCredential - obtains Azure AD (Entra ID) access tokens for scopes, the shape of azcore.TokenCredential so
+// the credentials of azidentity plug in without the client depending on the Azure SDK
+//
+//	cred, err := azidentity.NewDefaultAzureCredential(nil)
+//	credential := gocosmosdb.TokenCredentialFunc(func(ctx context.Context, scopes []string) (gocosmosdb.AccessToken, error) {
+//		t, err := cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: scopes})
+//		return gocosmosdb.AccessToken{Token: t.Token, ExpiresOn: t.ExpiresOn}, err
+//	})
+type TokenCredential interface {
+	GetToken(ctx context.Context, scopes []string) (AccessToken, error)
+}
+
+// TokenCredentialFunc - a function implementing TokenCredential
+type TokenCredentialFunc func(ctx context.Context, scopes []string) (AccessToken, error)
+
+// GetToken - calls the function
+func (f TokenCredentialFunc) GetToken(ctx context.Context, scopes []string) (AccessToken, error) {
+	return f(ctx, scopes)
+}
+
+// AADScope - returns the scope of the tokens for the data of an account eg. "https://myaccount.documents.azure.com/.default"
+func AADScope(accountURL string) string {
+	u, err := url.Parse(accountURL)
+	if err != nil || u.Host == "" {
+		return accountURL + "/.default"
+	}
+	return u.Scheme + "://" + u.Hostname() + "/.default"
+}
+
+// NewAADClient - creates a client authorized with Azure AD (Entra ID) tokens of the credential instead of the
+// master key, eg. of a managed identity or a service principal with a data plane role on the account. Tokens are
+// cached and replaced five minutes before they expire.
+//
+//	client := gocosmosdb.NewAADClient(url, gocosmosdb.Config{}, credential, log)
+func NewAADClient(url string, config Config, credential TokenCredential, log *logger.Logger) *CosmosDB {
+	c := New(url, config, log)
+	c.client.aad = &aadTokens{credential: credential, scopes: []string{AADScope(url)}, now: time.Now}
+	return c
+}
+
+// aadTokens - caches the token of a credential until it is due for refresh
+type aadTokens struct {
+	credential TokenCredential
+	scopes     []string
+	now        func() time.Time
+	mu         sync.Mutex
+	token      AccessToken
+}
+
+// get - returns the cached token, asking the credential for a new one once it is about to expire
+func (a *aadTokens) get(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token.Token != "" && a.now().Before(a.token.ExpiresOn.Add(-aadRefreshMargin)) {
+		return a.token.Token, nil
+	}
+	token, err := a.credential.GetToken(ctx, a.scopes)
+	if err != nil {
+		return "", err
+	}
+	if token.Token == "" {
+		return "", errors.New("token credential returned no access token")
+	}
+	a.token = token
+	return token.Token, nil
+}
+
+// AADTokenHeaders - adds the default headers with an Azure AD access token as the authorization
+func (req *Request) AADTokenHeaders(token string) {
+	req.ResourceTokenHeaders("type=aad&ver=1.0&sig=" + token)
+}
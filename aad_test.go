@@ -0,0 +1,61 @@
+package gocosmosdb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAADClient(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"id": "1"}`, `{"id": "1"}`, `{"id": "1"}`)
+	defer s.Close()
+
+	var scopes [][]string
+	issued := 0
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	credential := TokenCredentialFunc(func(ctx context.Context, s []string) (AccessToken, error) {
+		scopes = append(scopes, s)
+		issued++
+		return AccessToken{Token: "token-" + string(rune('0'+issued)), ExpiresOn: now.Add(time.Hour)}, nil
+	})
+	client := NewAADClient(s.URL, Config{}, credential, log)
+	client.client.aad.now = func() time.Time { return now }
+
+	var doc Document
+	_, err := client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+	assert.Nil(err)
+	assert.Equal("type%3Daad%26ver%3D1.0%26sig%3Dtoken-1", s.Header.Get(HeaderAuth))
+	assert.Equal([][]string{{"http://127.0.0.1/.default"}}, scopes)
+
+	// cached until five minutes before it expires
+	now = now.Add(54 * time.Minute)
+	_, err = client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+	assert.Nil(err)
+	assert.Equal(1, issued)
+	now = now.Add(time.Minute)
+	_, err = client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+	assert.Nil(err)
+	assert.Equal("type%3Daad%26ver%3D1.0%26sig%3Dtoken-2", s.Header.Get(HeaderAuth))
+}
+
+func TestAADClientCredentialError(t *testing.T) {
+	assert := assert.New(t)
+	failed := errors.New("no managed identity")
+	client := NewAADClient("https://myaccount.documents.azure.com:443/", Config{}, TokenCredentialFunc(func(ctx context.Context, s []string) (AccessToken, error) {
+		return AccessToken{}, failed
+	}), log)
+
+	var doc Document
+	_, err := client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+	assert.Equal(failed, err)
+}
+
+func TestAADScope(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("https://myaccount.documents.azure.com/.default", AADScope("https://myaccount.documents.azure.com:443/"))
+	assert.Equal("https://myaccount.documents.azure.com/.default", AADScope("https://myaccount.documents.azure.com"))
+}
@@ -0,0 +1,86 @@
+package gocosmosdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Aliases - maps logical names eg. "orders" to the database or collection links of each environment, so code
+// refers to the name only and swapping a collection is a change of configuration
+type Aliases struct {
+	mu    sync.RWMutex
+	env   string
+	links map[string]map[string]string
+}
+
+// NewAliases - creates an empty registry resolving names in the environment env
+func NewAliases(env string) *Aliases {
+	return &Aliases{env: env, links: map[string]map[string]string{}}
+}
+
+// LoadAliases - reads a registry from JSON keyed by environment then name, resolving names in the environment env
+//
+//	{
+//		"staging":    {"orders": "dbs/shop-staging/colls/orders/"},
+//		"production": {"orders": "dbs/shop/colls/orders-blue/"}
+//	}
+//	aliases, err := gocosmosdb.LoadAliases(file, os.Getenv("ENVIRONMENT"))
+//	link, err := aliases.Link("orders")
+//	_, err = client.ReadDocument(link+"docs/{doc-id}", &doc)
+func LoadAliases(r io.Reader, env string) (*Aliases, error) {
+	a := NewAliases(env)
+	links := map[string]map[string]string{}
+	if err := json.NewDecoder(r).Decode(&links); err != nil {
+		return nil, err
+	}
+	for e, names := range links {
+		for name, link := range names {
+			a.Set(e, name, link)
+		}
+	}
+	if _, ok := a.links[env]; !ok {
+		return nil, fmt.Errorf("no aliases for environment %s", env)
+	}
+	return a, nil
+}
+
+// Environment - returns the environment names are resolved in
+func (a *Aliases) Environment() string {
+	return a.env
+}
+
+// Set - maps a name to a link in an environment
+func (a *Aliases) Set(env, name, link string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.links[env] == nil {
+		a.links[env] = map[string]string{}
+	}
+	a.links[env][name] = normalizeLink(link)
+}
+
+// Swap - points a name of the current environment to another link eg. the green collection of a blue/green
+// deployment, returning the link it pointed to before
+func (a *Aliases) Swap(name, link string) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	old, ok := a.links[a.env][name]
+	if !ok {
+		return "", fmt.Errorf("no alias %s in environment %s", name, a.env)
+	}
+	a.links[a.env][name] = normalizeLink(link)
+	return old, nil
+}
+
+// Link - returns the link a name points to in the current environment, ending in a slash
+func (a *Aliases) Link(name string) (string, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	link, ok := a.links[a.env][name]
+	if !ok {
+		return "", fmt.Errorf("no alias %s in environment %s", name, a.env)
+	}
+	return link, nil
+}
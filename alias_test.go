@@ -0,0 +1,42 @@
+package gocosmosdb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAliases(t *testing.T) {
+	assert := assert.New(t)
+	config := `{
+		"staging":    {"orders": "dbs/shop-staging/colls/orders"},
+		"production": {"orders": "/dbs/shop/colls/orders-blue/"}
+	}`
+	aliases, err := LoadAliases(strings.NewReader(config), "production")
+	assert.Nil(err)
+	assert.Equal("production", aliases.Environment())
+
+	link, err := aliases.Link("orders")
+	assert.Nil(err)
+	assert.Equal("dbs/shop/colls/orders-blue/", link)
+
+	old, err := aliases.Swap("orders", "dbs/shop/colls/orders-green")
+	assert.Nil(err)
+	assert.Equal("dbs/shop/colls/orders-blue/", old)
+	link, _ = aliases.Link("orders")
+	assert.Equal("dbs/shop/colls/orders-green/", link)
+
+	_, err = aliases.Link("customers")
+	assert.Contains(err.Error(), "no alias customers in environment production")
+	_, err = aliases.Swap("customers", "dbs/shop/colls/customers")
+	assert.NotNil(err)
+
+	staging, err := LoadAliases(strings.NewReader(config), "staging")
+	assert.Nil(err)
+	link, _ = staging.Link("orders")
+	assert.Equal("dbs/shop-staging/colls/orders/", link)
+
+	_, err = LoadAliases(strings.NewReader(config), "dev")
+	assert.NotNil(err)
+}
@@ -0,0 +1,150 @@
+package gocosmosdb
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sort"
+)
+
+// AuditField - the document field ContextAudit stamps its metadata into
+const AuditField = "_audit"
+
+// AuditFunc - returns the fields to stamp into every written document for the passed context
+type AuditFunc func(ctx context.Context) map[string]interface{}
+
+// AuditMetadata - the metadata stamped by ContextAudit
+type AuditMetadata struct {
+	Actor     string `json:"actor,omitempty"`
+	Tenant    string `json:"tenant,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+type auditKey int
+
+const (
+	auditActorKey auditKey = iota
+	auditTenantKey
+	auditRequestIDKey
+)
+
+// WithActor - returns a copy of the context carrying the acting user
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, auditActorKey, actor)
+}
+
+// WithTenant - returns a copy of the context carrying the tenant id
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, auditTenantKey, tenant)
+}
+
+// WithRequestID - returns a copy of the context carrying the request id
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, auditRequestIDKey, requestID)
+}
+
+// ActorFromContext - returns the acting user set by WithActor
+func ActorFromContext(ctx context.Context) (string, bool) {
+	actor, ok := ctx.Value(auditActorKey).(string)
+	return actor, ok
+}
+
+// TenantFromContext - returns the tenant id set by WithTenant
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(auditTenantKey).(string)
+	return tenant, ok
+}
+
+// RequestIDFromContext - returns the request id set by WithRequestID
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(auditRequestIDKey).(string)
+	return requestID, ok
+}
+
+// ContextAudit - an AuditFunc that stamps the actor, tenant and request id carried by the context under AuditField
+//
+//	client := gocosmosdb.New(url, gocosmosdb.Config{MasterKey: key, Audit: gocosmosdb.ContextAudit}, log)
+func ContextAudit(ctx context.Context) map[string]interface{} {
+	meta := AuditMetadata{}
+	meta.Actor, _ = ActorFromContext(ctx)
+	meta.Tenant, _ = TenantFromContext(ctx)
+	meta.RequestID, _ = RequestIDFromContext(ctx)
+	if meta == (AuditMetadata{}) {
+		return nil
+	}
+	return map[string]interface{}{AuditField: meta}
+}
+
+// stamp - merges the configured audit fields into the body of a document write
+func (c *apiClient) stamp(r *Request) error {
+	if c.config.Audit == nil || r.rType != "docs" || r.Body == nil {
+		return nil
+	}
+	if r.Method != http.MethodPost && r.Method != http.MethodPut && r.Method != http.MethodPatch {
+		return nil
+	}
+	fields := c.config.Audit(r.ctx())
+	if len(fields) == 0 {
+		return nil
+	}
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	if r.Method == http.MethodPatch {
+		data, err = stampPatch(data, fields)
+	} else {
+		data, err = stampDocument(data, fields)
+	}
+	if err != nil {
+		return err
+	}
+	r.setBody(data)
+	return nil
+}
+
+// stampDocument - merges fields into a document
+func stampDocument(data []byte, fields map[string]interface{}) ([]byte, error) {
+	doc := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	for k, v := range fields {
+		var err error
+		if doc[k], err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(doc)
+}
+
+// stampPatch - appends operations setting fields to a partial update
+func stampPatch(data []byte, fields map[string]interface{}) ([]byte, error) {
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, err
+	}
+	// the operations of the caller are kept as sent
+	var ops []json.RawMessage
+	if err := json.Unmarshal(body["operations"], &ops); err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		op, err := json.Marshal(PatchOperation{Op: PatchSet, Path: "/" + k, Value: fields[k]})
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, op)
+	}
+	var err error
+	if body["operations"], err = json.Marshal(ops); err != nil {
+		return nil, err
+	}
+	return json.Marshal(body)
+}
@@ -0,0 +1,41 @@
+package gocosmosdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextAudit(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(ContextAudit(context.Background()))
+
+	ctx := WithRequestID(WithTenant(WithActor(context.Background(), "ariel"), "contoso"), "req-1")
+	fields := ContextAudit(ctx)
+	assert.Equal(AuditMetadata{Actor: "ariel", Tenant: "contoso", RequestID: "req-1"}, fields[AuditField])
+}
+
+func TestCreateDocumentWithAudit(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"id": "SalesOrder1"}`, `{"id": "9"}`)
+	s.SetStatus(http.StatusCreated)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", Audit: ContextAudit}, log)
+
+	ctx := WithActor(context.Background(), "ariel")
+	doc := testDoc{PONumber: "PO18009186470"}
+	_, err := client.CreateDocument("dbs/d9RzAA==/colls/d9RzAJRFKgw=/", &doc, WithContext(ctx))
+	assert.Nil(err)
+	body := map[string]interface{}{}
+	assert.Nil(json.Unmarshal([]byte(s.Body), &body))
+	assert.Equal(map[string]interface{}{"actor": "ariel"}, body[AuditField])
+	assert.Equal("PO18009186470", body["ponumber"])
+
+	// Non document writes are left untouched
+	_, err = client.CreateDatabase(`{"id": "9"}`, WithContext(ctx))
+	assert.Nil(err)
+	assert.Equal(`{"id": "9"}`, s.Body)
+}
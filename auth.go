@@ -26,3 +26,9 @@ func authorize(str, key string) (string, error) {
 	ret = enc.EncodeToString(b)
 	return ret, nil
 }
+
+// Sign - returns the base64 HMAC-SHA256 of str keyed with the base64 account key,
+// shared with the subpackages speaking other CosmosDB APIs
+func Sign(str, key string) (string, error) {
+	return authorize(str, key)
+}
@@ -0,0 +1,138 @@
+package gocosmosdb
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// Backoff reasons, as passed in a BackoffEvent
+const (
+	BackoffThrottled   = "throttled"   // the request exceeded the provisioned RUs, 429
+	BackoffServerError = "serverError" // the service failed the request, 5xx
+	BackoffConnection  = "connection"  // the request failed before a response arrived
+)
+
+// BackoffEvent - the client is about to sleep before retrying a request
+type BackoffEvent struct {
+	Operation  string        // method and path eg. "GET /dbs/db/colls/coll/docs/1", empty for connection failures
+	Attempt    int           // the attempt that failed, starting at 1
+	Wait       time.Duration // how long the client sleeps before the next attempt
+	Reason     string
+	StatusCode int // 0 for connection failures
+}
+
+// BackoffFunc - receives backoff events with the context of the request, eg. to add them to a trace span
+type BackoffFunc func(ctx context.Context, e BackoffEvent)
+
+// observeBackoff - wraps the backoff policy of a http client to emit an event for every wait
+func observeBackoff(backoff retryablehttp.Backoff, fn BackoffFunc) retryablehttp.Backoff {
+	return func(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+		wait := backoff(min, max, attemptNum, resp)
+		ctx := context.Background()
+		if resp != nil && resp.Request != nil {
+			ctx = resp.Request.Context()
+		}
+		fn(ctx, backoffEvent(attemptNum+1, wait, resp))
+		return wait
+	}
+}
+
+// backoffEvent - describes the wait after a failed attempt, resp is nil for connection failures
+func backoffEvent(attempt int, wait time.Duration, resp *http.Response) BackoffEvent {
+	e := BackoffEvent{Attempt: attempt, Wait: wait, Reason: BackoffConnection}
+	if resp != nil {
+		e.StatusCode = resp.StatusCode
+		e.Reason = BackoffServerError
+		if resp.StatusCode == http.StatusTooManyRequests {
+			e.Reason = BackoffThrottled
+		}
+		if resp.Request != nil {
+			e.Operation = resp.Request.Method + " " + resp.Request.URL.Path
+		}
+	}
+	return e
+}
+
+// retryState - the retries of a request so far, kept in its context while the http client retries it
+type retryState struct {
+	mu      sync.Mutex
+	retries int
+	waited  time.Duration
+}
+
+type retryStateKey struct{}
+
+// withRetryState - returns a copy of the request counting its retries
+func withRetryState(req *http.Request) (*http.Request, *retryState) {
+	state := &retryState{}
+	return req.WithContext(context.WithValue(req.Context(), retryStateKey{}, state)), state
+}
+
+func retryStateFrom(ctx context.Context) *retryState {
+	state, _ := ctx.Value(retryStateKey{}).(*retryState)
+	return state
+}
+
+// Retries - returns how often the request was retried
+func (s *retryState) Retries() int {
+	if s == nil {
+		return 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.retries
+}
+
+// retryAfter - the wait a throttled response asks for
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	ms, err := strconv.Atoi(resp.Header.Get(HeaderRetryAfterMs))
+	return time.Duration(ms) * time.Millisecond, err == nil
+}
+
+// retryThrottled - wraps a retry policy to also retry throttled requests, until the waits of a request would add
+// up past maxWait when set
+func retryThrottled(policy retryablehttp.CheckRetry, maxWait time.Duration) retryablehttp.CheckRetry {
+	return func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		if err == nil && ctx.Err() == nil && resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+			if state := retryStateFrom(ctx); maxWait > 0 && state != nil {
+				wait, _ := retryAfter(resp)
+				state.mu.Lock()
+				defer state.mu.Unlock()
+				return state.waited+wait <= maxWait, nil
+			}
+			return true, nil
+		}
+		return policy(ctx, resp, err)
+	}
+}
+
+// throttledBackoff - wraps a backoff policy to wait as long as a throttled response asks, plus up to jitter, and
+// to count the retries of the request
+func throttledBackoff(backoff retryablehttp.Backoff, jitter time.Duration) retryablehttp.Backoff {
+	return func(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+		wait, ok := time.Duration(0), false
+		if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+			if wait, ok = retryAfter(resp); ok && jitter > 0 {
+				wait += time.Duration(rand.Int63n(int64(jitter) + 1))
+			}
+		}
+		if !ok {
+			wait = backoff(min, max, attemptNum, resp)
+		}
+		if resp != nil && resp.Request != nil {
+			if state := retryStateFrom(resp.Request.Context()); state != nil {
+				state.mu.Lock()
+				state.retries++
+				state.waited += wait
+				state.mu.Unlock()
+			}
+		}
+		return wait
+	}
+}
@@ -0,0 +1,88 @@
+package gocosmosdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoffEvents(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(http.StatusTooManyRequests, http.StatusServiceUnavailable, `{"id": "1"}`)
+	s.SetHeader(HeaderRetryAfterMs, "5")
+	defer s.Close()
+
+	var (
+		mu     sync.Mutex
+		events []BackoffEvent
+	)
+	client := New(s.URL, Config{
+		MasterKey:      "YXJpZWwNCg==",
+		RetryMax:       2,
+		RetryWaitMin:   time.Millisecond,
+		RetryWaitMax:   time.Millisecond,
+		RetryThrottled: true,
+		OnBackoff: func(ctx context.Context, e BackoffEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, e)
+		},
+	}, log)
+
+	var doc Document
+	_, err := client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+	assert.Nil(err)
+	assert.Equal("1", doc.Id)
+	assert.Equal([]BackoffEvent{
+		{Operation: "GET /dbs/db/colls/coll/docs/1", Attempt: 1, Wait: 5 * time.Millisecond, Reason: BackoffThrottled, StatusCode: http.StatusTooManyRequests},
+		{Operation: "GET /dbs/db/colls/coll/docs/1", Attempt: 2, Wait: time.Millisecond, Reason: BackoffServerError, StatusCode: http.StatusServiceUnavailable},
+	}, events)
+}
+
+func TestThrottledNotRetriedByDefault(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(http.StatusTooManyRequests, `{"id": "1"}`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", RetryMax: 2}, log)
+
+	var doc Document
+	_, err := client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+	assert.True(errors.Is(err, ErrTooManyRequests))
+}
+
+func TestThrottledRetriesExhausted(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusTooManyRequests, `{"id": "1"}`)
+	s.SetHeader(HeaderRetryAfterMs, "5")
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", RetryMax: 2, RetryThrottled: true, RetryJitter: time.Millisecond}, log)
+
+	var doc Document
+	_, err := client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+	assert.True(errors.Is(err, ErrTooManyRequests))
+	var reqErr *RequestError
+	assert.True(errors.As(err, &reqErr))
+	assert.Equal(2, reqErr.Retries)
+	assert.Contains(err.Error(), "after 2 retries")
+}
+
+func TestThrottledMaxWait(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(http.StatusTooManyRequests, http.StatusTooManyRequests, `{"id": "1"}`)
+	s.SetHeader(HeaderRetryAfterMs, "30")
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", RetryMax: 5, RetryThrottled: true, RetryThrottledMaxWait: 50 * time.Millisecond}, log)
+
+	// the second wait would add up to 60ms
+	var doc Document
+	_, err := client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+	var reqErr *RequestError
+	assert.True(errors.As(err, &reqErr))
+	assert.Equal(http.StatusTooManyRequests, reqErr.StatusCode)
+	assert.Equal(1, reqErr.Retries)
+}
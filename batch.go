@@ -0,0 +1,223 @@
+package gocosmosdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// MaxBatchOperations - the most operations the service runs in one transactional batch
+const MaxBatchOperations = 100
+
+// Batch operation types, as sent in the operationType of a BatchOperation
+const (
+	BatchCreate  = "Create"
+	BatchUpsert  = "Upsert"
+	BatchReplace = "Replace"
+	BatchRead    = "Read"
+	BatchDelete  = "Delete"
+	BatchPatch   = "Patch"
+)
+
+// BatchOperation - an operation of a transactional batch
+type BatchOperation struct {
+	OperationType string      `json:"operationType"`
+	ID            string      `json:"id,omitempty"`
+	ResourceBody  interface{} `json:"resourceBody,omitempty"`
+	IfMatch       string      `json:"ifMatch,omitempty"`
+}
+
+// BatchResult - the outcome of an operation of a transactional batch, in the order the operations were added
+type BatchResult struct {
+	StatusCode    int             `json:"statusCode"`
+	RequestCharge float64         `json:"requestCharge"`
+	ETag          string          `json:"eTag,omitempty"`
+	ResourceBody  json.RawMessage `json:"resourceBody,omitempty"`
+	RetryAfterMs  int             `json:"retryAfterMilliseconds,omitempty"` // of a throttled operation
+}
+
+// BatchError - a transactional batch was rolled back because one of its operations failed, the other operations
+// report 424 Failed Dependency
+type BatchError struct {
+	Index      int // of the failed operation
+	StatusCode int // of the failed operation
+	Results    []BatchResult
+}
+
+// Implement Error function
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch operation %d failed with status %d, the batch was rolled back", e.Index, e.StatusCode)
+}
+
+// Is - matches the semantic error of the failed operation, eg. ErrConflict when a create found the document
+func (e *BatchError) Is(target error) bool {
+	return target != nil && statusErrors[e.StatusCode] == target
+}
+
+// TransactionalBatch - operations on the documents of one partition key that succeed or fail together
+//
+//	batch := gocosmosdb.NewTransactionalBatch(coll, "tenant-1").
+//		Create(order).
+//		Replace(stock.Id, stock).IfMatch(stock.Etag).
+//		Delete(cart.Id)
+//	results, err := client.ExecuteBatch(batch)
+type TransactionalBatch struct {
+	coll         string
+	partitionKey interface{}
+	ops          []BatchOperation
+}
+
+// NewTransactionalBatch - starts a batch on the documents of a collection sharing a partition key
+func NewTransactionalBatch(coll string, partitionKey interface{}) *TransactionalBatch {
+	return &TransactionalBatch{coll: coll, partitionKey: partitionKey}
+}
+
+// Create - adds creating a document, failing the batch when the id exists
+func (b *TransactionalBatch) Create(doc interface{}) *TransactionalBatch {
+	return b.add(BatchOperation{OperationType: BatchCreate, ResourceBody: doc})
+}
+
+// Upsert - adds creating or replacing a document
+func (b *TransactionalBatch) Upsert(doc interface{}) *TransactionalBatch {
+	return b.add(BatchOperation{OperationType: BatchUpsert, ResourceBody: doc})
+}
+
+// Replace - adds replacing the document with the id, failing the batch when it does not exist
+func (b *TransactionalBatch) Replace(id string, doc interface{}) *TransactionalBatch {
+	return b.add(BatchOperation{OperationType: BatchReplace, ID: id, ResourceBody: doc})
+}
+
+// Read - adds reading the document with the id, its result has the document
+func (b *TransactionalBatch) Read(id string) *TransactionalBatch {
+	return b.add(BatchOperation{OperationType: BatchRead, ID: id})
+}
+
+// Delete - adds deleting the document with the id, failing the batch when it does not exist
+func (b *TransactionalBatch) Delete(id string) *TransactionalBatch {
+	return b.add(BatchOperation{OperationType: BatchDelete, ID: id})
+}
+
+// Patch - adds a partial update of the document with the id, failing the batch when it does not exist
+func (b *TransactionalBatch) Patch(id string, ops []PatchOperation) *TransactionalBatch {
+	return b.add(BatchOperation{OperationType: BatchPatch, ID: id, ResourceBody: patchBody{Operations: ops}})
+}
+
+// IfMatch - makes the last added operation fail the batch unless the document still has the etag
+func (b *TransactionalBatch) IfMatch(etag string) *TransactionalBatch {
+	if len(b.ops) > 0 {
+		b.ops[len(b.ops)-1].IfMatch = etag
+	}
+	return b
+}
+
+// Operations - returns the operations added so far
+func (b *TransactionalBatch) Operations() []BatchOperation {
+	return b.ops
+}
+
+func (b *TransactionalBatch) add(op BatchOperation) *TransactionalBatch {
+	b.ops = append(b.ops, op)
+	return b
+}
+
+// ExecuteBatch - runs the operations of a batch atomically, returning their results in order. When an operation
+// fails nothing is applied and the error is a *BatchError, which also carries the results. The documents written
+// are audited, hashed and validated like those of single writes.
+func (c *CosmosDB) ExecuteBatch(b *TransactionalBatch, opts ...CallOption) ([]BatchResult, error) {
+	if len(b.ops) == 0 || len(b.ops) > MaxBatchOperations {
+		return nil, fmt.Errorf("a batch needs between 1 and %d operations, got %d", MaxBatchOperations, len(b.ops))
+	}
+	if b.partitionKey == nil {
+		return nil, errors.New("a batch needs the partition key its documents share")
+	}
+	var results []BatchResult
+	if _, err := c.client.batch(normalizeLink(b.coll)+"docs/", b.ops, &results, append(opts[:len(opts):len(opts)], PartitionKey(b.partitionKey))...); err != nil {
+		return nil, err
+	}
+	for i, result := range results {
+		if result.StatusCode >= http.StatusBadRequest && result.StatusCode != http.StatusFailedDependency {
+			return results, &BatchError{Index: i, StatusCode: result.StatusCode, Results: results}
+		}
+	}
+	return results, nil
+}
+
+// a batch that was rolled back replies with the results of its operations as multi-status
+var expectBatch = expect(expectStatusCode(http.StatusOK), expectStatusCode(http.StatusMultiStatus))
+
+// batch - posts the operations of a batch, keeping the partitioned API version like queries do
+func (c *apiClient) batch(link string, ops []BatchOperation, ret interface{}, opts ...CallOption) (*Response, error) {
+	req, err := http.NewRequest(http.MethodPost, path(c.uri, link), nil)
+	if err != nil {
+		return nil, err
+	}
+	r := ResourceRequest(link, req)
+	if err = c.apply(r, opts); err != nil {
+		return nil, err
+	}
+	if ops, err = c.batchDocuments(r, ops); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(ops)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.guardBatch(link, data); err != nil {
+		return nil, err
+	}
+	r.setBody(data)
+	r.Header.Set(HeaderIsBatchRequest, "true")
+	r.Header.Set(HeaderBatchAtomic, "true")
+	r.Header.Set(HeaderContentType, "application/json")
+	return c.do(r, expectBatch, ret)
+}
+
+// batchDocuments - stamps the audit fields and content hashes into the documents a batch writes and validates
+// them like the writes of single documents, the partial updates get operations setting the audit fields and
+// clearing the hash. The operations of the caller are left as they are.
+func (c *apiClient) batchDocuments(r *Request, ops []BatchOperation) ([]BatchOperation, error) {
+	var fields map[string]interface{}
+	if c.config.Audit != nil {
+		fields = c.config.Audit(r.ctx())
+	}
+	validate := c.config.ValidateDocuments || c.config.DryRun != nil
+	if len(fields) == 0 && !validate && !c.config.ContentHashes {
+		return ops, nil
+	}
+	written := make([]BatchOperation, len(ops))
+	for i, op := range ops {
+		written[i] = op
+		if op.ResourceBody == nil {
+			continue
+		}
+		data, err := stringify(op.ResourceBody)
+		if err != nil {
+			return nil, err
+		}
+		switch op.OperationType {
+		case BatchPatch:
+			if len(fields) > 0 {
+				data, err = stampPatch(data, fields)
+			}
+			if err == nil && c.config.ContentHashes {
+				data, err = stampPatch(data, map[string]interface{}{ContentHashField: ""})
+			}
+		case BatchCreate, BatchUpsert, BatchReplace:
+			if len(fields) > 0 {
+				data, err = stampDocument(data, fields)
+			}
+			if err == nil && c.config.ContentHashes {
+				data, err = hashDocument(data)
+			}
+			if err == nil && validate {
+				err = validateDocument(data)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		written[i].ResourceBody = json.RawMessage(data)
+	}
+	return written, nil
+}
@@ -0,0 +1,76 @@
+package gocosmosdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecuteBatch(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(
+		`[{"statusCode": 201, "requestCharge": 5.2, "eTag": "\"1\"", "resourceBody": {"id": "a"}}, {"statusCode": 204, "requestCharge": 5}]`,
+		`[{"statusCode": 424}, {"statusCode": 409}, {"statusCode": 424}]`,
+	)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	batch := NewTransactionalBatch("dbs/db/colls/coll", "tenant-1").
+		Create(&Document{Resource: Resource{Id: "a"}}).
+		Delete("b").IfMatch(`"2"`)
+	results, err := client.ExecuteBatch(batch)
+	assert.Nil(err)
+	assert.Len(results, 2)
+	assert.Equal(http.StatusCreated, results[0].StatusCode)
+	assert.JSONEq(`{"id": "a"}`, string(results[0].ResourceBody))
+	assert.Equal("true", s.Header.Get(HeaderIsBatchRequest))
+	assert.Equal("true", s.Header.Get(HeaderBatchAtomic))
+	assert.Equal(`["tenant-1"]`, s.Header.Get(HeaderPartitionKey))
+	assert.Equal(SupportedAPIVersion, s.Header.Get(HeaderVersion))
+	assert.JSONEq(`[{"operationType": "Create", "resourceBody": {"id": "a"}}, {"operationType": "Delete", "id": "b", "ifMatch": "\"2\""}]`, s.Body)
+
+	s.SetStatus(http.StatusMultiStatus)
+	results, err = client.ExecuteBatch(NewTransactionalBatch("dbs/db/colls/coll/", "tenant-1").Read("a").Create(map[string]string{"id": "a"}).Read("a"))
+	assert.Len(results, 3)
+	assert.True(errors.Is(err, ErrConflict))
+	var batchErr *BatchError
+	assert.True(errors.As(err, &batchErr))
+	assert.Equal(1, batchErr.Index)
+
+	_, err = client.ExecuteBatch(NewTransactionalBatch("dbs/db/colls/coll/", "tenant-1"))
+	assert.Contains(err.Error(), "between 1 and 100 operations")
+}
+
+func TestExecuteBatchDocumentHooks(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`[{"statusCode": 201}, {"statusCode": 200}, {"statusCode": 204}]`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", Audit: ContextAudit, ValidateDocuments: true}, log)
+
+	ctx := WithActor(context.Background(), "ariel")
+	doc := map[string]interface{}{"id": "a"}
+	_, err := client.ExecuteBatch(NewTransactionalBatch("dbs/db/colls/coll/", "tenant-1").
+		Create(doc).
+		Patch("b", []PatchOperation{{Op: PatchIncrement, Path: "/count", Value: 1}}).
+		Delete("c"), WithContext(ctx))
+	assert.Nil(err)
+	assert.JSONEq(`[
+		{"operationType": "Create", "resourceBody": {"id": "a", "_audit": {"actor": "ariel"}}},
+		{"operationType": "Patch", "id": "b", "resourceBody": {"operations": [
+			{"op": "incr", "path": "/count", "value": 1},
+			{"op": "set", "path": "/_audit", "value": {"actor": "ariel"}}
+		]}},
+		{"operationType": "Delete", "id": "c"}
+	]`, s.Body)
+	assert.Equal(map[string]interface{}{"id": "a"}, doc)
+
+	// documents over the limits fail the batch before it is sent
+	_, err = client.ExecuteBatch(NewTransactionalBatch("dbs/db/colls/coll/", "tenant-1").
+		Upsert(map[string]interface{}{"id": "a", "blob": strings.Repeat("x", MaxDocumentSize)}))
+	var limitErr *DocumentLimitError
+	assert.True(errors.As(err, &limitErr))
+}
@@ -0,0 +1,75 @@
+package gocosmosdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MaxQueryLength - the size in bytes a query body, its text and parameters, is kept under by ReadDocumentsByID
+var MaxQueryLength = 256 * 1024
+
+// ReadDocumentsByID - reads the documents with the given ids sharing one partition key with as few
+// `IN` queries as fit under MaxQueryLength, appending them to the slice docs points to. Ids that do not
+// exist are skipped and the documents are not returned in the order of the ids.
+//
+//	var docs []Doc
+//	err := client.ReadDocumentsByID(coll, "tenant-1", []string{"a", "b", "c"}, &docs)
+func (c *CosmosDB) ReadDocumentsByID(coll string, partitionKey interface{}, ids []string, docs interface{}, opts ...CallOption) error {
+	if partitionKey == nil {
+		return errors.New("ReadDocumentsByID needs the partition key the documents share")
+	}
+	opts = append(opts, PartitionKey(partitionKey))
+	for _, query := range inQueries(ids) {
+		if err := c.QueryAll(coll, query, docs, &DrainLimits{}, opts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inQueries - splits the distinct ids into parameterized `IN` queries under MaxQueryLength
+func inQueries(ids []string) []*QueryWithParameters {
+	const (
+		prefix = "SELECT * FROM root r WHERE r.id IN ("
+		// the JSON around the query and around each parameter
+		bodySize  = len(`{"query":"","parameters":[]}`)
+		paramSize = len(`{"name":"","value":},`)
+	)
+	var (
+		queries []*QueryWithParameters
+		names   []string
+		query   *QueryWithParameters
+		size    int
+	)
+	flush := func() {
+		if query != nil {
+			query.Query = prefix + strings.Join(names, ", ") + ")"
+			queries = append(queries, query)
+		}
+		query, names, size = &QueryWithParameters{}, nil, bodySize+len(prefix)+1
+	}
+	flush()
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		name := fmt.Sprintf("@id%d", len(names))
+		value, _ := json.Marshal(id)
+		cost := 2*len(name) + len(", ") + paramSize + len(value)
+		if len(names) > 0 && size+cost > MaxQueryLength {
+			flush()
+			name = "@id0"
+		}
+		names = append(names, name)
+		query.Parameters = append(query.Parameters, QueryParameter{Name: name, Value: id})
+		size += cost
+	}
+	if len(names) > 0 {
+		flush()
+	}
+	return queries
+}
@@ -0,0 +1,52 @@
+package gocosmosdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInQueries(t *testing.T) {
+	assert := assert.New(t)
+	queries := inQueries([]string{"a", "b", "a", "c"})
+	assert.Equal(1, len(queries))
+	assert.Equal("SELECT * FROM root r WHERE r.id IN (@id0, @id1, @id2)", queries[0].Query)
+	assert.Equal("c", queries[0].Parameters[2].Value)
+
+	assert.Equal(0, len(inQueries(nil)))
+
+	defer func(length int) { MaxQueryLength = length }(MaxQueryLength)
+	MaxQueryLength = 1024
+	ids := []string{}
+	for i := 0; i < 100; i++ {
+		ids = append(ids, fmt.Sprintf("order-%d", i))
+	}
+	queries = inQueries(ids)
+	assert.True(len(queries) > 1)
+	count := 0
+	for _, q := range queries {
+		body, err := json.Marshal(q)
+		assert.Nil(err)
+		assert.True(len(body) <= MaxQueryLength, "query body of %d bytes", len(body))
+		assert.Equal("@id0", q.Parameters[0].Name)
+		count += len(q.Parameters)
+	}
+	assert.Equal(100, count)
+}
+
+func TestReadDocumentsByID(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"Documents": [{"id": "SalesOrder1"}, {"id": "SalesOrder2"}], "_count": 2}`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", PartitionKeyStructField: "Id"}, log)
+	docs := []testDoc{}
+	err := client.ReadDocumentsByID("dbs/d9RzAA==/colls/d9RzAJRFKgw=/", "SalesOrder", []string{"SalesOrder1", "SalesOrder2"}, &docs)
+	assert.Nil(err)
+	assert.Equal(2, len(docs))
+	assert.Equal(`["SalesOrder"]`, s.Header.Get(HeaderPartitionKey))
+	assert.Contains(s.Body, "r.id IN (@id0, @id1)")
+
+	assert.NotNil(client.ReadDocumentsByID("dbs/d9RzAA==/colls/d9RzAJRFKgw=/", nil, []string{"SalesOrder1"}, &docs))
+}
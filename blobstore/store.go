@@ -0,0 +1,137 @@
+// Package blobstore keeps the large fields offloaded from CosmosDB documents as block blobs in an Azure Blob
+// storage container, it implements gocosmosdb.LargeFieldStore.
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/intwinelabs/gocosmosdb"
+	"github.com/intwinelabs/logger"
+)
+
+// APIVersion - the Blob service version spoken by the store
+const APIVersion = "2019-02-02"
+
+// Store - a container of an Azure storage account, the config MasterKey is the storage account key
+type Store struct {
+	uri        string
+	account    string
+	container  string
+	config     gocosmosdb.Config
+	httpClient *retryablehttp.Client
+	logger     *logger.Logger
+}
+
+// New - creates a store for the container, the account name is the first label of the url host
+//
+//	store := blobstore.New("https://{account}.blob.core.windows.net", "large-fields", gocosmosdb.Config{MasterKey: key}, log)
+func New(uri, container string, config gocosmosdb.Config, log *logger.Logger) *Store {
+	account := ""
+	if u, err := url.Parse(uri); err == nil {
+		account = strings.Split(u.Hostname(), ".")[0]
+	}
+	return &Store{
+		uri:        strings.TrimSuffix(uri, "/"),
+		account:    account,
+		container:  container,
+		config:     config,
+		httpClient: gocosmosdb.NewHTTPClient(config),
+		logger:     log,
+	}
+}
+
+// Error - a failed Blob service request
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+// Implement Error function
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d %v", e.StatusCode, e.Message)
+}
+
+// Put - uploads data as the block blob key
+func (s *Store) Put(ctx context.Context, key string, data []byte) error {
+	_, err := s.do(ctx, http.MethodPut, key, data, http.StatusCreated)
+	return err
+}
+
+// Get - downloads the block blob key
+func (s *Store) Get(ctx context.Context, key string) ([]byte, error) {
+	return s.do(ctx, http.MethodGet, key, nil, http.StatusOK)
+}
+
+// Delete - deletes the block blob key, a missing blob is not an error
+func (s *Store) Delete(ctx context.Context, key string) error {
+	_, err := s.do(ctx, http.MethodDelete, key, nil, http.StatusAccepted)
+	if e, ok := err.(*Error); ok && e.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+// do - sends a signed request for a blob of the container
+func (s *Store) do(ctx context.Context, method, key string, data []byte, status int) ([]byte, error) {
+	req, err := http.NewRequest(method, s.uri+"/"+s.container+"/"+key, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Ms-Version", APIVersion)
+	if method == http.MethodPut {
+		req.Header.Set("X-Ms-Blob-Type", "BlockBlob")
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+	if err = s.authorize(req); err != nil {
+		return nil, err
+	}
+	rr, err := retryablehttp.FromRequest(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.httpClient.Do(rr)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != status {
+		return nil, &Error{StatusCode: resp.StatusCode, Message: resp.Status}
+	}
+	return body, nil
+}
+
+// authorize - signs the request with SharedKeyLite over the verb, content type, x-ms headers and resource
+func (s *Store) authorize(req *http.Request) error {
+	req.Header.Set("X-Ms-Date", time.Now().UTC().Format(http.TimeFormat))
+	headers := []string{}
+	for k := range req.Header {
+		if k = strings.ToLower(k); strings.HasPrefix(k, "x-ms-") {
+			headers = append(headers, k)
+		}
+	}
+	sort.Strings(headers)
+	str := req.Method + "\n\n" + req.Header.Get("Content-Type") + "\n\n"
+	for _, k := range headers {
+		str += k + ":" + req.Header.Get(k) + "\n"
+	}
+	str += "/" + s.account + req.URL.EscapedPath()
+	sig, err := gocosmosdb.Sign(str, s.config.MasterKey)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "SharedKeyLite "+s.account+":"+sig)
+	return nil
+}
@@ -0,0 +1,66 @@
+package blobstore
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/intwinelabs/gocosmosdb"
+	"github.com/intwinelabs/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+var log = logger.New()
+
+func TestStore(t *testing.T) {
+	assert := assert.New(t)
+	blobs := map[string][]byte{}
+	var req *http.Request
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = r
+		switch r.Method {
+		case http.MethodPut:
+			blobs[r.URL.Path], _ = ioutil.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			data, ok := blobs[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(data)
+		case http.MethodDelete:
+			if _, ok := blobs[r.URL.Path]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			delete(blobs, r.URL.Path)
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}))
+	defer s.Close()
+	store := New(strings.Replace(s.URL, "127.0.0.1", "myaccount.localhost", 1), "fields", gocosmosdb.Config{MasterKey: "YXJpZWwNCg=="}, log)
+	store.uri = s.URL
+
+	ctx := context.Background()
+	assert.Nil(store.Put(ctx, "doc-1/body", []byte("large")))
+	assert.Equal("BlockBlob", req.Header.Get("X-Ms-Blob-Type"))
+	str := "PUT\n\napplication/octet-stream\n\nx-ms-blob-type:BlockBlob\nx-ms-date:" + req.Header.Get("X-Ms-Date") +
+		"\nx-ms-version:" + APIVersion + "\n/myaccount/fields/doc-1/body"
+	sig, _ := gocosmosdb.Sign(str, "YXJpZWwNCg==")
+	assert.Equal("SharedKeyLite myaccount:"+sig, req.Header.Get("Authorization"))
+
+	data, err := store.Get(ctx, "doc-1/body")
+	assert.Nil(err)
+	assert.Equal("large", string(data))
+
+	assert.Nil(store.Delete(ctx, "doc-1/body"))
+	assert.Nil(store.Delete(ctx, "doc-1/body"))
+	_, err = store.Get(ctx, "doc-1/body")
+	assert.Equal(http.StatusNotFound, err.(*Error).StatusCode)
+
+	var _ gocosmosdb.LargeFieldStore = store
+}
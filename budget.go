@@ -0,0 +1,102 @@
+package gocosmosdb
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExceeded - a non critical call was refused because the RU budget of the window is used up
+var ErrBudgetExceeded = errors.New("request unit budget exceeded")
+
+// BudgetEvent - the RUs charged in a window went over the budget
+type BudgetEvent struct {
+	Window   time.Time // start of the window
+	Limit    float64
+	Consumed float64
+}
+
+// RUBudget - tracks the RUs charged through a client per fixed window, set it on the Config to be alarmed once a
+// window goes over the limit and to refuse the calls marked NonCritical until the next window
+//
+//	budget := gocosmosdb.NewRUBudget(50000, time.Hour, func(e gocosmosdb.BudgetEvent) {
+//		log.Warnf("%.0f RUs used since %s, over the budget of %.0f", e.Consumed, e.Window, e.Limit)
+//	})
+//	client := gocosmosdb.New(url, gocosmosdb.Config{MasterKey: key, Budget: budget}, log)
+//	_, err := client.QueryDocuments(coll, reportQuery, &docs, gocosmosdb.NonCritical())
+type RUBudget struct {
+	limit      float64
+	window     time.Duration
+	onExceeded func(BudgetEvent)
+	now        func() time.Time
+	mu         sync.Mutex
+	start      time.Time
+	consumed   float64
+	alarmed    bool
+}
+
+// NewRUBudget - creates a budget of limit RUs per window, onExceeded is called once per window going over it
+// and may be nil
+func NewRUBudget(limit float64, window time.Duration, onExceeded func(BudgetEvent)) *RUBudget {
+	return &RUBudget{limit: limit, window: window, onExceeded: onExceeded, now: time.Now}
+}
+
+// NonCritical - marks a call as one the RUBudget of the client may refuse once over budget, eg. background work
+func NonCritical() CallOption {
+	return func(r *Request) error {
+		r.rNonCritical = true
+		return nil
+	}
+}
+
+// roll - starts a new window once the current one is over, the lock must be held
+func (b *RUBudget) roll() {
+	if start := b.now().Truncate(b.window); !start.Equal(b.start) {
+		b.start, b.consumed, b.alarmed = start, 0, false
+	}
+}
+
+// Consumed - returns the RUs charged in the current window
+func (b *RUBudget) Consumed() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.roll()
+	return b.consumed
+}
+
+// Exceeded - reports whether the current window went over the budget
+func (b *RUBudget) Exceeded() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.roll()
+	return b.consumed > b.limit
+}
+
+// admit - refuses non critical requests while over budget
+func (b *RUBudget) admit(r *Request) error {
+	if r.rNonCritical && b.Exceeded() {
+		return ErrBudgetExceeded
+	}
+	return nil
+}
+
+// record - adds the charge of a request to the window, alarming the first time the window goes over budget
+func (b *RUBudget) record(resp *Response) {
+	charge, err := resp.GetRUs()
+	if err != nil {
+		return
+	}
+	b.mu.Lock()
+	b.roll()
+	b.consumed += charge
+	var event *BudgetEvent
+	if b.consumed > b.limit && !b.alarmed {
+		b.alarmed = true
+		event = &BudgetEvent{Window: b.start, Limit: b.limit, Consumed: b.consumed}
+	}
+	b.mu.Unlock()
+	// outside the lock so the callback may read the budget
+	if event != nil && b.onExceeded != nil {
+		b.onExceeded(*event)
+	}
+}
@@ -0,0 +1,46 @@
+package gocosmosdb
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRUBudget(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"id": "1"}`, `{"id": "1"}`, `{"id": "1"}`, `{"id": "1"}`)
+	s.SetHeader(HeaderRequestCharge, "6")
+	defer s.Close()
+
+	now := time.Date(2019, 2, 13, 1, 0, 0, 0, time.UTC)
+	var events []BudgetEvent
+	budget := NewRUBudget(10, time.Hour, func(e BudgetEvent) {
+		events = append(events, e)
+	})
+	budget.now = func() time.Time { return now }
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", Budget: budget}, log)
+
+	var doc Document
+	_, err := client.ReadDocument("dbs/db/colls/coll/docs/1", &doc, NonCritical())
+	assert.Nil(err)
+	assert.False(budget.Exceeded())
+	_, err = client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+	assert.Nil(err)
+	assert.True(budget.Exceeded())
+	assert.Equal([]BudgetEvent{{Window: now, Limit: 10, Consumed: 12}}, events)
+
+	// over budget only the critical calls go through and the alarm is not repeated
+	_, err = client.ReadDocument("dbs/db/colls/coll/docs/1", &doc, NonCritical())
+	assert.True(errors.Is(err, ErrBudgetExceeded))
+	_, err = client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+	assert.Nil(err)
+	assert.Equal(18.0, budget.Consumed())
+	assert.Len(events, 1)
+
+	now = now.Add(time.Hour)
+	assert.Equal(0.0, budget.Consumed())
+	_, err = client.ReadDocument("dbs/db/colls/coll/docs/1", &doc, NonCritical())
+	assert.Nil(err)
+}
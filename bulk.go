@@ -0,0 +1,427 @@
+package gocosmosdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ExportOptions - tunes Export
+type ExportOptions struct {
+	Concurrency int // partition key ranges read at once, defaults to all of them
+	PageSize    int // documents per page, defaults to letting the service decide
+}
+
+// ExportFunc - receives each exported document, it is called from multiple goroutines
+type ExportFunc func(pkRange string, doc json.RawMessage) error
+
+// Export - reads every document of the collection by splitting it into its partition key ranges and reading
+// the ranges concurrently through the read feed, the first error stops the export
+//
+//	err := client.Export(ctx, "dbs/{db-id}/colls/{coll-id}/", nil, func(pkRange string, doc json.RawMessage) error {
+//		ch <- doc
+//		return nil
+//	})
+func (c *CosmosDB) Export(ctx context.Context, coll string, opts *ExportOptions, fn ExportFunc) error {
+	if opts == nil {
+		opts = &ExportOptions{}
+	}
+	ranges, err := c.QueryPartitionKeyRanges(coll, "", WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 || concurrency > len(ranges) {
+		concurrency = len(ranges)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, concurrency)
+	for _, pkRange := range ranges {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if err := c.exportRange(ctx, coll, id, opts.PageSize, fn); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(pkRange.Id)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// exportRange - pages through the read feed of a single partition key range
+func (c *CosmosDB) exportRange(ctx context.Context, coll, pkRange string, pageSize int, fn ExportFunc) error {
+	id, err := strconv.Atoi(pkRange)
+	if err != nil {
+		return err
+	}
+	if pageSize == 0 {
+		pageSize = -1
+	}
+	continuation := ""
+	for {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		docs := []json.RawMessage{}
+		resp, err := c.ReadDocuments(coll, &docs, Limit(pageSize), PartitionKeyRangeID(id), Continuation(continuation), WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		for _, doc := range docs {
+			if err = fn(pkRange, doc); err != nil {
+				return err
+			}
+		}
+		if continuation = resp.Continuation(); continuation == "" {
+			return nil
+		}
+	}
+}
+
+// BulkItem - a document write of a bulk import
+type BulkItem struct {
+	Operation    string      // BatchCreate, BatchUpsert, BatchReplace or BatchDelete, defaults to BatchUpsert
+	PartitionKey interface{} // value of the partition key of the document
+	ID           string      // of the document to replace or delete
+	Document     interface{} // to create, upsert or replace with
+}
+
+// operation - the batch operation writing the item
+func (item BulkItem) operation() (BatchOperation, error) {
+	switch item.Operation {
+	case "", BatchUpsert:
+		return BatchOperation{OperationType: BatchUpsert, ResourceBody: item.Document}, nil
+	case BatchCreate:
+		return BatchOperation{OperationType: BatchCreate, ResourceBody: item.Document}, nil
+	case BatchReplace:
+		return BatchOperation{OperationType: BatchReplace, ID: item.ID, ResourceBody: item.Document}, nil
+	case BatchDelete:
+		return BatchOperation{OperationType: BatchDelete, ID: item.ID}, nil
+	}
+	return BatchOperation{}, fmt.Errorf("%q is not a bulk operation", item.Operation)
+}
+
+// BulkResult - the outcome of an item of a bulk import
+type BulkResult struct {
+	BulkItem
+	StatusCode    int     // of the operation, 0 when its batch could not be sent
+	RequestCharge float64 // of the operation
+	Retries       int     // times its batch was retried after being throttled
+	Err           error   // nil when the write succeeded
+}
+
+// BulkResultFunc - receives the result of each item, it is called from multiple goroutines
+type BulkResultFunc func(result BulkResult)
+
+// BulkOptions - tunes Bulk
+type BulkOptions struct {
+	Workers    int           // batches written at once, defaults to the number of partition key ranges
+	BatchSize  int           // operations per batch, defaults to MaxBatchOperations
+	MaxRetries int           // times a throttled batch is retried before its items fail, defaults to 9
+	MinWait    time.Duration // first wait of a throttled batch the service gives no wait for, doubling, defaults to 100ms
+	MaxWait    time.Duration // longest wait of a throttled batch, defaults to 5s
+}
+
+// BulkReport - the totals of a bulk import
+type BulkReport struct {
+	Succeeded     int
+	Failed        int
+	Throttled     int     // times a batch was throttled
+	RequestCharge float64 // of every batch sent, including those rolled back or throttled
+}
+
+// Bulk - writes a stream of documents until the channel is closed, grouping them by partition key range and by
+// partition key into transactional batches written by a pool of workers. A throttled batch holds back the batches
+// of its partition key range until the wait is over and is retried, an item failing its operation is reported and
+// the batch it rolled back is written again without it. The report is returned when every item was written, the
+// error is for a collection that cannot be read or a cancelled context, which fails the items read so far and
+// leaves the others in the channel. Partition key ranges are only told apart for collections with version 2
+// partition keys, the items of other collections share one.
+//
+//	items := make(chan gocosmosdb.BulkItem)
+//	go func() {
+//		defer close(items)
+//		for _, order := range orders {
+//			items <- gocosmosdb.BulkItem{PartitionKey: order.Tenant, Document: order}
+//		}
+//	}()
+//	report, err := client.Bulk(ctx, "dbs/{db-id}/colls/{coll-id}/", items, nil, func(r gocosmosdb.BulkResult) {
+//		if r.Err != nil {
+//			log.Warnf("%v not written: %v", r.Document, r.Err)
+//		}
+//	})
+func (c *CosmosDB) Bulk(ctx context.Context, coll string, items <-chan BulkItem, opts *BulkOptions, fn BulkResultFunc) (*BulkReport, error) {
+	b := &bulkExecutor{c: c, coll: coll, fn: fn, report: &BulkReport{}, throttled: map[string]time.Time{}}
+	if opts != nil {
+		b.opts = *opts
+	}
+	if b.opts.BatchSize <= 0 || b.opts.BatchSize > MaxBatchOperations {
+		b.opts.BatchSize = MaxBatchOperations
+	}
+	if b.opts.MaxRetries <= 0 {
+		b.opts.MaxRetries = 9
+	}
+	if b.opts.MinWait <= 0 {
+		b.opts.MinWait = 100 * time.Millisecond
+	}
+	if b.opts.MaxWait <= 0 {
+		b.opts.MaxWait = 5 * time.Second
+	}
+	if err := b.routes(ctx); err != nil {
+		return nil, err
+	}
+	if b.opts.Workers <= 0 {
+		if b.opts.Workers = len(b.ranges); b.opts.Workers == 0 {
+			b.opts.Workers = 1
+		}
+	}
+
+	work := make(chan *bulkBatch)
+	var wg sync.WaitGroup
+	for i := 0; i < b.opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range work {
+				b.write(ctx, batch)
+			}
+		}()
+	}
+	// the batches being filled, by partition key range and partition key
+	pending := map[string]*bulkBatch{}
+	var order []string
+read:
+	for {
+		select {
+		case <-ctx.Done():
+			break read
+		case item, ok := <-items:
+			if !ok {
+				break read
+			}
+			result := &BulkResult{BulkItem: item}
+			pkRange, key, err := b.route(item)
+			if err != nil {
+				result.Err = err
+				b.done(result)
+				continue
+			}
+			batch, ok := pending[key]
+			if !ok {
+				batch = &bulkBatch{pkRange: pkRange, partitionKey: item.PartitionKey}
+				pending[key] = batch
+				order = append(order, key)
+			}
+			if batch.results = append(batch.results, result); len(batch.results) == b.opts.BatchSize {
+				delete(pending, key)
+				work <- batch
+			}
+		}
+	}
+	for _, key := range order {
+		if batch, ok := pending[key]; ok {
+			delete(pending, key)
+			work <- batch
+		}
+	}
+	close(work)
+	wg.Wait()
+	return b.report, ctx.Err()
+}
+
+// bulkBatch - items of a bulk import sharing a partition key
+type bulkBatch struct {
+	pkRange      string
+	partitionKey interface{}
+	results      []*BulkResult
+}
+
+// bulkExecutor - the state of a bulk import
+type bulkExecutor struct {
+	c      *CosmosDB
+	coll   string
+	opts   BulkOptions
+	fn     BulkResultFunc
+	ranges []PartitionKeyRange // sorted by their start, nil when the collection does not hash version 2 keys
+
+	mu        sync.Mutex
+	report    *BulkReport
+	throttled map[string]time.Time // when the partition key ranges may be written again
+}
+
+// routes - reads the partition key ranges of a collection with version 2 partition keys
+func (b *bulkExecutor) routes(ctx context.Context) error {
+	coll, err := b.c.ReadCollection(b.coll, WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	if coll.PartitionKeyDef.Version != 2 {
+		return nil
+	}
+	if b.ranges, err = b.c.ReadPartitionKeyRanges(normalizeLink(b.coll), WithContext(ctx)); err != nil {
+		return err
+	}
+	sort.Slice(b.ranges, func(i, j int) bool { return b.ranges[i].MinInclusive < b.ranges[j].MinInclusive })
+	return nil
+}
+
+// route - returns the partition key range of an item and the key of the batch it goes in
+func (b *bulkExecutor) route(item BulkItem) (pkRange, key string, err error) {
+	if _, err = item.operation(); err != nil {
+		return "", "", err
+	}
+	pk, err := json.Marshal(item.PartitionKey)
+	if err != nil {
+		return "", "", err
+	}
+	if b.ranges != nil {
+		epk, err := EffectivePartitionKey(item.PartitionKey)
+		if err != nil {
+			return "", "", err
+		}
+		r, ok := rangeOf(b.ranges, epk)
+		if !ok {
+			return "", "", fmt.Errorf("no partition key range holds the partition key %s", pk)
+		}
+		pkRange = r.Id
+	}
+	return pkRange, pkRange + "/" + string(pk), nil
+}
+
+// write - writes a batch, retrying it while it is throttled and without the items failing it
+func (b *bulkExecutor) write(ctx context.Context, batch *bulkBatch) {
+	pending := batch.results
+	for attempt := 1; len(pending) > 0; {
+		if err := b.wait(ctx, batch.pkRange); err != nil {
+			b.fail(pending, err)
+			return
+		}
+		tb := NewTransactionalBatch(b.coll, batch.partitionKey)
+		for _, result := range pending {
+			op, _ := result.operation()
+			tb.add(op)
+		}
+		results, err := b.c.ExecuteBatch(tb, WithContext(ctx))
+		b.charge(results)
+		var batchErr *BatchError
+		errors.As(err, &batchErr)
+		switch {
+		case errors.Is(err, ErrTooManyRequests):
+			if attempt > b.opts.MaxRetries {
+				b.fail(pending, err)
+				return
+			}
+			for _, result := range pending {
+				result.Retries++
+			}
+			b.throttle(batch.pkRange, b.retryWait(attempt, batchErr))
+			attempt++
+			continue
+		case (err == nil || batchErr != nil) && len(results) != len(pending):
+			err = fmt.Errorf("a batch of %d operations returned %d results", len(pending), len(results))
+		case batchErr != nil:
+			failed := pending[batchErr.Index]
+			failed.StatusCode = batchErr.StatusCode
+			failed.RequestCharge = results[batchErr.Index].RequestCharge
+			failed.Err = &RequestError{StatusCode: batchErr.StatusCode, Message: http.StatusText(batchErr.StatusCode)}
+			b.done(failed)
+			pending = append(pending[:batchErr.Index:batchErr.Index], pending[batchErr.Index+1:]...)
+			continue
+		}
+		if err != nil {
+			b.fail(pending, err)
+			return
+		}
+		for i, result := range pending {
+			result.StatusCode = results[i].StatusCode
+			result.RequestCharge = results[i].RequestCharge
+			b.done(result)
+		}
+		return
+	}
+}
+
+// retryWait - how long to hold back a throttled batch, as long as the throttled operation asks when it does
+func (b *bulkExecutor) retryWait(attempt int, batchErr *BatchError) time.Duration {
+	if batchErr != nil && batchErr.Results[batchErr.Index].RetryAfterMs > 0 {
+		return time.Duration(batchErr.Results[batchErr.Index].RetryAfterMs) * time.Millisecond
+	}
+	return (&DefaultRetryPolicy{MinWait: b.opts.MinWait, MaxWait: b.opts.MaxWait}).backoff(attempt)
+}
+
+// throttle - holds back the batches of a partition key range for a while
+func (b *bulkExecutor) throttle(pkRange string, wait time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.report.Throttled++
+	if until := time.Now().Add(wait); until.After(b.throttled[pkRange]) {
+		b.throttled[pkRange] = until
+	}
+}
+
+// wait - waits until the partition key range is no longer throttled
+func (b *bulkExecutor) wait(ctx context.Context, pkRange string) error {
+	b.mu.Lock()
+	until := b.throttled[pkRange]
+	b.mu.Unlock()
+	if wait := time.Until(until); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return ctx.Err()
+}
+
+// charge - adds the charge of the operations of a batch to the report
+func (b *bulkExecutor) charge(results []BatchResult) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, result := range results {
+		b.report.RequestCharge += result.RequestCharge
+	}
+}
+
+// fail - reports the items as failed with the error
+func (b *bulkExecutor) fail(results []*BulkResult, err error) {
+	for _, result := range results {
+		result.Err = err
+		b.done(result)
+	}
+}
+
+// done - counts the result of an item and passes it on
+func (b *bulkExecutor) done(result *BulkResult) {
+	b.mu.Lock()
+	if result.Err == nil {
+		b.report.Succeeded++
+	} else {
+		b.report.Failed++
+	}
+	b.mu.Unlock()
+	if b.fn != nil {
+		b.fn(*result)
+	}
+}
@@ -0,0 +1,220 @@
+package gocosmosdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testPartitionKeyRanges = `{
+	"_rid": "qYcAAPEvJBQ=",
+	"PartitionKeyRanges": [
+		{"id": "0", "minInclusive": "", "maxExclusive": "05C1C9CD673398"},
+		{"id": "1", "minInclusive": "05C1C9CD673398", "maxExclusive": "FF"}
+	],
+	"_count": 2
+}`
+
+func TestExport(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(testPartitionKeyRanges,
+		`{"Documents": [{"id": "SalesOrder1"}, {"id": "SalesOrder2"}], "_count": 2}`,
+		`{"Documents": [{"id": "SalesOrder3"}], "_count": 1}`,
+	)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	var mu sync.Mutex
+	ids := []string{}
+	err := client.Export(context.Background(), "dbs/d9RzAA==/colls/d9RzAJRFKgw=/", &ExportOptions{Concurrency: 1}, func(pkRange string, doc json.RawMessage) error {
+		d := testDoc{}
+		json.Unmarshal(doc, &d)
+		mu.Lock()
+		ids = append(ids, d.Id)
+		mu.Unlock()
+		return nil
+	})
+	assert.Nil(err)
+	assert.ElementsMatch([]string{"SalesOrder1", "SalesOrder2", "SalesOrder3"}, ids)
+	assert.NotEqual("", s.Header.Get(HeaderPartitionKeyRangeID))
+}
+
+func TestExportStopsOnError(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(testPartitionKeyRanges,
+		`{"Documents": [{"id": "SalesOrder1"}], "_count": 1}`,
+		`{"Documents": [{"id": "SalesOrder2"}], "_count": 1}`,
+	)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	errStop := errors.New("stop")
+	err := client.Export(context.Background(), "dbs/d9RzAA==/colls/d9RzAJRFKgw=/", &ExportOptions{Concurrency: 1}, func(pkRange string, doc json.RawMessage) error {
+		return errStop
+	})
+	assert.Equal(errStop, err)
+}
+
+func TestBulk(t *testing.T) {
+	assert := assert.New(t)
+	var mu sync.Mutex
+	throttled := false
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/pkranges/"):
+			w.Write([]byte(testPartitionKeyRanges))
+			return
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`{"id": "coll", "partitionKey": {"kind": "Hash", "paths": ["/tenant"], "version": 2}}`))
+			return
+		}
+		var ops []BatchOperation
+		json.NewDecoder(r.Body).Decode(&ops)
+		mu.Lock()
+		defer mu.Unlock()
+		// the first batch of tenant-2 is throttled, creating dup conflicts
+		results := make([]BatchResult, len(ops))
+		for i := range results {
+			results[i] = BatchResult{StatusCode: http.StatusFailedDependency}
+		}
+		if r.Header.Get(HeaderPartitionKey) == `["tenant-2"]` && !throttled {
+			throttled = true
+			results[0] = BatchResult{StatusCode: http.StatusTooManyRequests, RetryAfterMs: 1}
+			w.WriteHeader(http.StatusMultiStatus)
+			json.NewEncoder(w).Encode(results)
+			return
+		}
+		for i, op := range ops {
+			if op.ResourceBody.(map[string]interface{})["id"] == "dup" {
+				results[i] = BatchResult{StatusCode: http.StatusConflict, RequestCharge: 1}
+				w.WriteHeader(http.StatusMultiStatus)
+				json.NewEncoder(w).Encode(results)
+				return
+			}
+		}
+		for i := range results {
+			results[i] = BatchResult{StatusCode: http.StatusCreated, RequestCharge: 2}
+		}
+		json.NewEncoder(w).Encode(results)
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	items := make(chan BulkItem)
+	go func() {
+		defer close(items)
+		for _, item := range []BulkItem{
+			{Operation: BatchCreate, PartitionKey: "tenant-1", Document: map[string]string{"id": "a"}},
+			{Operation: BatchCreate, PartitionKey: "tenant-1", Document: map[string]string{"id": "dup"}},
+			{PartitionKey: "tenant-2", Document: map[string]string{"id": "c"}},
+			{Operation: BatchCreate, PartitionKey: "tenant-1", Document: map[string]string{"id": "b"}},
+			{Operation: "Merge", PartitionKey: "tenant-1", Document: map[string]string{"id": "d"}},
+			{PartitionKey: struct{}{}, Document: map[string]string{"id": "e"}},
+		} {
+			items <- item
+		}
+	}()
+	var results []BulkResult
+	report, err := client.Bulk(context.Background(), "dbs/db/colls/coll/", items, &BulkOptions{Workers: 2}, func(r BulkResult) {
+		mu.Lock()
+		results = append(results, r)
+		mu.Unlock()
+	})
+	assert.Nil(err)
+	assert.Equal(&BulkReport{Succeeded: 3, Failed: 3, Throttled: 1, RequestCharge: 7}, report)
+	assert.Len(results, 6)
+	for _, r := range results {
+		switch id := r.Document.(map[string]string)["id"]; id {
+		case "a", "b":
+			assert.Nil(r.Err)
+			assert.Equal(http.StatusCreated, r.StatusCode)
+			assert.Equal(2.0, r.RequestCharge)
+		case "c":
+			assert.Nil(r.Err)
+			assert.Equal(1, r.Retries)
+		case "dup":
+			assert.True(errors.Is(r.Err, ErrConflict))
+			assert.Equal(1.0, r.RequestCharge)
+		case "d":
+			assert.EqualError(r.Err, `"Merge" is not a bulk operation`)
+		case "e":
+			assert.EqualError(r.Err, "cannot hash a partition key of type struct {}")
+		}
+	}
+}
+
+func TestBulkGivesUpWhenThrottled(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"id": "coll", "partitionKey": {"kind": "Hash", "paths": ["/tenant"]}}`,
+		`[{"statusCode": 429, "requestCharge": 0.5}]`,
+		`[{"statusCode": 429, "requestCharge": 0.5}]`,
+	)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	items := make(chan BulkItem, 1)
+	items <- BulkItem{PartitionKey: "tenant-1", Document: map[string]string{"id": "a"}}
+	close(items)
+	var result BulkResult
+	report, err := client.Bulk(context.Background(), "dbs/db/colls/coll/", items, &BulkOptions{MaxRetries: 1, MinWait: time.Millisecond}, func(r BulkResult) {
+		result = r
+	})
+	assert.Nil(err)
+	assert.Equal(&BulkReport{Failed: 1, Throttled: 1, RequestCharge: 1}, report)
+	assert.True(errors.Is(result.Err, ErrTooManyRequests))
+	assert.Equal(1, result.Retries)
+}
+
+func TestBulkDocumentHooks(t *testing.T) {
+	assert := assert.New(t)
+	var body []byte
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"id": "coll", "partitionKey": {"kind": "Hash", "paths": ["/tenant"]}}`))
+			return
+		}
+		body, _ = ioutil.ReadAll(r.Body)
+		if strings.Contains(string(body), `"short"`) {
+			// more results than operations
+			w.WriteHeader(http.StatusMultiStatus)
+			w.Write([]byte(`[{"statusCode": 424}, {"statusCode": 409}]`))
+			return
+		}
+		w.Write([]byte(`[{"statusCode": 201, "requestCharge": 2}]`))
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", Audit: ContextAudit, ContentHashes: true}, log)
+	bulk := func(id string) BulkResult {
+		items := make(chan BulkItem, 1)
+		items <- BulkItem{PartitionKey: "tenant-1", Document: map[string]string{"id": id, "tenant": "tenant-1"}}
+		close(items)
+		var result BulkResult
+		_, err := client.Bulk(WithActor(context.Background(), "importer"), "dbs/db/colls/coll/", items, nil, func(r BulkResult) {
+			result = r
+		})
+		assert.Nil(err)
+		return result
+	}
+
+	// the documents imported are audited and hashed like single writes
+	result := bulk("a")
+	assert.Nil(result.Err)
+	var ops []struct {
+		ResourceBody map[string]interface{} `json:"resourceBody"`
+	}
+	assert.Nil(json.Unmarshal(body, &ops))
+	assert.Equal(map[string]interface{}{"actor": "importer"}, ops[0].ResourceBody[AuditField])
+	assert.Len(ops[0].ResourceBody[ContentHashField], 64)
+
+	result = bulk("short")
+	assert.EqualError(result.Err, "a batch of 1 operations returned 2 results")
+}
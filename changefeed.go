@@ -0,0 +1,202 @@
+package gocosmosdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// DefaultChangeFeedPollInterval - how long a polling change feed reader waits when the feed has no new changes
+var DefaultChangeFeedPollInterval = 5 * time.Second
+
+// StartFromBeginning - starts a change feed reader at the first change of the collection, the default
+func StartFromBeginning() CallOption {
+	return func(r *Request) error {
+		r.Header.Del(HeaderIfNonMatch)
+		r.Header.Del(HeaderIfModifiedSince)
+		return nil
+	}
+}
+
+// StartFromNow - starts a change feed reader after the current changes, only reading new ones
+func StartFromNow() CallOption {
+	return func(r *Request) error {
+		r.Header.Del(HeaderIfModifiedSince)
+		r.Header.Set(HeaderIfNonMatch, "*")
+		return nil
+	}
+}
+
+// StartFromTime - starts a change feed reader at the changes made after t
+func StartFromTime(t time.Time) CallOption {
+	return func(r *Request) error {
+		r.Header.Del(HeaderIfNonMatch)
+		r.Header.Set(HeaderIfModifiedSince, t.UTC().Format(http.TimeFormat))
+		return nil
+	}
+}
+
+// ChangeFeedReader - reads the change feed of one partition key range of a collection, page by page
+type ChangeFeedReader struct {
+	db           *CosmosDB
+	coll         string
+	pkRange      string
+	etag         string
+	opts         []CallOption
+	maxItems     int
+	pollInterval time.Duration
+	maxPoll      time.Duration
+}
+
+// NewChangeFeedReader - creates a reader for the changes of a partition key range, starting where the start
+// option passed says, StartFromBeginning, StartFromNow or StartFromTime, or resuming from a saved
+// Continuation passed with IfNoneMatch
+//
+//	reader := client.NewChangeFeedReader("dbs/{db-id}/colls/{coll-id}/", "0", gocosmosdb.StartFromNow())
+//	for {
+//		var docs []Doc
+//		changed, err := reader.Next(&docs)
+//		...
+//	}
+func (c *CosmosDB) NewChangeFeedReader(coll, pkRange string, opts ...CallOption) *ChangeFeedReader {
+	return &ChangeFeedReader{db: c, coll: coll, pkRange: pkRange, opts: opts, pollInterval: DefaultChangeFeedPollInterval}
+}
+
+// WithMaxItemCount - sets the most changes read per page, by default the service decides
+func (r *ChangeFeedReader) WithMaxItemCount(n int) *ChangeFeedReader {
+	r.maxItems = n
+	return r
+}
+
+// WithPollInterval - sets how long Poll waits when the feed has no new changes, doubling the wait up to max
+// while the feed stays empty, a max below interval keeps the wait constant
+func (r *ChangeFeedReader) WithPollInterval(interval, max time.Duration) *ChangeFeedReader {
+	r.pollInterval = interval
+	r.maxPoll = max
+	return r
+}
+
+// Next - reads the next page of changes into docs, returning false when there were no new changes
+func (r *ChangeFeedReader) Next(docs interface{}, opts ...CallOption) (bool, error) {
+	id, err := strconv.Atoi(r.pkRange)
+	if err != nil {
+		return false, err
+	}
+	opts = append(append([]CallOption{PartitionKeyRangeID(id)}, r.opts...), opts...)
+	if r.maxItems > 0 {
+		opts = append(opts, Limit(r.maxItems))
+	}
+	// once a page was read continue from its position whatever the start option
+	if r.etag != "" {
+		opts = append(opts, IfNoneMatch(r.etag))
+	}
+	data := struct {
+		Documents interface{} `json:"Documents,omitempty"`
+		Count     int         `json:"_count,omitempty"`
+	}{Documents: docs}
+	resp, err := r.db.client.changes(r.coll+"docs/", &data, opts...)
+	if err != nil {
+		return false, err
+	}
+	if etag := resp.ETag(); etag != "" {
+		r.etag = etag
+	}
+	return data.Count > 0, nil
+}
+
+// Continuation - returns the position the reader reached, pass it with IfNoneMatch to a new reader to resume
+func (r *ChangeFeedReader) Continuation() string {
+	return r.etag
+}
+
+// Poll - reads the changes until ctx is done or fn fails, calling fn with every page of changes and waiting
+// the poll interval whenever the feed has no new changes
+//
+//	err := reader.Poll(ctx, func(docs []json.RawMessage) error {
+//		return handle(docs)
+//	})
+func (r *ChangeFeedReader) Poll(ctx context.Context, fn func(docs []json.RawMessage) error) error {
+	wait := r.pollInterval
+	for {
+		docs := []json.RawMessage{}
+		changed, err := r.Next(&docs, WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		if changed {
+			if err = fn(docs); err != nil {
+				return err
+			}
+			wait = r.pollInterval
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		if wait *= 2; wait > r.maxPoll {
+			wait = r.maxPoll
+		}
+		if wait < r.pollInterval {
+			wait = r.pollInterval
+		}
+	}
+}
+
+// ChangeFeedPage - the changes of a collection read by ReadChangeFeed
+type ChangeFeedPage struct {
+	Documents    []json.RawMessage
+	Continuation string // where every partition key range was read up to, pass it to the next ReadChangeFeed
+}
+
+// ReadChangeFeed - reads a page of the changes of every partition key range of a collection, from the position of
+// each range in the continuation of the page read before or, with none, where the start option passed says. The
+// ranges split since take over the position of the range they were split from.
+//
+//	page, err := client.ReadChangeFeed(ctx, "dbs/{db-id}/colls/{coll-id}/", "", gocosmosdb.StartFromNow())
+//	for err == nil {
+//		handle(page.Documents)
+//		time.Sleep(time.Second)
+//		page, err = client.ReadChangeFeed(ctx, "dbs/{db-id}/colls/{coll-id}/", page.Continuation)
+//	}
+func (c *CosmosDB) ReadChangeFeed(ctx context.Context, coll, continuation string, opts ...CallOption) (*ChangeFeedPage, error) {
+	coll = normalizeLink(coll)
+	etags := map[string]string{}
+	if continuation != "" {
+		if err := json.Unmarshal([]byte(continuation), &etags); err != nil {
+			return nil, fmt.Errorf("invalid change feed continuation: %v", err)
+		}
+	}
+	ranges, err := c.ReadPartitionKeyRanges(coll, WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].MinInclusive < ranges[j].MinInclusive })
+	page := &ChangeFeedPage{Documents: []json.RawMessage{}}
+	next := map[string]string{}
+	for _, pkRange := range ranges {
+		reader := c.NewChangeFeedReader(coll, pkRange.Id, opts...)
+		reader.etag = etags[pkRange.Id]
+		// a range split since the last page continues from the newest of the ranges it was split from
+		for i := len(pkRange.Parents) - 1; i >= 0 && reader.etag == ""; i-- {
+			reader.etag = etags[pkRange.Parents[i]]
+		}
+		docs := []json.RawMessage{}
+		if _, err = reader.Next(&docs, WithContext(ctx)); err != nil {
+			return nil, err
+		}
+		page.Documents = append(page.Documents, docs...)
+		next[pkRange.Id] = reader.Continuation()
+	}
+	data, err := json.Marshal(next)
+	if err != nil {
+		return nil, err
+	}
+	page.Continuation = string(data)
+	return page, nil
+}
@@ -0,0 +1,122 @@
+package gocosmosdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeFeedStartOptions(t *testing.T) {
+	assert := assert.New(t)
+	req, err := http.NewRequest("GET", "http://localhost:8080", nil)
+	assert.Nil(err)
+	r := ResourceRequest("/dbs/db/colls/coll/docs", req)
+
+	assert.Nil(StartFromNow()(r))
+	assert.Equal("*", r.Header.Get(HeaderIfNonMatch))
+
+	assert.Nil(StartFromTime(time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC))(r))
+	assert.Equal("", r.Header.Get(HeaderIfNonMatch))
+	assert.Equal("Sat, 01 Jun 2019 12:00:00 GMT", r.Header.Get(HeaderIfModifiedSince))
+
+	assert.Nil(StartFromBeginning()(r))
+	assert.Equal("", r.Header.Get(HeaderIfNonMatch))
+	assert.Equal("", r.Header.Get(HeaderIfModifiedSince))
+}
+
+func TestChangeFeedReader(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"Documents": [{"id": "SalesOrder1"}, {"id": "SalesOrder2"}], "_count": 2}`, ``)
+	s.SetHeader(HeaderETag, `"42"`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+	reader := client.NewChangeFeedReader("dbs/db/colls/coll/", "0", StartFromNow())
+
+	docs := []testDoc{}
+	changed, err := reader.Next(&docs)
+	assert.Nil(err)
+	assert.True(changed)
+	assert.Equal(2, len(docs))
+	assert.Equal("Incremental feed", s.Header.Get(HeaderAIM))
+	assert.Equal("0", s.Header.Get(HeaderPartitionKeyRangeID))
+	assert.Equal("*", s.Header.Get(HeaderIfNonMatch))
+	assert.Equal(`"42"`, reader.Continuation())
+
+	s.SetStatus(http.StatusNotModified)
+	docs = []testDoc{}
+	changed, err = reader.Next(&docs)
+	assert.Nil(err)
+	assert.False(changed)
+	assert.Equal(0, len(docs))
+	assert.Equal(`"42"`, s.Header.Get(HeaderIfNonMatch))
+}
+
+func TestChangeFeedReaderPoll(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"Documents": [{"id": "SalesOrder1"}, {"id": "SalesOrder2"}], "_count": 2}`, `{"Documents": [], "_count": 0}`)
+	s.SetHeader(HeaderETag, `"42"`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+	reader := client.NewChangeFeedReader("dbs/db/colls/coll/", "0").WithMaxItemCount(100).WithPollInterval(time.Minute, time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	changes := 0
+	err := reader.Poll(ctx, func(docs []json.RawMessage) error {
+		changes += len(docs)
+		return nil
+	})
+	assert.Equal(context.DeadlineExceeded, err)
+	assert.Equal(2, changes)
+	assert.Equal("100", s.Header.Get(HeaderMaxItemCount))
+}
+
+func TestReadChangeFeed(t *testing.T) {
+	assert := assert.New(t)
+	split := false
+	ifNoneMatch := map[string]string{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/pkranges/") {
+			if split {
+				// range 1 was split into ranges 2 and 3
+				fmt.Fprint(w, `{"PartitionKeyRanges": [
+					{"id": "3", "minInclusive": "3F", "maxExclusive": "FF", "parents": ["1"]},
+					{"id": "0", "minInclusive": "", "maxExclusive": "1F"},
+					{"id": "2", "minInclusive": "1F", "maxExclusive": "3F", "parents": ["1"]}
+				]}`)
+				return
+			}
+			fmt.Fprint(w, testPartitionKeyRanges)
+			return
+		}
+		pkRange := r.Header.Get(HeaderPartitionKeyRangeID)
+		ifNoneMatch[pkRange] = r.Header.Get(HeaderIfNonMatch)
+		w.Header().Set(HeaderETag, `"`+pkRange+`"`)
+		fmt.Fprintf(w, `{"Documents": [{"id": "%s"}], "_count": 1}`, pkRange)
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	page, err := client.ReadChangeFeed(context.Background(), "dbs/db/colls/coll", "", StartFromNow())
+	assert.Nil(err)
+	assert.Equal([]json.RawMessage{json.RawMessage(`{"id": "0"}`), json.RawMessage(`{"id": "1"}`)}, page.Documents)
+	assert.Equal(map[string]string{"0": "*", "1": "*"}, ifNoneMatch)
+	assert.JSONEq(`{"0": "\"0\"", "1": "\"1\""}`, page.Continuation)
+
+	split = true
+	page, err = client.ReadChangeFeed(context.Background(), "dbs/db/colls/coll", page.Continuation, StartFromNow())
+	assert.Nil(err)
+	assert.Len(page.Documents, 3)
+	assert.Equal(map[string]string{"0": `"0"`, "1": "*", "2": `"1"`, "3": `"1"`}, ifNoneMatch)
+	assert.JSONEq(`{"0": "\"0\"", "2": "\"2\"", "3": "\"3\""}`, page.Continuation)
+
+	_, err = client.ReadChangeFeed(context.Background(), "dbs/db/colls/coll", "0")
+	assert.EqualError(err, "invalid change feed continuation: json: cannot unmarshal number into Go value of type map[string]string")
+}
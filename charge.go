@@ -0,0 +1,99 @@
+package gocosmosdb
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+type chargeKey int
+
+const requestChargeKey chargeKey = 0
+
+// RequestCharge - accumulates the RUs charged for the calls made with a context, see WithRequestCharge
+type RequestCharge struct {
+	mu     sync.Mutex
+	parent *RequestCharge
+	total  float64
+	calls  int
+}
+
+// WithRequestCharge - returns a copy of the context accumulating the RUs of every call passed it through
+// WithContext, charges also count towards accumulators of the contexts it derives from
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//		ctx := gocosmosdb.WithRequestCharge(r.Context())
+//		_, err := client.ReadDocument(link, &doc, gocosmosdb.WithContext(ctx))
+//		...
+//		charge, _ := gocosmosdb.RequestChargeFromContext(ctx)
+//		metrics.Observe(r.URL.Path, charge.Total())
+//	}
+func WithRequestCharge(ctx context.Context) context.Context {
+	parent, _ := RequestChargeFromContext(ctx)
+	return context.WithValue(ctx, requestChargeKey, &RequestCharge{parent: parent})
+}
+
+// RequestChargeFromContext - returns the accumulator set by WithRequestCharge
+func RequestChargeFromContext(ctx context.Context) (*RequestCharge, bool) {
+	charge, ok := ctx.Value(requestChargeKey).(*RequestCharge)
+	return charge, ok
+}
+
+// Total - returns the RUs charged so far
+func (c *RequestCharge) Total() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.total
+}
+
+// Calls - returns the number of calls that returned a charge so far
+func (c *RequestCharge) Calls() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+// Reset - forgets the charges accumulated so far, eg. to report per interval, the charges already added to
+// parents stay there
+func (c *RequestCharge) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.total = 0
+	c.calls = 0
+}
+
+// RequestCharge - returns the accumulator of the RUs charged for every call made by the client, the charge of a
+// single call is returned by the Response of the calls returning one or read with WithResponse
+//
+//	var resp gocosmosdb.Response
+//	db, err := client.ReadDatabase(link, gocosmosdb.WithResponse(&resp))
+//	charge, _ := resp.GetRUs()
+//	...
+//	log.Infof("%.2f RUs over %d calls", client.RequestCharge().Total(), client.RequestCharge().Calls())
+func (c *CosmosDB) RequestCharge() *RequestCharge {
+	return c.client.charge
+}
+
+// add - records the charge of a call on the accumulator and its parents
+func (c *RequestCharge) add(charge float64) {
+	for ; c != nil; c = c.parent {
+		c.mu.Lock()
+		c.total += charge
+		c.calls++
+		c.mu.Unlock()
+	}
+}
+
+// chargeContext - adds the charge of a response, failed calls included, to the accumulator of the client and to
+// that of the context
+func chargeContext(ctx context.Context, client *RequestCharge, header http.Header) {
+	charge, err := strconv.ParseFloat(header.Get(HeaderRequestCharge), 64)
+	if err != nil {
+		return
+	}
+	client.add(charge)
+	if acc, ok := RequestChargeFromContext(ctx); ok {
+		acc.add(charge)
+	}
+}
@@ -0,0 +1,67 @@
+package gocosmosdb
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestCharge(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"id": "1"}`, `{"id": "2"}`, http.StatusNotFound, `{"id": "3"}`)
+	s.SetHeader(HeaderRequestCharge, "2.5")
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	ctx := WithRequestCharge(context.Background())
+	var doc Document
+	_, err := client.ReadDocument("dbs/db/colls/coll/docs/1", &doc, WithContext(ctx))
+	assert.Nil(err)
+
+	inner := WithRequestCharge(ctx)
+	_, err = client.ReadDocument("dbs/db/colls/coll/docs/2", &doc, WithContext(inner))
+	assert.Nil(err)
+	// failed calls are charged too
+	_, err = client.ReadDocument("dbs/db/colls/coll/docs/9", &doc, WithContext(inner))
+	assert.NotNil(err)
+	// calls without the context are not
+	_, err = client.ReadDocument("dbs/db/colls/coll/docs/3", &doc)
+	assert.Nil(err)
+
+	charge, ok := RequestChargeFromContext(ctx)
+	assert.True(ok)
+	assert.Equal(7.5, charge.Total())
+	assert.Equal(3, charge.Calls())
+	charge, _ = RequestChargeFromContext(inner)
+	assert.Equal(5.0, charge.Total())
+
+	_, ok = RequestChargeFromContext(context.Background())
+	assert.False(ok)
+}
+
+func TestClientRequestCharge(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"id": "1"}`, http.StatusNotFound, `{"id": "db"}`)
+	s.SetHeader(HeaderRequestCharge, "2.5")
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	var doc Document
+	_, err := client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+	assert.Nil(err)
+	_, err = client.ReadDocument("dbs/db/colls/coll/docs/9", &doc)
+	assert.NotNil(err)
+	var resp Response
+	_, err = client.ReadDatabase("dbs/db/", WithResponse(&resp))
+	assert.Nil(err)
+	charge, err := resp.GetRUs()
+	assert.Nil(err)
+	assert.Equal(2.5, charge)
+
+	assert.Equal(7.5, client.RequestCharge().Total())
+	assert.Equal(3, client.RequestCharge().Calls())
+	client.RequestCharge().Reset()
+	assert.Equal(0.0, client.RequestCharge().Total())
+}
@@ -0,0 +1,165 @@
+package gocosmosdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Chunker - stores documents over a size threshold as chunk documents and a manifest in the documents place,
+// reassembling them on read, for collections with occasional documents over the service limit
+type Chunker struct {
+	db               *CosmosDB
+	coll             string
+	partitionKeyPath string
+	Threshold        int // documents larger than this in bytes are chunked, 1MB by default
+	ChunkSize        int // bytes of the document per chunk, 1MB by default
+}
+
+// ChunkManifest - stands in for a chunked document, listing its chunks
+type ChunkManifest struct {
+	Chunked bool `json:"_chunked"`
+	Chunks  int  `json:"_chunks"`
+	Size    int  `json:"_size"`
+}
+
+// NewChunker - creates a chunker for a collection partitioned by partitionKeyPath eg. "/tenant", the chunks of a
+// document are stored in its partition
+//
+//	chunker := client.NewChunker("dbs/{db-id}/colls/{coll-id}/", "/tenant")
+//	err := chunker.Write(ctx, doc.Id, doc.Tenant, &doc)
+//	err = chunker.Read(ctx, doc.Id, doc.Tenant, &doc)
+func (c *CosmosDB) NewChunker(coll, partitionKeyPath string) *Chunker {
+	return &Chunker{db: c, coll: coll, partitionKeyPath: partitionKeyPath, Threshold: 1024 * 1024, ChunkSize: 1024 * 1024}
+}
+
+func chunkID(id string, i int) string {
+	return fmt.Sprintf("%s:chunk:%d", id, i)
+}
+
+// Write - upserts the document with id, chunking it when it is over the threshold
+func (k *Chunker) Write(ctx context.Context, id string, partitionKey, doc interface{}) error {
+	data, err := stringify(doc)
+	if err != nil {
+		return err
+	}
+	previous, err := k.manifest(ctx, id, partitionKey)
+	if err != nil {
+		return err
+	}
+	chunks := 0
+	if len(data) > k.Threshold {
+		for ; chunks*k.ChunkSize < len(data); chunks++ {
+			end := (chunks + 1) * k.ChunkSize
+			if end > len(data) {
+				end = len(data)
+			}
+			part := map[string]interface{}{"id": chunkID(id, chunks), "manifest": id, "index": chunks, "data": data[chunks*k.ChunkSize : end]}
+			if err = k.upsert(ctx, part, partitionKey); err != nil {
+				return err
+			}
+		}
+		// the manifest is written last so it never lists missing chunks
+		manifest := map[string]interface{}{"id": id, "_chunked": true, "_chunks": chunks, "_size": len(data)}
+		if err = k.upsert(ctx, manifest, partitionKey); err != nil {
+			return err
+		}
+	} else if _, err = k.db.client.method(http.MethodPost, k.coll+"docs/", expectUpserted, nil, bytes.NewBuffer(data),
+		Upsert(), PartitionKey(partitionKey), WithContext(ctx)); err != nil {
+		return err
+	}
+	// drop the chunks of the previous version the new one no longer uses
+	for i := chunks; previous != nil && i < previous.Chunks; i++ {
+		if err = k.deleteDoc(ctx, chunkID(id, i), partitionKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Read - reads the document with id into doc, reassembling it when it was chunked
+func (k *Chunker) Read(ctx context.Context, id string, partitionKey, doc interface{}) error {
+	var raw json.RawMessage
+	if _, err := k.db.client.read(k.coll+"docs/"+id, &raw, PartitionKey(partitionKey), WithContext(ctx)); err != nil {
+		return err
+	}
+	var manifest ChunkManifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return err
+	}
+	if !manifest.Chunked {
+		return json.Unmarshal(raw, doc)
+	}
+	data := make([]byte, 0, manifest.Size)
+	for i := 0; i < manifest.Chunks; i++ {
+		var part struct {
+			Data []byte `json:"data"`
+		}
+		if _, err := k.db.client.read(k.coll+"docs/"+chunkID(id, i), &part, PartitionKey(partitionKey), WithContext(ctx)); err != nil {
+			return err
+		}
+		data = append(data, part.Data...)
+	}
+	if len(data) != manifest.Size {
+		return fmt.Errorf("chunked document %s is %d bytes, the manifest says %d", id, len(data), manifest.Size)
+	}
+	return json.Unmarshal(data, doc)
+}
+
+// Delete - deletes the document with id and its chunks
+func (k *Chunker) Delete(ctx context.Context, id string, partitionKey interface{}) error {
+	manifest, err := k.manifest(ctx, id, partitionKey)
+	if err != nil {
+		return err
+	}
+	if err = k.deleteDoc(ctx, id, partitionKey); err != nil {
+		return err
+	}
+	for i := 0; manifest != nil && i < manifest.Chunks; i++ {
+		if err = k.deleteDoc(ctx, chunkID(id, i), partitionKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// manifest - reads the manifest of a chunked document, nil when the document is missing or not chunked
+func (k *Chunker) manifest(ctx context.Context, id string, partitionKey interface{}) (*ChunkManifest, error) {
+	var manifest ChunkManifest
+	_, err := k.db.client.read(k.coll+"docs/"+id, &manifest, PartitionKey(partitionKey), WithContext(ctx))
+	if errors.Is(err, ErrNotFound) || (err == nil && !manifest.Chunked) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// upsert - writes a manifest or chunk with the partition key set at the partition key path
+func (k *Chunker) upsert(ctx context.Context, doc map[string]interface{}, partitionKey interface{}) error {
+	parts := strings.Split(strings.TrimPrefix(k.partitionKeyPath, "/"), "/")
+	obj := doc
+	for _, part := range parts[:len(parts)-1] {
+		next := map[string]interface{}{}
+		obj[part] = next
+		obj = next
+	}
+	obj[parts[len(parts)-1]] = partitionKey
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	_, err = k.db.client.method(http.MethodPost, k.coll+"docs/", expectUpserted, nil, bytes.NewBuffer(data),
+		Upsert(), PartitionKey(partitionKey), WithContext(ctx))
+	return err
+}
+
+func (k *Chunker) deleteDoc(ctx context.Context, id string, partitionKey interface{}) error {
+	_, err := k.db.client.delete(k.coll+"docs/"+id, PartitionKey(partitionKey), WithIgnoreNotFound(), WithContext(ctx))
+	return err
+}
@@ -0,0 +1,88 @@
+package gocosmosdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// docStore - a mock server keeping documents by id
+func docStore() (*httptest.Server, map[string]json.RawMessage) {
+	var mu sync.Mutex
+	docs := map[string]json.RawMessage{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		id := parts[len(parts)-1]
+		switch r.Method {
+		case http.MethodGet, http.MethodDelete:
+			doc, ok := docs[id]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"code": "NotFound"}`)
+				return
+			}
+			if r.Method == http.MethodDelete {
+				delete(docs, id)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			w.Write(doc)
+		case http.MethodPost:
+			body, _ := ioutil.ReadAll(r.Body)
+			var doc struct {
+				Id string `json:"id"`
+			}
+			json.Unmarshal(body, &doc)
+			docs[doc.Id] = body
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		}
+	}))
+	return s, docs
+}
+
+func TestChunker(t *testing.T) {
+	assert := assert.New(t)
+	s, docs := docStore()
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+	chunker := client.NewChunker("dbs/db/colls/coll/", "/tenant/id")
+	chunker.Threshold = 100
+	chunker.ChunkSize = 40
+
+	type big struct {
+		Id      string `json:"id"`
+		Payload string `json:"payload"`
+	}
+	doc := big{Id: "1", Payload: strings.Repeat("x", 150)}
+	assert.Nil(chunker.Write(context.Background(), "1", "t1", &doc))
+	assert.Equal(6, len(docs))
+	assert.Contains(string(docs["1"]), `"_chunked":true`)
+	assert.Contains(string(docs["1:chunk:0"]), `"tenant":{"id":"t1"}`)
+
+	var read big
+	assert.Nil(chunker.Read(context.Background(), "1", "t1", &read))
+	assert.Equal(doc, read)
+
+	// a smaller version replaces the manifest and drops the old chunks
+	doc.Payload = "small"
+	assert.Nil(chunker.Write(context.Background(), "1", "t1", &doc))
+	assert.Equal(1, len(docs))
+	assert.Nil(chunker.Read(context.Background(), "1", "t1", &read))
+	assert.Equal("small", read.Payload)
+
+	doc.Payload = strings.Repeat("y", 150)
+	assert.Nil(chunker.Write(context.Background(), "1", "t1", &doc))
+	assert.Nil(chunker.Delete(context.Background(), "1", "t1"))
+	assert.Equal(0, len(docs))
+}
@@ -5,8 +5,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
 	"net/http"
 	"reflect"
+	"strconv"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
@@ -22,37 +26,102 @@ type apiClient struct {
 	config     Config
 	httpClient *retryablehttp.Client
 	logger     *logger.Logger
+	tokens     *userTokens
+	aad        *aadTokens
+	defaults   *collectionDefaults
+	routes     *routingPolicies
+	slots      chan struct{} // taken by the requests in flight with MaxConcurrentRequests
+	sessions   *sessionTokens
+	charge     *RequestCharge // every RU charged to the client, see CosmosDB.RequestCharge
 }
 
 func newAPIClient(conf *Config) *apiClient {
-	client := &apiClient{}
+	client := &apiClient{
+		defaults: &collectionDefaults{defaults: map[string]CollectionDefaults{}},
+		routes:   &routingPolicies{policies: map[string]RoutingPolicy{}},
+		charge:   &RequestCharge{},
+	}
+	if conf.MaxConcurrentRequests > 0 {
+		client.slots = make(chan struct{}, conf.MaxConcurrentRequests)
+	}
+	if conf.SessionTokens {
+		client.sessions = &sessionTokens{tokens: map[string]map[string]string{}}
+	}
+	client.httpClient = NewHTTPClient(*conf)
+	return client
+}
+
+// NewHTTPClient - returns a retrying http client set up from the retry and pooling settings of the config,
+// shared with the subpackages speaking other CosmosDB APIs
+func NewHTTPClient(conf Config) *retryablehttp.Client {
 	httpClient := retryablehttp.NewClient()
 	httpClient.Logger = nil
-	client.httpClient = httpClient
 	var zeroDuration time.Duration
 	if conf.RetryWaitMin == zeroDuration {
-		client.httpClient.RetryWaitMin = 10 * time.Millisecond
+		httpClient.RetryWaitMin = 10 * time.Millisecond
 	} else {
-		client.httpClient.RetryWaitMin = conf.RetryWaitMin
+		httpClient.RetryWaitMin = conf.RetryWaitMin
 	}
 	if conf.RetryWaitMax == zeroDuration {
-		client.httpClient.RetryWaitMax = 50 * time.Millisecond
+		httpClient.RetryWaitMax = 50 * time.Millisecond
 	} else {
-		client.httpClient.RetryWaitMax = conf.RetryWaitMax
+		httpClient.RetryWaitMax = conf.RetryWaitMax
 	}
-	client.httpClient.RetryMax = conf.RetryMax
+	httpClient.RetryMax = conf.RetryMax
 	if conf.Pooled {
-		client.httpClient.HTTPClient.Transport = cleanhttp.DefaultPooledTransport()
+		httpClient.HTTPClient.Transport = cleanhttp.DefaultPooledTransport()
+	}
+	if transport, ok := httpClient.HTTPClient.Transport.(*http.Transport); ok && conf.DNSCache != nil {
+		// the dialer settings of cleanhttp
+		transport.DialContext = conf.DNSCache.DialContext(&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second})
+	}
+	switch {
+	case conf.RetryPolicy != nil:
+		// the policy waits and reports its backoff itself
+		httpClient.CheckRetry = checkRetryPolicy(conf.RetryPolicy, conf.RetryMax, conf.OnBackoff)
+		httpClient.Backoff = noBackoff
+		httpClient.ErrorHandler = retryablehttp.PassthroughErrorHandler
+	case conf.RetryThrottled:
+		httpClient.CheckRetry = retryThrottled(httpClient.CheckRetry, conf.RetryThrottledMaxWait)
+		httpClient.Backoff = throttledBackoff(httpClient.Backoff, conf.RetryJitter)
+		// hand back the last response once the retries are exhausted so it fails like any other
+		httpClient.ErrorHandler = retryablehttp.PassthroughErrorHandler
+	}
+	if conf.OnBackoff != nil && conf.RetryPolicy == nil {
+		httpClient.Backoff = observeBackoff(httpClient.Backoff, conf.OnBackoff)
+	}
+	httpClient.CheckRetry = retryUnapplied(httpClient.CheckRetry)
+	if conf.Governor != nil {
+		httpClient.CheckRetry = conf.Governor.observe(httpClient.CheckRetry)
+	}
+	return httpClient
+}
+
+// discardBody - reads what the decoder left of a response body before closing it, so the connection goes back to the pool
+func discardBody(body io.ReadCloser) {
+	io.Copy(ioutil.Discard, body)
+	body.Close()
+}
+
+// retryableRequest - wraps a request for the http client, which sends a copy of it reading the body afresh from
+// GetBody for every attempt, so retries resend the whole body and the body of the request itself stays unread
+func retryableRequest(req *http.Request) (*retryablehttp.Request, error) {
+	if req.GetBody == nil {
+		return retryablehttp.FromRequest(req)
+	}
+	rr, err := retryablehttp.NewRequest(req.Method, req.URL.String(), retryablehttp.ReaderFunc(func() (io.Reader, error) {
+		return req.GetBody()
+	}))
+	if err != nil {
+		return nil, err
 	}
-	return client
+	rr.Request = req.Clone(req.Context())
+	return rr, nil
 }
 
 // apply - iterates over all opts and runs the functions to apply additional request headers
 func (c *apiClient) apply(r *Request, opts []CallOption) (err error) {
-	if err = r.DefaultHeaders(c.config.MasterKey); err != nil {
-		return err
-	}
-
+	opts = c.withDefaults(r.URL.Path, opts)
 	for i := 0; i < len(opts); i++ {
 		// check to make sure someone did not pass nil ass a call option
 		if opts[i] != nil {
@@ -61,6 +130,42 @@ func (c *apiClient) apply(r *Request, opts []CallOption) (err error) {
 			}
 		}
 	}
+	if err = c.emulate(r); err != nil {
+		return err
+	}
+	c.correlate(r)
+	// sign last so the auth headers can use the context passed in the options
+	return c.sign(r)
+}
+
+// sign - adds the default headers authorizing the request with the master key, an Azure AD token or a users
+// resource token
+func (c *apiClient) sign(r *Request) error {
+	if c.aad != nil {
+		token, err := c.aad.get(r.ctx())
+		if err != nil {
+			return err
+		}
+		r.AADTokenHeaders(token)
+		return nil
+	}
+	if c.tokens == nil {
+		return r.DefaultHeaders(c.config.MasterKey)
+	}
+	perm, err := c.tokens.get(r.ctx())
+	if err != nil {
+		return err
+	}
+	r.ResourceTokenHeaders(perm.Token)
+	// scope document operations to the partition of the users permission
+	if r.rType == "docs" && len(perm.ResourcePartitionKey) > 0 {
+		pk, err := json.Marshal(perm.ResourcePartitionKey)
+		if err != nil {
+			return err
+		}
+		r.Header[HeaderPartitionKey] = []string{string(pk)}
+		r.Header.Del(HeaderCrossPartition)
+	}
 	return nil
 }
 
@@ -86,41 +191,51 @@ func (c *apiClient) disableDebug() {
 
 // Read - reads a resource by self link
 func (c *apiClient) read(link string, ret interface{}, opts ...CallOption) (*Response, error) {
-	return c.method("GET", link, http.StatusOK, ret, &bytes.Buffer{}, opts...)
+	return c.method("GET", link, expectOK, ret, &bytes.Buffer{}, opts...)
+}
+
+// Changes - reads a page of the change feed of a resource
+func (c *apiClient) changes(link string, ret interface{}, opts ...CallOption) (*Response, error) {
+	opts = append([]CallOption{ChangeFeed()}, opts...)
+	return c.method("GET", link, expectChanges, ret, &bytes.Buffer{}, opts...)
 }
 
 // Delete - deletes a resource by self link
 func (c *apiClient) delete(link string, opts ...CallOption) (*Response, error) {
-	return c.method("DELETE", link, http.StatusNoContent, nil, &bytes.Buffer{}, opts...)
+	return c.method("DELETE", link, expectDeleted, nil, &bytes.Buffer{}, opts...)
 }
 
 // Query - queries a resource
 func (c *apiClient) query(link, query string, ret interface{}, opts ...CallOption) (*Response, error) {
-	query = escapeSQL(query)
-	buf := bytes.NewBufferString(querify(query))
+	q, err := querify(query)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBuffer(q)
 	req, err := http.NewRequest("POST", path(c.uri, link), buf)
 	if err != nil {
 		return nil, err
 	}
 	r := ResourceRequest(link, req)
-	if c.config.PartitionKeyStructField != "" {
-		opts = append(opts, CrossPartition())
+	// fan out by default, passed options can still pin the query to a partition with SinglePartition, and
+	// queries comparing the partition key with a value are pinned to it
+	if c.partitioned() {
+		opts = append(append([]CallOption{CrossPartition()}, opts...), c.pinnedQuery(link, &QueryWithParameters{Query: query}))
 	}
 	if err = c.apply(r, opts); err != nil {
 		return nil, err
 	}
 	r.QueryHeaders(buf.Len())
 	// revert version if collection is not partitioned
-	if c.config.PartitionKeyStructField == "" {
+	if !c.partitioned() {
 		r.Header.Set(HeaderVersion, SupportedAPIVersionNoPartition)
 	}
 	// try the request and return if successful
-	return c.do(r, http.StatusOK, ret)
+	return c.do(r, expectOK, ret)
 }
 
 // QueryWithParameters - queries a resource
 func (c *apiClient) queryWithParameters(link string, query *QueryWithParameters, ret interface{}, opts ...CallOption) (*Response, error) {
-	query.Query = escapeSQL(query.Query)
 	q, err := stringify(query)
 	if err != nil {
 		return nil, err
@@ -131,18 +246,25 @@ func (c *apiClient) queryWithParameters(link string, query *QueryWithParameters,
 		return nil, err
 	}
 	r := ResourceRequest(link, req)
-	if c.config.PartitionKeyStructField != "" {
-		opts = append(opts, CrossPartition())
+	// fan out by default, passed options can still pin the query to a partition with SinglePartition, and
+	// queries comparing the partition key with a value are pinned to it
+	if c.partitioned() {
+		opts = append(append([]CallOption{CrossPartition()}, opts...), c.pinnedQuery(link, query))
 	}
 	if err = c.apply(r, opts); err != nil {
 		return nil, err
 	}
 	r.QueryHeaders(buf.Len())
 	// revert version if collection is not partitioned
-	if c.config.PartitionKeyStructField == "" {
+	if !c.partitioned() {
 		r.Header.Set(HeaderVersion, SupportedAPIVersionNoPartition)
 	}
-	return c.do(r, http.StatusOK, ret)
+	return c.do(r, expectOK, ret)
+}
+
+// partitioned - whether queries fan out across the partitions of the collections
+func (c *apiClient) partitioned() bool {
+	return c.config.PartitionKeyStructField != "" || c.config.CrossPartitionQueries
 }
 
 // Create - creates a resource
@@ -152,7 +274,7 @@ func (c *apiClient) create(link string, body, ret interface{}, opts ...CallOptio
 		return nil, err
 	}
 	buf := bytes.NewBuffer(data)
-	return c.method("POST", link, http.StatusCreated, ret, buf, opts...)
+	return c.method("POST", link, expectCreated, ret, buf, opts...)
 }
 
 // Replace - replaces a resource
@@ -162,12 +284,22 @@ func (c *apiClient) replace(link string, body, ret interface{}, opts ...CallOpti
 		return nil, err
 	}
 	buf := bytes.NewBuffer(data)
-	if c.config.PartitionKeyStructField != "" {
-		partKey := reflect.ValueOf(body).Elem().FieldByName(c.config.PartitionKeyStructField)
-		partKeyI := partKey.Interface()
-		opts = append(opts, PartitionKey(partKeyI))
+	opts = c.structPartitionKey(body, opts)
+	return c.method("PUT", link, expectOK, ret, buf, opts...)
+}
+
+// structPartitionKey - adds the partition key held in the PartitionKeyStructField of a struct body to the options,
+// raw bodies and resources other than documents, eg. collections and offers, have no such field
+func (c *apiClient) structPartitionKey(body interface{}, opts []CallOption) []CallOption {
+	if c.config.PartitionKeyStructField == "" {
+		return opts
 	}
-	return c.method("PUT", link, http.StatusOK, ret, buf, opts...)
+	if v := reflect.Indirect(reflect.ValueOf(body)); v.Kind() == reflect.Struct {
+		if partKey := v.FieldByName(c.config.PartitionKeyStructField); partKey.IsValid() {
+			opts = append(opts, PartitionKey(partKey.Interface()))
+		}
+	}
+	return opts
 }
 
 // Upsert - upserts a resource
@@ -178,12 +310,9 @@ func (c *apiClient) upsert(link string, body, ret interface{}, opts ...CallOptio
 		return nil, err
 	}
 	buf := bytes.NewBuffer(data)
-	if c.config.PartitionKeyStructField != "" {
-		partKey := reflect.ValueOf(body).Elem().FieldByName(c.config.PartitionKeyStructField)
-		partKeyI := partKey.Interface()
-		opts = append(opts, PartitionKey(partKeyI))
-	}
-	return c.method(http.MethodPost, link, http.StatusOK, ret, buf, opts...)
+	opts = c.structPartitionKey(body, opts)
+	// the service answers 201 when the upsert created the resource
+	return c.method(http.MethodPost, link, expectUpserted, ret, buf, opts...)
 }
 
 // ReplaceAsync - replaces a resource
@@ -206,13 +335,9 @@ func (c *apiClient) replaceAsync(link string, body, ret interface{}, opts ...Cal
 	} else {
 		return nil, errors.New("_etag does not exist for async replace")
 	}
-	if c.config.PartitionKeyStructField != "" {
-		partKey := reflect.ValueOf(body).Elem().FieldByName(c.config.PartitionKeyStructField)
-		partKeyI := partKey.Interface()
-		opts = append(opts, PartitionKey(partKeyI))
-	}
+	opts = c.structPartitionKey(body, opts)
 	opts = append(opts, IfMatch(Etag))
-	return c.method("PUT", link, http.StatusOK, ret, buf, opts...)
+	return c.method("PUT", link, expectOK, ret, buf, opts...)
 }
 
 // Execute - executes a resource
@@ -222,11 +347,39 @@ func (c *apiClient) execute(link string, body, ret interface{}, opts ...CallOpti
 		return nil, err
 	}
 	buf := bytes.NewBuffer(data)
-	return c.method("POST", link, http.StatusOK, ret, buf, opts...)
+	return c.method("POST", link, expectOK, ret, buf, append(opts, execution)...)
 }
 
+// expectation - the success semantics of an operation, deciding from the request and the response status
+type expectation func(r *Request, statusCode int) bool
+
+// expect - succeeds when any of the validators accepts the status
+func expect(validators ...statusCodeValidatorFunc) expectation {
+	return func(r *Request, statusCode int) bool {
+		for _, ok := range validators {
+			if ok(statusCode) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+var (
+	expectOK      = expect(expectStatusCode(http.StatusOK))
+	expectCreated = expect(expectStatusCode(http.StatusCreated))
+	// an upsert creates or replaces
+	expectUpserted = expect(expectStatusCode(http.StatusOK), expectStatusCode(http.StatusCreated))
+	// a change feed read without new changes is not modified
+	expectChanges = expect(expectStatusCode(http.StatusOK), expectStatusCode(http.StatusNotModified))
+	// a delete of a missing resource succeeds when the caller asked to ignore it with WithIgnoreNotFound
+	expectDeleted expectation = func(r *Request, statusCode int) bool {
+		return statusCode == http.StatusNoContent || (statusCode == http.StatusNotFound && r.rIgnoreNotFound)
+	}
+)
+
 // method - generic method for a resource
-func (c *apiClient) method(method, link string, status int, ret interface{}, body *bytes.Buffer, opts ...CallOption) (*Response, error) {
+func (c *apiClient) method(method, link string, want expectation, ret interface{}, body *bytes.Buffer, opts ...CallOption) (*Response, error) {
 	req, err := http.NewRequest(method, path(c.uri, link), body)
 	if err != nil {
 		return nil, err
@@ -235,33 +388,87 @@ func (c *apiClient) method(method, link string, status int, ret interface{}, bod
 	if err = c.apply(r, opts); err != nil {
 		return nil, err
 	}
+	if err = c.readOld(r, link); err != nil {
+		return nil, err
+	}
+	if err = c.stamp(r); err != nil {
+		return nil, err
+	}
+	if err = c.hashContent(r); err != nil {
+		return nil, err
+	}
+	if err = c.validate(r); err != nil {
+		return nil, err
+	}
 	// revert version if collection is not partitioned
 	if c.config.PartitionKeyStructField == "" {
 		r.Header.Set(HeaderVersion, SupportedAPIVersionNoPartition)
 	}
-	return c.do(r, status, ret)
+	return c.do(r, want, ret)
 }
 
 // do - private do function
-func (c *apiClient) do(r *Request, status int, data interface{}) (*Response, error) {
+func (c *apiClient) do(r *Request, want expectation, data interface{}) (*Response, error) {
+	if err := c.guardWrite(r); err != nil {
+		return nil, err
+	}
+	if response, answered, err := c.dryRun(r, want); answered || err != nil {
+		return response, err
+	}
+	if err := c.route(r); err != nil {
+		return nil, err
+	}
+	if c.config.Budget != nil {
+		if err := c.config.Budget.admit(r); err != nil {
+			return nil, err
+		}
+	}
+	if c.config.Governor != nil {
+		release, err := c.config.Governor.admit(r.ctx(), r)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+	if c.slots != nil {
+		release, err := c.acquire(r.ctx())
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+	if c.sessions != nil {
+		c.replaySession(r)
+	}
 	if c.config.Debug && c.logger != nil {
 		r.QueryMetricsHeaders()
-		c.logger.Infof("CosmosDB Request: ID: %+v, Type: %+v, HTTP Request: %+v", r.rId, r.rType, r.Request)
+		c.logger.Infof("CosmosDB Request: ID: %+v, Type: %+v, Correlation: %s, HTTP Request: %+v", r.rId, r.rType, c.correlation(r), r.Request)
 		curl, _ := http2curl.GetCurlCommand(r.Request)
 		c.logger.Infof("CURL: %s", curl)
 	}
-	var rr *retryablehttp.Request
-	var err error
+	req := r.Request
 	if r.rContext != nil {
-		req := r.WithContext(r.rContext)
-		rr, err = retryablehttp.FromRequest(req)
-	} else {
-		rr, err = retryablehttp.FromRequest(r.Request)
+		req = r.WithContext(r.rContext)
+	}
+	if c.config.ConnectionStats != nil {
+		req = req.WithContext(c.config.ConnectionStats.trace(req.Context()))
+	}
+	if r.rExecution && !r.rIdempotent {
+		req = unappliedOnly(req)
 	}
+	var retries *retryState
+	if c.config.RetryThrottled || c.config.RetryPolicy != nil {
+		req, retries = withRetryState(req)
+	}
+	rr, err := retryableRequest(req)
 	if err != nil {
-		return nil, fmt.Errorf("error creating retryable request: %s", err)
+		return nil, fmt.Errorf("error creating retryable request: %w", err)
 	}
+	start := time.Now()
 	resp, err := c.httpClient.Do(rr)
+	if c.config.Instrumentation != nil {
+		c.instrument(r, resp, err, start, retries)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -270,18 +477,42 @@ func (c *apiClient) do(r *Request, status int, data interface{}) (*Response, err
 		c.logger.Infof("CosmosDB Response Headers: %s", spew.Sdump(resp.Header))
 		c.logger.Infof("CosmosDB Response Content-Length: %s", spew.Sdump(resp.ContentLength))
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != status {
+	defer discardBody(resp.Body)
+	chargeContext(r.ctx(), c.charge, resp.Header)
+	if c.sessions != nil {
+		c.captureSession(r, resp)
+	}
+	response := &Response{Header: resp.Header, StatusCode: resp.StatusCode, Endpoint: resp.Request.URL.Scheme + "://" + resp.Request.URL.Host}
+	if r.rResponse != nil {
+		*r.rResponse = *response
+	}
+	if c.config.PartitionStats != nil {
+		c.config.PartitionStats.record(r, response)
+	}
+	if c.config.CostStats != nil {
+		c.config.CostStats.record(r, response)
+	}
+	if c.config.Budget != nil {
+		c.config.Budget.record(response)
+	}
+	if !want(r, resp.StatusCode) {
 		err := &RequestError{}
-		readJson(resp.Body, &err)
+		readJson(resp.Body, err)
 		err.StatusCode = resp.StatusCode
 		err.RId = r.rId
 		err.RType = r.rType
 		err.Request = r.Request
+		err.Retries = retries.Retries()
+		err.ActivityID = resp.Header.Get(HeaderActivityID)
+		err.SubStatus, _ = strconv.Atoi(resp.Header.Get(HeaderSubStatus))
+		if resp.StatusCode == http.StatusTooManyRequests {
+			err.RetryAfter, _ = retryAfter(resp)
+		}
 		return nil, err
 	}
-	if data == nil {
-		return nil, nil
+	// not modified responses of conditional reads carry no body
+	if data == nil || resp.StatusCode == http.StatusNotModified {
+		return response, nil
 	}
 	if c.config.Debug && c.config.Verbose && c.logger != nil {
 		c.logger.Infof("CosmosDB Request: %s", spew.Sdump(resp.Request))
@@ -289,5 +520,14 @@ func (c *apiClient) do(r *Request, status int, data interface{}) (*Response, err
 		c.logger.Infof("CosmosDB Response Content-Length: %s", spew.Sdump(resp.ContentLength))
 		c.logger.Infof("CosmosDB Response Content: %s", spew.Sdump(data))
 	}
-	return &Response{resp.Header}, readJson(resp.Body, data)
+	body := io.Reader(resp.Body)
+	if r.rSchema != nil {
+		if body, err = c.upgrade(r, body); err != nil {
+			return nil, err
+		}
+	}
+	if r.rMissing != nil {
+		return response, readMissing(body, data, r.rMissing)
+	}
+	return response, readJson(body, data)
 }
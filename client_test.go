@@ -1,6 +1,8 @@
 package gocosmosdb
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -28,13 +30,18 @@ type RequestRecorder struct {
 type MockServer struct {
 	*httptest.Server
 	RequestRecorder
-	Status interface{}
+	Status     interface{}
+	RespHeader http.Header
 }
 
 func (m *MockServer) SetStatus(status int) {
 	m.Status = status
 }
 
+func (m *MockServer) SetHeader(key, value string) {
+	m.RespHeader.Set(key, value)
+}
+
 func (s *MockServer) Record(r *http.Request) {
 	s.Header = r.Header
 	b, err := ioutil.ReadAll(r.Body)
@@ -52,9 +59,12 @@ func (s *MockServer) AssertHeaders(t *testing.T, headers ...string) {
 }
 
 func ServerFactory(resp ...interface{}) *MockServer {
-	s := &MockServer{}
+	s := &MockServer{RespHeader: http.Header{}}
 	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Add("Content-Type", "application/json")
+		for k, v := range s.RespHeader {
+			w.Header()[k] = v
+		}
 		// Record the last request
 		s.Record(r)
 		if v, ok := resp[0].(int); ok {
@@ -318,3 +328,127 @@ func TestExecute(t *testing.T) {
 	_, err = client.execute("dbs", tDoc, &doc)
 	assert.Contains(err.Error(), "giving up after 1 attempts")
 }
+
+func TestDeleteIgnoreNotFound(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"code": "NotFound", "message": "Entity with the specified id does not exist in the system."}`, `{"code": "NotFound", "message": "Entity with the specified id does not exist in the system."}`)
+	s.SetStatus(http.StatusNotFound)
+	defer s.Close()
+	client := &apiClient{
+		uri: s.URL,
+		config: Config{
+			MasterKey: "YXJpZWwNCg==",
+		},
+		httpClient: httpClient,
+		logger:     log,
+	}
+
+	_, err := client.delete("dbs/b7NTAS==/colls/Ad352/docs/1/")
+	assert.True(errors.Is(err, ErrNotFound))
+
+	_, err = client.delete("dbs/b7NTAS==/colls/Ad352/docs/1/", WithIgnoreNotFound())
+	assert.Nil(err)
+}
+
+func TestCreateConflict(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"code": "Conflict", "message": "Entity with the specified id already exists in the system."}`)
+	s.SetStatus(http.StatusConflict)
+	defer s.Close()
+	client := &apiClient{
+		uri: s.URL,
+		config: Config{
+			MasterKey: "YXJpZWwNCg==",
+		},
+		httpClient: httpClient,
+		logger:     log,
+	}
+
+	var doc Document
+	_, err := client.create("dbs/b7NTAS==/colls/Ad352/docs", `{"id": "1"}`, &doc)
+	assert.True(errors.Is(err, ErrConflict))
+	assert.False(errors.Is(err, ErrNotFound))
+	assert.IsType(&RequestError{}, err)
+	assert.Equal(http.StatusConflict, err.(*RequestError).StatusCode)
+}
+
+func TestRequestErrorDetails(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"code": "TooManyRequests", "message": "Request rate is large"}`)
+	s.SetStatus(http.StatusTooManyRequests)
+	s.SetHeader(HeaderActivityID, "a1b2")
+	s.SetHeader(HeaderRetryAfterMs, "250")
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	var doc Document
+	_, err := client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+	assert.True(IsThrottled(err))
+	assert.False(IsNotFound(err) || IsConflict(err) || IsPreconditionFailed(err))
+	var reqErr *RequestError
+	assert.True(errors.As(fmt.Errorf("reading the order: %w", err), &reqErr))
+	assert.Equal("TooManyRequests", reqErr.Code)
+	assert.Equal("a1b2", reqErr.ActivityID)
+	assert.Equal(250*time.Millisecond, reqErr.RetryAfter)
+}
+
+func TestRequestErrorSubStatus(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"code": "Gone", "message": "The requested resource is no longer available"}`,
+		`{"code": "NotFound", "message": "Read session not available"}`)
+	s.SetStatus(http.StatusGone)
+	s.SetHeader(HeaderSubStatus, "1002")
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	var doc Document
+	_, err := client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+	assert.True(IsPartitionGone(err))
+	assert.Equal(SubStatusPartitionKeyRangeGone, err.(*RequestError).SubStatus)
+
+	// the same substatus of another status code is not a partition split
+	s.SetStatus(http.StatusNotFound)
+	_, err = client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+	assert.False(IsPartitionGone(err))
+	assert.True(IsNotFound(err))
+	assert.Equal(SubStatusReadSessionNotAvailable, err.(*RequestError).SubStatus)
+	assert.False(IsPartitionGone(errors.New("connection reset")))
+}
+
+func TestRetriesResendBody(t *testing.T) {
+	assert := assert.New(t)
+	var bodies []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{
+		MasterKey:    "YXJpZWwNCg==",
+		RetryMax:     1,
+		RetryWaitMin: time.Millisecond,
+		RetryWaitMax: time.Millisecond,
+		Audit:        func(ctx context.Context) map[string]interface{} { return map[string]interface{}{"by": "test"} },
+	}, log)
+
+	_, err := client.CreateDocument("dbs/db/colls/coll/", &Document{Resource: Resource{Id: "1"}})
+	assert.Nil(err)
+	assert.Len(bodies, 2)
+	assert.Contains(bodies[0], `"by":"test"`)
+	assert.Equal(bodies[0], bodies[1])
+
+	r := ResourceRequest("dbs/db/colls/coll/docs", httptest.NewRequest(http.MethodPost, s.URL, nil))
+	r.setBody([]byte(`{"id": "2"}`))
+	rr, err := retryableRequest(r.Request)
+	assert.Nil(err)
+	assert.Equal(int64(11), rr.ContentLength)
+	// the body of the request itself is left for the error of a failed request
+	body, _ := ioutil.ReadAll(r.Body)
+	assert.Equal(`{"id": "2"}`, string(body))
+}
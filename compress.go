@@ -0,0 +1,194 @@
+package gocosmosdb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// compressedPrefix - starts a compressed field value, followed by the codec name and a colon
+const compressedPrefix = "gocosmosdb-"
+
+// Codec - compresses the fields tagged `cosmosdb:"compress"`
+type Codec interface {
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[string]Codec{"gzip": gzipCodec{}, "zstd": zstdCodec{}}
+)
+
+// RegisterCodec - makes a codec available by name, gzip and zstd are built in
+//
+//	gocosmosdb.RegisterCodec("brotli", brotliCodec{})
+func RegisterCodec(name string, codec Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[name] = codec
+}
+
+func lookupCodec(name string) (Codec, error) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	codec, ok := codecs[name]
+	if !ok {
+		return nil, fmt.Errorf("no codec registered as %q", name)
+	}
+	return codec, nil
+}
+
+type gzipCodec struct{}
+
+func (gzipCodec) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gzipCodec) Decompress(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+// zstdCodec - zstd sharing one encoder and decoder, safe for concurrent use and created on first use
+type zstdCodec struct{}
+
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	zstdErr     error
+)
+
+func zstdCoders() (*zstd.Encoder, *zstd.Decoder, error) {
+	zstdOnce.Do(func() {
+		if zstdEncoder, zstdErr = zstd.NewWriter(nil); zstdErr != nil {
+			return
+		}
+		zstdDecoder, zstdErr = zstd.NewReader(nil)
+	})
+	return zstdEncoder, zstdDecoder, zstdErr
+}
+
+func (zstdCodec) Compress(data []byte) ([]byte, error) {
+	encoder, _, err := zstdCoders()
+	if err != nil {
+		return nil, err
+	}
+	return encoder.EncodeAll(data, nil), nil
+}
+
+func (zstdCodec) Decompress(data []byte) ([]byte, error) {
+	_, decoder, err := zstdCoders()
+	if err != nil {
+		return nil, err
+	}
+	return decoder.DecodeAll(data, nil)
+}
+
+// CompressedFields - compresses the string and []byte fields tagged `cosmosdb:"compress"` over Threshold bytes,
+// each value records the codec it was compressed with, strings are stored base64 encoded
+//
+//	type Log struct {
+//		gocosmosdb.Document
+//		Trace string `json:"trace" cosmosdb:"compress"`
+//	}
+//
+//	fields := &gocosmosdb.CompressedFields{Codec: "gzip", Threshold: 1024}
+//	stored, err := fields.Compress(ctx, &log)
+//	_, err = client.CreateDocument(coll, stored)
+//	...
+//	err = fields.Decompress(ctx, &log)
+type CompressedFields struct {
+	Codec     string
+	Threshold int
+}
+
+// Compress - returns a copy of the struct doc points to with the large tagged fields compressed
+func (c *CompressedFields) Compress(ctx context.Context, doc interface{}) (interface{}, error) {
+	v := reflect.ValueOf(doc)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("CompressedFields doc must be a pointer to a struct")
+	}
+	codec, err := lookupCodec(c.Codec)
+	if err != nil {
+		return nil, err
+	}
+	cp := reflect.New(v.Elem().Type())
+	cp.Elem().Set(v.Elem())
+	err = eachTaggedField(cp.Elem(), "compress", func(name string, field reflect.Value) error {
+		data := fieldBytes(field)
+		if len(data) <= c.Threshold || strings.HasPrefix(string(data), compressedPrefix) {
+			return nil
+		}
+		compressed, err := codec.Compress(data)
+		if err != nil {
+			return err
+		}
+		prefix := compressedPrefix + c.Codec + ":"
+		if field.Kind() == reflect.String {
+			field.SetString(prefix + base64.StdEncoding.EncodeToString(compressed))
+		} else {
+			field.SetBytes(append([]byte(prefix), compressed...))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cp.Interface(), nil
+}
+
+// Decompress - restores the compressed tagged fields of the struct doc points to with the codec each records
+func (c *CompressedFields) Decompress(ctx context.Context, doc interface{}) error {
+	v := reflect.ValueOf(doc)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return errors.New("CompressedFields doc must be a pointer to a struct")
+	}
+	return eachTaggedField(v.Elem(), "compress", func(name string, field reflect.Value) error {
+		data := fieldBytes(field)
+		if !bytes.HasPrefix(data, []byte(compressedPrefix)) {
+			return nil
+		}
+		i := bytes.IndexByte(data, ':')
+		if i < 0 {
+			return fmt.Errorf("field %s has no codec", name)
+		}
+		codec, err := lookupCodec(string(data[len(compressedPrefix):i]))
+		if err != nil {
+			return err
+		}
+		payload := data[i+1:]
+		if field.Kind() == reflect.String {
+			if payload, err = base64.StdEncoding.DecodeString(string(payload)); err != nil {
+				return err
+			}
+		}
+		if payload, err = codec.Decompress(payload); err != nil {
+			return err
+		}
+		setFieldBytes(field, payload)
+		return nil
+	})
+}
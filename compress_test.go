@@ -0,0 +1,69 @@
+package gocosmosdb
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type reverseCodec struct{}
+
+func (reverseCodec) Compress(data []byte) ([]byte, error) {
+	out := make([]byte, len(data))
+	for i, b := range data {
+		out[len(data)-1-i] = b
+	}
+	return out, nil
+}
+
+func (r reverseCodec) Decompress(data []byte) ([]byte, error) {
+	return r.Compress(data)
+}
+
+func TestCompressedFields(t *testing.T) {
+	assert := assert.New(t)
+	type trace struct {
+		Document
+		Trace string `json:"trace" cosmosdb:"compress"`
+		Raw   []byte `json:"raw" cosmosdb:"compress"`
+		Note  string `json:"note" cosmosdb:"compress"`
+	}
+	doc := &trace{Trace: strings.Repeat("stack frame\n", 100), Raw: []byte(strings.Repeat("r", 100)), Note: "short"}
+	fields := &CompressedFields{Codec: "gzip", Threshold: 64}
+
+	stored, err := fields.Compress(context.Background(), doc)
+	assert.Nil(err)
+	out := stored.(*trace)
+	assert.True(strings.HasPrefix(out.Trace, "gocosmosdb-gzip:"))
+	assert.True(len(out.Trace) < len(doc.Trace))
+	assert.Equal("short", out.Note)
+
+	// round trip through JSON as stored
+	data, err := json.Marshal(out)
+	assert.Nil(err)
+	read := &trace{}
+	assert.Nil(json.Unmarshal(data, read))
+	assert.Nil(fields.Decompress(context.Background(), read))
+	assert.Equal(doc, read)
+
+	zstd := &CompressedFields{Codec: "zstd", Threshold: 64}
+	stored, err = zstd.Compress(context.Background(), doc)
+	assert.Nil(err)
+	assert.True(strings.HasPrefix(stored.(*trace).Trace, "gocosmosdb-zstd:"))
+	assert.True(len(stored.(*trace).Trace) < len(doc.Trace))
+	assert.Nil(fields.Decompress(context.Background(), stored))
+	assert.Equal(doc, stored)
+
+	// each value records its codec, so fields compressed with another codec are still read
+	RegisterCodec("reverse", reverseCodec{})
+	stored, err = (&CompressedFields{Codec: "reverse"}).Compress(context.Background(), doc)
+	assert.Nil(err)
+	assert.Nil(fields.Decompress(context.Background(), stored))
+	assert.Equal(doc, stored)
+
+	_, err = (&CompressedFields{Codec: "lz4"}).Compress(context.Background(), doc)
+	assert.Contains(err.Error(), `no codec registered as "lz4"`)
+}
@@ -0,0 +1,137 @@
+package gocosmosdb
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// ConnectionStats - records how the requests of a client get their connections to the gateway, set it on the
+// Config to tell a cold or churning connection pool, slow DNS or slow TLS handshakes apart
+//
+//	conns := gocosmosdb.NewConnectionStats()
+//	client := gocosmosdb.New(url, gocosmosdb.Config{MasterKey: key, Pooled: true, ConnectionStats: conns}, log)
+//	...
+//	report := conns.Report()
+//	log.Infof("%.0f%% of connections reused, %d idle, TLS handshakes %v on average", report.ReuseRate*100, report.Idle, report.TLS.Mean())
+type ConnectionStats struct {
+	mu     sync.Mutex
+	report ConnectionReport
+}
+
+// ConnectionReport - the connections obtained by the requests of a client, retries included
+type ConnectionReport struct {
+	Connections int     // obtained for a request
+	Reused      int     // of them kept open from an earlier request
+	ReuseRate   float64 // Reused of Connections
+	Idle        int     // put back into the pool and not taken since, connections the pool closed later still count
+	DNS         Latency // of the lookups of the gateway host
+	Connect     Latency // of the dials, successful or not
+	TLS         Latency // of the handshakes
+	Failed      int     // dials and handshakes that failed
+}
+
+// Latency - the durations of a step of getting connections
+type Latency struct {
+	Count int
+	Total time.Duration
+	Max   time.Duration
+}
+
+// Mean - returns the average duration, 0 when there were none
+func (l Latency) Mean() time.Duration {
+	if l.Count == 0 {
+		return 0
+	}
+	return l.Total / time.Duration(l.Count)
+}
+
+func (l *Latency) add(d time.Duration) {
+	l.Count++
+	l.Total += d
+	if d > l.Max {
+		l.Max = d
+	}
+}
+
+// NewConnectionStats - creates empty stats
+func NewConnectionStats() *ConnectionStats {
+	return &ConnectionStats{}
+}
+
+// Report - returns the stats so far
+func (s *ConnectionStats) Report() ConnectionReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	report := s.report
+	if report.Connections > 0 {
+		report.ReuseRate = float64(report.Reused) / float64(report.Connections)
+	}
+	return report
+}
+
+// trace - returns a copy of the context tracing the connections of a request into the stats
+func (s *ConnectionStats) trace(ctx context.Context) context.Context {
+	var dnsStart, tlsStart time.Time
+	connectStart := map[string]time.Time{}
+	// the timestamps are shared by the attempts of the request, which never overlap
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.report.DNS.add(time.Since(dnsStart))
+		},
+		// dials of several addresses of a host may race each other
+		ConnectStart: func(network, addr string) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			connectStart[network+addr] = time.Now()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.report.Connect.add(time.Since(connectStart[network+addr]))
+			if err != nil {
+				s.report.Failed++
+			}
+		},
+		TLSHandshakeStart: func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.report.TLS.add(time.Since(tlsStart))
+			if err != nil {
+				s.report.Failed++
+			}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.report.Connections++
+			if info.Reused {
+				s.report.Reused++
+			}
+			if info.WasIdle && s.report.Idle > 0 {
+				s.report.Idle--
+			}
+		},
+		PutIdleConn: func(err error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if err == nil {
+				s.report.Idle++
+			}
+		},
+	})
+}
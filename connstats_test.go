@@ -0,0 +1,71 @@
+package gocosmosdb
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectionStats(t *testing.T) {
+	assert := assert.New(t)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": "1"}`))
+	}))
+	defer s.Close()
+	conns := NewConnectionStats()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", Pooled: true, ConnectionStats: conns}, log)
+
+	for i := 0; i < 3; i++ {
+		var doc Document
+		_, err := client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+		assert.Nil(err)
+	}
+	report := conns.Report()
+	assert.Equal(3, report.Connections)
+	// every connection not reused was dialed
+	assert.Equal(3-report.Reused, report.Connect.Count)
+	assert.Equal(0, report.TLS.Count)
+	assert.Equal(0, report.Failed)
+}
+
+func TestConnectionStatsReuse(t *testing.T) {
+	assert := assert.New(t)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": "1"}`))
+	}))
+	defer s.Close()
+	conns := NewConnectionStats()
+	client := &http.Client{Transport: &http.Transport{}}
+
+	for i := 0; i < 3; i++ {
+		req, err := http.NewRequest(http.MethodGet, s.URL, nil)
+		assert.Nil(err)
+		resp, err := client.Do(req.WithContext(conns.trace(context.Background())))
+		assert.Nil(err)
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}
+	report := conns.Report()
+	assert.Equal(3, report.Connections)
+	assert.Equal(2, report.Reused)
+	assert.InDelta(2.0/3, report.ReuseRate, 0.001)
+	assert.Equal(1, report.Idle)
+	assert.Equal(1, report.Connect.Count)
+	assert.Equal(report.Connect.Total, report.Connect.Mean())
+}
+
+func TestLatencyMean(t *testing.T) {
+	assert := assert.New(t)
+	var l Latency
+	assert.Equal(time.Duration(0), l.Mean())
+	l.add(10 * time.Millisecond)
+	l.add(30 * time.Millisecond)
+	assert.Equal(20*time.Millisecond, l.Mean())
+	assert.Equal(30*time.Millisecond, l.Max)
+}
@@ -0,0 +1,188 @@
+package gocosmosdb
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ContainerSpec - the declared definition of a collection, applied with ApplyContainerSpec
+type ContainerSpec struct {
+	Id                     string
+	PartitionKeyPath       string          // eg. /tenantId, cannot change once the collection exists
+	IndexingPolicy         *IndexingPolicy // nil leaves the indexing policy as it is
+	DefaultTTL             int             // 0 disables expiry, -1 enables it without a default
+	UniqueKeys             [][]string      // cannot change once the collection exists
+	Throughput             int             // dedicated RUs, 0 with AutoscaleMaxThroughput 0 shares the database throughput
+	AutoscaleMaxThroughput int             // dedicated autoscale RUs, cannot be combined with Throughput
+}
+
+// Container spec changes, as returned by ApplyContainerSpec
+const (
+	ContainerCreated        = "created"
+	ContainerIndexingPolicy = "indexingPolicy"
+	ContainerDefaultTTL     = "defaultTtl"
+	ContainerThroughput     = "throughput"
+)
+
+// ApplyContainerSpec - creates the collection of the spec in the database, or diffs the spec against the existing
+// collection and applies only what changed. It returns the changes applied, none when the collection already
+// matches. Changes the service does not allow on an existing collection, eg. of the partition key, unique keys or
+// between manual and autoscale throughput, fail without anything being applied.
+//
+//	changes, err := client.ApplyContainerSpec("dbs/{db-id}/", &gocosmosdb.ContainerSpec{
+//		Id:               "users",
+//		PartitionKeyPath: "/tenantId",
+//		DefaultTTL:       86400,
+//		UniqueKeys:       [][]string{{"/email"}},
+//		Throughput:       400,
+//	})
+func (c *CosmosDB) ApplyContainerSpec(db string, spec *ContainerSpec, opts ...CallOption) ([]string, error) {
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+	coll, err := c.ReadCollection(db+"colls/"+spec.Id+"/", opts...)
+	if errors.Is(err, ErrNotFound) {
+		return c.createContainer(db, spec, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// check everything first so a spec that cannot be applied changes nothing
+	if paths := coll.PartitionKeyDef.Paths; len(paths) != 1 || paths[0] != spec.PartitionKeyPath {
+		return nil, fmt.Errorf("container %s is partitioned by %v, the partition key cannot change to %s", spec.Id, paths, spec.PartitionKeyPath)
+	}
+	var live [][]string
+	if coll.UniqueKeyPolicy != nil {
+		for _, key := range coll.UniqueKeyPolicy.UniqueKeys {
+			live = append(live, key.Paths)
+		}
+	}
+	if !sameUniqueKeys(live, spec.UniqueKeys) {
+		return nil, fmt.Errorf("container %s has unique keys %v, unique keys cannot change to %v", spec.Id, live, spec.UniqueKeys)
+	}
+	offer, err := c.containerOffer(coll, spec, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []string
+	if spec.IndexingPolicy != nil && !reflect.DeepEqual(*spec.IndexingPolicy, coll.IndexingPolicy) {
+		coll.IndexingPolicy = *spec.IndexingPolicy
+		changes = append(changes, ContainerIndexingPolicy)
+	}
+	if coll.DefaultTTL != spec.DefaultTTL {
+		coll.DefaultTTL = spec.DefaultTTL
+		changes = append(changes, ContainerDefaultTTL)
+	}
+	if len(changes) > 0 {
+		if _, err = c.ReplaceCollection(coll.Self, coll, opts...); err != nil {
+			return nil, err
+		}
+	}
+	if offer != nil {
+		if _, err = c.ReplaceOffer("offers/"+offer.Rid+"/", offer, opts...); err != nil {
+			return changes, err
+		}
+		changes = append(changes, ContainerThroughput)
+	}
+	return changes, nil
+}
+
+// validate - checks the spec has what any collection needs
+func (spec *ContainerSpec) validate() error {
+	if spec.Id == "" || spec.PartitionKeyPath == "" {
+		return errors.New("container spec needs an Id and a PartitionKeyPath")
+	}
+	if spec.Throughput > 0 && spec.AutoscaleMaxThroughput > 0 {
+		return errors.New("container spec cannot combine Throughput with AutoscaleMaxThroughput")
+	}
+	return nil
+}
+
+// createContainer - creates the collection of a spec, reporting it created
+func (c *CosmosDB) createContainer(db string, spec *ContainerSpec, opts []CallOption) ([]string, error) {
+	if _, err := c.createSpecCollection(db, spec, opts); err != nil {
+		return nil, err
+	}
+	return []string{ContainerCreated}, nil
+}
+
+// createSpecCollection - creates the collection of a spec with its throughput
+func (c *CosmosDB) createSpecCollection(db string, spec *ContainerSpec, opts []CallOption) (*Collection, error) {
+	body := map[string]interface{}{
+		"id":           spec.Id,
+		"partitionKey": PartitionKeyDef{Kind: "Hash", Paths: []string{spec.PartitionKeyPath}},
+	}
+	if spec.IndexingPolicy != nil {
+		body["indexingPolicy"] = spec.IndexingPolicy
+	}
+	if spec.DefaultTTL != 0 {
+		body["defaultTtl"] = spec.DefaultTTL
+	}
+	if len(spec.UniqueKeys) > 0 {
+		policy := UniqueKeyPolicy{}
+		for _, paths := range spec.UniqueKeys {
+			policy.UniqueKeys = append(policy.UniqueKeys, UniqueKey{Paths: paths})
+		}
+		body["uniqueKeyPolicy"] = policy
+	}
+	switch {
+	case spec.Throughput > 0:
+		opts = append(opts, ThroughputRUs(spec.Throughput))
+	case spec.AutoscaleMaxThroughput > 0:
+		opts = append(opts, AutoscaleThroughput(spec.AutoscaleMaxThroughput))
+	}
+	return c.CreateCollection(db, body, opts...)
+}
+
+// containerOffer - returns the offer of the collection updated to the throughput of the spec, nil when the
+// throughput already matches or the spec leaves it to the database
+func (c *CosmosDB) containerOffer(coll *Collection, spec *ContainerSpec, opts []CallOption) (*Offer, error) {
+	if spec.Throughput == 0 && spec.AutoscaleMaxThroughput == 0 {
+		return nil, nil
+	}
+	offers, err := c.QueryOffers(fmt.Sprintf("SELECT * FROM ROOT r WHERE r.offerResourceId = '%s'", coll.Rid), opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(offers) == 0 {
+		return nil, fmt.Errorf("container %s shares the database throughput, dedicated throughput can only be set on creation", spec.Id)
+	}
+	offer := offers[0]
+	autoscale := offer.Content.OfferAutopilotSettings
+	switch {
+	case spec.Throughput > 0 && autoscale != nil:
+		return nil, fmt.Errorf("container %s uses autoscale throughput, it cannot change to manual throughput", spec.Id)
+	case spec.AutoscaleMaxThroughput > 0 && autoscale == nil:
+		return nil, fmt.Errorf("container %s uses manual throughput, it cannot change to autoscale throughput", spec.Id)
+	case spec.Throughput > 0 && offer.Content.OfferThroughput == spec.Throughput,
+		spec.AutoscaleMaxThroughput > 0 && autoscale.MaxThroughput == spec.AutoscaleMaxThroughput:
+		return nil, nil
+	case spec.Throughput > 0:
+		offer.Content.OfferThroughput = spec.Throughput
+	default:
+		autoscale.MaxThroughput = spec.AutoscaleMaxThroughput
+		// the service derives the current throughput from the autoscale maximum
+		offer.Content.OfferThroughput = 0
+	}
+	return &offer, nil
+}
+
+// sameUniqueKeys - compares unique keys ignoring their order and the order of their paths
+func sameUniqueKeys(a, b [][]string) bool {
+	normalize := func(keys [][]string) []string {
+		ret := make([]string, 0, len(keys))
+		for _, paths := range keys {
+			sorted := append([]string(nil), paths...)
+			sort.Strings(sorted)
+			ret = append(ret, strings.Join(sorted, ","))
+		}
+		sort.Strings(ret)
+		return ret
+	}
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
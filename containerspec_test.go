@@ -0,0 +1,68 @@
+package gocosmosdb
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testContainer = `{"id": "users", "_self": "dbs/db==/colls/users==/", "_rid": "users==", "partitionKey": {"kind": "Hash", "paths": ["/tenantId"]}, "uniqueKeyPolicy": {"uniqueKeys": [{"paths": ["/email"]}]}}`
+
+func TestApplyContainerSpecCreate(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(http.StatusNotFound, testContainer)
+	s.SetStatus(http.StatusCreated)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	changes, err := client.ApplyContainerSpec("dbs/db/", &ContainerSpec{
+		Id:               "users",
+		PartitionKeyPath: "/tenantId",
+		DefaultTTL:       3600,
+		UniqueKeys:       [][]string{{"/email"}},
+		Throughput:       400,
+	})
+	assert.Nil(err)
+	assert.Equal([]string{ContainerCreated}, changes)
+	assert.Equal("400", s.Header.Get(HeaderOfferThroughput))
+	assert.Contains(s.Body, `"defaultTtl":3600`)
+	assert.Contains(s.Body, `"uniqueKeyPolicy":{"uniqueKeys":[{"paths":["/email"]}]}`)
+}
+
+func TestApplyContainerSpecUpdate(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(testContainer, `{"Offers": [{"id": "Bp5Y", "_rid": "Bp5Y", "offerResourceId": "users==", "content": {"offerThroughput": 400}}], "_count": 1}`, testContainer, `{}`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	changes, err := client.ApplyContainerSpec("dbs/db/", &ContainerSpec{
+		Id:               "users",
+		PartitionKeyPath: "/tenantId",
+		DefaultTTL:       3600,
+		UniqueKeys:       [][]string{{"/email"}},
+		Throughput:       1000,
+	})
+	assert.Nil(err)
+	assert.Equal([]string{ContainerDefaultTTL, ContainerThroughput}, changes)
+	assert.Contains(s.Body, `"offerThroughput":1000`)
+}
+
+func TestApplyContainerSpecUnchanged(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(testContainer, testContainer)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	spec := &ContainerSpec{Id: "users", PartitionKeyPath: "/tenantId", UniqueKeys: [][]string{{"/email"}}}
+	changes, err := client.ApplyContainerSpec("dbs/db/", spec)
+	assert.Nil(err)
+	assert.Empty(changes)
+
+	spec.UniqueKeys = nil
+	_, err = client.ApplyContainerSpec("dbs/db/", spec)
+	assert.Contains(err.Error(), "unique keys cannot change")
+
+	_, err = client.ApplyContainerSpec("dbs/db/", &ContainerSpec{Id: "users", PartitionKeyPath: "/tenantId", Throughput: 400, AutoscaleMaxThroughput: 4000})
+	assert.NotNil(err)
+}
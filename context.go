@@ -0,0 +1,323 @@
+package gocosmosdb
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// The Ctx variants of the client operations take the context first, cancelling the requests of the operation
+// with it and bounding them by its deadline, as if passed WithContext(ctx) last
+//
+//	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+//	defer cancel()
+//	_, err := client.QueryDocumentsCtx(ctx, coll, "SELECT * FROM root r", &docs)
+
+// ReadAccountCtx - ReadAccount with a context
+func (c *CosmosDB) ReadAccountCtx(ctx context.Context, opts ...CallOption) (account *Account, err error) {
+	return c.ReadAccount(append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReadDatabaseCtx - ReadDatabase with a context
+func (c *CosmosDB) ReadDatabaseCtx(ctx context.Context, link string, opts ...CallOption) (db *Database, err error) {
+	return c.ReadDatabase(link, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReadCollectionCtx - ReadCollection with a context
+func (c *CosmosDB) ReadCollectionCtx(ctx context.Context, link string, opts ...CallOption) (coll *Collection, err error) {
+	return c.ReadCollection(link, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReadDocumentCtx - ReadDocument with a context
+func (c *CosmosDB) ReadDocumentCtx(ctx context.Context, link string, doc interface{}, opts ...CallOption) (resp *Response, err error) {
+	return c.ReadDocument(link, doc, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReadStoredProcedureCtx - ReadStoredProcedure with a context
+func (c *CosmosDB) ReadStoredProcedureCtx(ctx context.Context, link string, opts ...CallOption) (sproc *Sproc, err error) {
+	return c.ReadStoredProcedure(link, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReadUserDefinedFunctionCtx - ReadUserDefinedFunction with a context
+func (c *CosmosDB) ReadUserDefinedFunctionCtx(ctx context.Context, link string, opts ...CallOption) (udf *UDF, err error) {
+	return c.ReadUserDefinedFunction(link, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReadTriggerCtx - ReadTrigger with a context
+func (c *CosmosDB) ReadTriggerCtx(ctx context.Context, link string, opts ...CallOption) (trigger *Trigger, err error) {
+	return c.ReadTrigger(link, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReadUserCtx - ReadUser with a context
+func (c *CosmosDB) ReadUserCtx(ctx context.Context, link string, opts ...CallOption) (user *User, err error) {
+	return c.ReadUser(link, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReadPermissionCtx - ReadPermission with a context
+func (c *CosmosDB) ReadPermissionCtx(ctx context.Context, link string, opts ...CallOption) (perm *Permission, err error) {
+	return c.ReadPermission(link, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReadPartitionKeyRangeCtx - ReadPartitionKeyRange with a context
+func (c *CosmosDB) ReadPartitionKeyRangeCtx(ctx context.Context, link string, opts ...CallOption) (pkr *PartitionKeyRange, err error) {
+	return c.ReadPartitionKeyRange(link, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReadDatabasesCtx - ReadDatabases with a context
+func (c *CosmosDB) ReadDatabasesCtx(ctx context.Context, opts ...CallOption) (dbs []Database, err error) {
+	return c.ReadDatabases(append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReadCollectionsCtx - ReadCollections with a context
+func (c *CosmosDB) ReadCollectionsCtx(ctx context.Context, db string, opts ...CallOption) (colls []Collection, err error) {
+	return c.ReadCollections(db, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReadStoredProceduresCtx - ReadStoredProcedures with a context
+func (c *CosmosDB) ReadStoredProceduresCtx(ctx context.Context, coll string, opts ...CallOption) (sprocs []Sproc, err error) {
+	return c.ReadStoredProcedures(coll, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReadUserDefinedFunctionsCtx - ReadUserDefinedFunctions with a context
+func (c *CosmosDB) ReadUserDefinedFunctionsCtx(ctx context.Context, coll string, opts ...CallOption) (udfs []UDF, err error) {
+	return c.ReadUserDefinedFunctions(coll, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReadTriggersCtx - ReadTriggers with a context
+func (c *CosmosDB) ReadTriggersCtx(ctx context.Context, coll string, opts ...CallOption) (triggers []Trigger, err error) {
+	return c.ReadTriggers(coll, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReadPartitionKeyRangesCtx - ReadPartitionKeyRanges with a context
+func (c *CosmosDB) ReadPartitionKeyRangesCtx(ctx context.Context, coll string, opts ...CallOption) (ranges []PartitionKeyRange, err error) {
+	return c.ReadPartitionKeyRanges(coll, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReadDocumentsCtx - ReadDocuments with a context
+func (c *CosmosDB) ReadDocumentsCtx(ctx context.Context, coll string, docs interface{}, opts ...CallOption) (*Response, error) {
+	return c.ReadDocuments(coll, docs, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReadOffersCtx - ReadOffers with a context
+func (c *CosmosDB) ReadOffersCtx(ctx context.Context, opts ...CallOption) (offers []Offer, err error) {
+	return c.ReadOffers(append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// QueryDatabasesCtx - QueryDatabases with a context
+func (c *CosmosDB) QueryDatabasesCtx(ctx context.Context, query string, opts ...CallOption) (dbs []Database, err error) {
+	return c.QueryDatabases(query, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// QueryCollectionsCtx - QueryCollections with a context
+func (c *CosmosDB) QueryCollectionsCtx(ctx context.Context, db, query string, opts ...CallOption) (colls []Collection, err error) {
+	return c.QueryCollections(db, query, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// QueryStoredProceduresCtx - QueryStoredProcedures with a context
+func (c *CosmosDB) QueryStoredProceduresCtx(ctx context.Context, coll, query string, opts ...CallOption) (sprocs []Sproc, err error) {
+	return c.QueryStoredProcedures(coll, query, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// QueryUserDefinedFunctionsCtx - QueryUserDefinedFunctions with a context
+func (c *CosmosDB) QueryUserDefinedFunctionsCtx(ctx context.Context, coll, query string, opts ...CallOption) (udfs []UDF, err error) {
+	return c.QueryUserDefinedFunctions(coll, query, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// QueryTriggersCtx - QueryTriggers with a context
+func (c *CosmosDB) QueryTriggersCtx(ctx context.Context, coll, query string, opts ...CallOption) (triggers []Trigger, err error) {
+	return c.QueryTriggers(coll, query, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// QueryDocumentsCtx - QueryDocuments with a context
+func (c *CosmosDB) QueryDocumentsCtx(ctx context.Context, coll, query string, docs interface{}, opts ...CallOption) (resp *Response, err error) {
+	return c.QueryDocuments(coll, query, docs, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// QueryDocumentsWithParametersCtx - QueryDocumentsWithParameters with a context
+func (c *CosmosDB) QueryDocumentsWithParametersCtx(ctx context.Context, coll string, query *QueryWithParameters, docs interface{}, opts ...CallOption) (resp *Response, err error) {
+	return c.QueryDocumentsWithParameters(coll, query, docs, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// QueryWithParametersCtx - QueryWithParameters with a context
+func (c *CosmosDB) QueryWithParametersCtx(ctx context.Context, link, query string, params []QueryParameter, ret interface{}, opts ...CallOption) (*Response, error) {
+	return c.QueryWithParameters(link, query, params, ret, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// QueryPartitionKeyRangesCtx - QueryPartitionKeyRanges with a context
+func (c *CosmosDB) QueryPartitionKeyRangesCtx(ctx context.Context, coll string, query string, opts ...CallOption) (ranges []PartitionKeyRange, err error) {
+	return c.QueryPartitionKeyRanges(coll, query, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// QueryOffersCtx - QueryOffers with a context
+func (c *CosmosDB) QueryOffersCtx(ctx context.Context, query string, opts ...CallOption) (offers []Offer, err error) {
+	return c.QueryOffers(query, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// CreateDatabaseCtx - CreateDatabase with a context
+func (c *CosmosDB) CreateDatabaseCtx(ctx context.Context, body interface{}, opts ...CallOption) (db *Database, err error) {
+	return c.CreateDatabase(body, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// CreateCollectionCtx - CreateCollection with a context
+func (c *CosmosDB) CreateCollectionCtx(ctx context.Context, db string, body interface{}, opts ...CallOption) (coll *Collection, err error) {
+	return c.CreateCollection(db, body, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// CreateDatabaseIfNotExistsCtx - CreateDatabaseIfNotExists with a context
+func (c *CosmosDB) CreateDatabaseIfNotExistsCtx(ctx context.Context, body interface{}, opts ...CallOption) (*Database, error) {
+	return c.CreateDatabaseIfNotExists(body, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// CreateCollectionIfNotExistsCtx - CreateCollectionIfNotExists with a context
+func (c *CosmosDB) CreateCollectionIfNotExistsCtx(ctx context.Context, db string, body interface{}, opts ...CallOption) (*Collection, error) {
+	return c.CreateCollectionIfNotExists(db, body, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// CreateUserCtx - CreateUser with a context
+func (c *CosmosDB) CreateUserCtx(ctx context.Context, db string, body interface{}, opts ...CallOption) (user *User, err error) {
+	return c.CreateUser(db, body, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// CreatePermissionCtx - CreatePermission with a context
+func (c *CosmosDB) CreatePermissionCtx(ctx context.Context, user string, body interface{}, opts ...CallOption) (perm *Permission, err error) {
+	return c.CreatePermission(user, body, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// CreateStoredProcedureCtx - CreateStoredProcedure with a context
+func (c *CosmosDB) CreateStoredProcedureCtx(ctx context.Context, coll string, body interface{}, opts ...CallOption) (sproc *Sproc, err error) {
+	return c.CreateStoredProcedure(coll, body, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// CreateUserDefinedFunctionCtx - CreateUserDefinedFunction with a context
+func (c *CosmosDB) CreateUserDefinedFunctionCtx(ctx context.Context, coll string, body interface{}, opts ...CallOption) (udf *UDF, err error) {
+	return c.CreateUserDefinedFunction(coll, body, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// CreateTriggerCtx - CreateTrigger with a context
+func (c *CosmosDB) CreateTriggerCtx(ctx context.Context, coll string, body interface{}, opts ...CallOption) (trigger *Trigger, err error) {
+	return c.CreateTrigger(coll, body, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// CreateDocumentCtx - CreateDocument with a context
+func (c *CosmosDB) CreateDocumentCtx(ctx context.Context, coll string, doc interface{}, opts ...CallOption) (*Response, error) {
+	return c.CreateDocument(coll, doc, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// UpsertDocumentCtx - UpsertDocument with a context
+func (c *CosmosDB) UpsertDocumentCtx(ctx context.Context, coll string, doc interface{}, opts ...CallOption) (*Response, error) {
+	return c.UpsertDocument(coll, doc, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// UpsertCtx - Upsert with a context
+func (c *CosmosDB) UpsertCtx(ctx context.Context, link string, body, ret interface{}, opts ...CallOption) (*Response, error) {
+	return c.Upsert(link, body, ret, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// PatchCtx - Patch with a context
+func (c *CosmosDB) PatchCtx(ctx context.Context, link string, ops []PatchOperation, ret interface{}, opts ...CallOption) (*Response, error) {
+	return c.Patch(link, ops, ret, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// PatchManyCtx - PatchMany with a context
+func (c *CosmosDB) PatchManyCtx(ctx context.Context, coll string, targets []PatchTarget, ops []PatchOperation, opts ...CallOption) ([]PatchResult, error) {
+	return c.PatchMany(coll, targets, ops, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// DeleteDatabaseCtx - DeleteDatabase with a context
+func (c *CosmosDB) DeleteDatabaseCtx(ctx context.Context, link string, opts ...CallOption) (*Response, error) {
+	return c.DeleteDatabase(link, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// DeleteCollectionCtx - DeleteCollection with a context
+func (c *CosmosDB) DeleteCollectionCtx(ctx context.Context, link string, opts ...CallOption) (*Response, error) {
+	return c.DeleteCollection(link, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// DeleteDocumentCtx - DeleteDocument with a context
+func (c *CosmosDB) DeleteDocumentCtx(ctx context.Context, link string, opts ...CallOption) (*Response, error) {
+	return c.DeleteDocument(link, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// DeleteStoredProcedureCtx - DeleteStoredProcedure with a context
+func (c *CosmosDB) DeleteStoredProcedureCtx(ctx context.Context, link string, opts ...CallOption) (*Response, error) {
+	return c.DeleteStoredProcedure(link, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// DeleteUserDefinedFunctionCtx - DeleteUserDefinedFunction with a context
+func (c *CosmosDB) DeleteUserDefinedFunctionCtx(ctx context.Context, link string, opts ...CallOption) (*Response, error) {
+	return c.DeleteUserDefinedFunction(link, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// DeleteTriggerCtx - DeleteTrigger with a context
+func (c *CosmosDB) DeleteTriggerCtx(ctx context.Context, link string, opts ...CallOption) (*Response, error) {
+	return c.DeleteTrigger(link, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReplaceDatabaseCtx - ReplaceDatabase with a context
+func (c *CosmosDB) ReplaceDatabaseCtx(ctx context.Context, link string, body interface{}, opts ...CallOption) (db *Database, err error) {
+	return c.ReplaceDatabase(link, body, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReplaceCollectionCtx - ReplaceCollection with a context
+func (c *CosmosDB) ReplaceCollectionCtx(ctx context.Context, link string, body interface{}, opts ...CallOption) (coll *Collection, err error) {
+	return c.ReplaceCollection(link, body, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReplaceOfferCtx - ReplaceOffer with a context
+func (c *CosmosDB) ReplaceOfferCtx(ctx context.Context, link string, body interface{}, opts ...CallOption) (offer *Offer, err error) {
+	return c.ReplaceOffer(link, body, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReplaceDocumentCtx - ReplaceDocument with a context
+func (c *CosmosDB) ReplaceDocumentCtx(ctx context.Context, link string, doc interface{}, opts ...CallOption) (*Response, error) {
+	return c.ReplaceDocument(link, doc, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReplaceDocumentAsyncCtx - ReplaceDocumentAsync with a context
+func (c *CosmosDB) ReplaceDocumentAsyncCtx(ctx context.Context, link string, doc interface{}, opts ...CallOption) (*Response, error) {
+	return c.ReplaceDocumentAsync(link, doc, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReplaceStoredProcedureCtx - ReplaceStoredProcedure with a context
+func (c *CosmosDB) ReplaceStoredProcedureCtx(ctx context.Context, link string, body interface{}, opts ...CallOption) (sproc *Sproc, err error) {
+	return c.ReplaceStoredProcedure(link, body, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReplaceUserDefinedFunctionCtx - ReplaceUserDefinedFunction with a context
+func (c *CosmosDB) ReplaceUserDefinedFunctionCtx(ctx context.Context, link string, body interface{}, opts ...CallOption) (udf *UDF, err error) {
+	return c.ReplaceUserDefinedFunction(link, body, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReplaceTriggerCtx - ReplaceTrigger with a context
+func (c *CosmosDB) ReplaceTriggerCtx(ctx context.Context, link string, body interface{}, opts ...CallOption) (trigger *Trigger, err error) {
+	return c.ReplaceTrigger(link, body, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ExecuteStoredProcedureCtx - ExecuteStoredProcedure with a context
+func (c *CosmosDB) ExecuteStoredProcedureCtx(ctx context.Context, link string, params, body interface{}, opts ...CallOption) (resp *Response, err error) {
+	return c.ExecuteStoredProcedure(link, params, body, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ExecuteBatchCtx - ExecuteBatch with a context
+func (c *CosmosDB) ExecuteBatchCtx(ctx context.Context, b *TransactionalBatch, opts ...CallOption) ([]BatchResult, error) {
+	return c.ExecuteBatch(b, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ExecuteUntilDoneCtx - ExecuteUntilDone with a context
+func (c *CosmosDB) ExecuteUntilDoneCtx(ctx context.Context, link string, params []interface{}, fn func(body json.RawMessage) error, options *ExecutionOptions, opts ...CallOption) error {
+	return c.ExecuteUntilDone(link, params, fn, options, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// GetQueryPlanCtx - GetQueryPlan with a context
+func (c *CosmosDB) GetQueryPlanCtx(ctx context.Context, coll string, query *QueryWithParameters, opts ...CallOption) (*QueryPlan, error) {
+	return c.GetQueryPlan(coll, query, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// ReplaceThroughputCtx - ReplaceThroughput with a context
+func (c *CosmosDB) ReplaceThroughputCtx(ctx context.Context, link string, t Throughput, opts ...CallOption) (*Offer, error) {
+	return c.ReplaceThroughput(link, t, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// UpdateCtx - Update with a context
+func UpdateCtx[T any](ctx context.Context, c *CosmosDB, link string, pk interface{}, mutate func(doc *T) error, opts ...CallOption) (*T, error) {
+	return Update(c, link, pk, mutate, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
@@ -0,0 +1,47 @@
+package gocosmosdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextVariants(t *testing.T) {
+	assert := assert.New(t)
+	release := make(chan struct{})
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer s.Close()
+	defer close(release)
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	var docs []Document
+	_, err := client.QueryDocumentsCtx(ctx, "dbs/db/colls/coll/", "SELECT * FROM root r", &docs)
+	assert.True(errors.Is(err, context.DeadlineExceeded))
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = client.DeleteCollectionCtx(ctx, "dbs/db/colls/coll/")
+	assert.True(errors.Is(err, context.Canceled))
+	_, err = client.QueryWithParametersCtx(ctx, "dbs/db/colls/coll/docs/", "SELECT * FROM root r", nil, &docs)
+	assert.True(errors.Is(err, context.Canceled))
+
+	// the options of the caller are not appended to in place
+	opts := make([]CallOption, 1, 2)
+	opts[0] = PartitionKey("t1")
+	_, err = client.DeleteCollectionCtx(ctx, "dbs/db/colls/coll/", opts...)
+	assert.True(errors.Is(err, context.Canceled))
+	assert.Nil(opts[:2][1])
+}
@@ -0,0 +1,65 @@
+package gocosmosdb
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// HeaderRequestID - the default header CorrelateRequestID sends the request id in
+const HeaderRequestID = "X-Request-Id"
+
+// CorrelationFunc - returns the headers to send with every request for the passed context, so calls can be
+// matched with the upstream request in logs and diagnostics
+type CorrelationFunc func(ctx context.Context) map[string]string
+
+// CorrelateRequestID - a CorrelationFunc sending the request id set by WithRequestID in the header
+//
+//	client := gocosmosdb.New(url, gocosmosdb.Config{MasterKey: key, Correlation: gocosmosdb.CorrelateRequestID(gocosmosdb.HeaderRequestID)}, log)
+func CorrelateRequestID(header string) CorrelationFunc {
+	return func(ctx context.Context) map[string]string {
+		if id, ok := RequestIDFromContext(ctx); ok {
+			return map[string]string{header: id}
+		}
+		return nil
+	}
+}
+
+// CorrelateValues - a CorrelationFunc sending string context values, eg. set by middleware or taken from baggage,
+// in the headers their keys map to
+//
+//	gocosmosdb.CorrelateValues(map[interface{}]string{traceKey: "X-Trace-Id", userKey: "X-User-Id"})
+func CorrelateValues(headers map[interface{}]string) CorrelationFunc {
+	return func(ctx context.Context) map[string]string {
+		values := map[string]string{}
+		for key, header := range headers {
+			if v, ok := ctx.Value(key).(string); ok && v != "" {
+				values[header] = v
+			}
+		}
+		return values
+	}
+}
+
+// correlate - adds the configured correlation headers of the request context
+func (c *apiClient) correlate(r *Request) {
+	if c.config.Correlation == nil {
+		return
+	}
+	for header, value := range c.config.Correlation(r.ctx()) {
+		r.Header.Set(header, value)
+	}
+}
+
+// correlation - returns the correlation headers of a request for the logs, eg. "X-Request-Id=abc"
+func (c *apiClient) correlation(r *Request) string {
+	if c.config.Correlation == nil {
+		return ""
+	}
+	pairs := []string{}
+	for header := range c.config.Correlation(r.ctx()) {
+		pairs = append(pairs, header+"="+r.Header.Get(header))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
@@ -0,0 +1,38 @@
+package gocosmosdb
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type traceKey struct{}
+
+func TestCorrelation(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"id": "db"}`, `{"id": "db"}`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", Correlation: CorrelateRequestID(HeaderRequestID)}, log)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	_, err := client.ReadDatabase("dbs/db", WithContext(ctx))
+	assert.Nil(err)
+	assert.Equal("req-1", s.Header.Get(HeaderRequestID))
+	req, _ := http.NewRequest("GET", s.URL, nil)
+	req.Header = s.Header
+	assert.Equal("X-Request-Id=req-1", client.client.correlation(&Request{Request: req, rContext: ctx}))
+
+	_, err = client.ReadDatabase("dbs/db")
+	assert.Nil(err)
+	assert.Equal("", s.Header.Get(HeaderRequestID))
+}
+
+func TestCorrelateValues(t *testing.T) {
+	assert := assert.New(t)
+	correlation := CorrelateValues(map[interface{}]string{traceKey{}: "X-Trace-Id"})
+	ctx := context.WithValue(context.Background(), traceKey{}, "trace-1")
+	assert.Equal(map[string]string{"X-Trace-Id": "trace-1"}, correlation(ctx))
+	assert.Equal(map[string]string{}, correlation(context.Background()))
+}
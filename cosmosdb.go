@@ -5,8 +5,10 @@
 package gocosmosdb
 
 import (
+	"encoding/json"
 	"errors"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/intwinelabs/logger"
@@ -18,11 +20,36 @@ type Config struct {
 	Debug                   bool
 	Verbose                 bool
 	PartitionKeyStructField string // eg. "Id"
-	PartitionKeyPath        string // slash denoted path eg. "/id"
+	PartitionKeyPath        string // slash denoted path eg. "/id", queries comparing it with a value run in its partition
+	CrossPartitionQueries   bool   // fans queries out across partitions without a PartitionKeyStructField, see SinglePartition
+	QueryPlans              bool   // asks for the plan of the fanned out queries it cannot pin, running those reading one range there
 	RetryWaitMin            time.Duration
 	RetryWaitMax            time.Duration
 	RetryMax                int
+	RetryThrottled          bool          // also retries requests throttled with 429, waiting as long as the service asks
+	RetryThrottledMaxWait   time.Duration // stops retrying a throttled request once its waits would add up past it, 0 is unlimited
+	RetryJitter             time.Duration // adds up to this much random wait to throttled retries, spreading out clients throttled together
+	RetryPolicy             RetryPolicy   // decides the retries instead of the settings above but RetryMax, see DefaultRetryPolicy
 	Pooled                  bool
+	DNSCache                *DNSCache        // dials cached addresses of the account host, resolving afresh when they fail
+	MaxConcurrentRequests   int              // caps the requests in flight, the others wait for a slot
+	MaxConcurrentWait       time.Duration    // fails a request waiting longer for a slot with a QueueTimeoutError, 0 waits for its context
+	Audit                   AuditFunc        // stamps fields into every written document, eg. ContextAudit
+	ContentHashes           bool             // stores the hash of the content of every written document, see VerifyContentHashes
+	TokenRefresh            time.Duration    // resource token lifetime for clients created with NewUserClient
+	PartitionStats          *PartitionStats  // records the RUs charged per partition key when set
+	CostStats               *CostStats       // records the RUs charged per cost center when set
+	Budget                  *RUBudget        // alarms once the RUs charged in a window go over budget, refusing NonCritical calls
+	Governor                *Governor        // admits calls by their priority while throttled, see WithPriority
+	ValidateDocuments       bool             // checks written documents against the size and depth limits before sending
+	SessionTokens           bool             // replays the session tokens of writes with later reads of the collection, for read-your-writes
+	Correlation             CorrelationFunc  // headers sent with every request from its context, also logged in debug mode
+	OnBackoff               BackoffFunc      // called whenever a request is about to be retried after a wait
+	ConnectionStats         *ConnectionStats // records connection reuse and DNS, dial and TLS latency when set
+	Instrumentation         Instrumentation  // observes every request, eg. a PrometheusCollector
+	StrictEmulator          bool             // rejects calls relying on behavior the emulator lacks, see EmulatorError
+	ReadOnly                bool             // refuses the calls that would write with a ReadOnlyError, eg. for analytics services
+	DryRun                  *DryRun          // withholds the writes, or sends them to a shadow collection, to rehearse jobs
 }
 
 // CosmosDB - Struct that stores the client and logger
@@ -61,6 +88,16 @@ func (c *CosmosDB) DisableDebug() {
 	c.client.disableDebug()
 }
 
+// ReadAccount - Retrieves the database account properties by performing a GET on the account endpoint.
+//	account, err := client.ReadAccount()
+func (c *CosmosDB) ReadAccount(opts ...CallOption) (account *Account, err error) {
+	_, err = c.client.read("", &account, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
 // ReadDatabase - Retrieves a database resource by performing a GET on the database resource.
 //	db, err := client.ReadDatabase("dbs/{db-id}")
 func (c *CosmosDB) ReadDatabase(link string, opts ...CallOption) (db *Database, err error) {
@@ -108,6 +145,46 @@ func (c *CosmosDB) ReadUserDefinedFunction(link string, opts ...CallOption) (udf
 	return
 }
 
+// ReadTrigger - Retrieves a trigger by performing a GET on a specific trigger resource.
+//	trigger, err := client.ReadTrigger("dbs/{db-id}/colls/{coll-id}/triggers/{trigger-id}")
+func (c *CosmosDB) ReadTrigger(link string, opts ...CallOption) (trigger *Trigger, err error) {
+	_, err = c.client.read(link, &trigger, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// ReadUser - Retrieves a user by performing a GET on a specific user resource.
+//	user, err := client.ReadUser("dbs/{db-id}/users/{user-id}")
+func (c *CosmosDB) ReadUser(link string, opts ...CallOption) (user *User, err error) {
+	_, err = c.client.read(link, &user, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// ReadPermission - Retrieves a permission, including a newly issued resource token, by performing a GET on a specific permission resource.
+//	perm, err := client.ReadPermission("dbs/{db-id}/users/{user-id}/permissions/{perm-id}")
+func (c *CosmosDB) ReadPermission(link string, opts ...CallOption) (perm *Permission, err error) {
+	_, err = c.client.read(link, &perm, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// ReadPartitionKeyRange - Retrieves a partition key range, with the ids of the ranges it was split from and its status.
+//	pkr, err := client.ReadPartitionKeyRange("dbs/{db-id}/colls/{coll-id}/pkranges/{pkrange-id}")
+func (c *CosmosDB) ReadPartitionKeyRange(link string, opts ...CallOption) (pkr *PartitionKeyRange, err error) {
+	_, err = c.client.read(link, &pkr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
 // ReadDatabases - Retrieves all databases by performing a GET on a specific account.
 //	dbs, err := client.ReadDatabases("dbs")
 func (c *CosmosDB) ReadDatabases(opts ...CallOption) (dbs []Database, err error) {
@@ -132,12 +209,44 @@ func (c *CosmosDB) ReadUserDefinedFunctions(coll string, opts ...CallOption) (ud
 	return c.QueryUserDefinedFunctions(coll, "", opts...)
 }
 
-// ReadDocuments - Retrieves a stored procedure by performing a GET on a specific stored procedure resource.
-//	err = client.ReadDocuments("dbs/{db-id}/colls/{coll-id}/docs", &docStructSlice)
+// ReadTriggers - Retrieves all triggers by performing a GET on a specific collection.
+//	triggers, err := client.ReadTriggers("dbs/{db-id}/colls/{coll-id}/")
+func (c *CosmosDB) ReadTriggers(coll string, opts ...CallOption) (triggers []Trigger, err error) {
+	return c.QueryTriggers(coll, "", opts...)
+}
+
+// ReadPartitionKeyRanges - Retrieves every partition key range of a collection by following the continuations of its feed.
+//	ranges, err := client.ReadPartitionKeyRanges("dbs/{db-id}/colls/{coll-id}/")
+func (c *CosmosDB) ReadPartitionKeyRanges(coll string, opts ...CallOption) (ranges []PartitionKeyRange, err error) {
+	continuation := ""
+	for {
+		data := struct {
+			PartitionKeyRanges []PartitionKeyRange `json:"PartitionKeyRanges,omitempty"`
+			Count              int                 `json:"_count,omitempty"`
+		}{}
+		resp, err := c.client.read(coll+"pkranges/", &data, append(opts, Continuation(continuation))...)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, data.PartitionKeyRanges...)
+		if continuation = resp.Continuation(); continuation == "" {
+			return ranges, nil
+		}
+	}
+}
+
+// ReadDocuments - Retrieves a page of the documents of a collection from its read feed, pass Limit and the Continuation of the previous response for the next page, see NewDocumentFeed and ReadAllDocuments to follow every page.
+//	resp, err = client.ReadDocuments("dbs/{db-id}/colls/{coll-id}/", &docStructSlice, gocosmosdb.Limit(100), gocosmosdb.Continuation(continuation))
 func (c *CosmosDB) ReadDocuments(coll string, docs interface{}, opts ...CallOption) (*Response, error) {
 	return c.QueryDocuments(coll, "", docs, opts...)
 }
 
+// ReadOffers - Retrieves the offers of every database and collection with dedicated throughput in the account, see ReadNamedOffers to resolve their names.
+//	offers, err := client.ReadOffers()
+func (c *CosmosDB) ReadOffers(opts ...CallOption) (offers []Offer, err error) {
+	return c.QueryOffers("", opts...)
+}
+
 // QueryDatabases - Retrieves all databases that satisfy the passed query.
 //	dbs, err := client.QueryDatabases("SELECT * FROM ROOT r")
 func (c *CosmosDB) QueryDatabases(query string, opts ...CallOption) (dbs []Database, err error) {
@@ -210,6 +319,24 @@ func (c *CosmosDB) QueryUserDefinedFunctions(coll, query string, opts ...CallOpt
 	return
 }
 
+// QueryTriggers - Retrieves all triggers that satisfy the passed query.
+//	triggers, err := client.QueryTriggers("dbs/{db-id}/colls/{coll-id}/", "SELECT * FROM ROOT r WHERE r.triggerType = 'Pre'")
+func (c *CosmosDB) QueryTriggers(coll, query string, opts ...CallOption) (triggers []Trigger, err error) {
+	data := struct {
+		Triggers []Trigger `json:"Triggers,omitempty"`
+		Count    int       `json:"_count,omitempty"`
+	}{}
+	if len(query) > 0 {
+		_, err = c.client.query(coll+"triggers/", query, &data, opts...)
+	} else {
+		_, err = c.client.read(coll+"triggers/", &data, opts...)
+	}
+	if triggers = data.Triggers; err != nil {
+		triggers = nil
+	}
+	return
+}
+
 // QueryDocuments - Retrieves all documents in a collection that satisfy the passed query and marshals them into the passed interface.
 //	err := client.QueryDocuments(coll, "SELECT * FROM ROOT r", &docs)
 func (c *CosmosDB) QueryDocuments(coll, query string, docs interface{}, opts ...CallOption) (resp *Response, err error) {
@@ -220,7 +347,7 @@ func (c *CosmosDB) QueryDocuments(coll, query string, docs interface{}, opts ...
 	if len(query) > 0 {
 		resp, err = c.client.query(coll+"docs/", query, &data, opts...)
 	} else {
-		resp, err = c.client.read(coll+"docs/", &data)
+		resp, err = c.client.read(coll+"docs/", &data, opts...)
 	}
 	return
 }
@@ -240,6 +367,26 @@ func (c *CosmosDB) QueryDocumentsWithParameters(coll string, query *QueryWithPar
 	return
 }
 
+// QueryWithParameters - Retrieves the resources of a feed that satisfy a query with parameters and marshals them into the passed interface. Values are sent as parameters rather than spliced into the query text.
+//	err := client.QueryWithParameters("dbs/{db-id}/colls/{coll-id}/docs/", "SELECT * FROM root r WHERE r.name = @name", []gocosmosdb.QueryParameter{{Name: "@name", Value: name}}, &docs)
+func (c *CosmosDB) QueryWithParameters(link, query string, params []QueryParameter, ret interface{}, opts ...CallOption) (*Response, error) {
+	if params == nil {
+		params = []QueryParameter{}
+	}
+	feed := map[string]json.RawMessage{}
+	resp, err := c.client.queryWithParameters(link, &QueryWithParameters{Query: query, Parameters: params}, &feed, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// the resources are under a key named after their type, eg. Documents, next to _rid and _count
+	for key, items := range feed {
+		if !strings.HasPrefix(key, "_") {
+			return resp, json.Unmarshal(items, ret)
+		}
+	}
+	return resp, nil
+}
+
 // QueryPartitionKeyRanges - Retrieves all partition ranges in a collection.
 //	pks, err := client.QueryPartitionKeyRanges(coll, "SELECT * FROM ROOT r")
 func (c *CosmosDB) QueryPartitionKeyRanges(coll string, query string, opts ...CallOption) (ranges []PartitionKeyRange, err error) {
@@ -258,8 +405,25 @@ func (c *CosmosDB) QueryPartitionKeyRanges(coll string, query string, opts ...Ca
 	return
 }
 
-// CreateDatabase - Creates a new database in the database account.
+// QueryOffers - Retrieves all offers of the database account that satisfy the passed query.
+//	offers, err := client.QueryOffers("SELECT * FROM ROOT r WHERE r.offerResourceId = 'PaYSAPH7qAo='")
+func (c *CosmosDB) QueryOffers(query string, opts ...CallOption) (offers []Offer, err error) {
+	data := offerFeed{}
+	if len(query) > 0 {
+		_, err = c.client.query("offers", query, &data, opts...)
+	} else {
+		_, err = c.client.read("offers", &data, opts...)
+	}
+	if offers = data.Offers; err != nil {
+		offers = nil
+	}
+	return
+}
+
+// CreateDatabase - Creates a new database in the database account. Throughput passed with ThroughputRUs or
+// AutoscaleThroughput is shared by the collections of the database created without their own.
 //	db, err := client.CreateDatabase(`{ "id": "db-id" }`)
+//	db, err := client.CreateDatabase(`{ "id": "db-id" }`, gocosmosdb.ThroughputRUs(400))
 func (c *CosmosDB) CreateDatabase(body interface{}, opts ...CallOption) (db *Database, err error) {
 	_, err = c.client.create("dbs", body, &db, opts...)
 	if err != nil {
@@ -268,8 +432,10 @@ func (c *CosmosDB) CreateDatabase(body interface{}, opts ...CallOption) (db *Dat
 	return
 }
 
-// CreateCollection - Creates a new collections in the database.
+// CreateCollection - Creates a new collections in the database. Throughput is only provisioned when passed
+// with ThroughputRUs or AutoscaleThroughput, otherwise or with NoThroughput the collection shares the database throughput.
 //	coll, err := client.CreateCollection("dbs/{db-id}/", `{"id": "coll-id"}`)
+//	coll, err := client.CreateCollection("dbs/{db-id}/", `{"id": "coll-id"}`, gocosmosdb.AutoscaleThroughput(4000))
 func (c *CosmosDB) CreateCollection(db string, body interface{}, opts ...CallOption) (coll *Collection, err error) {
 	_, err = c.client.create(db+"colls/", body, &coll, opts...)
 	if err != nil {
@@ -278,6 +444,62 @@ func (c *CosmosDB) CreateCollection(db string, body interface{}, opts ...CallOpt
 	return
 }
 
+// CreateDatabaseIfNotExists - Creates a new database like CreateDatabase, or reads the database with its id when it
+// already exists, making provisioning at startup idempotent. The throughput of an existing database is left as it is.
+//	db, err := client.CreateDatabaseIfNotExists(`{ "id": "db-id" }`, gocosmosdb.ThroughputRUs(400))
+func (c *CosmosDB) CreateDatabaseIfNotExists(body interface{}, opts ...CallOption) (*Database, error) {
+	id, err := resourceID(body)
+	if err != nil {
+		return nil, err
+	}
+	db, err := c.CreateDatabase(body, opts...)
+	if errors.Is(err, ErrConflict) {
+		return c.ReadDatabase("dbs/"+id+"/", opts...)
+	}
+	return db, err
+}
+
+// CreateCollectionIfNotExists - Creates a new collection like CreateCollection, or reads the collection with its id
+// when it already exists, making provisioning at startup idempotent. The existing collection is returned as it is,
+// use ApplyContainerSpec to also bring its settings up to date.
+//	coll, err := client.CreateCollectionIfNotExists("dbs/{db-id}/", `{"id": "coll-id"}`)
+func (c *CosmosDB) CreateCollectionIfNotExists(db string, body interface{}, opts ...CallOption) (*Collection, error) {
+	id, err := resourceID(body)
+	if err != nil {
+		return nil, err
+	}
+	coll, err := c.CreateCollection(db, body, opts...)
+	if errors.Is(err, ErrConflict) {
+		return c.ReadCollection(db+"colls/"+id+"/", opts...)
+	}
+	return coll, err
+}
+
+// CreateUser - Creates a new user in the database.
+//	user, err := client.CreateUser("dbs/{db-id}/", `{"id": "user-id"}`)
+func (c *CosmosDB) CreateUser(db string, body interface{}, opts ...CallOption) (user *User, err error) {
+	_, err = c.client.create(db+"users/", body, &user, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// CreatePermission - Creates a new permission for a user on a collection, optionally limited to a partition key.
+//	perm, err := client.CreatePermission("dbs/{db-id}/users/{user-id}/", &gocosmosdb.Permission{
+//		Resource:             gocosmosdb.Resource{Id: "read-own"},
+//		PermissionMode:       "All",
+//		ResourceLink:         "dbs/{db-id}/colls/{coll-id}",
+//		ResourcePartitionKey: []interface{}{"{user-id}"},
+//	})
+func (c *CosmosDB) CreatePermission(user string, body interface{}, opts ...CallOption) (perm *Permission, err error) {
+	_, err = c.client.create(user+"permissions/", body, &perm, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
 // CreateStoredProcedure - Creates a new stored procedure in the collection.
 //	sprocBody := gocosmosdb.Sproc{
 //    	Body: "function () {\r\n    var context = getContext();\r\n    var response = context.getResponse();\r\n\r\n    response.setBody(\"Hello, World\");\r\n}",
@@ -306,41 +528,61 @@ func (c *CosmosDB) CreateUserDefinedFunction(coll string, body interface{}, opts
 	return
 }
 
+// CreateTrigger - Creates a new trigger in the collection, run by the writes naming it with PreTriggers or PostTriggers.
+//	trigger, err := client.CreateTrigger("dbs/{db-id}/colls/{coll-id}/", &gocosmosdb.Trigger{
+//		Resource:         gocosmosdb.Resource{Id: "stampCreatedAt"},
+//		Body:             "function stamp() { ... }",
+//		TriggerType:      gocosmosdb.TriggerTypePre,
+//		TriggerOperation: gocosmosdb.TriggerOperationCreate,
+//	})
+func (c *CosmosDB) CreateTrigger(coll string, body interface{}, opts ...CallOption) (trigger *Trigger, err error) {
+	_, err = c.client.create(coll+"triggers/", body, &trigger, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
 // CreateDocument - Creates a new document in the collection.
 //	err := client.CreateDocument("dbs/{db-id}/colls/{coll-id}", &doc)
 func (c *CosmosDB) CreateDocument(coll string, doc interface{}, opts ...CallOption) (*Response, error) {
-	id := reflect.ValueOf(doc).Elem().FieldByName("Id")
-	if id.IsValid() && id.CanSet() && id.String() == "" {
-		id.SetString(genId())
-	}
-	if c.Config.PartitionKeyStructField != "" {
-		partKey := reflect.ValueOf(doc).Elem().FieldByName(c.Config.PartitionKeyStructField)
-		partKeyI := partKey.Interface()
-		opts = append(opts, PartitionKey(partKeyI))
+	if v := reflect.Indirect(reflect.ValueOf(doc)); v.Kind() == reflect.Struct {
+		if id := v.FieldByName("Id"); id.IsValid() && id.CanSet() && id.String() == "" {
+			id.SetString(genId())
+		}
 	}
+	opts = c.client.structPartitionKey(doc, opts)
 	return c.client.create(coll+"docs/", doc, &doc, opts...)
 }
 
 // UpsertDocument - Creates a new document or replaces the existing document with matching id in the collection.
 //	err := client.UpsertDocument("dbs/{db-id}/colls/{coll-id}", &doc)
 func (c *CosmosDB) UpsertDocument(coll string, doc interface{}, opts ...CallOption) (*Response, error) {
-	id := reflect.ValueOf(doc).Elem().FieldByName("Id")
-	if id.IsValid() && id.CanSet() && id.String() == "" {
-		id.SetString(genId())
+	if v := reflect.Indirect(reflect.ValueOf(doc)); v.Kind() == reflect.Struct {
+		if id := v.FieldByName("Id"); id.IsValid() && id.CanSet() && id.String() == "" {
+			id.SetString(genId())
+		}
 	}
 	return c.client.upsert(coll+"docs/", doc, &doc, opts...)
 }
 
+// Upsert - Creates a resource or replaces the existing one with matching id in one round trip, for the resources
+// that take upserts eg. documents, stored procedures and user defined functions. The link is of their feed.
+//	_, err := client.Upsert("dbs/{db-id}/colls/{coll-id}/sprocs/", &sproc, &sproc)
+func (c *CosmosDB) Upsert(link string, body, ret interface{}, opts ...CallOption) (*Response, error) {
+	return c.client.upsert(link, body, ret, opts...)
+}
+
 // DeleteDatabase - Deletes a database from a database account.
 //	err := client.DeleteDatabase("dbs/{db-id}")
-func (c *CosmosDB) DeleteDatabase(link string) (*Response, error) {
-	return c.client.delete(link)
+func (c *CosmosDB) DeleteDatabase(link string, opts ...CallOption) (*Response, error) {
+	return c.client.delete(link, opts...)
 }
 
 // DeleteCollection - Deletes a collection from a database.
 //	err := client.DeleteCollection("dbs/{db-id}/colls/{coll-id}")
-func (c *CosmosDB) DeleteCollection(link string) (*Response, error) {
-	return c.client.delete(link)
+func (c *CosmosDB) DeleteCollection(link string, opts ...CallOption) (*Response, error) {
+	return c.client.delete(link, opts...)
 }
 
 // DeleteDocument -  Deletes a document from a collection.
@@ -351,14 +593,20 @@ func (c *CosmosDB) DeleteDocument(link string, opts ...CallOption) (*Response, e
 
 // DeleteStoredProcedure -  Deletes a stored procedure from a collection.
 //	err := client.DeleteStoredProcedure("dbs/{db-id}/colls/{coll-id}/sprocs/{sproc-id}")
-func (c *CosmosDB) DeleteStoredProcedure(link string) (*Response, error) {
-	return c.client.delete(link)
+func (c *CosmosDB) DeleteStoredProcedure(link string, opts ...CallOption) (*Response, error) {
+	return c.client.delete(link, opts...)
 }
 
 // DeleteUserDefinedFunction -  Deletes a user defined function from a collection.
 //	err := client.DeleteUserDefinedFunction("dbs/{db-id}/colls/{coll-id}/udfs/{udf-id}")
-func (c *CosmosDB) DeleteUserDefinedFunction(link string) (*Response, error) {
-	return c.client.delete(link)
+func (c *CosmosDB) DeleteUserDefinedFunction(link string, opts ...CallOption) (*Response, error) {
+	return c.client.delete(link, opts...)
+}
+
+// DeleteTrigger -  Deletes a trigger from a collection.
+//	err := client.DeleteTrigger("dbs/{db-id}/colls/{coll-id}/triggers/{trigger-id}")
+func (c *CosmosDB) DeleteTrigger(link string, opts ...CallOption) (*Response, error) {
+	return c.client.delete(link, opts...)
 }
 
 // ReplaceDatabase - Replaces a existing database in a database account.
@@ -371,6 +619,30 @@ func (c *CosmosDB) ReplaceDatabase(link string, body interface{}, opts ...CallOp
 	return
 }
 
+// ReplaceCollection - Replaces the indexing policy or default TTL of an existing collection, the partition key and
+// unique keys of a collection cannot be changed.
+//	coll.DefaultTTL = 3600
+//	coll, err = client.ReplaceCollection(coll.Self, coll)
+func (c *CosmosDB) ReplaceCollection(link string, body interface{}, opts ...CallOption) (coll *Collection, err error) {
+	_, err = c.client.replace(link, body, &coll, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// ReplaceOffer - Replaces the throughput of an offer.
+//	offer.Content.OfferThroughput = 1000
+//	offer, err = client.ReplaceOffer("offers/"+offer.Rid, offer)
+func (c *CosmosDB) ReplaceOffer(link string, body interface{}, opts ...CallOption) (offer *Offer, err error) {
+	offer = &Offer{}
+	_, err = c.client.replace(link, body, offer, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
 // ReplaceDocument - Replaces a existing document in a collection.
 //	db, err := client.ReplaceDocument("dbs/{db-id}/colls/{coll-id}/docs/{doc-id}", &doc)
 func (c *CosmosDB) ReplaceDocument(link string, doc interface{}, opts ...CallOption) (*Response, error) {
@@ -403,6 +675,16 @@ func (c *CosmosDB) ReplaceUserDefinedFunction(link string, body interface{}, opt
 	return
 }
 
+// ReplaceTrigger - Replaces a trigger in a collection.
+//	trigger, err := client.ReplaceTrigger("dbs/{db-id}/colls/{coll-id}/triggers/{trigger-id}", &triggerBody)
+func (c *CosmosDB) ReplaceTrigger(link string, body interface{}, opts ...CallOption) (trigger *Trigger, err error) {
+	_, err = c.client.replace(link, body, &trigger, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
 // ExecuteStoredProcedure - Executes a stored procedure and marshals the data into the passed interface.
 //	err := client.ExecuteStoredProcedure("dbs/{db-id}/colls/{coll-id}/sprocs/{sproc-id}", []interface{}{p1, p2}, &docs)
 func (c *CosmosDB) ExecuteStoredProcedure(link string, params, body interface{}, opts ...CallOption) (resp *Response, err error) {
@@ -2,7 +2,10 @@ package gocosmosdb
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -699,6 +702,26 @@ func TestQueryDocumentsWithParameters(t *testing.T) {
 	assert.Equal("SalesOrder2", docs[1].Id)
 }
 
+func TestQueryWithParameters(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"_rid": "d9RzAJRFKgw=", "Documents": [{"id": "SalesOrder1", "ponumber": "PO18009186470"}], "_count": 1}`,
+		`{"_rid": "d9RzAJRFKgw=", "Documents": [], "_count": 0}`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	docs := []testDoc{}
+	_, err := client.QueryWithParameters("dbs/d9RzAA==/colls/d9RzAJRFKgw=/docs/", "SELECT * FROM root r WHERE r.ponumber = @po",
+		[]QueryParameter{{Name: "@po", Value: `PO" OR 1=1`}}, &docs)
+	assert.Nil(err)
+	assert.Equal("PO18009186470", docs[0].PONumber)
+	assert.JSONEq(`{"query": "SELECT * FROM root r WHERE r.ponumber = @po", "parameters": [{"name": "@po", "value": "PO\" OR 1=1"}]}`, s.Body)
+
+	// quotes and control characters in a query without parameters still make a valid body
+	_, err = client.QueryDocuments("dbs/d9RzAA==/colls/d9RzAJRFKgw=/", "SELECT * FROM root r WHERE r.ponumber = \"PO\"\n", &docs)
+	assert.Nil(err)
+	assert.JSONEq(`{"query": "SELECT * FROM root r WHERE r.ponumber = \"PO\"\n", "parameters": []}`, s.Body)
+}
+
 func TestQueryDocumentsWithParametersWithPartitionKey(t *testing.T) {
 	assert := assert.New(t)
 	resp := `{  
@@ -769,6 +792,36 @@ func TestQueryPartitionKeyRanges(t *testing.T) {
 	assert.Equal("0", ranges[0].Id)
 }
 
+func TestReadPartitionKeyRanges(t *testing.T) {
+	assert := assert.New(t)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/pkranges/2"):
+			fmt.Fprint(w, `{"id": "2", "minInclusive": "3F", "maxExclusive": "FF", "status": "online", "parents": ["0"], "ridPrefix": 2, "_lsn": 42}`)
+		case r.Header.Get(HeaderContinuation) == "":
+			w.Header().Set(HeaderContinuation, "next")
+			fmt.Fprint(w, `{"PartitionKeyRanges": [{"id": "1", "minInclusive": "", "maxExclusive": "3F", "status": "online", "parents": ["0"], "throughputFraction": 0.5}], "_count": 1}`)
+		default:
+			fmt.Fprint(w, `{"PartitionKeyRanges": [{"id": "2", "minInclusive": "3F", "maxExclusive": "FF", "status": "online", "parents": ["0"], "throughputFraction": 0.5}], "_count": 1}`)
+		}
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	ranges, err := client.ReadPartitionKeyRanges("dbs/qYcAAA==/colls/qYcAAPEvJBQ=/", Limit(1))
+	assert.Nil(err)
+	assert.Len(ranges, 2)
+	assert.Equal("3F", ranges[1].MinInclusive)
+	assert.Equal([]string{"0"}, ranges[1].Parents)
+	assert.Equal(0.5, ranges[1].ThroughputFraction)
+
+	pkr, err := client.ReadPartitionKeyRange("dbs/qYcAAA==/colls/qYcAAPEvJBQ=/pkranges/2")
+	assert.Nil(err)
+	assert.Equal(PartitionKeyRangeOnline, pkr.Status)
+	assert.Equal(2, pkr.RidPrefix)
+	assert.Equal(int64(42), pkr.Lsn)
+}
+
 func TestCreateDatabase(t *testing.T) {
 	assert := assert.New(t)
 	resp := `{  
@@ -828,6 +881,36 @@ func TestCreateCollection(t *testing.T) {
 	assert.Equal("testcoll", coll.Id)
 }
 
+func TestCreateIfNotExists(t *testing.T) {
+	assert := assert.New(t)
+	var requests []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/colls/"):
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"code": "Conflict"}`)
+		case r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id": "db", "_self": "dbs/new/"}`)
+		default:
+			fmt.Fprint(w, `{"id": "coll", "_self": "dbs/x/colls/existing/"}`)
+		}
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	db, err := client.CreateDatabaseIfNotExists(`{"id": "db"}`)
+	assert.Nil(err)
+	assert.Equal("dbs/new/", db.Self)
+	coll, err := client.CreateCollectionIfNotExists("dbs/db/", map[string]string{"id": "coll"})
+	assert.Nil(err)
+	assert.Equal("dbs/x/colls/existing/", coll.Self)
+	assert.Equal([]string{"POST /dbs", "POST /dbs/db/colls/", "GET /dbs/db/colls/coll/"}, requests)
+	_, err = client.CreateCollectionIfNotExists("dbs/db/", `{}`)
+	assert.NotNil(err)
+}
+
 func TestCreateStoredProcedure(t *testing.T) {
 	assert := assert.New(t)
 	resp := `{  
@@ -942,6 +1025,24 @@ func TestUpsertDocument(t *testing.T) {
 	assert.NotNil(r)
 }
 
+func TestUpsertCreated(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"id": "sproc-1", "body": "function () {}"}`)
+	s.SetStatus(http.StatusCreated)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	sproc := Sproc{Body: "function () {}"}
+	sproc.Id = "sproc-1"
+	var created Sproc
+	r, err := client.Upsert("dbs/db/colls/coll/sprocs/", &sproc, &created)
+	assert.Nil(err)
+	assert.NotNil(r)
+	assert.Equal("sproc-1", created.Id)
+	assert.Equal("true", s.Header.Get(HeaderUpsert))
+	assert.Equal(`{"id":"sproc-1","body":"function () {}"}`, s.Body)
+}
+
 func TestUpsertDocumentWithPartitionKey(t *testing.T) {
 	assert := assert.New(t)
 	resp := `{  
@@ -1204,7 +1305,7 @@ func TestExecuteStoredProcedureWithContextCancel(t *testing.T) {
 		time.Sleep(250 * time.Microsecond)
 		cancel()
 	}()
-	_, err := client.ExecuteStoredProcedure("dbs/Sl8fAA==/colls/Sl8fALN4sw4=/sprocs/Sl8fALN4sw4CAAAAAAAAgA==", []string{"param1"}, &docs, WithContext(ctx))
+	_, err := client.ExecuteStoredProcedure("dbs/Sl8fAA==/colls/Sl8fALN4sw4=/sprocs/Sl8fALN4sw4CAAAAAAAAgA==", []string{"param1"}, &docs, WithContext(ctx), Idempotent())
 	assert.NotNil(err)
 	assert.Contains(err.Error(), "context canceled")
 
@@ -1217,8 +1318,77 @@ func TestExecuteStoredProcedureWithContextTimeout(t *testing.T) {
 	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", RetryWaitMin: 100 * time.Millisecond, RetryWaitMax: 100 * time.Millisecond, RetryMax: 3}, log)
 	ctx, _ := context.WithTimeout(context.Background(), 250*time.Millisecond)
 	docs := []testDoc{}
-	_, err := client.ExecuteStoredProcedure("dbs/Sl8fAA==/colls/Sl8fALN4sw4=/sprocs/Sl8fALN4sw4CAAAAAAAAgA==", []string{"param1"}, &docs, WithContext(ctx))
+	_, err := client.ExecuteStoredProcedure("dbs/Sl8fAA==/colls/Sl8fALN4sw4=/sprocs/Sl8fALN4sw4CAAAAAAAAgA==", []string{"param1"}, &docs, WithContext(ctx), Idempotent())
 	assert.NotNil(err)
 	assert.Contains(err.Error(), "context deadline exceeded")
 
 }
+
+func TestCreateTrigger(t *testing.T) {
+	assert := assert.New(t)
+	resp := `{
+		"body": "function stamp() { var doc = getContext().getRequest().getBody(); doc.createdAt = Date.now(); getContext().getRequest().setBody(doc); }",
+		"id": "stamp",
+		"triggerOperation": "Create",
+		"triggerType": "Pre",
+		"_rid": "Sl8fALN4sw4BAAAAAAAAcA==",
+		"_ts": 1449689654,
+		"_self": "dbs/Sl8fAA==/colls/Sl8fALN4sw4=/triggers/Sl8fALN4sw4BAAAAAAAAcA==/",
+		"_etag": "\"0600a2e4-0000-0000-0000-566882360000\""
+	}`
+	s := ServerFactory(resp)
+	s.SetStatus(http.StatusCreated)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+	trigger, err := client.CreateTrigger("dbs/Sl8fAA==/colls/Sl8fALN4sw4=/", &Trigger{
+		Resource:         Resource{Id: "stamp"},
+		Body:             "function stamp() { ... }",
+		TriggerType:      TriggerTypePre,
+		TriggerOperation: TriggerOperationCreate,
+	})
+	assert.Nil(err)
+	assert.Equal("stamp", trigger.Id)
+	assert.Equal(TriggerTypePre, trigger.TriggerType)
+	assert.Equal(TriggerOperationCreate, trigger.TriggerOperation)
+	assert.JSONEq(`{"id": "stamp", "body": "function stamp() { ... }", "triggerType": "Pre", "triggerOperation": "Create"}`, s.Body)
+}
+
+func TestReadTriggers(t *testing.T) {
+	assert := assert.New(t)
+	resp := `{
+		"_rid": "Sl8fALN4sw4=",
+		"Triggers": [{
+			"body": "function audit() { ... }",
+			"id": "audit",
+			"triggerOperation": "All",
+			"triggerType": "Post",
+			"_rid": "Sl8fALN4sw4BAAAAAAAAcA==",
+			"_self": "dbs/Sl8fAA==/colls/Sl8fALN4sw4=/triggers/Sl8fALN4sw4BAAAAAAAAcA==/"
+		}],
+		"_count": 1
+	}`
+	s := ServerFactory(resp, resp)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+	triggers, err := client.ReadTriggers("dbs/Sl8fAA==/colls/Sl8fALN4sw4=/")
+	assert.Nil(err)
+	assert.Equal("audit", triggers[0].Id)
+	assert.Equal(TriggerTypePost, triggers[0].TriggerType)
+	triggers, err = client.QueryTriggers("dbs/Sl8fAA==/colls/Sl8fALN4sw4=/", "SELECT * FROM ROOT r WHERE r.triggerType = 'Post'")
+	assert.Nil(err)
+	assert.Len(triggers, 1)
+}
+
+func TestReplaceAndDeleteTrigger(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"id": "stamp", "body": "function stamp() { }", "triggerType": "Pre", "triggerOperation": "All"}`, ``)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+	link := "dbs/Sl8fAA==/colls/Sl8fALN4sw4=/triggers/Sl8fALN4sw4BAAAAAAAAcA==/"
+	trigger, err := client.ReplaceTrigger(link, &Trigger{Resource: Resource{Id: "stamp"}, Body: "function stamp() { }", TriggerType: TriggerTypePre, TriggerOperation: TriggerOperationAll})
+	assert.Nil(err)
+	assert.Equal(TriggerOperationAll, trigger.TriggerOperation)
+	s.SetStatus(http.StatusNoContent)
+	_, err = client.DeleteTrigger(link)
+	assert.Nil(err)
+}
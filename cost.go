@@ -0,0 +1,117 @@
+package gocosmosdb
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+type costCenterKey int
+
+const costCenterContextKey costCenterKey = 0
+
+// WithCostCenter - returns a copy of the context attributing the RUs of the calls made with it to a cost center,
+// eg. the feature or team making them
+func WithCostCenter(ctx context.Context, costCenter string) context.Context {
+	return context.WithValue(ctx, costCenterContextKey, costCenter)
+}
+
+// CostCenterFromContext - returns the cost center set by WithCostCenter
+func CostCenterFromContext(ctx context.Context) (string, bool) {
+	costCenter, ok := ctx.Value(costCenterContextKey).(string)
+	return costCenter, ok
+}
+
+// CostCenter - attributes the RUs of the call to a cost center, overriding the one of its context
+func CostCenter(costCenter string) CallOption {
+	return func(r *Request) error {
+		r.rCostCenter = costCenter
+		return nil
+	}
+}
+
+// costCenter - returns the cost center of a request, empty when unattributed
+func (r *Request) costCenter() string {
+	if r.rCostCenter != "" {
+		return r.rCostCenter
+	}
+	costCenter, _ := CostCenterFromContext(r.ctx())
+	return costCenter
+}
+
+// CostStats - aggregates the RUs charged per cost center, set it on the Config for chargeback reports
+//
+//	costs := gocosmosdb.NewCostStats()
+//	client := gocosmosdb.New(url, gocosmosdb.Config{MasterKey: key, CostStats: costs}, log)
+//	_, err := client.QueryDocuments(coll, query, &docs, gocosmosdb.WithContext(gocosmosdb.WithCostCenter(ctx, "search")))
+//	...
+//	for _, usage := range costs.Report() {
+//		log.Infof("%s: %.2f RUs over %d requests", usage.CostCenter, usage.RequestCharge, usage.Requests)
+//	}
+type CostStats struct {
+	mu    sync.Mutex
+	usage map[string]*CostCenterUsage
+}
+
+// CostCenterUsage - the RUs charged to a cost center, the empty cost center holds the unattributed calls
+type CostCenterUsage struct {
+	CostCenter    string
+	Requests      int
+	RequestCharge float64
+	Share         float64 // of all the RUs charged
+}
+
+// NewCostStats - creates empty stats
+func NewCostStats() *CostStats {
+	return &CostStats{usage: map[string]*CostCenterUsage{}}
+}
+
+// record - adds the charge of a request to its cost center
+func (s *CostStats) record(r *Request, resp *Response) {
+	charge, err := resp.GetRUs()
+	if err != nil {
+		return
+	}
+	costCenter := r.costCenter()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	usage, ok := s.usage[costCenter]
+	if !ok {
+		usage = &CostCenterUsage{CostCenter: costCenter}
+		s.usage[costCenter] = usage
+	}
+	usage.Requests++
+	usage.RequestCharge += charge
+}
+
+// Report - returns the usage of every cost center recorded so far, highest charge first then by name
+func (s *CostStats) Report() []CostCenterUsage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	total := 0.0
+	for _, usage := range s.usage {
+		total += usage.RequestCharge
+	}
+	report := []CostCenterUsage{}
+	for _, usage := range s.usage {
+		u := *usage
+		if total > 0 {
+			u.Share = u.RequestCharge / total
+		}
+		report = append(report, u)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].RequestCharge == report[j].RequestCharge {
+			return report[i].CostCenter < report[j].CostCenter
+		}
+		return report[i].RequestCharge > report[j].RequestCharge
+	})
+	return report
+}
+
+// Reset - forgets the usage recorded so far, eg. to report per interval
+func (s *CostStats) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.usage = map[string]*CostCenterUsage{}
+}
@@ -0,0 +1,33 @@
+package gocosmosdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCostStats(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"id": "1"}`, `{"id": "1"}`, `{"id": "1"}`, `{"id": "1"}`)
+	s.SetHeader(HeaderRequestCharge, "2")
+	defer s.Close()
+	costs := NewCostStats()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", CostStats: costs}, log)
+
+	ctx := WithCostCenter(context.Background(), "search")
+	var doc Document
+	client.ReadDocument("dbs/db/colls/coll/docs/1", &doc, WithContext(ctx))
+	client.ReadDocument("dbs/db/colls/coll/docs/1", &doc, WithContext(ctx))
+	client.ReadDocument("dbs/db/colls/coll/docs/1", &doc, WithContext(ctx), CostCenter("checkout"))
+	client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+
+	assert.Equal([]CostCenterUsage{
+		{CostCenter: "search", Requests: 2, RequestCharge: 4, Share: 0.5},
+		{CostCenter: "", Requests: 1, RequestCharge: 2, Share: 0.25},
+		{CostCenter: "checkout", Requests: 1, RequestCharge: 2, Share: 0.25},
+	}, costs.Report())
+
+	costs.Reset()
+	assert.Empty(costs.Report())
+}
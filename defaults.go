@@ -0,0 +1,127 @@
+package gocosmosdb
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// CollectionDefaults - options applied to every document request of a collection, before the options passed to
+// the call so call sites can still override them
+type CollectionDefaults struct {
+	ConsistencyLevel  Consistency
+	MaxItemCount      int
+	PartitionKeyPath  string // documents the partition key of the collection for helpers needing it
+	IndexingDirective string // Include or Exclude written documents from the index
+	Options           []CallOption
+}
+
+// options - returns the defaults as call options
+func (d CollectionDefaults) options() []CallOption {
+	opts := []CallOption{}
+	if d.ConsistencyLevel != "" {
+		opts = append(opts, ConsistencyLevel(d.ConsistencyLevel))
+	}
+	if d.MaxItemCount != 0 {
+		opts = append(opts, Limit(d.MaxItemCount))
+	}
+	if d.IndexingDirective != "" {
+		opts = append(opts, IndexingDirective(d.IndexingDirective))
+	}
+	return append(opts, d.Options...)
+}
+
+// collectionDefaults - the defaults registered per collection link
+type collectionDefaults struct {
+	mu       sync.RWMutex
+	defaults map[string]CollectionDefaults
+}
+
+// collectionKey - normalizes a collection link eg. "/dbs/db/colls/coll/" to "dbs/db/colls/coll"
+func collectionKey(coll string) string {
+	return strings.Trim(coll, "/")
+}
+
+// SetCollectionDefaults - registers the defaults of a collection, replacing any registered before
+//
+//	client.SetCollectionDefaults("dbs/{db-id}/colls/{coll-id}/", gocosmosdb.CollectionDefaults{
+//		ConsistencyLevel: gocosmosdb.Eventual,
+//		MaxItemCount:     100,
+//	})
+func (c *CosmosDB) SetCollectionDefaults(coll string, defaults CollectionDefaults) {
+	c.client.defaults.mu.Lock()
+	defer c.client.defaults.mu.Unlock()
+	c.client.defaults.defaults[collectionKey(coll)] = defaults
+}
+
+// GetCollectionDefaults - returns the defaults registered for a collection
+func (c *CosmosDB) GetCollectionDefaults(coll string) (CollectionDefaults, bool) {
+	return c.client.collectionDefaults(coll)
+}
+
+func (c *apiClient) collectionDefaults(coll string) (CollectionDefaults, bool) {
+	if c.defaults == nil {
+		return CollectionDefaults{}, false
+	}
+	c.defaults.mu.RLock()
+	defer c.defaults.mu.RUnlock()
+	d, ok := c.defaults.defaults[collectionKey(coll)]
+	return d, ok
+}
+
+// withDefaults - prepends the defaults of the collection a document request targets, then those of the context
+// the options pass, to its options
+func (c *apiClient) withDefaults(link string, opts []CallOption) []CallOption {
+	opts = withContextDefaults(opts)
+	coll := collectionOf(link)
+	if coll == strings.Trim(link, "/") {
+		return opts
+	}
+	d, ok := c.collectionDefaults(coll)
+	if !ok {
+		return opts
+	}
+	return append(d.options(), opts...)
+}
+
+type defaultOptionsKey int
+
+const defaultOptionsContextKey defaultOptionsKey = 0
+
+// WithDefaultOptions - returns a copy of the context whose calls, made with it through WithContext, take opts
+// before their own options, so the options of a call still override them and they override the defaults of its
+// collection. The options add to those of the contexts it derives from, saving to pass them down deep call stacks.
+//
+//	ctx = gocosmosdb.WithDefaultOptions(ctx, gocosmosdb.ConsistencyLevel(gocosmosdb.Eventual), gocosmosdb.WithPriority(gocosmosdb.PriorityBackground))
+//	...
+//	_, err := client.ReadDocumentCtx(ctx, link, &doc)
+func WithDefaultOptions(ctx context.Context, opts ...CallOption) context.Context {
+	parent := DefaultOptionsFromContext(ctx)
+	return context.WithValue(ctx, defaultOptionsContextKey, append(parent[:len(parent):len(parent)], opts...))
+}
+
+// DefaultOptionsFromContext - returns the options set by WithDefaultOptions
+func DefaultOptionsFromContext(ctx context.Context) []CallOption {
+	opts, _ := ctx.Value(defaultOptionsContextKey).([]CallOption)
+	return opts
+}
+
+// withContextDefaults - prepends the default options of the context passed with WithContext, found by applying the
+// options to a scratch request
+func withContextDefaults(opts []CallOption) []CallOption {
+	probe := &Request{Request: &http.Request{Header: http.Header{}}}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(probe)
+		}
+	}
+	if probe.rContext == nil {
+		return opts
+	}
+	defaults := DefaultOptionsFromContext(probe.rContext)
+	if len(defaults) == 0 {
+		return opts
+	}
+	return append(defaults[:len(defaults):len(defaults)], opts...)
+}
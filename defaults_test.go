@@ -0,0 +1,71 @@
+package gocosmosdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionDefaults(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"Documents": [], "_count": 0}`, `{"Documents": [], "_count": 0}`, `{"id": "coll"}`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+	client.SetCollectionDefaults("/dbs/db/colls/coll/", CollectionDefaults{
+		ConsistencyLevel:  Eventual,
+		MaxItemCount:      100,
+		PartitionKeyPath:  "/tenant",
+		IndexingDirective: "Exclude",
+		Options:           []CallOption{EnableQueryScan()},
+	})
+	d, ok := client.GetCollectionDefaults("dbs/db/colls/coll")
+	assert.True(ok)
+	assert.Equal("/tenant", d.PartitionKeyPath)
+
+	docs := []testDoc{}
+	_, err := client.QueryDocuments("dbs/db/colls/coll/", "SELECT * FROM root r", &docs)
+	assert.Nil(err)
+	assert.Equal(string(Eventual), s.Header.Get(HeaderConsistencyLevel))
+	assert.Equal("100", s.Header.Get(HeaderMaxItemCount))
+	assert.Equal("Exclude", s.Header.Get(HeaderIndexingDirective))
+	assert.Equal("true", s.Header.Get(HeaderEnableScan))
+
+	// passed options override the defaults
+	_, err = client.QueryDocuments("dbs/db/colls/coll/", "SELECT * FROM root r", &docs, Limit(5))
+	assert.Nil(err)
+	assert.Equal("5", s.Header.Get(HeaderMaxItemCount))
+
+	// the defaults only apply to the documents of the collection
+	_, err = client.ReadCollection("dbs/db/colls/coll/")
+	assert.Nil(err)
+	assert.Equal("", s.Header.Get(HeaderMaxItemCount))
+}
+
+func TestContextDefaultOptions(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"id": "1"}`, `{"id": "2"}`, `{"id": "3"}`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+	client.SetCollectionDefaults("dbs/db/colls/coll/", CollectionDefaults{ConsistencyLevel: Strong, MaxItemCount: 10})
+
+	ctx := WithDefaultOptions(context.Background(), ConsistencyLevel(Eventual))
+	ctx = WithDefaultOptions(ctx, Limit(20))
+	assert.Len(DefaultOptionsFromContext(ctx), 2)
+	var doc testDoc
+	_, err := client.ReadDocumentCtx(ctx, "dbs/db/colls/coll/docs/1", &doc)
+	assert.Nil(err)
+	assert.Equal(string(Eventual), s.Header.Get(HeaderConsistencyLevel))
+	assert.Equal("20", s.Header.Get(HeaderMaxItemCount))
+
+	// the options of a call override those of its context
+	_, err = client.ReadDocument("dbs/db/colls/coll/docs/2", &doc, WithContext(ctx), ConsistencyLevel(Session))
+	assert.Nil(err)
+	assert.Equal(string(Session), s.Header.Get(HeaderConsistencyLevel))
+
+	// calls without the context only take the defaults of the collection
+	_, err = client.ReadDocument("dbs/db/colls/coll/docs/3", &doc)
+	assert.Nil(err)
+	assert.Equal(string(Strong), s.Header.Get(HeaderConsistencyLevel))
+	assert.Equal("10", s.Header.Get(HeaderMaxItemCount))
+}
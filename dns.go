@@ -0,0 +1,117 @@
+package gocosmosdb
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// DNSCache - caches the addresses of the hosts a client dials, set it on the Config to ride out DNS flaps eg. during
+// a regional failover. Addresses older than the ttl are refreshed in the background while the cached ones are
+// still dialed, a failed refresh keeps them for another ttl, and when none of them can be dialed the host is resolved afresh once.
+//
+//	client := gocosmosdb.New(url, gocosmosdb.Config{MasterKey: key, Pooled: true, DNSCache: gocosmosdb.NewDNSCache(time.Minute)}, log)
+type DNSCache struct {
+	ttl    time.Duration
+	lookup func(ctx context.Context, host string) ([]string, error)
+	now    func() time.Time
+	mu     sync.Mutex
+	hosts  map[string]*dnsEntry
+}
+
+type dnsEntry struct {
+	addrs      []string
+	resolved   time.Time
+	refreshing bool
+}
+
+// NewDNSCache - creates a cache keeping the addresses of a host for ttl before refreshing them
+func NewDNSCache(ttl time.Duration) *DNSCache {
+	return &DNSCache{ttl: ttl, lookup: net.DefaultResolver.LookupHost, now: time.Now, hosts: map[string]*dnsEntry{}}
+}
+
+// Addrs - returns the cached addresses of a host, resolving it when it is not cached yet
+func (c *DNSCache) Addrs(ctx context.Context, host string) ([]string, error) {
+	c.mu.Lock()
+	entry, ok := c.hosts[host]
+	if ok && !entry.refreshing && c.now().Sub(entry.resolved) > c.ttl {
+		entry.refreshing = true
+		go c.Refresh(context.Background(), host)
+	}
+	c.mu.Unlock()
+	if ok {
+		return entry.addrs, nil
+	}
+	return c.Refresh(ctx, host)
+}
+
+// Refresh - resolves a host afresh, keeping the cached addresses for another ttl when that fails
+func (c *DNSCache) Refresh(ctx context.Context, host string) ([]string, error) {
+	addrs, err := c.lookup(ctx, host)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.hosts[host]
+	if err != nil || len(addrs) == 0 {
+		if ok {
+			// try again once the ttl passed rather than on every call
+			entry.refreshing = false
+			entry.resolved = c.now()
+			return entry.addrs, nil
+		}
+		if err == nil {
+			err = &net.DNSError{Err: "no addresses", Name: host, IsNotFound: true}
+		}
+		return nil, err
+	}
+	c.hosts[host] = &dnsEntry{addrs: addrs, resolved: c.now()}
+	return addrs, nil
+}
+
+// DialContext - returns a dial function for a transport dialing the cached addresses of the host with the dialer
+func (c *DNSCache) DialContext(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil || net.ParseIP(host) != nil {
+			return dialer.DialContext(ctx, network, addr)
+		}
+		addrs, err := c.Addrs(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		conn, err := c.dial(ctx, dialer, network, addrs, port)
+		if err == nil || ctx.Err() != nil {
+			return conn, err
+		}
+		// the cached addresses may be gone with a failover, try those resolved now
+		fresh, rerr := c.Refresh(ctx, host)
+		if rerr != nil || sameAddrs(addrs, fresh) {
+			return nil, err
+		}
+		return c.dial(ctx, dialer, network, fresh, port)
+	}
+}
+
+// dial - dials the addresses in order, returning the first connection or the last error
+func (c *DNSCache) dial(ctx context.Context, dialer *net.Dialer, network string, addrs []string, port string) (net.Conn, error) {
+	var err error
+	for _, ip := range addrs {
+		var conn net.Conn
+		if conn, err = dialer.DialContext(ctx, network, net.JoinHostPort(ip, port)); err == nil {
+			return conn, nil
+		}
+	}
+	return nil, err
+}
+
+func sameAddrs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,103 @@
+package gocosmosdb
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDNSCacheFailover(t *testing.T) {
+	assert := assert.New(t)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": "1"}`))
+	}))
+	defer s.Close()
+	_, port, _ := net.SplitHostPort(s.Listener.Addr().String())
+
+	var mu sync.Mutex
+	var lookups []string
+	// the first answer points at an address nothing listens on, as after a failover
+	answers := [][]string{{"127.0.0.2"}, {"127.0.0.1"}}
+	cache := NewDNSCache(time.Hour)
+	cache.lookup = func(ctx context.Context, host string) ([]string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		lookups = append(lookups, host)
+		addrs := answers[0]
+		if len(answers) > 1 {
+			answers = answers[1:]
+		}
+		return addrs, nil
+	}
+	client := New("http://myaccount.documents.test:"+port, Config{MasterKey: "YXJpZWwNCg==", Pooled: true, DNSCache: cache}, log)
+
+	var doc Document
+	_, err := client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+	assert.Nil(err)
+	assert.Equal("1", doc.Id)
+	assert.Equal([]string{"myaccount.documents.test", "myaccount.documents.test"}, lookups)
+	addrs, err := cache.Addrs(context.Background(), "myaccount.documents.test")
+	assert.Nil(err)
+	assert.Equal([]string{"127.0.0.1"}, addrs)
+}
+
+func TestDNSCacheRefresh(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	refreshed := make(chan struct{}, 1)
+	answer, failure := []string{"10.0.0.1"}, error(nil)
+	cache := NewDNSCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	cache.lookup = func(ctx context.Context, host string) ([]string, error) {
+		defer func() { refreshed <- struct{}{} }()
+		return answer, failure
+	}
+
+	addrs, err := cache.Addrs(context.Background(), "host")
+	<-refreshed
+	assert.Nil(err)
+	assert.Equal([]string{"10.0.0.1"}, addrs)
+
+	// stale addresses are served while they are refreshed in the background
+	answer, now = []string{"10.0.0.2"}, now.Add(2*time.Minute)
+	addrs, _ = cache.Addrs(context.Background(), "host")
+	assert.Equal([]string{"10.0.0.1"}, addrs)
+	<-refreshed
+	addrs, _ = cache.Addrs(context.Background(), "host")
+	assert.Equal([]string{"10.0.0.2"}, addrs)
+
+	// a failed lookup keeps the cached addresses
+	failure = errors.New("no such host")
+	addrs, err = cache.Refresh(context.Background(), "host")
+	<-refreshed
+	assert.Nil(err)
+	assert.Equal([]string{"10.0.0.2"}, addrs)
+
+	// and they are not refreshed again until the ttl passed
+	now = now.Add(2 * time.Minute)
+	cache.Addrs(context.Background(), "host")
+	<-refreshed
+	assert.True(eventually(func() bool {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		return !cache.hosts["host"].refreshing
+	}))
+	addrs, _ = cache.Addrs(context.Background(), "host")
+	assert.Equal([]string{"10.0.0.2"}, addrs)
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(refreshed, 0)
+	now = now.Add(2 * time.Minute)
+	cache.Addrs(context.Background(), "host")
+	<-refreshed
+
+	_, err = cache.Addrs(context.Background(), "other")
+	<-refreshed
+	assert.Equal(failure, err)
+}
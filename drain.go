@@ -0,0 +1,93 @@
+package gocosmosdb
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// DrainLimits - bounds how much QueryAll and ReadAllDocuments read, zero values are unlimited
+type DrainLimits struct {
+	MaxItems int // pages are sized so the drain reads at most this many items
+	MaxRUs   float64
+	Timeout  time.Duration // checked between pages
+}
+
+// DefaultDrainLimits - used by QueryAll and ReadAllDocuments when no limits are passed
+var DefaultDrainLimits = DrainLimits{MaxItems: 10000}
+
+// DrainLimitError - returned by QueryAll and ReadAllDocuments when a limit stops the drain, the documents read so far are kept
+type DrainLimitError struct {
+	Limit         string
+	Items         int
+	RequestCharge float64
+	Elapsed       time.Duration
+}
+
+// Implement Error function
+func (e *DrainLimitError) Error() string {
+	return fmt.Sprintf("query drain stopped by %s after %d items, %.2f RUs and %s", e.Limit, e.Items, e.RequestCharge, e.Elapsed)
+}
+
+// QueryAll - follows every continuation of the query appending the documents to the slice docs points to,
+// stopping with a *DrainLimitError once one of the limits is hit
+//
+//	var docs []Doc
+//	err := client.QueryAll(coll, &gocosmosdb.QueryWithParameters{Query: "SELECT * FROM root r"}, &docs, &gocosmosdb.DrainLimits{MaxItems: 500})
+func (c *CosmosDB) QueryAll(coll string, query *QueryWithParameters, docs interface{}, limits *DrainLimits, opts ...CallOption) error {
+	return drain(c.NewPagableQuery(coll, query, -1, nil, opts...), docs, limits)
+}
+
+// ReadAllDocuments - follows every continuation of the read feed of a collection appending the documents to the
+// slice docs points to, stopping with a *DrainLimitError once one of the limits is hit
+//
+//	var docs []Doc
+//	err := client.ReadAllDocuments(coll, &docs, &gocosmosdb.DrainLimits{MaxRUs: 1000})
+func (c *CosmosDB) ReadAllDocuments(coll string, docs interface{}, limits *DrainLimits, opts ...CallOption) error {
+	return drain(c.NewDocumentFeed(coll, -1, nil, opts...), docs, limits)
+}
+
+// drain - reads every page of a pagable query into the slice docs points to
+func drain(pg *PagableQuery, docs interface{}, limits *DrainLimits) error {
+	out := reflect.ValueOf(docs)
+	if out.Kind() != reflect.Ptr || out.Elem().Kind() != reflect.Slice {
+		return errors.New("drained docs must be a pointer to a slice")
+	}
+	if limits == nil {
+		limits = &DefaultDrainLimits
+	}
+	start := time.Now()
+	pg.WithMaxRUs(limits.MaxRUs)
+	stop := func(limit string) error {
+		return &DrainLimitError{Limit: limit, Items: out.Elem().Len(), RequestCharge: pg.RequestCharge(), Elapsed: time.Since(start)}
+	}
+	for !pg.Done() {
+		if limits.Timeout > 0 && time.Since(start) > limits.Timeout {
+			return stop("Timeout")
+		}
+		remaining := limits.MaxItems - out.Elem().Len()
+		if limits.MaxItems > 0 {
+			if remaining <= 0 {
+				return stop("MaxItems")
+			}
+			// read no more than the limit leaves room for, so the drain stays within it
+			pg.limit = Limit(remaining)
+		}
+		page := reflect.New(out.Elem().Type())
+		pg.docs = page.Interface()
+		err := pg.Next()
+		items := page.Elem()
+		if limits.MaxItems > 0 && items.Len() > remaining {
+			items = items.Slice(0, remaining)
+		}
+		out.Elem().Set(reflect.AppendSlice(out.Elem(), items))
+		if _, ok := err.(*RUCapError); ok {
+			return stop("MaxRUs")
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package gocosmosdb
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryAll(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"Documents": [{"id": "SalesOrder1"}, {"id": "SalesOrder2"}], "_count": 2}`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+	docs := []testDoc{}
+	err := client.QueryAll("dbs/d9RzAA==/colls/d9RzAJRFKgw=/", &QueryWithParameters{Query: "SELECT * FROM root r"}, &docs, nil)
+	assert.Nil(err)
+	assert.Equal(2, len(docs))
+	assert.Equal("SalesOrder2", docs[1].Id)
+
+	assert.NotNil(client.QueryAll("dbs/d9RzAA==/colls/d9RzAJRFKgw=/", nil, docs, nil))
+}
+
+func TestQueryAllMaxItems(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"Documents": [{"id": "SalesOrder1"}], "_count": 1}`,
+		`{"Documents": [{"id": "SalesOrder2"}, {"id": "SalesOrder3"}], "_count": 2}`)
+	s.SetHeader(HeaderContinuation, "testContinuation")
+	s.SetHeader(HeaderRequestCharge, "2.5")
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+	docs := []testDoc{}
+	err := client.QueryAll("dbs/d9RzAA==/colls/d9RzAJRFKgw=/", &QueryWithParameters{Query: "SELECT * FROM root r"}, &docs, &DrainLimits{MaxItems: 2})
+	assert.IsType(&DrainLimitError{}, err)
+	drainErr := err.(*DrainLimitError)
+	assert.Equal("MaxItems", drainErr.Limit)
+	assert.Equal(2, drainErr.Items)
+	assert.Equal(5.0, drainErr.RequestCharge)
+	// the second page is sized to the room left and never drains past the limit
+	assert.Equal("1", s.Header.Get(HeaderMaxItemCount))
+	assert.Equal(2, len(docs))
+	assert.Equal("SalesOrder2", docs[1].Id)
+}
+
+func TestReadAllDocuments(t *testing.T) {
+	assert := assert.New(t)
+	var methods, continuations []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		continuations = append(continuations, r.Header.Get(HeaderContinuation))
+		if r.Header.Get(HeaderContinuation) == "" {
+			w.Header().Set(HeaderContinuation, "page2")
+			fmt.Fprint(w, `{"Documents": [{"id": "SalesOrder1"}], "_count": 1}`)
+			return
+		}
+		fmt.Fprint(w, `{"Documents": [{"id": "SalesOrder2"}], "_count": 1}`)
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	docs := []testDoc{}
+	err := client.ReadAllDocuments("dbs/d9RzAA==/colls/d9RzAJRFKgw=/", &docs, nil)
+	assert.Nil(err)
+	assert.Equal(2, len(docs))
+	assert.Equal("SalesOrder2", docs[1].Id)
+	assert.Equal([]string{http.MethodGet, http.MethodGet}, methods)
+	assert.Equal([]string{"", "page2"}, continuations)
+
+	page := []testDoc{}
+	feed := client.NewDocumentFeed("dbs/d9RzAA==/colls/d9RzAJRFKgw=/", 1, &page)
+	assert.Nil(feed.Next())
+	assert.Equal("SalesOrder1", page[0].Id)
+	assert.False(feed.Done())
+}
@@ -0,0 +1,75 @@
+package gocosmosdb
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRun(t *testing.T) {
+	assert := assert.New(t)
+	sent := []string{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Method+" "+r.URL.Path)
+		fmt.Fprintln(w, `{"id": "1", "value": "read"}`)
+	}))
+	defer s.Close()
+	rehearsal := NewDryRun()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", DryRun: rehearsal}, log)
+
+	doc := testDoc{Document: Document{Resource: Resource{Id: "1"}}}
+	resp, err := client.CreateDocument("dbs/db/colls/coll/", &doc)
+	assert.Nil(err)
+	assert.Equal(http.StatusCreated, resp.StatusCode)
+	assert.Equal("1", doc.Id)
+	_, err = client.DeleteDocument("dbs/db/colls/coll/docs/1")
+	assert.Nil(err)
+	_, err = client.ExecuteStoredProcedure("dbs/db/colls/coll/sprocs/sp", nil, nil)
+	assert.Nil(err)
+	var read testDoc
+	_, err = client.ReadDocument("dbs/db/colls/coll/docs/1", &read)
+	assert.Nil(err)
+	assert.Equal([]string{"GET /dbs/db/colls/coll/docs/1"}, sent)
+
+	stats := rehearsal.Stats()
+	assert.Equal(int64(3), stats.Writes)
+	assert.Equal(int64(0), stats.Shadowed)
+	assert.True(stats.Bytes > 0)
+	assert.Equal(map[string]int64{"POST docs": 1, "DELETE docs": 1, "POST sprocs": 1}, stats.Operations)
+
+	// dry runs validate the documents written
+	_, err = client.CreateDocument("dbs/db/colls/coll/", &testDoc{PONumber: strings.Repeat("x", MaxDocumentSize)})
+	assert.NotNil(err)
+}
+
+func TestDryRunShadow(t *testing.T) {
+	assert := assert.New(t)
+	sent := []string{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Method+" "+r.URL.Path)
+		assert.Len(r.Header[HeaderXDate], 1)
+		assert.NotEmpty(r.Header.Get(HeaderAuth))
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+		fmt.Fprintln(w, `{"id": "1"}`)
+	}))
+	defer s.Close()
+	rehearsal := NewDryRun().WithShadow("/dbs/db/colls/rehearsal")
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", DryRun: rehearsal}, log)
+
+	doc := testDoc{Document: Document{Resource: Resource{Id: "1"}}}
+	_, err := client.CreateDocument("dbs/db/colls/coll/", &doc)
+	assert.Nil(err)
+	_, err = client.ReplaceDocument("dbs/db/colls/coll/docs/1", &doc)
+	assert.Nil(err)
+	_, err = client.DeleteCollection("dbs/db/colls/coll/")
+	assert.Nil(err)
+	assert.Equal([]string{"POST /dbs/db/colls/rehearsal/docs/", "PUT /dbs/db/colls/rehearsal/docs/1"}, sent)
+	assert.Equal(int64(2), rehearsal.Stats().Shadowed)
+	assert.Equal(int64(3), rehearsal.Stats().Writes)
+}
@@ -0,0 +1,188 @@
+package gocosmosdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+// DualWriteClient - writes the documents of a collection being migrated, eg. to a new partition key, to both the
+// old and the new collection during the migration window. The old collection stays the source of truth: its
+// writes are returned to the caller, while their copies are upserted to the new collection unconditionally and
+// their failures only counted and reported, to be repaired by the backfill. Check compares the two copies of a
+// document before reads are switched to the new collection.
+//
+//	migration := client.NewDualWriteClient("dbs/{db-id}/colls/orders/", "dbs/{db-id}/colls/orders-by-customer/", "/customerId").
+//		OnNewWriteError(func(id string, err error) { log.Infof("order %s not copied: %s", id, err) })
+//	_, err := migration.UpsertDocument(&order, gocosmosdb.PartitionKey(order.TenantId))
+type DualWriteClient struct {
+	client     *CosmosDB
+	oldColl    string
+	newColl    string
+	newPath    string
+	onNewError func(id string, err error)
+	mu         sync.Mutex
+	stats      DualWriteStats
+}
+
+// DualWriteStats - counts the writes and checks of a DualWriteClient
+type DualWriteStats struct {
+	Writes      int64 // successful writes to the old collection
+	NewWrites   int64 // successful copies to the new collection
+	NewFailures int64 // copies to the new collection that failed
+	Checks      int64
+	Mismatches  int64 // checks finding the copies of a document differ
+}
+
+// NewDualWriteClient - creates a client writing to the collections oldColl and newColl, newPartitionKeyPath is the
+// partition key of the new collection eg. "/customerId"
+func (c *CosmosDB) NewDualWriteClient(oldColl, newColl, newPartitionKeyPath string) *DualWriteClient {
+	return &DualWriteClient{client: c, oldColl: normalizeLink(oldColl), newColl: normalizeLink(newColl), newPath: newPartitionKeyPath}
+}
+
+// OnNewWriteError - sets the function called with the failures of the copies to the new collection
+func (d *DualWriteClient) OnNewWriteError(fn func(id string, err error)) *DualWriteClient {
+	d.onNewError = fn
+	return d
+}
+
+// Stats - returns the counts so far
+func (d *DualWriteClient) Stats() DualWriteStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.stats
+}
+
+// CreateDocument - creates a document in the old collection and copies it to the new one
+func (d *DualWriteClient) CreateDocument(doc interface{}, opts ...CallOption) (*Response, error) {
+	resp, err := d.client.CreateDocument(d.oldColl, doc, opts...)
+	return resp, d.copy(doc, err, opts)
+}
+
+// UpsertDocument - upserts a document in the old collection and copies it to the new one
+func (d *DualWriteClient) UpsertDocument(doc interface{}, opts ...CallOption) (*Response, error) {
+	resp, err := d.client.UpsertDocument(d.oldColl, doc, opts...)
+	return resp, d.copy(doc, err, opts)
+}
+
+// ReplaceDocument - replaces the document with the id in the old collection, conditions like IfMatch only apply
+// to it, and copies it to the new one
+func (d *DualWriteClient) ReplaceDocument(id string, doc interface{}, opts ...CallOption) (*Response, error) {
+	resp, err := d.client.ReplaceDocument(d.oldColl+"docs/"+id, doc, opts...)
+	return resp, d.copy(doc, err, opts)
+}
+
+// DeleteDocument - deletes the document with the id from the old collection then its copy, with the partition key
+// of the new collection, from the new one
+func (d *DualWriteClient) DeleteDocument(id string, newPartitionKey interface{}, opts ...CallOption) (*Response, error) {
+	resp, err := d.client.DeleteDocument(d.oldColl+"docs/"+id, opts...)
+	if err != nil {
+		return nil, err
+	}
+	d.count(func(s *DualWriteStats) { s.Writes++ })
+	_, err = d.client.DeleteDocument(d.newColl+"docs/"+id, d.newOptions(opts, newPartitionKey)...)
+	if errors.Is(err, ErrNotFound) {
+		err = nil
+	}
+	d.copied(id, err)
+	return resp, nil
+}
+
+// Check - reads the document with the id from both collections and reports whether they hold the same fields,
+// system properties aside. A document missing from the new collection does not match.
+func (d *DualWriteClient) Check(id string, oldPartitionKey interface{}, opts ...CallOption) (bool, error) {
+	var old json.RawMessage
+	if _, err := d.client.ReadDocument(d.oldColl+"docs/"+id, &old, append(opts, PartitionKey(oldPartitionKey))...); err != nil {
+		return false, err
+	}
+	pk, err := valueAtPath(old, d.newPath)
+	if err != nil {
+		return false, err
+	}
+	var copied json.RawMessage
+	_, err = d.client.ReadDocument(d.newColl+"docs/"+id, &copied, d.newOptions(opts, pk)...)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return false, err
+	}
+	same := err == nil && sameFields(old, copied)
+	d.count(func(s *DualWriteStats) {
+		s.Checks++
+		if !same {
+			s.Mismatches++
+		}
+	})
+	return same, nil
+}
+
+// copy - upserts the document written to the old collection to the new one, unless the old write failed
+func (d *DualWriteClient) copy(doc interface{}, err error, opts []CallOption) error {
+	if err != nil {
+		return err
+	}
+	d.count(func(s *DualWriteStats) { s.Writes++ })
+	data, err := stringify(doc)
+	if err != nil {
+		return err
+	}
+	var meta struct {
+		Id string `json:"id"`
+	}
+	json.Unmarshal(data, &meta)
+	pk, err := valueAtPath(data, d.newPath)
+	if err == nil {
+		_, err = d.client.client.method(http.MethodPost, d.newColl+"docs/", expectUpserted, nil, bytes.NewBuffer(data),
+			append(d.newOptions(opts, pk), Upsert())...)
+	}
+	d.copied(meta.Id, err)
+	return nil
+}
+
+// newOptions - the options of a write to the old collection for its copy, unconditional and with the partition key
+// of the new collection
+func (d *DualWriteClient) newOptions(opts []CallOption, pk interface{}) []CallOption {
+	unconditional := func(r *Request) error {
+		r.Header.Del(HeaderIfMatch)
+		return nil
+	}
+	return append(opts[:len(opts):len(opts)], unconditional, PartitionKey(pk))
+}
+
+// copied - counts a write to the new collection, reporting its failure
+func (d *DualWriteClient) copied(id string, err error) {
+	d.count(func(s *DualWriteStats) {
+		if err != nil {
+			s.NewFailures++
+		} else {
+			s.NewWrites++
+		}
+	})
+	if err != nil && d.onNewError != nil {
+		d.onNewError(id, err)
+	}
+}
+
+// count - updates the stats
+func (d *DualWriteClient) count(update func(s *DualWriteStats)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	update(&d.stats)
+}
+
+// sameFields - compares two documents leaving out the system properties, which start with an underscore
+func sameFields(a, b json.RawMessage) bool {
+	var x, y map[string]interface{}
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return false
+	}
+	for _, doc := range []map[string]interface{}{x, y} {
+		for k := range doc {
+			if len(k) > 0 && k[0] == '_' {
+				delete(doc, k)
+			}
+		}
+	}
+	return reflect.DeepEqual(x, y)
+}
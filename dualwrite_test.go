@@ -0,0 +1,120 @@
+package gocosmosdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// collectionsServer - keeps the documents of collections by link and id, recording the partition keys written
+type collectionsServer struct {
+	mu   sync.Mutex
+	docs map[string]string
+	pks  map[string]string
+	fail string // the collection whose writes fail
+}
+
+func (s *collectionsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	body, _ := ioutil.ReadAll(r.Body)
+	path := strings.Trim(r.URL.Path, "/")
+	if s.fail != "" && strings.HasPrefix(path, s.fail) && r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, `{"code": "ServiceUnavailable"}`)
+		return
+	}
+	if r.Method == http.MethodPost {
+		var doc struct{ Id string }
+		json.Unmarshal(body, &doc)
+		path += "/" + doc.Id
+	}
+	switch r.Method {
+	case http.MethodGet:
+		doc, ok := s.docs[path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"code": "NotFound"}`)
+			return
+		}
+		fmt.Fprint(w, doc)
+	case http.MethodDelete:
+		if _, ok := s.docs[path]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"code": "NotFound"}`)
+			return
+		}
+		delete(s.docs, path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s.docs[path] = string(body)
+		s.pks[path] = r.Header.Get(HeaderPartitionKey)
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+		w.Write(body)
+	}
+}
+
+type dualOrder struct {
+	Document
+	TenantId   string `json:"tenantId"`
+	CustomerId string `json:"customerId"`
+	Total      int    `json:"total"`
+}
+
+func TestDualWriteClient(t *testing.T) {
+	assert := assert.New(t)
+	server := &collectionsServer{docs: map[string]string{}, pks: map[string]string{}}
+	s := httptest.NewServer(server)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+	var failed []string
+	migration := client.NewDualWriteClient("dbs/db/colls/orders", "dbs/db/colls/orders2", "/customerId").
+		OnNewWriteError(func(id string, err error) { failed = append(failed, id) })
+
+	order := &dualOrder{Document: Document{Resource: Resource{Id: "o1"}}, TenantId: "t1", CustomerId: "c1", Total: 10}
+	_, err := migration.CreateDocument(order, PartitionKey("t1"))
+	assert.Nil(err)
+	assert.Equal(`["t1"]`, server.pks["dbs/db/colls/orders/docs/o1"])
+	assert.Equal(`["c1"]`, server.pks["dbs/db/colls/orders2/docs/o1"])
+	same, err := migration.Check("o1", "t1")
+	assert.Nil(err)
+	assert.True(same)
+
+	// conditions only apply to the old collection
+	order.Total = 20
+	_, err = migration.ReplaceDocument("o1", order, PartitionKey("t1"), IfMatch(`"1"`))
+	assert.Nil(err)
+	assert.Contains(server.docs["dbs/db/colls/orders2/docs/o1"], `"total":20`)
+
+	// failed copies are reported and found by checks, failed writes to the old collection are returned
+	server.fail = "dbs/db/colls/orders2"
+	order.Total = 30
+	_, err = migration.UpsertDocument(order, PartitionKey("t1"))
+	assert.Nil(err)
+	assert.Equal([]string{"o1"}, failed)
+	same, err = migration.Check("o1", "t1")
+	assert.Nil(err)
+	assert.False(same)
+	server.fail = "dbs/db/colls/orders/"
+	_, err = migration.UpsertDocument(order, PartitionKey("t1"))
+	assert.NotNil(err)
+	server.fail = ""
+
+	_, err = migration.DeleteDocument("o1", "c1", PartitionKey("t1"))
+	assert.Nil(err)
+	assert.Empty(server.docs)
+	_, err = migration.Check("o1", "t1")
+	assert.True(errors.Is(err, ErrNotFound))
+
+	assert.Equal(DualWriteStats{Writes: 4, NewWrites: 3, NewFailures: 1, Checks: 2, Mismatches: 1}, migration.Stats())
+}
@@ -0,0 +1,50 @@
+package gocosmosdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+const (
+	// EmulatorURI - the default endpoint of the local emulator
+	EmulatorURI = "https://localhost:8081"
+
+	// EmulatorMasterKey - the well-known master key of the local emulator
+	EmulatorMasterKey = "C2y6yDjf5/R+ob0N8A7Cgv30VRDJIWEHLM+4QDU5DE2nQ9nDuVTqobD4b8mGGyPMbIZnqyMsEcaGQy67XIw/Jw=="
+
+	// EmulatorMaxThroughput - the most RU/s the emulator provisions for a database or collection
+	EmulatorMaxThroughput = 10000
+)
+
+// EmulatorError - returned in StrictEmulator mode for calls relying on behavior the emulator does not have
+type EmulatorError struct {
+	Feature string
+}
+
+// Implement Error function
+func (e *EmulatorError) Error() string {
+	return fmt.Sprintf("%s is not supported by the emulator, StrictEmulator rejects it in every environment", e.Feature)
+}
+
+// emulate - rejects requests the emulator would serve differently from the service when in StrictEmulator mode,
+// so test suites run against the emulator fail the same way as in Azure
+func (c *apiClient) emulate(r *Request) error {
+	if !c.config.StrictEmulator {
+		return nil
+	}
+	if v := r.Header.Get(HeaderOfferThroughput); v != "" {
+		if rus, err := strconv.Atoi(v); err == nil && rus > EmulatorMaxThroughput {
+			return &EmulatorError{Feature: fmt.Sprintf("throughput above %d RU/s", EmulatorMaxThroughput)}
+		}
+	}
+	if v := r.Header.Get(HeaderOfferAutopilotSettings); v != "" {
+		settings := struct {
+			MaxThroughput int `json:"maxThroughput"`
+		}{}
+		if err := json.Unmarshal([]byte(v), &settings); err == nil && settings.MaxThroughput > EmulatorMaxThroughput {
+			return &EmulatorError{Feature: fmt.Sprintf("autoscale throughput above %d RU/s", EmulatorMaxThroughput)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package gocosmosdb
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrictEmulator(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"id": "coll"}`, `{"id": "1"}`)
+	s.SetStatus(http.StatusCreated)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: EmulatorMasterKey, StrictEmulator: true}, log)
+	client.SetRoutingPolicy("analytics", RoutingPolicy{Endpoint: "https://acct-westus.documents.azure.com:443/"})
+
+	_, err := client.CreateCollection("dbs/db/", `{"id": "coll"}`, ThroughputRUs(20000))
+	assert.IsType(&EmulatorError{}, err)
+	_, err = client.CreateCollection("dbs/db/", `{"id": "coll"}`, AutoscaleThroughput(40000))
+	assert.Contains(err.Error(), "autoscale throughput above 10000 RU/s is not supported by the emulator")
+	_, err = client.CreateCollection("dbs/db/", `{"id": "coll"}`, ThroughputRUs(400))
+	assert.Nil(err)
+
+	s.SetStatus(http.StatusOK)
+	var doc Document
+	_, err = client.ReadDocument("dbs/db/colls/coll/docs/1", &doc, OperationTag("analytics"))
+	assert.IsType(&EmulatorError{}, err)
+	_, err = client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+	assert.Nil(err)
+}
@@ -0,0 +1,154 @@
+package gocosmosdb
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+	"strings"
+)
+
+// the markers of the components of a partition key as they are hashed
+const (
+	epkNull   = 0x01
+	epkFalse  = 0x02
+	epkTrue   = 0x03
+	epkNumber = 0x05
+	epkString = 0x08
+)
+
+// EffectivePartitionKey - returns the hash of a partition key value that places it in a partition key range, as
+// the service computes it for collections with version 2 partition keys, as 32 upper case hex digits.
+// Values are strings, numbers, booleans or nil, and slices of them for hierarchical partition keys.
+func EffectivePartitionKey(value interface{}) (string, error) {
+	components, ok := value.([]interface{})
+	if !ok {
+		components = []interface{}{value}
+	}
+	var data []byte
+	for _, component := range components {
+		switch v := component.(type) {
+		case nil:
+			data = append(data, epkNull)
+		case bool:
+			if v {
+				data = append(data, epkTrue)
+			} else {
+				data = append(data, epkFalse)
+			}
+		case string:
+			data = append(data, epkString)
+			data = append(data, v...)
+			data = append(data, 0xFF)
+		default:
+			n, ok := number(v)
+			if !ok {
+				return "", fmt.Errorf("cannot hash a partition key of type %T", component)
+			}
+			data = append(data, epkNumber)
+			var b [8]byte
+			binary.LittleEndian.PutUint64(b[:], math.Float64bits(n))
+			data = append(data, b[:]...)
+		}
+	}
+	h1, h2 := murmur3x64(data)
+	hash := make([]byte, 16)
+	binary.BigEndian.PutUint64(hash, h2)
+	binary.BigEndian.PutUint64(hash[8:], h1)
+	// the two top bits are cleared to keep the hash below the "FF" end of the last range
+	hash[0] &= 0x3F
+	return strings.ToUpper(hex.EncodeToString(hash)), nil
+}
+
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// RangeOf - returns the partition key range an effective partition key falls in, ranges are as read with
+// ReadPartitionKeyRanges
+func RangeOf(ranges []PartitionKeyRange, epk string) (PartitionKeyRange, bool) {
+	sorted := make([]PartitionKeyRange, len(ranges))
+	copy(sorted, ranges)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].MinInclusive < sorted[j].MinInclusive })
+	return rangeOf(sorted, epk)
+}
+
+// rangeOf - RangeOf on ranges sorted by their start
+func rangeOf(sorted []PartitionKeyRange, epk string) (PartitionKeyRange, bool) {
+	i := sort.Search(len(sorted), func(i int) bool { return sorted[i].MaxInclusive > epk })
+	if i == len(sorted) || sorted[i].MinInclusive > epk {
+		return PartitionKeyRange{}, false
+	}
+	return sorted[i], true
+}
+
+// murmur3x64 - the 128 bit x64 MurmurHash3 of the data with a zero seed
+func murmur3x64(data []byte) (h1, h2 uint64) {
+	const c1, c2 = 0x87c37b91114253d5, 0x4cf5ad432745937f
+	n := len(data)
+	for ; len(data) >= 16; data = data[16:] {
+		k1 := binary.LittleEndian.Uint64(data)
+		k2 := binary.LittleEndian.Uint64(data[8:])
+		h1 ^= bits.RotateLeft64(k1*c1, 31) * c2
+		h1 = bits.RotateLeft64(h1, 27) + h2
+		h1 = h1*5 + 0x52dce729
+		h2 ^= bits.RotateLeft64(k2*c2, 33) * c1
+		h2 = bits.RotateLeft64(h2, 31) + h1
+		h2 = h2*5 + 0x38495ab5
+	}
+	var tail [16]byte
+	copy(tail[:], data)
+	k1 := binary.LittleEndian.Uint64(tail[:])
+	k2 := binary.LittleEndian.Uint64(tail[8:])
+	if len(data) > 8 {
+		h2 ^= bits.RotateLeft64(k2*c2, 33) * c1
+	}
+	if len(data) > 0 {
+		h1 ^= bits.RotateLeft64(k1*c1, 31) * c2
+	}
+	h1 ^= uint64(n)
+	h2 ^= uint64(n)
+	h1 += h2
+	h2 += h1
+	h1, h2 = fmix64(h1), fmix64(h2)
+	h1 += h2
+	h2 += h1
+	return h1, h2
+}
+
+func fmix64(k uint64) uint64 {
+	k ^= k >> 33
+	k *= 0xff51afd7ed558ccd
+	k ^= k >> 33
+	k *= 0xc4ceb9fe1a85ec53
+	k ^= k >> 33
+	return k
+}
@@ -0,0 +1,61 @@
+package gocosmosdb
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMurmur3x64(t *testing.T) {
+	assert := assert.New(t)
+	h1, h2 := murmur3x64([]byte("hello"))
+	assert.Equal(uint64(0xcbd8a7b341bd9b02), h1)
+	assert.Equal(uint64(0x5b1e906a48ae1d19), h2)
+	h1, h2 = murmur3x64([]byte("The quick brown fox jumps over the lazy dog"))
+	assert.Equal(uint64(0xe34bbc7bbc071b6c), h1)
+	assert.Equal(uint64(0x7a433ca9c49a9347), h2)
+	h1, h2 = murmur3x64(nil)
+	assert.Zero(h1)
+	assert.Zero(h2)
+}
+
+func TestEffectivePartitionKey(t *testing.T) {
+	assert := assert.New(t)
+	epk, err := EffectivePartitionKey("tenant-1")
+	assert.Nil(err)
+	assert.Len(epk, 32)
+	assert.True(epk < "40")
+	again, _ := EffectivePartitionKey([]interface{}{"tenant-1"})
+	assert.Equal(epk, again)
+
+	// numbers hash as doubles, whatever their type
+	i, _ := EffectivePartitionKey(42)
+	f, _ := EffectivePartitionKey(42.0)
+	assert.Equal(i, f)
+	n, _ := EffectivePartitionKey(nil)
+	b, _ := EffectivePartitionKey(false)
+	s, _ := EffectivePartitionKey("42")
+	assert.NotEqual(i, n)
+	assert.NotEqual(n, b)
+	assert.NotEqual(i, s)
+
+	_, err = EffectivePartitionKey(json.Number("42"))
+	assert.EqualError(err, "cannot hash a partition key of type json.Number")
+}
+
+func TestRangeOf(t *testing.T) {
+	assert := assert.New(t)
+	ranges := []PartitionKeyRange{
+		{Resource: Resource{Id: "2"}, MinInclusive: "1F", MaxInclusive: "FF"},
+		{Resource: Resource{Id: "1"}, MinInclusive: "", MaxInclusive: "1F"},
+	}
+	r, ok := RangeOf(ranges, "05C1DFE85AB1C5B61C1A4B1E0CD5F4B1")
+	assert.True(ok)
+	assert.Equal("1", r.Id)
+	r, ok = RangeOf(ranges, "1F")
+	assert.True(ok)
+	assert.Equal("2", r.Id)
+	_, ok = RangeOf(ranges[:1], "05")
+	assert.False(ok)
+}
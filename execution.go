@@ -0,0 +1,92 @@
+package gocosmosdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ExecutionOptions - where ExecuteUntilDone starts and bounds how long it runs, zero values are unlimited
+type ExecutionOptions struct {
+	From          json.RawMessage // a continuation to resume from eg. that of an *ExecutionLimitError
+	MaxExecutions int
+	MaxRUs        float64       // checked between executions
+	Timeout       time.Duration // checked between executions
+}
+
+// ExecutionLimitError - returned by ExecuteUntilDone when a limit stops it before the procedure is done, the
+// continuation resumes it
+type ExecutionLimitError struct {
+	Limit         string
+	Executions    int
+	RequestCharge float64
+	Elapsed       time.Duration
+	Continuation  json.RawMessage
+}
+
+// Implement Error function
+func (e *ExecutionLimitError) Error() string {
+	return fmt.Sprintf("procedure execution stopped by %s after %d executions, %.2f RUs and %s", e.Limit, e.Executions, e.RequestCharge, e.Elapsed)
+}
+
+// executionPage - the continuation a bounded procedure sets along its response
+type executionPage struct {
+	Continuation json.RawMessage `json:"continuation"`
+}
+
+// ExecuteUntilDone - runs a procedure doing a bounded amount of work per execution again and again until it is
+// done. The procedure takes the params followed by a continuation, null on the first execution, and sets a body
+// with the continuation to pass to the next execution, null or missing once done. The body of every execution is
+// handed to fn when it is not nil, the executions stop at the first error it returns. Once a limit is hit it stops
+// with an *ExecutionLimitError.
+//
+//	function purge(tenant, continuation) {
+//		// delete documents until isAccepted returns false, then
+//		getContext().getResponse().setBody({deleted: n, continuation: token})
+//	}
+//
+//	err := client.ExecuteUntilDone(link, []interface{}{"t1"}, func(body json.RawMessage) error {
+//		...
+//	}, &gocosmosdb.ExecutionOptions{MaxRUs: 10000}, gocosmosdb.PartitionKey("t1"))
+func (c *CosmosDB) ExecuteUntilDone(link string, params []interface{}, fn func(body json.RawMessage) error, options *ExecutionOptions, opts ...CallOption) error {
+	if options == nil {
+		options = &ExecutionOptions{}
+	}
+	start := time.Now()
+	continuation := options.From
+	executions, charge := 0, 0.0
+	stop := func(limit string) error {
+		return &ExecutionLimitError{Limit: limit, Executions: executions, RequestCharge: charge, Elapsed: time.Since(start), Continuation: continuation}
+	}
+	for {
+		var body json.RawMessage
+		resp, err := c.ExecuteStoredProcedure(link, append(params[:len(params):len(params)], continuation), &body, opts...)
+		if err != nil {
+			return err
+		}
+		executions++
+		ru, _ := resp.GetRUs()
+		charge += ru
+		page := executionPage{}
+		if err = json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		continuation = page.Continuation
+		if fn != nil {
+			if err = fn(body); err != nil {
+				return err
+			}
+		}
+		switch {
+		case len(continuation) == 0 || bytes.Equal(continuation, []byte("null")):
+			return nil
+		case options.MaxExecutions > 0 && executions >= options.MaxExecutions:
+			return stop("MaxExecutions")
+		case options.MaxRUs > 0 && charge >= options.MaxRUs:
+			return stop("MaxRUs")
+		case options.Timeout > 0 && time.Since(start) > options.Timeout:
+			return stop("Timeout")
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package gocosmosdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// purgeServer - executes a procedure deleting one document per execution of the total left
+func purgeServer(total int, params *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		*params = append(*params, string(body))
+		var args []interface{}
+		json.Unmarshal(body, &args)
+		next := 1
+		if n, ok := args[len(args)-1].(float64); ok {
+			next = int(n) + 1
+		}
+		w.Header().Set(HeaderRequestCharge, "2.5")
+		if next == total {
+			fmt.Fprint(w, `{"deleted": 1}`)
+			return
+		}
+		fmt.Fprintf(w, `{"deleted": 1, "continuation": %d}`, next)
+	}))
+}
+
+func TestExecuteUntilDone(t *testing.T) {
+	assert := assert.New(t)
+	var params []string
+	s := purgeServer(3, &params)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	deleted := 0
+	err := client.ExecuteUntilDone("dbs/db/colls/coll/sprocs/purge", []interface{}{"t1"}, func(body json.RawMessage) error {
+		var page struct{ Deleted int }
+		json.Unmarshal(body, &page)
+		deleted += page.Deleted
+		return nil
+	}, nil)
+	assert.Nil(err)
+	assert.Equal(3, deleted)
+	assert.Equal([]string{`["t1",null]`, `["t1",1]`, `["t1",2]`}, params)
+}
+
+func TestExecuteUntilDoneLimits(t *testing.T) {
+	assert := assert.New(t)
+	var params []string
+	s := purgeServer(10, &params)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+	link := "dbs/db/colls/coll/sprocs/purge"
+
+	err := client.ExecuteUntilDone(link, []interface{}{"t1"}, nil, &ExecutionOptions{MaxRUs: 5})
+	limit, ok := err.(*ExecutionLimitError)
+	assert.True(ok)
+	assert.Equal("MaxRUs", limit.Limit)
+	assert.Equal(2, limit.Executions)
+	assert.Equal(5.0, limit.RequestCharge)
+	assert.Equal(json.RawMessage("2"), limit.Continuation)
+
+	// resumes from the continuation of the limit
+	params = nil
+	err = client.ExecuteUntilDone(link, []interface{}{"t1"}, nil, &ExecutionOptions{From: limit.Continuation, MaxExecutions: 3})
+	assert.Equal("MaxExecutions", err.(*ExecutionLimitError).Limit)
+	assert.Equal([]string{`["t1",2]`, `["t1",3]`, `["t1",4]`}, params)
+
+	stopped := fmt.Errorf("stopped")
+	err = client.ExecuteUntilDone(link, []interface{}{"t1"}, func(json.RawMessage) error { return stopped }, nil)
+	assert.Equal(stopped, err)
+}
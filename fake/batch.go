@@ -0,0 +1,159 @@
+package fake
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/intwinelabs/gocosmosdb"
+)
+
+// SetPartitionKey - partitions a collection eg. "dbs/db/colls/coll" by a path eg. "/tenantId", so the operations
+// of a batch only see the documents of its partition key. Ids stay unique across the collection in the fake.
+func (s *Server) SetPartitionKey(coll, path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pkPaths[normalizeColl(coll)] = path
+}
+
+// batchResult - the result of a batch operation, as sent by the service
+type batchResult struct {
+	StatusCode    int                    `json:"statusCode"`
+	RequestCharge float64                `json:"requestCharge"`
+	ETag          string                 `json:"eTag,omitempty"`
+	ResourceBody  map[string]interface{} `json:"resourceBody,omitempty"`
+}
+
+// batch - runs the operations of a transactional batch on a copy of the collection, which replaces the collection
+// only when every operation succeeded. A rolled back batch replies 207 with the status of the failed operation and
+// 424 for the others.
+func (s *Server) batch(w http.ResponseWriter, r *http.Request, coll string) {
+	var ops []gocosmosdb.BatchOperation
+	if err := json.NewDecoder(r.Body).Decode(&ops); err != nil {
+		s.fail(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+	if len(ops) == 0 || len(ops) > gocosmosdb.MaxBatchOperations {
+		s.fail(w, http.StatusBadRequest, "BadRequest", "a batch must have between 1 and 100 operations")
+		return
+	}
+	var pk []interface{}
+	if err := json.Unmarshal([]byte(r.Header.Get(gocosmosdb.HeaderPartitionKey)), &pk); err != nil || len(pk) != 1 {
+		s.fail(w, http.StatusBadRequest, "BadRequest", "a batch needs the partition key of its operations")
+		return
+	}
+	m, _ := w.(*meteredWriter)
+	c := s.collection(coll).clone()
+	results := make([]batchResult, len(ops))
+	failed := -1
+	for i, op := range ops {
+		results[i] = s.batchOperation(c, coll, pk[0], op)
+		kind := OpWrite
+		switch {
+		case results[i].StatusCode >= http.StatusBadRequest:
+			kind = OpError
+		case op.OperationType == gocosmosdb.BatchRead:
+			kind = OpRead
+		case op.OperationType == gocosmosdb.BatchDelete:
+			kind = OpDelete
+		}
+		data, _ := json.Marshal(results[i].ResourceBody)
+		results[i].RequestCharge = s.charge(Operation{Kind: kind, Bytes: len(data)})
+		if m != nil {
+			m.batch = append(m.batch, results[i].RequestCharge)
+		}
+		if kind == OpError {
+			failed = i
+			break
+		}
+	}
+	if failed < 0 {
+		s.colls[coll] = c
+		s.reply(w, http.StatusOK, results)
+		return
+	}
+	for i := range results {
+		if i != failed {
+			results[i] = batchResult{StatusCode: http.StatusFailedDependency}
+		}
+	}
+	s.reply(w, http.StatusMultiStatus, results)
+}
+
+// batchOperation - runs an operation of a batch on the copy of a collection, the lock must be held
+func (s *Server) batchOperation(c *collection, coll string, pk interface{}, op gocosmosdb.BatchOperation) batchResult {
+	id := op.ID
+	var doc map[string]interface{}
+	switch op.OperationType {
+	case gocosmosdb.BatchCreate, gocosmosdb.BatchUpsert, gocosmosdb.BatchReplace:
+		doc, _ = op.ResourceBody.(map[string]interface{})
+		if docID, ok := doc["id"].(string); !ok || docID == "" || (id != "" && docID != id) {
+			return batchResult{StatusCode: http.StatusBadRequest}
+		}
+		id = doc["id"].(string)
+		if !s.inPartition(coll, doc, pk) {
+			return batchResult{StatusCode: http.StatusBadRequest}
+		}
+	case gocosmosdb.BatchRead, gocosmosdb.BatchDelete:
+	default:
+		return batchResult{StatusCode: http.StatusBadRequest}
+	}
+
+	existing, exists := c.docs[id]
+	// a document of another partition key is not visible to the batch
+	exists = exists && s.inPartition(coll, existing, pk)
+	switch {
+	case op.OperationType == gocosmosdb.BatchCreate && exists:
+		return batchResult{StatusCode: http.StatusConflict}
+	case op.OperationType == gocosmosdb.BatchCreate || op.OperationType == gocosmosdb.BatchUpsert:
+		if _, taken := c.docs[id]; taken && !exists {
+			return batchResult{StatusCode: http.StatusConflict}
+		}
+	case !exists:
+		return batchResult{StatusCode: http.StatusNotFound}
+	}
+	if exists && op.IfMatch != "" && op.IfMatch != existing["_etag"] {
+		return batchResult{StatusCode: http.StatusPreconditionFailed}
+	}
+
+	switch op.OperationType {
+	case gocosmosdb.BatchRead:
+		return batchResult{StatusCode: http.StatusOK, ETag: existing["_etag"].(string), ResourceBody: existing}
+	case gocosmosdb.BatchDelete:
+		c.remove(id)
+		return batchResult{StatusCode: http.StatusNoContent}
+	}
+	status := http.StatusCreated
+	if exists {
+		status = http.StatusOK
+	}
+	doc = c.put(s.stamp(coll, doc))
+	return batchResult{StatusCode: status, ETag: doc["_etag"].(string), ResourceBody: doc}
+}
+
+// inPartition - reports whether a document has the partition key, always when the collection is not partitioned
+func (s *Server) inPartition(coll string, doc map[string]interface{}, pk interface{}) bool {
+	path, ok := s.pkPaths[coll]
+	if !ok {
+		return true
+	}
+	var value interface{} = doc
+	for _, field := range strings.Split(strings.Trim(path, "/"), "/") {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		value = obj[field]
+	}
+	return reflect.DeepEqual(value, pk)
+}
+
+// clone - copies a collection for a batch to run on, documents are replaced rather than modified when written
+func (c *collection) clone() *collection {
+	docs := make(map[string]map[string]interface{}, len(c.docs))
+	for id, doc := range c.docs {
+		docs[id] = doc
+	}
+	return &collection{ids: append([]string(nil), c.ids...), docs: docs}
+}
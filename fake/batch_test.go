@@ -0,0 +1,57 @@
+package fake
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/intwinelabs/gocosmosdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerBatch(t *testing.T) {
+	assert := assert.New(t)
+	s := NewServer()
+	defer s.Close()
+	s.SetPartitionKey("dbs/db/colls/orders", "/tenant")
+	client := gocosmosdb.New(s.URL, gocosmosdb.Config{MasterKey: MasterKey}, log)
+	coll := "dbs/db/colls/orders/"
+	assert.Nil(s.Insert(coll,
+		map[string]interface{}{"id": "stock", "tenant": "t1", "count": 3},
+		map[string]interface{}{"id": "other", "tenant": "t2"},
+	))
+	stock := s.Documents(coll)[0]
+
+	results, err := client.ExecuteBatch(gocosmosdb.NewTransactionalBatch(coll, "t1").
+		Create(map[string]interface{}{"id": "order", "tenant": "t1"}).
+		Replace("stock", map[string]interface{}{"id": "stock", "tenant": "t1", "count": 2}).IfMatch(stock["_etag"].(string)).
+		Read("stock"))
+	assert.Nil(err)
+	assert.Len(results, 3)
+	assert.Equal(http.StatusCreated, results[0].StatusCode)
+	assert.Equal(http.StatusOK, results[1].StatusCode)
+	assert.JSONEq(string(results[1].ResourceBody), string(results[2].ResourceBody))
+	assert.Equal(5.0, results[0].RequestCharge)
+	assert.Equal(11.0, s.Consumed(coll))
+	assert.Len(s.Documents(coll), 3)
+
+	// the stale etag fails the replace and rolls back the delete before it
+	results, err = client.ExecuteBatch(gocosmosdb.NewTransactionalBatch(coll, "t1").
+		Delete("order").
+		Replace("stock", map[string]interface{}{"id": "stock", "tenant": "t1", "count": 1}).IfMatch(stock["_etag"].(string)).
+		Upsert(map[string]interface{}{"id": "note", "tenant": "t1"}))
+	assert.True(errors.Is(err, gocosmosdb.ErrPreconditionFailed))
+	assert.Equal([]int{http.StatusFailedDependency, http.StatusPreconditionFailed, http.StatusFailedDependency},
+		[]int{results[0].StatusCode, results[1].StatusCode, results[2].StatusCode})
+	assert.Len(s.Documents(coll), 3)
+	assert.Equal(2.0, s.Documents(coll)[0]["count"])
+
+	// documents of another partition key are out of reach
+	_, err = client.ExecuteBatch(gocosmosdb.NewTransactionalBatch(coll, "t1").Read("other"))
+	assert.True(errors.Is(err, gocosmosdb.ErrNotFound))
+	_, err = client.ExecuteBatch(gocosmosdb.NewTransactionalBatch(coll, "t1").Create(map[string]interface{}{"id": "x", "tenant": "t2"}))
+	var batchErr *gocosmosdb.BatchError
+	assert.True(errors.As(err, &batchErr))
+	assert.Equal(http.StatusBadRequest, batchErr.StatusCode)
+	assert.Len(s.Documents(coll), 3)
+}
@@ -0,0 +1,1019 @@
+package fake
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// undefined - the value of missing properties and of expressions on mismatched types, unlike null it is
+// dropped from projections and fails every comparison
+type undefinedValue struct{}
+
+var undefined = undefinedValue{}
+
+// Query - a parsed query of the supported CosmosDB SQL subset:
+//
+//	SELECT [TOP n] * | VALUE expr | expr [AS name], ... FROM alias [WHERE expr] [ORDER BY expr [ASC|DESC], ...] [OFFSET n LIMIT n]
+//
+// Expressions support property paths, literals, @parameters, arithmetic, comparisons, AND, OR, NOT, IN, BETWEEN
+// and the functions IS_DEFINED, IS_NULL, CONTAINS, STARTSWITH, ENDSWITH, LOWER, UPPER, LENGTH, ARRAY_CONTAINS
+// and ARRAY_LENGTH.
+type Query struct {
+	top     int // -1 without TOP
+	star    bool
+	value   expr
+	fields  []field
+	alias   string
+	where   expr
+	orderBy []orderItem
+	offset  int
+	limit   int // -1 without LIMIT
+}
+
+type field struct {
+	name string
+	expr expr
+}
+
+type orderItem struct {
+	expr expr
+	desc bool
+}
+
+// ParseQuery - parses a query, failing on syntax outside the supported subset
+func ParseQuery(sql string) (*Query, error) {
+	tokens, err := tokenize(sql)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	q, err := p.query()
+	if err != nil {
+		return nil, fmt.Errorf("query %q: %s", sql, err)
+	}
+	return q, nil
+}
+
+// Run - returns the documents matching the query in order, projected by its select list
+func (q *Query) Run(docs []map[string]interface{}, params map[string]interface{}) ([]interface{}, error) {
+	env := &env{alias: q.alias, params: params}
+	matched := []map[string]interface{}{}
+	for _, doc := range docs {
+		env.doc = doc
+		if q.where != nil {
+			ok, err := q.where.eval(env)
+			if err != nil {
+				return nil, err
+			}
+			if ok != true {
+				continue
+			}
+		}
+		matched = append(matched, doc)
+	}
+	if len(q.orderBy) > 0 {
+		keys := make([][]interface{}, len(matched))
+		for i, doc := range matched {
+			env.doc = doc
+			for _, item := range q.orderBy {
+				v, err := item.expr.eval(env)
+				if err != nil {
+					return nil, err
+				}
+				keys[i] = append(keys[i], v)
+			}
+		}
+		idx := make([]int, len(matched))
+		for i := range idx {
+			idx[i] = i
+		}
+		sort.SliceStable(idx, func(a, b int) bool {
+			for k, item := range q.orderBy {
+				c := order(keys[idx[a]][k], keys[idx[b]][k])
+				if c == 0 {
+					continue
+				}
+				if item.desc {
+					return c > 0
+				}
+				return c < 0
+			}
+			return false
+		})
+		sorted := make([]map[string]interface{}, len(matched))
+		for i, j := range idx {
+			sorted[i] = matched[j]
+		}
+		matched = sorted
+	}
+	if q.offset >= len(matched) {
+		matched = nil
+	} else if q.offset > 0 {
+		matched = matched[q.offset:]
+	}
+	if q.limit >= 0 && q.limit < len(matched) {
+		matched = matched[:q.limit]
+	}
+	if q.top >= 0 && q.top < len(matched) {
+		matched = matched[:q.top]
+	}
+
+	results := []interface{}{}
+	for _, doc := range matched {
+		env.doc = doc
+		switch {
+		case q.star:
+			results = append(results, doc)
+		case q.value != nil:
+			v, err := q.value.eval(env)
+			if err != nil {
+				return nil, err
+			}
+			if v != undefined {
+				results = append(results, v)
+			}
+		default:
+			obj := map[string]interface{}{}
+			for _, f := range q.fields {
+				v, err := f.expr.eval(env)
+				if err != nil {
+					return nil, err
+				}
+				if v != undefined {
+					obj[f.name] = v
+				}
+			}
+			results = append(results, obj)
+		}
+	}
+	return results, nil
+}
+
+// env - what expressions are evaluated against
+type env struct {
+	alias  string
+	doc    map[string]interface{}
+	params map[string]interface{}
+}
+
+type expr interface {
+	eval(e *env) (interface{}, error)
+}
+
+type literal struct{ v interface{} }
+
+func (l literal) eval(e *env) (interface{}, error) { return l.v, nil }
+
+type param struct{ name string }
+
+func (p param) eval(e *env) (interface{}, error) {
+	v, ok := e.params[p.name]
+	if !ok {
+		return nil, fmt.Errorf("parameter %s is not defined", p.name)
+	}
+	return v, nil
+}
+
+// path - a property path from the alias, segments are strings or float64 array indexes
+type path struct{ segments []interface{} }
+
+func (p path) eval(e *env) (interface{}, error) {
+	var v interface{} = e.doc
+	for _, seg := range p.segments {
+		switch s := seg.(type) {
+		case string:
+			obj, ok := v.(map[string]interface{})
+			if !ok {
+				return undefined, nil
+			}
+			if v, ok = obj[s]; !ok {
+				return undefined, nil
+			}
+		case float64:
+			arr, ok := v.([]interface{})
+			if !ok || int(s) < 0 || int(s) >= len(arr) {
+				return undefined, nil
+			}
+			v = arr[int(s)]
+		}
+	}
+	return v, nil
+}
+
+// name - the property name a projected path is returned under
+func (p path) name() string {
+	for i := len(p.segments) - 1; i >= 0; i-- {
+		if s, ok := p.segments[i].(string); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+type not struct{ x expr }
+
+func (n not) eval(e *env) (interface{}, error) {
+	v, err := n.x.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return undefined, nil
+	}
+	return !b, nil
+}
+
+type neg struct{ x expr }
+
+func (n neg) eval(e *env) (interface{}, error) {
+	v, err := n.x.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	f, ok := v.(float64)
+	if !ok {
+		return undefined, nil
+	}
+	return -f, nil
+}
+
+type binary struct {
+	op   string
+	l, r expr
+}
+
+func (b binary) eval(e *env) (interface{}, error) {
+	l, err := b.l.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	// AND and OR short circuit on false and true, anything not a boolean is undefined
+	switch b.op {
+	case "AND":
+		if l == false {
+			return false, nil
+		}
+	case "OR":
+		if l == true {
+			return true, nil
+		}
+	}
+	r, err := b.r.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	switch b.op {
+	case "AND":
+		if r == false {
+			return false, nil
+		}
+		if l == true && r == true {
+			return true, nil
+		}
+		return undefined, nil
+	case "OR":
+		if r == true {
+			return true, nil
+		}
+		if l == false && r == false {
+			return false, nil
+		}
+		return undefined, nil
+	case "=", "!=", "<>", "<", "<=", ">", ">=":
+		c, ok := compare(l, r)
+		if !ok {
+			return undefined, nil
+		}
+		switch b.op {
+		case "=":
+			return c == 0, nil
+		case "!=", "<>":
+			return c != 0, nil
+		case "<":
+			return c < 0, nil
+		case "<=":
+			return c <= 0, nil
+		case ">":
+			return c > 0, nil
+		default:
+			return c >= 0, nil
+		}
+	case "||":
+		ls, lok := l.(string)
+		rs, rok := r.(string)
+		if !lok || !rok {
+			return undefined, nil
+		}
+		return ls + rs, nil
+	}
+	lf, lok := l.(float64)
+	rf, rok := r.(float64)
+	if !lok || !rok {
+		return undefined, nil
+	}
+	switch b.op {
+	case "+":
+		return lf + rf, nil
+	case "-":
+		return lf - rf, nil
+	case "*":
+		return lf * rf, nil
+	case "/":
+		return lf / rf, nil
+	default:
+		return math.Mod(lf, rf), nil
+	}
+}
+
+type in struct {
+	x    expr
+	list []expr
+}
+
+func (i in) eval(e *env) (interface{}, error) {
+	v, err := i.x.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range i.list {
+		w, err := item.eval(e)
+		if err != nil {
+			return nil, err
+		}
+		if c, ok := compare(v, w); ok && c == 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type call struct {
+	name string
+	args []expr
+}
+
+func (c call) eval(e *env) (interface{}, error) {
+	args := make([]interface{}, len(c.args))
+	for i, arg := range c.args {
+		v, err := arg.eval(e)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	str := func(i int) (string, bool) {
+		s, ok := args[i].(string)
+		return s, ok
+	}
+	switch c.name {
+	case "IS_DEFINED":
+		return args[0] != undefined, nil
+	case "IS_NULL":
+		return args[0] == nil, nil
+	case "CONTAINS", "STARTSWITH", "ENDSWITH":
+		s, ok1 := str(0)
+		sub, ok2 := str(1)
+		if !ok1 || !ok2 {
+			return undefined, nil
+		}
+		if len(args) > 2 && args[2] == true {
+			s, sub = strings.ToLower(s), strings.ToLower(sub)
+		}
+		switch c.name {
+		case "CONTAINS":
+			return strings.Contains(s, sub), nil
+		case "STARTSWITH":
+			return strings.HasPrefix(s, sub), nil
+		default:
+			return strings.HasSuffix(s, sub), nil
+		}
+	case "LOWER", "UPPER", "LENGTH":
+		s, ok := str(0)
+		if !ok {
+			return undefined, nil
+		}
+		switch c.name {
+		case "LOWER":
+			return strings.ToLower(s), nil
+		case "UPPER":
+			return strings.ToUpper(s), nil
+		default:
+			return float64(len([]rune(s))), nil
+		}
+	case "ARRAY_CONTAINS":
+		arr, ok := args[0].([]interface{})
+		if !ok {
+			return undefined, nil
+		}
+		for _, item := range arr {
+			if c, ok := compare(item, args[1]); ok && c == 0 {
+				return true, nil
+			}
+		}
+		return false, nil
+	default: // ARRAY_LENGTH
+		arr, ok := args[0].([]interface{})
+		if !ok {
+			return undefined, nil
+		}
+		return float64(len(arr)), nil
+	}
+}
+
+// functions - the supported functions and their argument counts
+var functions = map[string][2]int{
+	"IS_DEFINED":     {1, 1},
+	"IS_NULL":        {1, 1},
+	"CONTAINS":       {2, 3},
+	"STARTSWITH":     {2, 3},
+	"ENDSWITH":       {2, 3},
+	"LOWER":          {1, 1},
+	"UPPER":          {1, 1},
+	"LENGTH":         {1, 1},
+	"ARRAY_CONTAINS": {2, 2},
+	"ARRAY_LENGTH":   {1, 1},
+}
+
+// compare - compares values of the same primitive type, ok is false for any other pair
+func compare(a, b interface{}) (int, bool) {
+	switch x := a.(type) {
+	case nil:
+		if b == nil {
+			return 0, true
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			switch {
+			case x == y:
+				return 0, true
+			case !x:
+				return -1, true
+			default:
+				return 1, true
+			}
+		}
+	case float64:
+		if y, ok := b.(float64); ok {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			default:
+				return 0, true
+			}
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	}
+	return 0, false
+}
+
+// order - orders any two values the way ORDER BY does, undefined < null < booleans < numbers < strings
+func order(a, b interface{}) int {
+	rank := func(v interface{}) int {
+		switch v.(type) {
+		case undefinedValue:
+			return 0
+		case nil:
+			return 1
+		case bool:
+			return 2
+		case float64:
+			return 3
+		case string:
+			return 4
+		default:
+			return 5
+		}
+	}
+	if ra, rb := rank(a), rank(b); ra != rb {
+		return ra - rb
+	}
+	c, _ := compare(a, b)
+	return c
+}
+
+// token kinds
+const (
+	tIdent = iota
+	tKeyword
+	tNumber
+	tString
+	tParam
+	tOp
+	tEOF
+)
+
+type token struct {
+	kind int
+	text string // upper cased for keywords
+	raw  string // as written
+	num  float64
+}
+
+var keywords = map[string]bool{
+	"SELECT": true, "TOP": true, "VALUE": true, "FROM": true, "WHERE": true, "ORDER": true, "BY": true,
+	"ASC": true, "DESC": true, "AND": true, "OR": true, "NOT": true, "AS": true, "IN": true, "BETWEEN": true,
+	"TRUE": true, "FALSE": true, "NULL": true, "UNDEFINED": true, "OFFSET": true, "LIMIT": true, "JOIN": true,
+}
+
+// tokenize - splits a query into tokens, keywords are upper cased
+func tokenize(sql string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(sql)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsLetter(c) || c == '_' || c == '$' || c == '@':
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_' || runes[j] == '$') {
+				j++
+			}
+			text := string(runes[i:j])
+			switch {
+			case c == '@':
+				tokens = append(tokens, token{kind: tParam, text: text})
+			case keywords[strings.ToUpper(text)]:
+				tokens = append(tokens, token{kind: tKeyword, text: strings.ToUpper(text), raw: text})
+			default:
+				tokens = append(tokens, token{kind: tIdent, text: text})
+			}
+			i = j
+		case unicode.IsDigit(c):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.' || runes[j] == 'e' || runes[j] == 'E') {
+				j++
+			}
+			num, err := strconv.ParseFloat(string(runes[i:j]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("bad number %s", string(runes[i:j]))
+			}
+			tokens = append(tokens, token{kind: tNumber, text: string(runes[i:j]), num: num})
+			i = j
+		case c == '\'' || c == '"':
+			var sb strings.Builder
+			j := i + 1
+			for ; j < len(runes) && runes[j] != c; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+					switch runes[j] {
+					case 'n':
+						sb.WriteRune('\n')
+					case 't':
+						sb.WriteRune('\t')
+					default:
+						sb.WriteRune(runes[j])
+					}
+					continue
+				}
+				sb.WriteRune(runes[j])
+			}
+			if j == len(runes) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			tokens = append(tokens, token{kind: tString, text: sb.String()})
+			i = j + 1
+		default:
+			op := string(c)
+			if i+1 < len(runes) {
+				if two := string(runes[i : i+2]); two == "!=" || two == "<>" || two == "<=" || two == ">=" || two == "||" {
+					op = two
+				}
+			}
+			if !strings.Contains("=!<>()[],.*+-/%|", string(c)) || op == "!" || op == "|" {
+				return nil, fmt.Errorf("unexpected %q", op)
+			}
+			tokens = append(tokens, token{kind: tOp, text: op})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: tEOF}), nil
+}
+
+// parser - a recursive descent parser over the tokens of a query
+type parser struct {
+	tokens []token
+	pos    int
+	alias  string
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept - consumes the next token when it is the keyword or operator text
+func (p *parser) accept(text string) bool {
+	t := p.peek()
+	if (t.kind == tKeyword || t.kind == tOp) && t.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		return fmt.Errorf("expected %s near %s", text, p.describe())
+	}
+	return nil
+}
+
+func (p *parser) describe() string {
+	if t := p.peek(); t.kind != tEOF {
+		return fmt.Sprintf("%q", t.text)
+	}
+	return "end of query"
+}
+
+func (p *parser) integer() (int, error) {
+	t := p.next()
+	if t.kind != tNumber || t.num != math.Trunc(t.num) || t.num < 0 {
+		return 0, fmt.Errorf("expected a positive integer near %q", t.text)
+	}
+	return int(t.num), nil
+}
+
+func (p *parser) query() (*Query, error) {
+	q := &Query{top: -1, limit: -1}
+	if err := p.expect("SELECT"); err != nil {
+		return nil, err
+	}
+	if p.accept("TOP") {
+		top, err := p.integer()
+		if err != nil {
+			return nil, err
+		}
+		q.top = top
+	}
+	// the select list refers to the alias declared after it, parse it once the alias is known
+	selectStart := p.pos
+	depth := 0
+	for t := p.peek(); !(t.kind == tKeyword && t.text == "FROM" && depth == 0); t = p.peek() {
+		switch {
+		case t.kind == tEOF:
+			return nil, fmt.Errorf("expected FROM")
+		case t.text == "(" || t.text == "[":
+			depth++
+		case t.text == ")" || t.text == "]":
+			depth--
+		}
+		p.pos++
+	}
+	selectEnd := p.pos
+	p.next()
+	alias := p.next()
+	if alias.kind != tIdent {
+		return nil, fmt.Errorf("expected a collection alias after FROM")
+	}
+	q.alias, p.alias = alias.text, alias.text
+	if p.peek().kind == tIdent {
+		// FROM root r
+		q.alias, p.alias = p.peek().text, p.next().text
+	}
+	if t := p.peek(); t.kind == tKeyword && t.text == "JOIN" {
+		return nil, fmt.Errorf("JOIN is not supported by the fake")
+	}
+	if p.accept("WHERE") {
+		where, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		q.where = where
+	}
+	if p.accept("ORDER") {
+		if err := p.expect("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			e, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			item := orderItem{expr: e}
+			if p.accept("DESC") {
+				item.desc = true
+			} else {
+				p.accept("ASC")
+			}
+			q.orderBy = append(q.orderBy, item)
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+	if p.accept("OFFSET") {
+		offset, err := p.integer()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expect("LIMIT"); err != nil {
+			return nil, err
+		}
+		limit, err := p.integer()
+		if err != nil {
+			return nil, err
+		}
+		q.offset, q.limit = offset, limit
+	}
+	if p.peek().kind != tEOF {
+		return nil, fmt.Errorf("unexpected %s", p.describe())
+	}
+
+	// back to the select list
+	end := p.pos
+	p.tokens[selectEnd] = token{kind: tEOF}
+	defer func() { p.tokens[selectEnd] = token{kind: tKeyword, text: "FROM"} }()
+	p.pos = selectStart
+	if err := p.selectList(q); err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tEOF {
+		return nil, fmt.Errorf("unexpected %s", p.describe())
+	}
+	p.pos = end
+	return q, nil
+}
+
+func (p *parser) selectList(q *Query) error {
+	if p.accept("*") {
+		q.star = true
+		return nil
+	}
+	if p.accept("VALUE") {
+		v, err := p.expr()
+		q.value = v
+		return err
+	}
+	for i := 1; ; i++ {
+		e, err := p.expr()
+		if err != nil {
+			return err
+		}
+		f := field{name: fmt.Sprintf("$%d", i), expr: e}
+		if pa, ok := e.(path); ok && pa.name() != "" {
+			f.name = pa.name()
+		}
+		if p.accept("AS") {
+			name := p.next()
+			if name.kind != tIdent {
+				return fmt.Errorf("expected a name after AS")
+			}
+			f.name = name.text
+		}
+		q.fields = append(q.fields, f)
+		if !p.accept(",") {
+			return nil
+		}
+	}
+}
+
+func (p *parser) expr() (expr, error) {
+	return p.or()
+}
+
+func (p *parser) or() (expr, error) {
+	l, err := p.and()
+	for err == nil && p.accept("OR") {
+		var r expr
+		if r, err = p.and(); err == nil {
+			l = binary{op: "OR", l: l, r: r}
+		}
+	}
+	return l, err
+}
+
+func (p *parser) and() (expr, error) {
+	l, err := p.not()
+	for err == nil && p.accept("AND") {
+		var r expr
+		if r, err = p.not(); err == nil {
+			l = binary{op: "AND", l: l, r: r}
+		}
+	}
+	return l, err
+}
+
+func (p *parser) not() (expr, error) {
+	if p.accept("NOT") {
+		x, err := p.not()
+		return not{x: x}, err
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (expr, error) {
+	l, err := p.additive()
+	if err != nil {
+		return nil, err
+	}
+	negate := p.accept("NOT")
+	switch {
+	case p.accept("IN"):
+		if err = p.expect("("); err != nil {
+			return nil, err
+		}
+		list := []expr{}
+		for {
+			item, err := p.additive()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+			if !p.accept(",") {
+				break
+			}
+		}
+		if err = p.expect(")"); err != nil {
+			return nil, err
+		}
+		return negated(in{x: l, list: list}, negate), nil
+	case p.accept("BETWEEN"):
+		lo, err := p.additive()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expect("AND"); err != nil {
+			return nil, err
+		}
+		hi, err := p.additive()
+		if err != nil {
+			return nil, err
+		}
+		return negated(binary{op: "AND", l: binary{op: ">=", l: l, r: lo}, r: binary{op: "<=", l: l, r: hi}}, negate), nil
+	case negate:
+		return nil, fmt.Errorf("expected IN or BETWEEN after NOT near %s", p.describe())
+	}
+	for _, op := range []string{"=", "!=", "<>", "<=", ">=", "<", ">"} {
+		if p.accept(op) {
+			r, err := p.additive()
+			return binary{op: op, l: l, r: r}, err
+		}
+	}
+	return l, nil
+}
+
+func negated(e expr, negate bool) expr {
+	if negate {
+		return not{x: e}
+	}
+	return e
+}
+
+func (p *parser) additive() (expr, error) {
+	l, err := p.multiplicative()
+	for err == nil {
+		op := p.peek().text
+		if p.peek().kind != tOp || (op != "+" && op != "-" && op != "||") {
+			break
+		}
+		p.next()
+		var r expr
+		if r, err = p.multiplicative(); err == nil {
+			l = binary{op: op, l: l, r: r}
+		}
+	}
+	return l, err
+}
+
+func (p *parser) multiplicative() (expr, error) {
+	l, err := p.unary()
+	for err == nil {
+		op := p.peek().text
+		if p.peek().kind != tOp || (op != "*" && op != "/" && op != "%") {
+			break
+		}
+		p.next()
+		var r expr
+		if r, err = p.unary(); err == nil {
+			l = binary{op: op, l: l, r: r}
+		}
+	}
+	return l, err
+}
+
+func (p *parser) unary() (expr, error) {
+	if p.accept("-") {
+		x, err := p.unary()
+		return neg{x: x}, err
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tNumber:
+		return literal{t.num}, nil
+	case tString:
+		return literal{t.text}, nil
+	case tParam:
+		return param{name: t.text}, nil
+	case tKeyword:
+		switch t.text {
+		case "TRUE":
+			return literal{true}, nil
+		case "FALSE":
+			return literal{false}, nil
+		case "NULL":
+			return literal{nil}, nil
+		case "UNDEFINED":
+			return literal{undefined}, nil
+		}
+	case tOp:
+		if t.text == "(" {
+			e, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			return e, p.expect(")")
+		}
+	case tIdent:
+		if p.accept("(") {
+			return p.call(t.text)
+		}
+		if t.text != p.alias {
+			return nil, fmt.Errorf("%s is not the collection alias %s", t.text, p.alias)
+		}
+		return p.path()
+	}
+	return nil, fmt.Errorf("unexpected %q", t.text)
+}
+
+func (p *parser) call(name string) (expr, error) {
+	name = strings.ToUpper(name)
+	arity, ok := functions[name]
+	if !ok {
+		return nil, fmt.Errorf("function %s is not supported by the fake", name)
+	}
+	args := []expr{}
+	if !p.accept(")") {
+		for {
+			arg, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if !p.accept(",") {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+	if len(args) < arity[0] || len(args) > arity[1] {
+		return nil, fmt.Errorf("function %s takes %d to %d arguments", name, arity[0], arity[1])
+	}
+	return call{name: name, args: args}, nil
+}
+
+func (p *parser) path() (expr, error) {
+	pa := path{}
+	for {
+		switch {
+		case p.accept("."):
+			t := p.next()
+			if t.kind != tIdent && t.kind != tKeyword {
+				return nil, fmt.Errorf("expected a property name near %q", t.text)
+			}
+			if t.kind == tKeyword {
+				// properties named like keywords eg. r.value
+				t.text = t.raw
+			}
+			pa.segments = append(pa.segments, t.text)
+		case p.accept("["):
+			t := p.next()
+			switch t.kind {
+			case tString:
+				pa.segments = append(pa.segments, t.text)
+			case tNumber:
+				pa.segments = append(pa.segments, t.num)
+			default:
+				return nil, fmt.Errorf("expected a property name or index near %q", t.text)
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+		default:
+			return pa, nil
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package fake
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testDocs = []map[string]interface{}{}
+
+func init() {
+	json.Unmarshal([]byte(`[
+		{"id": "1", "name": "Ada", "age": 36, "tags": ["math"], "address": {"city": "London"}},
+		{"id": "2", "name": "Grace", "age": 85, "tags": ["navy", "cobol"], "address": {"city": "New York"}},
+		{"id": "3", "name": "Alan", "age": 41, "tags": [], "value": 7},
+		{"id": "4", "name": "Edsger", "age": 72, "address": {"city": "Austin"}}
+	]`), &testDocs)
+}
+
+func run(t *testing.T, sql string, params map[string]interface{}) []interface{} {
+	q, err := ParseQuery(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := q.Run(testDocs, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return results
+}
+
+func ids(results []interface{}) []string {
+	ret := []string{}
+	for _, r := range results {
+		ret = append(ret, r.(map[string]interface{})["id"].(string))
+	}
+	return ret
+}
+
+func TestQueryWhere(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal([]string{"2", "4"}, ids(run(t, "SELECT * FROM root r WHERE r.age > 50", nil)))
+	assert.Equal([]string{"1", "3"}, ids(run(t, "select * from c where c.age >= @min and c.age < @max", map[string]interface{}{"@min": 30.0, "@max": 50.0})))
+	assert.Equal([]string{"1", "2"}, ids(run(t, "SELECT * FROM c WHERE c.address.city = 'London' OR c['address']['city'] = \"New York\"", nil)))
+	assert.Equal([]string{"3", "4"}, ids(run(t, "SELECT * FROM c WHERE NOT (c.id IN ('1', '2'))", nil)))
+	assert.Equal([]string{"1", "3"}, ids(run(t, "SELECT * FROM c WHERE c.age BETWEEN 36 AND 41", nil)))
+	assert.Equal([]string{"2"}, ids(run(t, "SELECT * FROM c WHERE ARRAY_CONTAINS(c.tags, 'cobol') AND STARTSWITH(c.name, 'gr', true)", nil)))
+	assert.Equal([]string{"3"}, ids(run(t, "SELECT * FROM c WHERE NOT IS_DEFINED(c.address)", nil)))
+	assert.Equal([]string{"3"}, ids(run(t, "SELECT * FROM c WHERE c.value = 7", nil)))
+	// comparing mismatched types is undefined and never matches, not even negated
+	assert.Empty(run(t, "SELECT * FROM c WHERE c.age = '36' OR NOT (c.age = '36')", nil))
+}
+
+func TestQuerySelectOrderTop(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal([]string{"2", "4", "3", "1"}, ids(run(t, "SELECT * FROM c ORDER BY c.age DESC", nil)))
+	assert.Equal([]string{"1", "3"}, ids(run(t, "SELECT TOP 2 * FROM c ORDER BY c.name", nil)))
+	assert.Equal([]string{"3", "4"}, ids(run(t, "SELECT * FROM c ORDER BY c.id OFFSET 2 LIMIT 5", nil)))
+	assert.Equal([]interface{}{"Ada", "Grace"}, run(t, "SELECT VALUE c.name FROM c WHERE c.age < 90 AND ARRAY_LENGTH(c.tags) > 0", nil))
+	assert.Equal([]interface{}{
+		map[string]interface{}{"name": "Ada", "city": "London", "next": 37.0},
+		map[string]interface{}{"name": "Alan", "next": 42.0},
+	}, run(t, "SELECT c.name, c.address.city, c.age + 1 AS next FROM c WHERE c.age < 50", nil))
+}
+
+func TestQueryErrors(t *testing.T) {
+	assert := assert.New(t)
+	for _, sql := range []string{
+		"SELECT * FROM",
+		"SELECT * FROM c WHERE",
+		"SELECT * FROM c JOIN t IN c.tags",
+		"SELECT * FROM c WHERE x.id = 1",
+		"SELECT * FROM c WHERE REGEXMATCH(c.name, 'A')",
+		"SELECT * FROM c WHERE c.name = 'Ada",
+		"SELECT TOP -1 * FROM c",
+	} {
+		_, err := ParseQuery(sql)
+		assert.NotNil(err, sql)
+	}
+	q, err := ParseQuery("SELECT * FROM c WHERE c.id = @id")
+	assert.Nil(err)
+	_, err = q.Run(testDocs, nil)
+	assert.Contains(err.Error(), "parameter @id is not defined")
+}
@@ -0,0 +1,362 @@
+// Package fake serves an in-memory CosmosDB SQL API for unit tests. It keeps the documents of any collection a
+// request names, and runs queries with an evaluator of a small CosmosDB SQL subset, see Query.
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/intwinelabs/gocosmosdb"
+)
+
+// MasterKey - any well formed key authorizes requests to the fake, this one is handy for the client config
+const MasterKey = "ZmFrZQ=="
+
+// Server - an in-memory CosmosDB, pass its URL to gocosmosdb.New
+//
+//	s := fake.NewServer()
+//	defer s.Close()
+//	client := gocosmosdb.New(s.URL, gocosmosdb.Config{MasterKey: fake.MasterKey}, log)
+type Server struct {
+	*httptest.Server
+	mu      sync.Mutex
+	colls   map[string]*collection
+	seq     int
+	clock   Clock
+	charge  ChargeFunc
+	budgets map[string]*budget
+	pkPaths map[string]string
+}
+
+// collection - the documents of a collection in the order they were first written
+type collection struct {
+	ids  []string
+	docs map[string]map[string]interface{}
+}
+
+// Error - the body of a failed request, as sent by the service
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewServer - starts a fake with no documents, on the system clock, charging DefaultCharge without throughput limits
+func NewServer() *Server {
+	s := &Server{colls: map[string]*collection{}, clock: systemClock{}, charge: DefaultCharge, budgets: map[string]*budget{}, pkPaths: map[string]string{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Insert - writes documents straight to a collection eg. "dbs/db/colls/coll", replacing any with the same id
+func (s *Server) Insert(coll string, docs ...interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, doc := range docs {
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		d := map[string]interface{}{}
+		if err = json.Unmarshal(data, &d); err != nil {
+			return err
+		}
+		if _, ok := d["id"].(string); !ok {
+			return fmt.Errorf("document has no string id")
+		}
+		s.write(normalizeColl(coll), d)
+	}
+	return nil
+}
+
+// Documents - returns the documents of a collection in the order they were first written, they must not be modified
+func (s *Server) Documents(coll string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.collection(normalizeColl(coll)).list()
+}
+
+// serve - routes the document requests of a collection, dbs/{db}/colls/{coll}/docs[/{id}]
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 5 || parts[0] != "dbs" || parts[2] != "colls" {
+		s.fail(w, http.StatusBadRequest, "BadRequest", "only the documents of collections are supported by the fake")
+		return
+	}
+	coll := strings.Join(parts[:4], "/")
+	id := ""
+	if len(parts) == 6 {
+		id = parts[5]
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.throttle(w, coll) {
+		return
+	}
+	m := &meteredWriter{ResponseWriter: w, s: s, coll: coll, op: Operation{Kind: OpRead}}
+	w = m
+	switch {
+	case id == "" && r.Method == http.MethodPost && r.Header.Get(gocosmosdb.HeaderIsQuery) == "true":
+		m.op = Operation{Kind: OpQuery, Scanned: len(s.collection(coll).ids)}
+	case id == "" && r.Method == http.MethodGet:
+		m.op = Operation{Kind: OpFeed, Scanned: len(s.collection(coll).ids)}
+	case r.Method == http.MethodPost || r.Method == http.MethodPut:
+		m.op.Kind = OpWrite
+	case r.Method == http.MethodDelete:
+		m.op.Kind = OpDelete
+	}
+	switch {
+	case parts[4] == "pkranges" && len(parts) == 5 && r.Method == http.MethodGet:
+		s.reply(w, http.StatusOK, map[string]interface{}{
+			"PartitionKeyRanges": []map[string]string{{"id": "0", "minInclusive": "", "maxExclusive": "FF"}},
+			"_count":             1,
+		})
+	case parts[4] != "docs" || len(parts) > 6:
+		s.fail(w, http.StatusBadRequest, "BadRequest", "only the documents of collections are supported by the fake")
+	case id == "" && r.Method == http.MethodPost && r.Header.Get(gocosmosdb.HeaderIsQuery) == "true":
+		s.query(w, r, coll)
+	case id == "" && r.Method == http.MethodPost && r.Header.Get(gocosmosdb.HeaderIsBatchRequest) == "true":
+		s.batch(w, r, coll)
+	case id == "" && r.Method == http.MethodPost:
+		s.create(w, r, coll)
+	case id == "" && r.Method == http.MethodGet:
+		s.page(w, r, s.collection(coll).list())
+	case id != "" && r.Method == http.MethodGet:
+		if doc, ok := s.collection(coll).docs[id]; ok {
+			s.reply(w, http.StatusOK, doc)
+			return
+		}
+		s.notFound(w)
+	case id != "" && r.Method == http.MethodPut:
+		s.replace(w, r, coll, id)
+	case id != "" && r.Method == http.MethodDelete:
+		if !s.collection(coll).remove(id) {
+			s.notFound(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s.fail(w, http.StatusMethodNotAllowed, "MethodNotAllowed", r.Method+" is not supported by the fake")
+	}
+}
+
+// create - creates or, with the upsert header, upserts a document
+func (s *Server) create(w http.ResponseWriter, r *http.Request, coll string) {
+	doc, ok := s.body(w, r)
+	if !ok {
+		return
+	}
+	id, _ := doc["id"].(string)
+	_, exists := s.collection(coll).docs[id]
+	if exists && r.Header.Get(gocosmosdb.HeaderUpsert) != "true" {
+		s.fail(w, http.StatusConflict, "Conflict", "Entity with the specified id already exists in the system.")
+		return
+	}
+	if exists && !s.matches(w, r, coll, id) {
+		return
+	}
+	status := http.StatusCreated
+	if exists {
+		status = http.StatusOK
+	}
+	s.reply(w, status, s.write(coll, doc))
+}
+
+// replace - replaces an existing document, honoring If-Match
+func (s *Server) replace(w http.ResponseWriter, r *http.Request, coll, id string) {
+	doc, ok := s.body(w, r)
+	if !ok {
+		return
+	}
+	if _, exists := s.collection(coll).docs[id]; !exists {
+		s.notFound(w)
+		return
+	}
+	if doc["id"] != id {
+		s.fail(w, http.StatusBadRequest, "BadRequest", "the id of the document does not match the link")
+		return
+	}
+	if !s.matches(w, r, coll, id) {
+		return
+	}
+	s.reply(w, http.StatusOK, s.write(coll, doc))
+}
+
+// query - runs a query over the documents of a collection
+func (s *Server) query(w http.ResponseWriter, r *http.Request, coll string) {
+	body := struct {
+		Query      string `json:"query"`
+		Parameters []struct {
+			Name  string      `json:"name"`
+			Value interface{} `json:"value"`
+		} `json:"parameters"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		s.fail(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+	q, err := ParseQuery(body.Query)
+	if err != nil {
+		s.fail(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+	params := map[string]interface{}{}
+	for _, p := range body.Parameters {
+		params[p.Name] = p.Value
+	}
+	results, err := q.Run(s.collection(coll).list(), params)
+	if err != nil {
+		s.fail(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+	s.page(w, r, results)
+}
+
+// page - replies with the page of results the max item count and continuation headers ask for
+func (s *Server) page(w http.ResponseWriter, r *http.Request, results interface{}) {
+	items := []interface{}{}
+	switch rs := results.(type) {
+	case []interface{}:
+		items = rs
+	case []map[string]interface{}:
+		for _, doc := range rs {
+			items = append(items, doc)
+		}
+	}
+	start, _ := strconv.Atoi(r.Header.Get(gocosmosdb.HeaderContinuation))
+	if start > len(items) {
+		start = len(items)
+	}
+	end := len(items)
+	// the service pages by 100 unless asked otherwise, the fake answers -1 with every result
+	size := 100
+	if v, err := strconv.Atoi(r.Header.Get(gocosmosdb.HeaderMaxItemCount)); err == nil && v > 0 {
+		size = v
+	} else if err == nil && v < 0 {
+		size = len(items)
+	}
+	if start+size < end {
+		end = start + size
+		w.Header().Set(gocosmosdb.HeaderContinuation, strconv.Itoa(end))
+	}
+	w.Header().Set(gocosmosdb.HeaderItemCount, strconv.Itoa(end-start))
+	s.reply(w, http.StatusOK, map[string]interface{}{"Documents": items[start:end], "_count": end - start})
+}
+
+// body - decodes the document of a write, failing the request when it is not a document with an id
+func (s *Server) body(w http.ResponseWriter, r *http.Request) (map[string]interface{}, bool) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		s.fail(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return nil, false
+	}
+	doc := map[string]interface{}{}
+	if err = json.Unmarshal(data, &doc); err != nil {
+		s.fail(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return nil, false
+	}
+	if id, ok := doc["id"].(string); !ok || id == "" {
+		s.fail(w, http.StatusBadRequest, "BadRequest", "The input content is invalid because the required properties - 'id; ' - are missing")
+		return nil, false
+	}
+	return doc, true
+}
+
+// matches - fails the request with 412 when its If-Match is not the etag of the document
+func (s *Server) matches(w http.ResponseWriter, r *http.Request, coll, id string) bool {
+	etag := r.Header.Get(gocosmosdb.HeaderIfMatch)
+	if etag == "" || etag == s.collection(coll).docs[id]["_etag"] {
+		return true
+	}
+	s.fail(w, http.StatusPreconditionFailed, "PreconditionFailed", "Operation cannot be performed because one of the specified precondition is not met.")
+	return false
+}
+
+// write - stores a document with fresh system properties, the lock must be held
+func (s *Server) write(coll string, doc map[string]interface{}) map[string]interface{} {
+	return s.collection(coll).put(s.stamp(coll, doc))
+}
+
+// stamp - sets fresh system properties on a document about to be written, the lock must be held
+func (s *Server) stamp(coll string, doc map[string]interface{}) map[string]interface{} {
+	s.seq++
+	doc["_rid"] = fmt.Sprintf("doc%d==", s.seq)
+	doc["_self"] = coll + "/docs/" + doc["id"].(string) + "/"
+	doc["_etag"] = fmt.Sprintf(`"%08x-0000-0000-0000-000000000000"`, s.seq)
+	doc["_ts"] = float64(s.clock.Now().Unix())
+	return doc
+}
+
+// collection - returns a collection, creating it on first use, the lock must be held
+func (s *Server) collection(coll string) *collection {
+	c, ok := s.colls[coll]
+	if !ok {
+		c = &collection{docs: map[string]map[string]interface{}{}}
+		s.colls[coll] = c
+	}
+	return c
+}
+
+func (c *collection) put(doc map[string]interface{}) map[string]interface{} {
+	id := doc["id"].(string)
+	if _, ok := c.docs[id]; !ok {
+		c.ids = append(c.ids, id)
+	}
+	c.docs[id] = doc
+	return doc
+}
+
+func (c *collection) list() []map[string]interface{} {
+	docs := make([]map[string]interface{}, 0, len(c.ids))
+	for _, id := range c.ids {
+		docs = append(docs, c.docs[id])
+	}
+	return docs
+}
+
+func (c *collection) remove(id string) bool {
+	if _, ok := c.docs[id]; !ok {
+		return false
+	}
+	delete(c.docs, id)
+	for i, existing := range c.ids {
+		if existing == id {
+			c.ids = append(c.ids[:i], c.ids[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+func (s *Server) reply(w http.ResponseWriter, status int, body interface{}) {
+	data, _ := json.Marshal(body)
+	if m, ok := w.(*meteredWriter); ok {
+		m.op.Bytes = len(data)
+	}
+	w.Header().Set(gocosmosdb.HeaderContentType, "application/json")
+	if doc, ok := body.(map[string]interface{}); ok {
+		if etag, ok := doc["_etag"].(string); ok {
+			w.Header().Set(gocosmosdb.HeaderETag, etag)
+		}
+	}
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+func (s *Server) fail(w http.ResponseWriter, status int, code, message string) {
+	s.reply(w, status, &Error{Code: code, Message: message})
+}
+
+func (s *Server) notFound(w http.ResponseWriter) {
+	s.fail(w, http.StatusNotFound, "NotFound", "Entity with the specified id does not exist in the system.")
+}
+
+// normalizeColl - normalizes a collection link eg. "/dbs/db/colls/coll/" to "dbs/db/colls/coll"
+func normalizeColl(coll string) string {
+	return strings.Trim(coll, "/")
+}
@@ -0,0 +1,91 @@
+package fake
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/intwinelabs/gocosmosdb"
+	"github.com/intwinelabs/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+var log = logger.New()
+
+type user struct {
+	gocosmosdb.Document
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func TestServerDocuments(t *testing.T) {
+	assert := assert.New(t)
+	s := NewServer()
+	defer s.Close()
+	client := gocosmosdb.New(s.URL, gocosmosdb.Config{MasterKey: MasterKey}, log)
+	coll := "dbs/db/colls/users/"
+
+	ada := &user{Name: "Ada", Age: 36}
+	ada.Id = "1"
+	_, err := client.CreateDocument(coll, ada)
+	assert.Nil(err)
+	_, err = client.CreateDocument(coll, ada)
+	assert.True(errors.Is(err, gocosmosdb.ErrConflict))
+
+	var read user
+	_, err = client.ReadDocument(coll+"docs/1", &read)
+	assert.Nil(err)
+	assert.Equal("Ada", read.Name)
+	assert.NotEmpty(read.Etag)
+
+	stale := read.Etag
+	read.Age = 37
+	_, err = client.ReplaceDocument(coll+"docs/1", &read, gocosmosdb.IfMatch(stale))
+	assert.Nil(err)
+	_, err = client.ReplaceDocument(coll+"docs/1", &read, gocosmosdb.IfMatch(stale))
+	assert.True(errors.Is(err, gocosmosdb.ErrPreconditionFailed))
+
+	assert.Nil(s.Insert(coll, map[string]interface{}{"id": "2", "name": "Grace", "age": 85}))
+	var users []user
+	_, err = client.QueryDocumentsWithParameters(coll, &gocosmosdb.QueryWithParameters{
+		Query:      "SELECT * FROM root r WHERE r.age > @age ORDER BY r.age DESC",
+		Parameters: []gocosmosdb.QueryParameter{{Name: "@age", Value: 30}},
+	}, &users)
+	assert.Nil(err)
+	assert.Len(users, 2)
+	assert.Equal("Grace", users[0].Name)
+	assert.Equal(37, users[1].Age)
+
+	_, err = client.QueryDocuments(coll, "SELECT * FROM root r WHERE", &users)
+	assert.IsType(&gocosmosdb.RequestError{}, err)
+	assert.Equal(http.StatusBadRequest, err.(*gocosmosdb.RequestError).StatusCode)
+
+	_, err = client.DeleteDocument(coll + "docs/1")
+	assert.Nil(err)
+	_, err = client.ReadDocument(coll+"docs/1", &read)
+	assert.True(errors.Is(err, gocosmosdb.ErrNotFound))
+	assert.Len(s.Documents(coll), 1)
+}
+
+func TestServerPaging(t *testing.T) {
+	assert := assert.New(t)
+	s := NewServer()
+	defer s.Close()
+	client := gocosmosdb.New(s.URL, gocosmosdb.Config{MasterKey: MasterKey}, log)
+	coll := "dbs/db/colls/users/"
+	for _, id := range []string{"a", "b", "c"} {
+		assert.Nil(s.Insert(coll, map[string]interface{}{"id": id}))
+	}
+
+	var page []user
+	resp, err := client.QueryDocuments(coll, "SELECT * FROM c ORDER BY c.id DESC", &page, gocosmosdb.Limit(2))
+	assert.Nil(err)
+	assert.Len(page, 2)
+	assert.Equal("c", page[0].Id)
+	assert.NotEmpty(resp.Continuation())
+
+	_, err = client.QueryDocuments(coll, "SELECT * FROM c ORDER BY c.id DESC", &page, gocosmosdb.Limit(2), gocosmosdb.Continuation(resp.Continuation()))
+	assert.Nil(err)
+	assert.Len(page, 1)
+	assert.Equal("a", page[0].Id)
+}
@@ -0,0 +1,184 @@
+package fake
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/intwinelabs/gocosmosdb"
+)
+
+// Operation kinds, as passed to a ChargeFunc
+const (
+	OpRead   = "read"
+	OpWrite  = "write"
+	OpDelete = "delete"
+	OpQuery  = "query"
+	OpFeed   = "feed"
+	OpError  = "error" // any failed request but a throttled one, which is free
+)
+
+// Operation - a request the fake charges RUs for
+type Operation struct {
+	Kind    string
+	Bytes   int // size of the response body
+	Scanned int // documents a query or read feed went through
+}
+
+// ChargeFunc - returns the RUs an operation costs
+type ChargeFunc func(op Operation) float64
+
+// DefaultCharge - a deterministic approximation of the service: a read costs 1 RU per started KB, a write 5,
+// a delete 5 and a query or read feed 2 plus 0.1 per document scanned and 1 per started KB returned
+func DefaultCharge(op Operation) float64 {
+	kb := math.Max(1, math.Ceil(float64(op.Bytes)/1024))
+	switch op.Kind {
+	case OpRead:
+		return kb
+	case OpWrite:
+		return 5 * kb
+	case OpDelete:
+		return 5
+	case OpQuery, OpFeed:
+		return 2 + 0.1*float64(op.Scanned) + kb
+	default:
+		return 1
+	}
+}
+
+// Clock - the time of the fake, for the _ts of written documents and the throughput windows
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// ManualClock - a clock that only moves when told to, for deterministic tests
+type ManualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewManualClock - creates a clock stopped at start
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+// Now - returns the time the clock is stopped at
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance - moves the clock forward
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// SetClock - replaces the system clock of the fake
+func (s *Server) SetClock(clock Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = clock
+}
+
+// SetCharge - replaces DefaultCharge
+func (s *Server) SetCharge(charge ChargeFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.charge = charge
+}
+
+// SetThroughput - provisions RU/s for a collection eg. "dbs/db/colls/coll", once the RUs charged within a second
+// of the clock reach it further requests get 429 until the next second, 0 lifts the limit
+//
+//	clock := fake.NewManualClock(time.Now())
+//	s.SetClock(clock)
+//	s.SetThroughput("dbs/db/colls/coll", 10)
+//	... requests get 429 once they used up 10 RUs
+//	clock.Advance(time.Second)
+func (s *Server) SetThroughput(coll string, rus int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.budget(coll).throughput = float64(rus)
+}
+
+// Consumed - returns the RUs charged to a collection so far
+func (s *Server) Consumed(coll string) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.budget(coll).total
+}
+
+// budget - the RUs provisioned and consumed by a collection
+type budget struct {
+	throughput float64
+	window     time.Time // the second the consumed RUs were charged in
+	consumed   float64
+	total      float64
+}
+
+// budget - returns the budget of a collection, creating it on first use, the lock must be held
+func (s *Server) budget(coll string) *budget {
+	coll = normalizeColl(coll)
+	b, ok := s.budgets[coll]
+	if !ok {
+		b = &budget{}
+		s.budgets[coll] = b
+	}
+	return b
+}
+
+// throttle - fails the request with 429 when the collection used up its throughput in the current second,
+// the lock must be held
+func (s *Server) throttle(w http.ResponseWriter, coll string) bool {
+	b := s.budget(coll)
+	now := s.clock.Now()
+	if window := now.Truncate(time.Second); !window.Equal(b.window) {
+		b.window, b.consumed = window, 0
+	}
+	if b.throughput <= 0 || b.consumed < b.throughput {
+		return false
+	}
+	wait := b.window.Add(time.Second).Sub(now)
+	w.Header().Set(gocosmosdb.HeaderRetryAfterMs, strconv.FormatInt(int64(math.Ceil(float64(wait)/float64(time.Millisecond))), 10))
+	s.fail(w, http.StatusTooManyRequests, "TooManyRequests", "Request rate is large.")
+	return true
+}
+
+// meteredWriter - charges the operation of a request once its status is known
+type meteredWriter struct {
+	http.ResponseWriter
+	s     *Server
+	coll  string
+	op    Operation
+	batch []float64 // the charges of the operations of a batch, charged instead of op
+}
+
+// WriteHeader - sets the request charge header and adds the charge to the collection
+func (m *meteredWriter) WriteHeader(status int) {
+	charge := 0.0
+	switch {
+	case status == http.StatusTooManyRequests:
+	case status >= http.StatusBadRequest:
+		charge = m.s.charge(Operation{Kind: OpError, Bytes: m.op.Bytes})
+	case m.batch != nil:
+		for _, c := range m.batch {
+			charge += c
+		}
+	default:
+		charge = m.s.charge(m.op)
+	}
+	b := m.s.budget(m.coll)
+	b.consumed += charge
+	b.total += charge
+	m.Header().Set(gocosmosdb.HeaderRequestCharge, strconv.FormatFloat(charge, 'f', 2, 64))
+	m.ResponseWriter.WriteHeader(status)
+}
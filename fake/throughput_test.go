@@ -0,0 +1,68 @@
+package fake
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/intwinelabs/gocosmosdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerThroughput(t *testing.T) {
+	assert := assert.New(t)
+	s := NewServer()
+	defer s.Close()
+	start := time.Date(2019, 2, 13, 1, 17, 27, 250*int(time.Millisecond), time.UTC)
+	clock := NewManualClock(start)
+	s.SetClock(clock)
+	s.SetThroughput("dbs/db/colls/users", 10)
+	client := gocosmosdb.New(s.URL, gocosmosdb.Config{MasterKey: MasterKey}, log)
+	coll := "dbs/db/colls/users/"
+
+	doc := &user{Name: "Ada"}
+	doc.Id = "1"
+	resp, err := client.CreateDocument(coll, doc)
+	assert.Nil(err)
+	rus, _ := resp.GetRUs()
+	assert.Equal(5.0, rus)
+	assert.Equal(int(start.Unix()), doc.Ts)
+
+	var read user
+	for i := 0; i < 5; i++ {
+		_, err = client.ReadDocument(coll+"docs/1", &read)
+		assert.Nil(err)
+	}
+	assert.Equal(10.0, s.Consumed(coll))
+
+	_, err = client.ReadDocument(coll+"docs/1", &read)
+	assert.True(errors.Is(err, gocosmosdb.ErrTooManyRequests))
+
+	clock.Advance(time.Second)
+	_, err = client.ReadDocument(coll+"docs/1", &read)
+	assert.Nil(err)
+	assert.Equal(11.0, s.Consumed(coll))
+}
+
+func TestServerRetryAfter(t *testing.T) {
+	assert := assert.New(t)
+	s := NewServer()
+	defer s.Close()
+	s.SetClock(NewManualClock(time.Date(2019, 2, 13, 1, 17, 27, 750*int(time.Millisecond), time.UTC)))
+	s.SetThroughput("dbs/db/colls/users", 1)
+	s.SetCharge(func(op Operation) float64 { return 1 })
+	assert.Nil(s.Insert("dbs/db/colls/users", map[string]interface{}{"id": "1"}))
+
+	resp, err := http.Get(s.URL + "/dbs/db/colls/users/docs/1")
+	assert.Nil(err)
+	resp.Body.Close()
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal("1.00", resp.Header.Get(gocosmosdb.HeaderRequestCharge))
+
+	resp, err = http.Get(s.URL + "/dbs/db/colls/users/docs/1")
+	assert.Nil(err)
+	resp.Body.Close()
+	assert.Equal(http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal("250", resp.Header.Get(gocosmosdb.HeaderRetryAfterMs))
+}
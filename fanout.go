@@ -0,0 +1,121 @@
+package gocosmosdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// FanOut - describes the documents embedding a denormalized copy of a parent document
+type FanOut struct {
+	Collection       string                                                     // collection of the related documents
+	PartitionKeyPath string                                                     // partition key of the related documents eg. "/customerId"
+	Query            func(parent json.RawMessage) (*QueryWithParameters, error) // locates the related documents
+	// Apply - returns the related document with its copy of the parent updated, nil when it is already current
+	Apply       func(parent, related json.RawMessage) (json.RawMessage, error)
+	Concurrency int // related documents written at once, defaults to 10
+	MaxAttempts int // attempts per document when it changes while being updated, defaults to 5
+}
+
+// FanOutResult - counts the related documents of a fan out
+type FanOutResult struct {
+	Matched int
+	Updated int
+	Skipped int
+}
+
+// FanOut - propagates an update of the parent into the copies embedded in its related documents, replacing them
+// concurrently with optimistic concurrency and reapplying the update to the documents changed in the meantime
+//
+//	res, err := client.FanOut(ctx, customer, &gocosmosdb.FanOut{
+//		Collection:       "dbs/{db-id}/colls/orders/",
+//		PartitionKeyPath: "/customerId",
+//		Query: func(parent json.RawMessage) (*gocosmosdb.QueryWithParameters, error) {...},
+//		Apply: func(parent, related json.RawMessage) (json.RawMessage, error) {...},
+//	})
+func (c *CosmosDB) FanOut(ctx context.Context, parent json.RawMessage, f *FanOut) (*FanOutResult, error) {
+	if f == nil || f.Query == nil || f.Apply == nil {
+		return nil, errors.New("FanOut needs a Query and an Apply")
+	}
+	query, err := f.Query(parent)
+	if err != nil {
+		return nil, err
+	}
+	related := []json.RawMessage{}
+	if err = c.QueryAll(f.Collection, query, &related, &DrainLimits{}, WithContext(ctx)); err != nil {
+		return nil, err
+	}
+	concurrency := f.Concurrency
+	if concurrency <= 0 {
+		concurrency = 10
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	res := &FanOutResult{Matched: len(related)}
+	sem := make(chan struct{}, concurrency)
+	for _, doc := range related {
+		wg.Add(1)
+		go func(doc json.RawMessage) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			updated, err := c.fanOutOne(ctx, parent, doc, f)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil && firstErr == nil:
+				firstErr = err
+				cancel()
+			case err == nil && updated:
+				res.Updated++
+			case err == nil:
+				res.Skipped++
+			}
+		}(doc)
+	}
+	wg.Wait()
+	return res, firstErr
+}
+
+// fanOutOne - applies the update to a related document, rereading and retrying when its etag no longer matches
+func (c *CosmosDB) fanOutOne(ctx context.Context, parent, doc json.RawMessage, f *FanOut) (bool, error) {
+	attempts := f.MaxAttempts
+	if attempts <= 0 {
+		attempts = 5
+	}
+	pk, err := valueAtPath(doc, f.PartitionKeyPath)
+	if err != nil {
+		return false, err
+	}
+	for attempt := 1; ; attempt++ {
+		var meta struct {
+			Self string `json:"_self"`
+			Etag string `json:"_etag"`
+		}
+		if err = json.Unmarshal(doc, &meta); err != nil {
+			return false, err
+		}
+		body, err := f.Apply(parent, doc)
+		if err != nil || body == nil {
+			return false, err
+		}
+		_, err = c.client.method(http.MethodPut, meta.Self, expectOK, nil, bytes.NewBuffer(body),
+			IfMatch(meta.Etag), PartitionKey(pk), WithContext(ctx))
+		if !errors.Is(err, ErrPreconditionFailed) || attempt >= attempts {
+			return err == nil, err
+		}
+		doc = json.RawMessage{}
+		if _, err = c.client.read(meta.Self, &doc, PartitionKey(pk), WithContext(ctx)); err != nil {
+			return false, err
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package gocosmosdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFanOut(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"Documents": [{"id": "o1", "customerId": "c1", "_self": "dbs/db/colls/orders/docs/o1/", "_etag": "1", "customer": {"name": "old"}}], "_count": 1}`, `{}`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	fanOut := &FanOut{
+		Collection:       "dbs/db/colls/orders/",
+		PartitionKeyPath: "/customerId",
+		Query: func(parent json.RawMessage) (*QueryWithParameters, error) {
+			return &QueryWithParameters{Query: "SELECT * FROM root r WHERE r.customerId = @id", Parameters: []QueryParameter{{Name: "@id", Value: "c1"}}}, nil
+		},
+		Apply: func(parent, related json.RawMessage) (json.RawMessage, error) {
+			var doc map[string]interface{}
+			if err := json.Unmarshal(related, &doc); err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(parent, &doc); err != nil {
+				return nil, err
+			}
+			return json.Marshal(doc)
+		},
+	}
+	res, err := client.FanOut(context.Background(), json.RawMessage(`{"customer": {"name": "new"}}`), fanOut)
+	assert.Nil(err)
+	assert.Equal(&FanOutResult{Matched: 1, Updated: 1}, res)
+	assert.Equal("1", s.Header.Get(HeaderIfMatch))
+	assert.Equal(`["c1"]`, s.Header.Get(HeaderPartitionKey))
+	assert.Contains(s.Body, `"name":"new"`)
+
+	_, err = client.FanOut(context.Background(), nil, &FanOut{})
+	assert.NotNil(err)
+}
+
+func TestFanOutConflictRetry(t *testing.T) {
+	assert := assert.New(t)
+	puts := 0
+	etags := []string{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			fmt.Fprint(w, `{"Documents": [{"id": "o1", "customerId": "c1", "_self": "dbs/db/colls/orders/docs/o1/", "_etag": "1"}], "_count": 1}`)
+		case http.MethodGet:
+			fmt.Fprint(w, `{"id": "o1", "customerId": "c1", "_self": "dbs/db/colls/orders/docs/o1/", "_etag": "2"}`)
+		case http.MethodPut:
+			puts++
+			etags = append(etags, r.Header.Get(HeaderIfMatch))
+			if puts == 1 {
+				w.WriteHeader(http.StatusPreconditionFailed)
+			}
+			fmt.Fprint(w, `{}`)
+		}
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	fanOut := &FanOut{
+		Collection:       "dbs/db/colls/orders/",
+		PartitionKeyPath: "/customerId",
+		Query: func(parent json.RawMessage) (*QueryWithParameters, error) {
+			return &QueryWithParameters{Query: "SELECT * FROM root r"}, nil
+		},
+		Apply: func(parent, related json.RawMessage) (json.RawMessage, error) {
+			return related, nil
+		},
+	}
+	res, err := client.FanOut(context.Background(), json.RawMessage(`{}`), fanOut)
+	assert.Nil(err)
+	assert.Equal(1, res.Updated)
+	assert.Equal([]string{"1", "2"}, etags)
+
+	puts = 0
+	fanOut.MaxAttempts = 1
+	_, err = client.FanOut(context.Background(), json.RawMessage(`{}`), fanOut)
+	assert.True(errors.Is(err, ErrPreconditionFailed))
+}
+
+func TestValueAtPath(t *testing.T) {
+	assert := assert.New(t)
+	v, err := valueAtPath(json.RawMessage(`{"customer": {"id": "c1"}}`), "/customer/id")
+	assert.Nil(err)
+	assert.Equal("c1", v)
+	_, err = valueAtPath(json.RawMessage(`{"customer": "c1"}`), "/customer/id")
+	assert.NotNil(err)
+}
@@ -0,0 +1,288 @@
+package gocosmosdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// fastUnmarshaler - implemented by the response types decoded on every request, which read the JSON without
+// reflection and report false on anything they do not expect so it is decoded with encoding/json instead
+type fastUnmarshaler interface {
+	unmarshalFast(data []byte) bool
+}
+
+// scanner - reads JSON without reflection for the fast decoders
+type scanner struct {
+	data []byte
+	pos  int
+}
+
+func (s *scanner) space() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\n', '\r':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+// consume - reads past the byte c when it is next
+func (s *scanner) consume(c byte) bool {
+	s.space()
+	if s.pos < len(s.data) && s.data[s.pos] == c {
+		s.pos++
+		return true
+	}
+	return false
+}
+
+// end - reports whether only white space is left
+func (s *scanner) end() bool {
+	s.space()
+	return s.pos == len(s.data)
+}
+
+// object - reads an object, calling field for every key to read its value
+func (s *scanner) object(field func(key string) bool) bool {
+	if !s.consume('{') {
+		return false
+	}
+	if s.consume('}') {
+		return true
+	}
+	for {
+		key, ok := s.str()
+		if !ok || !s.consume(':') || !field(key) {
+			return false
+		}
+		if !s.consume(',') {
+			return s.consume('}')
+		}
+	}
+}
+
+// array - reads an array, calling item to read every value
+func (s *scanner) array(item func() bool) bool {
+	if !s.consume('[') {
+		return false
+	}
+	if s.consume(']') {
+		return true
+	}
+	for {
+		if !item() {
+			return false
+		}
+		if !s.consume(',') {
+			return s.consume(']')
+		}
+	}
+}
+
+// str - reads a string, those with escapes are unquoted by encoding/json
+func (s *scanner) str() (string, bool) {
+	if !s.consume('"') {
+		return "", false
+	}
+	start := s.pos
+	for ; s.pos < len(s.data); s.pos++ {
+		switch c := s.data[s.pos]; {
+		case c == '"':
+			s.pos++
+			return string(s.data[start : s.pos-1]), true
+		case c == '\\':
+			return s.escaped(start - 1)
+		case c < 0x20:
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// escaped - reads the string with escapes opened at start
+func (s *scanner) escaped(start int) (string, bool) {
+	for i := s.pos; i < len(s.data); i++ {
+		switch s.data[i] {
+		case '\\':
+			i++
+		case '"':
+			var v string
+			if json.Unmarshal(s.data[start:i+1], &v) != nil {
+				return "", false
+			}
+			s.pos = i + 1
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// int - reads an integer
+func (s *scanner) int() (int, bool) {
+	s.space()
+	start := s.pos
+	if s.pos < len(s.data) && s.data[s.pos] == '-' {
+		s.pos++
+	}
+	for s.pos < len(s.data) && s.data[s.pos] >= '0' && s.data[s.pos] <= '9' {
+		s.pos++
+	}
+	n, err := strconv.Atoi(string(s.data[start:s.pos]))
+	return n, err == nil
+}
+
+// null - reads past a null when it is next
+func (s *scanner) null() bool {
+	s.space()
+	if bytes.HasPrefix(s.data[s.pos:], []byte("null")) {
+		s.pos += 4
+		return true
+	}
+	return false
+}
+
+// skip - reads past any value
+func (s *scanner) skip() bool {
+	s.space()
+	if s.pos == len(s.data) {
+		return false
+	}
+	switch s.data[s.pos] {
+	case '"':
+		_, ok := s.str()
+		return ok
+	case '{':
+		return s.object(func(string) bool { return s.skip() })
+	case '[':
+		return s.array(s.skip)
+	}
+	// numbers, true, false and null
+	start := s.pos
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ',', '}', ']', ' ', '\t', '\n', '\r':
+			return s.pos > start
+		}
+		s.pos++
+	}
+	return s.pos > start
+}
+
+// resource - reads the value of a key of the Resource fields, found is false for other keys
+func (s *scanner) resource(r *Resource, key string) (ok, found bool) {
+	switch key {
+	case "id":
+		r.Id, ok = s.str()
+	case "_self":
+		r.Self, ok = s.str()
+	case "_etag":
+		r.Etag, ok = s.str()
+	case "_rid":
+		r.Rid, ok = s.str()
+	case "_ts":
+		r.Ts, ok = s.int()
+	case "_count":
+		r.Count, ok = s.int()
+	default:
+		return false, false
+	}
+	return ok, true
+}
+
+// unmarshalFast - reads the code and message of an error body
+func (e *RequestError) unmarshalFast(data []byte) bool {
+	s := &scanner{data: data}
+	return s.object(func(key string) (ok bool) {
+		switch key {
+		case "code":
+			e.Code, ok = s.str()
+		case "message":
+			e.Message, ok = s.str()
+		default:
+			ok = s.skip()
+		}
+		return
+	}) && s.end()
+}
+
+// unmarshalFast - reads an offer
+func (o *Offer) unmarshalFast(data []byte) bool {
+	s := &scanner{data: data}
+	return s.offer(o) && s.end()
+}
+
+func (s *scanner) offer(o *Offer) bool {
+	return s.object(func(key string) (ok bool) {
+		if ok, found := s.resource(&o.Resource, key); found {
+			return ok
+		}
+		switch key {
+		case "offerVersion":
+			o.OfferVersion, ok = s.str()
+		case "offerType":
+			o.OfferType, ok = s.str()
+		case "resource":
+			o.ResourceLink, ok = s.str()
+		case "offerResourceId":
+			o.OfferResourceId, ok = s.str()
+		case "content":
+			ok = s.object(func(key string) (ok bool) {
+				switch key {
+				case "offerThroughput":
+					o.Content.OfferThroughput, ok = s.int()
+				case "offerAutopilotSettings":
+					if s.null() {
+						o.Content.OfferAutopilotSettings = nil
+						return true
+					}
+					settings := &struct {
+						MaxThroughput int `json:"maxThroughput"`
+					}{}
+					o.Content.OfferAutopilotSettings = settings
+					ok = s.object(func(key string) (ok bool) {
+						if key == "maxThroughput" {
+							settings.MaxThroughput, ok = s.int()
+							return
+						}
+						return s.skip()
+					})
+				default:
+					ok = s.skip()
+				}
+				return
+			})
+		default:
+			ok = s.skip()
+		}
+		return
+	})
+}
+
+// offerFeed - the offers of the account as read or queried
+type offerFeed struct {
+	Offers []Offer `json:"Offers,omitempty"`
+	Count  int     `json:"_count,omitempty"`
+}
+
+// unmarshalFast - reads the offers and the count of the feed
+func (f *offerFeed) unmarshalFast(data []byte) bool {
+	s := &scanner{data: data}
+	return s.object(func(key string) (ok bool) {
+		switch key {
+		case "Offers":
+			f.Offers = nil
+			ok = s.array(func() bool {
+				f.Offers = append(f.Offers, Offer{})
+				return s.offer(&f.Offers[len(f.Offers)-1])
+			})
+		case "_count":
+			f.Count, ok = s.int()
+		default:
+			ok = s.skip()
+		}
+		return
+	}) && s.end()
+}
@@ -0,0 +1,64 @@
+package gocosmosdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFastOffer(t *testing.T) {
+	assert := assert.New(t)
+	for _, body := range []string{
+		`{"id": "1", "_rid": "rid", "_self": "offers/rid/", "_etag": "\"0000\"", "_ts": 1700000000, "offerVersion": "V2", "offerType": "Invalid",
+			"resource": "dbs/db/colls/coll/", "offerResourceId": "coll-rid", "content": {"offerThroughput": 400, "offerIsRUPerMinuteThroughputEnabled": false,
+			"offerAutopilotSettings": {"maxThroughput": 4000, "autoUpgradePolicy": {"throughputPolicy": {"incrementPercent": 0}}}}}`,
+		`{"id": "1", "content": {"offerThroughput": 400, "offerAutopilotSettings": null}, "tags": ["a", 1, true, null, {"b": [1.5e3]}]}`,
+		`{"id": "café \"quoted\"", "content": {}}`,
+	} {
+		var fast, slow Offer
+		assert.True(fast.unmarshalFast([]byte(body)), body)
+		assert.Nil(json.Unmarshal([]byte(body), &slow))
+		assert.Equal(slow, fast)
+	}
+}
+
+func TestFastFallback(t *testing.T) {
+	assert := assert.New(t)
+	// a float throughput and a null id are left to encoding/json
+	for _, body := range []string{
+		`{"id": "1", "content": {"offerThroughput": 400.0}}`,
+		`{"id": null, "content": {"offerThroughput": 400}}`,
+	} {
+		var offer Offer
+		assert.False(offer.unmarshalFast([]byte(body)))
+		offer = Offer{}
+		err := readJson(bytes.NewBufferString(body), &offer)
+		assert.Equal(400, offer.Content.OfferThroughput, err)
+	}
+	var offer Offer
+	assert.False(offer.unmarshalFast([]byte(`{"id": "1"} trailing`)))
+	assert.False(offer.unmarshalFast([]byte(`{"id": "1",}`)))
+	assert.False(offer.unmarshalFast([]byte(`{"id": "1`)))
+}
+
+func TestFastOfferFeed(t *testing.T) {
+	assert := assert.New(t)
+	body := `{"_rid": "", "Offers": [{"id": "a", "offerResourceId": "db-rid", "content": {"offerThroughput": 400}},
+		{"id": "b", "offerResourceId": "coll-rid", "content": {"offerThroughput": 1000}}], "_count": 2}`
+	var fast, slow offerFeed
+	assert.True(fast.unmarshalFast([]byte(body)))
+	assert.Nil(json.Unmarshal([]byte(body), &slow))
+	assert.Equal(slow, fast)
+	assert.Len(fast.Offers, 2)
+}
+
+func TestFastRequestError(t *testing.T) {
+	assert := assert.New(t)
+	body := `{"code": "Conflict", "message": "Entity with the specified id already exists in the system.\r\nActivityId: 1", "additionalErrorInfo": {"a": 1}}`
+	var fast RequestError
+	assert.True(fast.unmarshalFast([]byte(body)))
+	assert.Equal("Conflict", fast.Code)
+	assert.Equal("Entity with the specified id already exists in the system.\r\nActivityId: 1", fast.Message)
+}
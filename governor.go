@@ -0,0 +1,188 @@
+package gocosmosdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// ErrShed - a background call was refused by the Governor of the client while the account is throttled
+var ErrShed = errors.New("background request shed while throttled")
+
+// Priority - how urgently the Governor of the client admits a call while the account is throttled
+type Priority int
+
+const (
+	PriorityBackground Priority = -1 // admitted last or shed, eg. reports and migrations
+	PriorityNormal     Priority = 0  // calls made without WithPriority
+	PriorityHigh       Priority = 1  // admitted first, eg. user facing reads
+)
+
+// WithPriority - sets the priority the Governor of the client admits the call with
+func WithPriority(p Priority) CallOption {
+	return func(r *Request) error {
+		r.rPriority = p
+		return nil
+	}
+}
+
+// Governor - admits the calls of a client by priority once the account is throttled. Calls pass straight through
+// until a 429 is seen, then for as long as it asks to wait, at least a second, only concurrency calls are in flight
+// and the others queue, high priority calls first. Background calls are shed with ErrShed instead of queued when
+// shedBackground is set.
+//
+//	governor := gocosmosdb.NewGovernor(8, true)
+//	client := gocosmosdb.New(url, gocosmosdb.Config{MasterKey: key, RetryThrottled: true, Governor: governor}, log)
+//	_, err := client.ReadDocument(link, &order, gocosmosdb.WithPriority(gocosmosdb.PriorityHigh))
+type Governor struct {
+	concurrency int
+	shed        bool
+	hold        time.Duration // the least a 429 throttles for
+	now         func() time.Time
+	mu          sync.Mutex
+	until       time.Time
+	inflight    int
+	queues      [3][]chan struct{} // by priority, background first
+	timer       *time.Timer
+}
+
+// NewGovernor - creates a governor letting concurrency calls in flight while throttled
+func NewGovernor(concurrency int, shedBackground bool) *Governor {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Governor{concurrency: concurrency, shed: shedBackground, hold: time.Second, now: time.Now}
+}
+
+// Throttled - reports whether a 429 was seen within the wait it asked for
+func (g *Governor) Throttled() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.throttled()
+}
+
+// Waiting - returns the number of calls queued
+func (g *Governor) Waiting() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.waiting()
+}
+
+// throttled - the lock must be held
+func (g *Governor) throttled() bool {
+	return g.now().Before(g.until)
+}
+
+// waiting - the lock must be held
+func (g *Governor) waiting() int {
+	n := 0
+	for _, q := range g.queues {
+		n += len(q)
+	}
+	return n
+}
+
+// queue - the queue index of a priority
+func queue(p Priority) int {
+	switch {
+	case p < PriorityNormal:
+		return 0
+	case p > PriorityNormal:
+		return 2
+	}
+	return 1
+}
+
+// admit - lets a call through or queues it until it may be, the returned release must be called once it is done
+func (g *Governor) admit(ctx context.Context, r *Request) (func(), error) {
+	g.mu.Lock()
+	if !g.throttled() && g.waiting() == 0 {
+		g.inflight++
+		g.mu.Unlock()
+		return g.release, nil
+	}
+	if g.shed && r.rPriority < PriorityNormal && g.throttled() {
+		g.mu.Unlock()
+		return nil, ErrShed
+	}
+	q := queue(r.rPriority)
+	admitted := make(chan struct{})
+	g.queues[q] = append(g.queues[q], admitted)
+	g.dispatch()
+	g.mu.Unlock()
+
+	select {
+	case <-admitted:
+		return g.release, nil
+	case <-ctx.Done():
+		g.mu.Lock()
+		for i, ch := range g.queues[q] {
+			if ch == admitted {
+				g.queues[q] = append(g.queues[q][:i], g.queues[q][i+1:]...)
+				g.mu.Unlock()
+				return nil, ctx.Err()
+			}
+		}
+		g.mu.Unlock()
+		// admitted meanwhile, hand the slot on
+		g.release()
+		return nil, ctx.Err()
+	}
+}
+
+// release - frees the slot of a call that is done
+func (g *Governor) release() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.inflight--
+	g.dispatch()
+}
+
+// dispatch - admits the queued calls there is room for, highest priority first, the lock must be held
+func (g *Governor) dispatch() {
+	for q := len(g.queues) - 1; q >= 0; q-- {
+		for len(g.queues[q]) > 0 {
+			if g.throttled() && g.inflight >= g.concurrency {
+				return
+			}
+			close(g.queues[q][0])
+			g.queues[q] = g.queues[q][1:]
+			g.inflight++
+		}
+	}
+}
+
+// throttle - throttles the calls for as long as a 429 asks, waking the queue once it is over
+func (g *Governor) throttle(resp *http.Response) {
+	wait, ok := retryAfter(resp)
+	if !ok || wait < g.hold {
+		wait = g.hold
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if until := g.now().Add(wait); until.After(g.until) {
+		g.until = until
+		if g.timer != nil {
+			g.timer.Stop()
+		}
+		g.timer = time.AfterFunc(wait, func() {
+			g.mu.Lock()
+			defer g.mu.Unlock()
+			g.dispatch()
+		})
+	}
+}
+
+// observe - wraps a retry policy to throttle on every 429 seen, including those retried
+func (g *Governor) observe(policy retryablehttp.CheckRetry) retryablehttp.CheckRetry {
+	return func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+			g.throttle(resp)
+		}
+		return policy(ctx, resp, err)
+	}
+}
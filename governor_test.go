@@ -0,0 +1,105 @@
+package gocosmosdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// throttled429 - a throttled response asking to wait ms milliseconds
+func throttled429(ms string) *http.Response {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	resp.Header.Set(HeaderRetryAfterMs, ms)
+	return resp
+}
+
+func TestGovernorPriorities(t *testing.T) {
+	assert := assert.New(t)
+	g := NewGovernor(1, false)
+	g.hold = time.Millisecond
+	g.throttle(throttled429("5000"))
+	assert.True(g.Throttled())
+
+	release, err := g.admit(context.Background(), &Request{})
+	assert.Nil(err)
+
+	order := make(chan Priority, 3)
+	for _, p := range []Priority{PriorityBackground, PriorityNormal, PriorityHigh} {
+		go func(p Priority) {
+			done, err := g.admit(context.Background(), &Request{rPriority: p})
+			assert.Nil(err)
+			order <- p
+			done()
+		}(p)
+	}
+	for g.Waiting() < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	release()
+	assert.Equal(PriorityHigh, <-order)
+	assert.Equal(PriorityNormal, <-order)
+	assert.Equal(PriorityBackground, <-order)
+}
+
+func TestGovernorShed(t *testing.T) {
+	assert := assert.New(t)
+	g := NewGovernor(4, true)
+	g.hold = 0
+	g.throttle(throttled429("20"))
+
+	_, err := g.admit(context.Background(), &Request{rPriority: PriorityBackground})
+	assert.True(errors.Is(err, ErrShed))
+	release, err := g.admit(context.Background(), &Request{rPriority: PriorityHigh})
+	assert.Nil(err)
+	release()
+
+	time.Sleep(30 * time.Millisecond)
+	assert.False(g.Throttled())
+	release, err = g.admit(context.Background(), &Request{rPriority: PriorityBackground})
+	assert.Nil(err)
+	release()
+}
+
+func TestGovernorQueueDrainsOnceUnthrottled(t *testing.T) {
+	assert := assert.New(t)
+	g := NewGovernor(1, false)
+	g.hold = 0
+	g.throttle(throttled429("20"))
+	release, err := g.admit(context.Background(), &Request{})
+	assert.Nil(err)
+	defer release()
+
+	// the slot stays taken, the throttle ending admits the queued call
+	done, err := g.admit(context.Background(), &Request{rPriority: PriorityBackground})
+	assert.Nil(err)
+	done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	g.throttle(throttled429("5000"))
+	_, err = g.admit(ctx, &Request{})
+	assert.Equal(context.DeadlineExceeded, err)
+	assert.Equal(0, g.Waiting())
+}
+
+func TestGovernorObservesRetries(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(http.StatusTooManyRequests, `{"id": "1"}`)
+	s.SetHeader(HeaderRetryAfterMs, "5")
+	defer s.Close()
+	g := NewGovernor(2, true)
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", RetryMax: 2, RetryThrottled: true, Governor: g}, log)
+
+	var doc Document
+	_, err := client.ReadDocument("dbs/db/colls/coll/docs/1", &doc, WithPriority(PriorityHigh))
+	assert.Nil(err)
+	assert.Equal("1", doc.Id)
+	assert.True(g.Throttled())
+
+	_, err = client.ReadDocument("dbs/db/colls/coll/docs/1", &doc, WithPriority(PriorityBackground))
+	assert.True(errors.Is(err, ErrShed))
+}
@@ -0,0 +1,208 @@
+// Package gremlin speaks the Gremlin endpoint of a CosmosDB graph account with the same
+// Config as the SQL API client.
+package gremlin
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/intwinelabs/gocosmosdb"
+	"github.com/intwinelabs/logger"
+)
+
+// MimeType - the serialization the CosmosDB gremlin endpoint speaks
+const MimeType = "application/vnd.gremlin-v2.0+json"
+
+// Client - a gremlin client for a single graph collection
+type Client struct {
+	url      string
+	username string
+	config   gocosmosdb.Config
+	logger   *logger.Logger
+	dialer   *websocket.Dialer
+	mu       sync.Mutex
+	conn     *websocket.Conn
+}
+
+// New - creates a gremlin client for the graph at coll, the master key of the config is used as the password
+//
+//	g := gremlin.New("wss://{account}.gremlin.cosmos.azure.com:443/", config, "dbs/{db-id}/colls/{coll-id}", log)
+func New(url string, config gocosmosdb.Config, coll string, log *logger.Logger) *Client {
+	return &Client{
+		url:      url,
+		username: "/" + strings.Trim(coll, "/"),
+		config:   config,
+		logger:   log,
+		dialer:   &websocket.Dialer{HandshakeTimeout: 30 * time.Second},
+	}
+}
+
+// Close - closes the underlying connection
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// Execute - runs a gremlin traversal and unmarshals the collected result data into ret,
+// bindings are sent alongside the traversal rather than spliced into it.
+//
+//	var vertices []gremlin.Vertex
+//	_, err := g.Execute(ctx, "g.V().hasLabel(label)", map[string]interface{}{"label": "person"}, &vertices)
+func (c *Client) Execute(ctx context.Context, query string, bindings map[string]interface{}, ret interface{}) (*Response, error) {
+	args := map[string]interface{}{
+		"gremlin":  query,
+		"language": "gremlin-groovy",
+	}
+	if len(bindings) > 0 {
+		args["bindings"] = bindings
+	}
+	req := &request{RequestID: uuid.New().String(), Op: "eval", Args: args}
+	if c.config.Debug && c.logger != nil {
+		c.logger.Infof("Gremlin Request: ID: %s, Query: %s, Bindings: %+v", req.RequestID, query, bindings)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, resp, err := c.roundTrip(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	if ret != nil && len(data) > 0 {
+		if err = json.Unmarshal(data, ret); err != nil {
+			return resp, err
+		}
+	}
+	return resp, nil
+}
+
+// roundTrip - sends a request and collects the result data of every partial response
+func (c *Client) roundTrip(ctx context.Context, req *request) (json.RawMessage, *Response, error) {
+	if err := c.connect(ctx); err != nil {
+		return nil, nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		c.conn.SetReadDeadline(deadline)
+		c.conn.SetWriteDeadline(deadline)
+		defer c.conn.SetReadDeadline(time.Time{})
+		defer c.conn.SetWriteDeadline(time.Time{})
+	}
+	if err := c.send(req); err != nil {
+		return nil, nil, err
+	}
+	var results []json.RawMessage
+	resp := &Response{RequestID: req.RequestID}
+	for {
+		msg := &message{}
+		if err := c.conn.ReadJSON(msg); err != nil {
+			c.reset()
+			return nil, resp, err
+		}
+		if msg.RequestID != req.RequestID {
+			continue
+		}
+		resp.Status = msg.Status
+		if charge, ok := msg.Status.Attributes["x-ms-total-request-charge"].(float64); ok {
+			resp.RequestCharge = charge
+		}
+		switch msg.Status.Code {
+		case StatusAuthenticate:
+			if err := c.send(c.authentication(req.RequestID)); err != nil {
+				return nil, resp, err
+			}
+		case StatusPartialContent:
+			results = append(results, msg.Result.Data)
+		case StatusSuccess:
+			results = append(results, msg.Result.Data)
+			data, err := merge(results)
+			return data, resp, err
+		case StatusNoContent:
+			return nil, resp, nil
+		default:
+			return nil, resp, &Error{RequestID: req.RequestID, Status: msg.Status}
+		}
+	}
+}
+
+// connect - dials the gremlin endpoint unless a connection is open
+func (c *Client) connect(ctx context.Context) error {
+	if c.conn != nil {
+		return nil
+	}
+	if c.config.MasterKey == "" {
+		return errors.New("gremlin requires the master key of the account")
+	}
+	conn, _, err := c.dialer.DialContext(ctx, c.url, nil)
+	if err != nil {
+		return fmt.Errorf("error connecting to gremlin endpoint: %s", err)
+	}
+	c.conn = conn
+	return nil
+}
+
+// reset - drops a broken connection so the next request dials again
+func (c *Client) reset() {
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+}
+
+// send - writes a request prefixed with its mime type as a binary message
+func (c *Client) send(req *request) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	msg := make([]byte, 0, 1+len(MimeType)+len(body))
+	msg = append(msg, byte(len(MimeType)))
+	msg = append(msg, MimeType...)
+	msg = append(msg, body...)
+	if err = c.conn.WriteMessage(websocket.BinaryMessage, msg); err != nil {
+		c.reset()
+		return err
+	}
+	return nil
+}
+
+// authentication - the SASL PLAIN response to an authentication challenge
+func (c *Client) authentication(requestID string) *request {
+	sasl := base64.StdEncoding.EncodeToString([]byte("\x00" + c.username + "\x00" + c.config.MasterKey))
+	return &request{
+		RequestID: requestID,
+		Op:        "authentication",
+		Args:      map[string]interface{}{"SASL": sasl},
+	}
+}
+
+// merge - joins the data arrays of partial responses into one array
+func merge(results []json.RawMessage) (json.RawMessage, error) {
+	if len(results) == 1 {
+		return results[0], nil
+	}
+	all := []json.RawMessage{}
+	for _, result := range results {
+		if len(result) == 0 || string(result) == "null" {
+			continue
+		}
+		part := []json.RawMessage{}
+		if err := json.Unmarshal(result, &part); err != nil {
+			return nil, err
+		}
+		all = append(all, part...)
+	}
+	return json.Marshal(all)
+}
@@ -0,0 +1,105 @@
+package gremlin
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/intwinelabs/gocosmosdb"
+	"github.com/intwinelabs/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+var log = logger.New()
+
+// serverFactory - a gremlin server that challenges for authentication then answers with the passed messages
+func serverFactory(t *testing.T, replies ...string) (*httptest.Server, *[]request) {
+	received := &[]request{}
+	upgrader := websocket.Upgrader{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			req := request{}
+			if err = json.Unmarshal(msg[1+int(msg[0]):], &req); err != nil {
+				t.Fatal(err)
+			}
+			*received = append(*received, req)
+			if req.Op == "eval" {
+				conn.WriteJSON(map[string]interface{}{"requestId": req.RequestID, "status": map[string]interface{}{"code": StatusAuthenticate}})
+				continue
+			}
+			for _, reply := range replies {
+				conn.WriteMessage(websocket.TextMessage, []byte(strings.Replace(reply, "{id}", req.RequestID, 1)))
+			}
+		}
+	}))
+	return s, received
+}
+
+func TestExecute(t *testing.T) {
+	assert := assert.New(t)
+	s, received := serverFactory(t,
+		`{"requestId": "{id}", "status": {"code": 206}, "result": {"data": [{"id": "1", "label": "person", "type": "vertex"}]}}`,
+		`{"requestId": "{id}", "status": {"code": 200, "attributes": {"x-ms-total-request-charge": 4.2}}, "result": {"data": [{"id": "2", "label": "person", "type": "vertex"}]}}`,
+	)
+	defer s.Close()
+	g := New("ws"+strings.TrimPrefix(s.URL, "http"), gocosmosdb.Config{MasterKey: "YXJpZWwNCg=="}, "dbs/graphdb/colls/people/", log)
+	defer g.Close()
+
+	vertices := []Vertex{}
+	resp, err := g.Execute(context.Background(), "g.V().hasLabel(label)", map[string]interface{}{"label": "person"}, &vertices)
+	assert.Nil(err)
+	assert.Equal(4.2, resp.RequestCharge)
+	assert.Equal(2, len(vertices))
+	assert.Equal("2", vertices[1].ID)
+
+	assert.Equal(2, len(*received))
+	auth := (*received)[1]
+	assert.Equal("authentication", auth.Op)
+	sasl, _ := base64.StdEncoding.DecodeString(auth.Args["SASL"].(string))
+	assert.Equal("\x00/dbs/graphdb/colls/people\x00YXJpZWwNCg==", string(sasl))
+}
+
+func TestAddVertex(t *testing.T) {
+	assert := assert.New(t)
+	s, received := serverFactory(t,
+		`{"requestId": "{id}", "status": {"code": 200}, "result": {"data": [{"id": "ariel", "label": "person", "type": "vertex", "properties": {"pk": [{"id": "p1", "value": "ariel"}], "age": [{"id": "p2", "value": 7}]}}]}}`,
+	)
+	defer s.Close()
+	config := gocosmosdb.Config{MasterKey: "YXJpZWwNCg==", PartitionKeyPath: "/pk"}
+	g := New("ws"+strings.TrimPrefix(s.URL, "http"), config, "dbs/graphdb/colls/people", log)
+	defer g.Close()
+
+	v, err := g.AddVertex(context.Background(), "person", "ariel", "ariel", map[string]interface{}{"age": 7})
+	assert.Nil(err)
+	assert.Equal("ariel", v.ID)
+	assert.Equal(float64(7), v.Property("age"))
+	eval := (*received)[0]
+	assert.Equal("g.addV(label).property('id', id).property(pkProp, pk).property(k0, v0)", eval.Args["gremlin"])
+	assert.Equal("pk", eval.Args["bindings"].(map[string]interface{})["pkProp"])
+}
+
+func TestExecuteError(t *testing.T) {
+	assert := assert.New(t)
+	s, _ := serverFactory(t, `{"requestId": "{id}", "status": {"code": 597, "message": "ScriptEvaluationError"}}`)
+	defer s.Close()
+	g := New("ws"+strings.TrimPrefix(s.URL, "http"), gocosmosdb.Config{MasterKey: "YXJpZWwNCg=="}, "dbs/graphdb/colls/people", log)
+	defer g.Close()
+
+	_, err := g.Execute(context.Background(), "g.V(", nil, nil)
+	assert.IsType(&Error{}, err)
+	assert.Equal(597, err.(*Error).Status.Code)
+}
@@ -0,0 +1,105 @@
+package gremlin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// partitionProperty - the vertex property holding the partition key, from the configs PartitionKeyPath
+func (c *Client) partitionProperty() string {
+	return strings.TrimPrefix(c.config.PartitionKeyPath, "/")
+}
+
+// properties - appends a property step per property, binding every key and value
+func properties(b *strings.Builder, props map[string]interface{}, bindings map[string]interface{}) {
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for i, k := range keys {
+		fmt.Fprintf(b, ".property(k%d, v%d)", i, i)
+		bindings[fmt.Sprintf("k%d", i)] = k
+		bindings[fmt.Sprintf("v%d", i)] = props[k]
+	}
+}
+
+// AddVertex - adds a vertex, pk is the value of the partition key property for partitioned graphs
+func (c *Client) AddVertex(ctx context.Context, label, id string, pk interface{}, props map[string]interface{}) (*Vertex, error) {
+	b := &strings.Builder{}
+	bindings := map[string]interface{}{"label": label, "id": id}
+	b.WriteString("g.addV(label).property('id', id)")
+	if prop := c.partitionProperty(); prop != "" {
+		b.WriteString(".property(pkProp, pk)")
+		bindings["pkProp"] = prop
+		bindings["pk"] = pk
+	}
+	properties(b, props, bindings)
+	return c.vertex(ctx, b.String(), bindings)
+}
+
+// GetVertex - reads a vertex by id
+func (c *Client) GetVertex(ctx context.Context, id string) (*Vertex, error) {
+	return c.vertex(ctx, "g.V(id)", map[string]interface{}{"id": id})
+}
+
+// UpdateVertex - sets the passed properties on a vertex
+func (c *Client) UpdateVertex(ctx context.Context, id string, props map[string]interface{}) (*Vertex, error) {
+	b := &strings.Builder{}
+	bindings := map[string]interface{}{"id": id}
+	b.WriteString("g.V(id)")
+	properties(b, props, bindings)
+	return c.vertex(ctx, b.String(), bindings)
+}
+
+// DropVertex - drops a vertex and its edges
+func (c *Client) DropVertex(ctx context.Context, id string) error {
+	_, err := c.Execute(ctx, "g.V(id).drop()", map[string]interface{}{"id": id}, nil)
+	return err
+}
+
+// AddEdge - adds an edge from the vertex with id from to the vertex with id to
+func (c *Client) AddEdge(ctx context.Context, label, from, to string, props map[string]interface{}) (*Edge, error) {
+	b := &strings.Builder{}
+	bindings := map[string]interface{}{"label": label, "from": from, "to": to}
+	b.WriteString("g.V(from).addE(label).to(g.V(to))")
+	properties(b, props, bindings)
+	return c.edge(ctx, b.String(), bindings)
+}
+
+// GetEdge - reads an edge by id
+func (c *Client) GetEdge(ctx context.Context, id string) (*Edge, error) {
+	return c.edge(ctx, "g.E(id)", map[string]interface{}{"id": id})
+}
+
+// DropEdge - drops an edge
+func (c *Client) DropEdge(ctx context.Context, id string) error {
+	_, err := c.Execute(ctx, "g.E(id).drop()", map[string]interface{}{"id": id}, nil)
+	return err
+}
+
+// vertex - runs a traversal returning a single vertex
+func (c *Client) vertex(ctx context.Context, query string, bindings map[string]interface{}) (*Vertex, error) {
+	vertices := []Vertex{}
+	if _, err := c.Execute(ctx, query, bindings, &vertices); err != nil {
+		return nil, err
+	}
+	if len(vertices) == 0 {
+		return nil, nil
+	}
+	return &vertices[0], nil
+}
+
+// edge - runs a traversal returning a single edge
+func (c *Client) edge(ctx context.Context, query string, bindings map[string]interface{}) (*Edge, error) {
+	edges := []Edge{}
+	if _, err := c.Execute(ctx, query, bindings, &edges); err != nil {
+		return nil, err
+	}
+	if len(edges) == 0 {
+		return nil, nil
+	}
+	return &edges[0], nil
+}
@@ -0,0 +1,97 @@
+package gremlin
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// StatusSuccess - the request completed and carries the last of its results
+	StatusSuccess = 200
+
+	// StatusNoContent - the request completed without results
+	StatusNoContent = 204
+
+	// StatusPartialContent - more results for the request follow in further messages
+	StatusPartialContent = 206
+
+	// StatusAuthenticate - the server requires a SASL authentication challenge response
+	StatusAuthenticate = 407
+)
+
+// request - the message sent to the gremlin server
+type request struct {
+	RequestID string                 `json:"requestId"`
+	Op        string                 `json:"op"`
+	Processor string                 `json:"processor"`
+	Args      map[string]interface{} `json:"args"`
+}
+
+// message - a message received from the gremlin server
+type message struct {
+	RequestID string `json:"requestId"`
+	Status    Status `json:"status"`
+	Result    struct {
+		Data json.RawMessage        `json:"data"`
+		Meta map[string]interface{} `json:"meta"`
+	} `json:"result"`
+}
+
+// Status - the status of a gremlin response
+type Status struct {
+	Code       int                    `json:"code"`
+	Message    string                 `json:"message"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+// Response - the metadata of an executed traversal
+type Response struct {
+	RequestID     string
+	Status        Status
+	RequestCharge float64
+}
+
+// Error - a failed gremlin request
+type Error struct {
+	RequestID string
+	Status    Status
+}
+
+// Implement Error function
+func (e *Error) Error() string {
+	return fmt.Sprintf("gremlin %d, %s", e.Status.Code, e.Status.Message)
+}
+
+// Vertex - a graph vertex as returned by a traversal
+type Vertex struct {
+	ID         string                      `json:"id"`
+	Label      string                      `json:"label"`
+	Type       string                      `json:"type"`
+	Properties map[string][]VertexProperty `json:"properties,omitempty"`
+}
+
+// VertexProperty - a single value of a vertex property
+type VertexProperty struct {
+	ID    string      `json:"id"`
+	Value interface{} `json:"value"`
+}
+
+// Property - returns the first value of the named vertex property
+func (v *Vertex) Property(name string) interface{} {
+	if values := v.Properties[name]; len(values) > 0 {
+		return values[0].Value
+	}
+	return nil
+}
+
+// Edge - a graph edge as returned by a traversal
+type Edge struct {
+	ID         string                 `json:"id"`
+	Label      string                 `json:"label"`
+	Type       string                 `json:"type"`
+	InV        string                 `json:"inV"`
+	InVLabel   string                 `json:"inVLabel"`
+	OutV       string                 `json:"outV"`
+	OutVLabel  string                 `json:"outVLabel"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
@@ -0,0 +1,106 @@
+package gocosmosdb
+
+// DatabaseClient - the operations of a database addressed by its id, building the links of its resources
+//
+//	orders := client.Database("shop").Collection("orders")
+//	_, err := orders.CreateDocument(&order, gocosmosdb.PartitionKey(order.TenantId))
+//	_, err = orders.ReadDocument(order.Id, &order, gocosmosdb.PartitionKey(order.TenantId))
+type DatabaseClient struct {
+	client *CosmosDB
+	id     string
+	link   string
+}
+
+// CollectionClient - the operations of a collection addressed by the ids of its database and itself
+type CollectionClient struct {
+	client *CosmosDB
+	link   string
+}
+
+// Database - returns the client of the database with the id
+func (c *CosmosDB) Database(id string) *DatabaseClient {
+	return &DatabaseClient{client: c, id: id, link: "dbs/" + id + "/"}
+}
+
+// Link - returns the link of the database eg. "dbs/shop/"
+func (d *DatabaseClient) Link() string {
+	return d.link
+}
+
+// Create - creates the database
+func (d *DatabaseClient) Create(opts ...CallOption) (*Database, error) {
+	return d.client.CreateDatabase(&Database{Resource: Resource{Id: d.id}}, opts...)
+}
+
+// Read - reads the database
+func (d *DatabaseClient) Read(opts ...CallOption) (*Database, error) {
+	return d.client.ReadDatabase(d.link, opts...)
+}
+
+// Delete - deletes the database with its collections
+func (d *DatabaseClient) Delete(opts ...CallOption) (*Response, error) {
+	return d.client.DeleteDatabase(d.link, opts...)
+}
+
+// Collections - reads the collections of the database
+func (d *DatabaseClient) Collections(opts ...CallOption) ([]Collection, error) {
+	return d.client.ReadCollections(d.link, opts...)
+}
+
+// CreateCollection - creates the collection of a spec in the database
+func (d *DatabaseClient) CreateCollection(spec *ContainerSpec, opts ...CallOption) (*Collection, error) {
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+	return d.client.createSpecCollection(d.link, spec, opts)
+}
+
+// Collection - returns the client of the collection of the database with the id
+func (d *DatabaseClient) Collection(id string) *CollectionClient {
+	return &CollectionClient{client: d.client, link: d.link + "colls/" + id + "/"}
+}
+
+// Link - returns the link of the collection eg. "dbs/shop/colls/orders/"
+func (c *CollectionClient) Link() string {
+	return c.link
+}
+
+// Read - reads the collection
+func (c *CollectionClient) Read(opts ...CallOption) (*Collection, error) {
+	return c.client.ReadCollection(c.link, opts...)
+}
+
+// Delete - deletes the collection with its documents
+func (c *CollectionClient) Delete(opts ...CallOption) (*Response, error) {
+	return c.client.DeleteCollection(c.link, opts...)
+}
+
+// CreateDocument - creates a document in the collection, setting its Id when empty
+func (c *CollectionClient) CreateDocument(doc interface{}, opts ...CallOption) (*Response, error) {
+	return c.client.CreateDocument(c.link, doc, opts...)
+}
+
+// UpsertDocument - creates a document in the collection or replaces the one with its id
+func (c *CollectionClient) UpsertDocument(doc interface{}, opts ...CallOption) (*Response, error) {
+	return c.client.UpsertDocument(c.link, doc, opts...)
+}
+
+// ReadDocument - reads the document with the id into doc
+func (c *CollectionClient) ReadDocument(id string, doc interface{}, opts ...CallOption) (*Response, error) {
+	return c.client.ReadDocument(c.link+"docs/"+id, doc, opts...)
+}
+
+// ReplaceDocument - replaces the document with the id
+func (c *CollectionClient) ReplaceDocument(id string, doc interface{}, opts ...CallOption) (*Response, error) {
+	return c.client.ReplaceDocument(c.link+"docs/"+id, doc, opts...)
+}
+
+// DeleteDocument - deletes the document with the id
+func (c *CollectionClient) DeleteDocument(id string, opts ...CallOption) (*Response, error) {
+	return c.client.DeleteDocument(c.link+"docs/"+id, opts...)
+}
+
+// QueryDocuments - runs a query on the collection, decoding a page of documents into the slice docs points to
+func (c *CollectionClient) QueryDocuments(query *QueryWithParameters, docs interface{}, opts ...CallOption) (*Response, error) {
+	return c.client.QueryDocumentsWithParameters(c.link, query, docs, opts...)
+}
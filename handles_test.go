@@ -0,0 +1,65 @@
+package gocosmosdb
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatabaseClient(t *testing.T) {
+	assert := assert.New(t)
+	var paths, bodies []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		bodies = append(bodies, string(body))
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id": "shop", "_self": "dbs/x/"}`)
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	shop := client.Database("shop")
+	assert.Equal("dbs/shop/", shop.Link())
+	assert.Equal("dbs/shop/colls/orders/", shop.Collection("orders").Link())
+	db, err := shop.Create()
+	assert.Nil(err)
+	assert.Equal("dbs/x/", db.Self)
+	_, err = shop.CreateCollection(&ContainerSpec{Id: "orders", PartitionKeyPath: "/tenantId"})
+	assert.Nil(err)
+	_, err = shop.CreateCollection(&ContainerSpec{Id: "orders"})
+	assert.NotNil(err)
+	assert.Equal([]string{"POST /dbs", "POST /dbs/shop/colls/"}, paths)
+	assert.JSONEq(`{"id": "shop"}`, bodies[0])
+	assert.Contains(bodies[1], `"paths":["/tenantId"]`)
+}
+
+func TestCollectionClient(t *testing.T) {
+	assert := assert.New(t)
+	server := &collectionsServer{docs: map[string]string{}, pks: map[string]string{}}
+	s := httptest.NewServer(server)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+	orders := client.Database("db").Collection("orders")
+
+	order := &dualOrder{Document: Document{Resource: Resource{Id: "o1"}}, TenantId: "t1", Total: 10}
+	_, err := orders.CreateDocument(order, PartitionKey("t1"))
+	assert.Nil(err)
+	assert.Equal(`["t1"]`, server.pks["dbs/db/colls/orders/docs/o1"])
+	order.Total = 20
+	_, err = orders.ReplaceDocument("o1", order, PartitionKey("t1"))
+	assert.Nil(err)
+	var read dualOrder
+	_, err = orders.ReadDocument("o1", &read, PartitionKey("t1"))
+	assert.Nil(err)
+	assert.Equal(20, read.Total)
+	_, err = orders.DeleteDocument("o1", PartitionKey("t1"))
+	assert.Nil(err)
+	_, err = orders.ReadDocument("o1", &read, PartitionKey("t1"))
+	assert.True(errors.Is(err, ErrNotFound))
+}
@@ -14,6 +14,9 @@ const (
 	// HeaderAuth - The authorization token for the request
 	HeaderAuth = "Authorization"
 
+	// HeaderBatchAtomic - Makes a batch request all-or-nothing, the only mode supported by the service.
+	HeaderBatchAtomic = "X-Ms-Cosmos-Batch-Atomic"
+
 	// HeaderConsistencyLevel - The consistency level override for read options against documents and attachments.
 	// The valid values are: Strong, Bounded, Session, or Eventual
 	HeaderConsistencyLevel = "X-Ms-Consistency-Level"
@@ -40,6 +43,9 @@ const (
 	// HeaderEnableScan - Use an index scan to process the query if the right index path of type is not available.
 	HeaderEnableScan = "X-Ms-Documentdb-Query-Enable-Scan"
 
+	// HeaderETag - The etag of the resource, or the position reached in a change feed.
+	HeaderETag = "Etag"
+
 	// HeaderIfMatch - Used to make operation conditional for optimistic concurrency.
 	// The value should be the etag value of the resource.
 	HeaderIfMatch = "If-Match"
@@ -55,12 +61,22 @@ const (
 	// HeaderIndexingDirective - Overide the collections default indexing policy, set to Include or Exclude.
 	HeaderIndexingDirective = "x-ms-indexing-directive"
 
+	// HeaderIndexUtilization - The base64 encoded JSON of the indexes a query used and those it could have, returned
+	// when HeaderPopulateIndexMetrics is set.
+	HeaderIndexUtilization = "X-Ms-Cosmos-Index-Utilization"
+
+	// HeaderIsBatchRequest - Marks a request as a transactional batch of operations on one partition key.
+	HeaderIsBatchRequest = "X-Ms-Cosmos-Is-Batch-Request"
+
 	// HeaderIsQuery - Required for queries. This property must be set to true.
 	HeaderIsQuery = "X-Ms-Documentdb-Isquery"
 
-	// HeaderIsQueryPlan -
+	// HeaderIsQueryPlan - Asks for the plan of a query instead of its results.
 	HeaderIsQueryPlan = "X-Ms-Cosmos-Is-Query-Plan-Request"
 
+	// HeaderItemCount - The number of items returned by a query or read-feed page.
+	HeaderItemCount = "X-Ms-Item-Count"
+
 	// HeaderMaxItemCount - An integer indicating the maximum number of items to be returned per page.
 	// An x-ms-max-item-count of -1 can be specified to let the service determine the optimal item count.
 	HeaderMaxItemCount = "X-Ms-Max-Item-Count"
@@ -69,6 +85,9 @@ const (
 	// request units per second.
 	HeaderOfferThroughput = "X-Ms-Offer-Throughput"
 
+	// HeaderOfferAutopilotSettings - The autoscale settings for the collection as JSON, eg. {"maxThroughput": 4000}.
+	HeaderOfferAutopilotSettings = "X-Ms-Cosmos-Offer-Autopilot-Settings"
+
 	// HeaderParalelizeCrossPartition - Sets the query to run in parallel across partitions.
 	HeaderParalelizeCrossPartition = "X-Ms-Documentdb-Query-Parallelizecrosspartitionquery"
 
@@ -82,9 +101,18 @@ const (
 	// HeaderPartitionKeyRangeID - Used in change feed requests. The partition key range ID for reading data.
 	HeaderPartitionKeyRangeID = "X-Ms-Documentdb-Partitionkeyrangeid"
 
+	// HeaderPopulateIndexMetrics - Set to obtain the indexes a query used and those that would have helped it.
+	HeaderPopulateIndexMetrics = "X-Ms-Cosmos-Populateindexmetrics"
+
 	// HeaderPopulateQueryMetrics - Set to obtain detailed metrics on query execution.
 	HeaderPopulateQueryMetrics = "X-Ms-Documentdb-Populatequerymetrics"
 
+	// HeaderPostTriggerInclude - A comma separated list of the post triggers the write runs after it.
+	HeaderPostTriggerInclude = "X-Ms-Documentdb-Post-Trigger-Include"
+
+	// HeaderPreTriggerInclude - A comma separated list of the pre triggers the write runs before it.
+	HeaderPreTriggerInclude = "X-Ms-Documentdb-Pre-Trigger-Include"
+
 	// HeaderQueryMetrics - The query statistics for the execution. This is a delimited string containing statistics
 	// of time spent in the various phases of query execution.
 	HeaderQueryMetrics = "X-Ms-Documentdb-Query-Metrics"
@@ -95,10 +123,26 @@ const (
 	// HeaderRequestCharge - The number of request units consumed by the operation.
 	HeaderRequestCharge = "X-Ms-Request-Charge"
 
+	// HeaderResponseContinuationTokenLimit - Restricts the size of the continuation token returned by queries,
+	// expressed in KB. Valid values are 1 and above.
+	HeaderResponseContinuationTokenLimit = "X-Ms-Documentdb-Responsecontinuationtokenlimitinkb"
+
+	// HeaderRetryAfterMs - The number of milliseconds to wait before retrying a throttled request.
+	HeaderRetryAfterMs = "X-Ms-Retry-After-Ms"
+
+	// HeaderScriptEnableLogging - Makes the service return what a stored procedure logged with console.log.
+	HeaderScriptEnableLogging = "X-Ms-Documentdb-Script-Enable-Logging"
+
+	// HeaderScriptLogResults - The URL encoded console.log output of a stored procedure run with logging enabled.
+	HeaderScriptLogResults = "X-Ms-Documentdb-Script-Log-Results"
+
 	// HeaderSessionToken - A string token used with session level consistency.
 	HeaderSessionToken = "X-Ms-Session-Token"
 
-	// HeaderSupportedQueryFeatures -
+	// HeaderSubStatus - The substatus code of a response, telling apart the causes of the same status code.
+	HeaderSubStatus = "X-Ms-Substatus"
+
+	// HeaderSupportedQueryFeatures - The query features the client handles, sent along query plan requests.
 	HeaderSupportedQueryFeatures = "X-Ms-Cosmos-Supported-Query-Features"
 
 	// HeaderUpsert - If set to true, Cosmos DB creates the document with the ID (and partition key value if applicable)
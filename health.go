@@ -0,0 +1,74 @@
+package gocosmosdb
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// PingError - a failed health check
+type PingError struct {
+	Latency time.Duration
+	Err     error
+}
+
+// Implement Error function
+func (e *PingError) Error() string {
+	return fmt.Sprintf("cosmosdb ping failed after %s: %v", e.Latency, e.Err)
+}
+
+// Ping - reads the database account to check the endpoint is reachable and the credentials are accepted,
+// returning the latency of the round trip, for readiness and liveness probes
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+//	defer cancel()
+//	latency, err := client.Ping(ctx)
+func (c *CosmosDB) Ping(ctx context.Context) (time.Duration, error) {
+	start := time.Now()
+	_, err := c.ReadAccount(WithContext(ctx))
+	latency := time.Since(start)
+	if err != nil {
+		return latency, &PingError{Latency: latency, Err: err}
+	}
+	return latency, nil
+}
+
+// Warmup - prepares the client before it takes traffic by resolving the account host into the DNSCache of the
+// Config when one is set, reading the account metadata and reading the given collections concurrently, so the
+// first requests after a deploy find the addresses cached and the connections open. Missing collections fail it.
+//
+//	err := client.Warmup(ctx, "dbs/{db-id}/colls/{coll-id}/")
+func (c *CosmosDB) Warmup(ctx context.Context, colls ...string) error {
+	if cache := c.client.config.DNSCache; cache != nil {
+		u, err := url.Parse(c.client.getURI())
+		if err != nil {
+			return err
+		}
+		if _, err = cache.Addrs(ctx, u.Hostname()); err != nil {
+			return err
+		}
+	}
+	if _, err := c.ReadAccount(WithContext(ctx)); err != nil {
+		return err
+	}
+
+	// read the collections concurrently so a connection is opened per collection
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for _, coll := range colls {
+		wg.Add(1)
+		go func(coll string) {
+			defer wg.Done()
+			if _, err := c.ReadCollection(coll, WithContext(ctx)); err != nil {
+				once.Do(func() { firstErr = err })
+			}
+		}(coll)
+	}
+	wg.Wait()
+	return firstErr
+}
@@ -0,0 +1,58 @@
+package gocosmosdb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPing(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"id": "myaccount", "_self": "", "media": "//media/", "writableLocations": [{"name": "West US", "databaseAccountEndpoint": "https://myaccount-westus.documents.azure.com:443/"}]}`, 500)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	latency, err := client.Ping(context.Background())
+	assert.Nil(err)
+	assert.True(latency > 0)
+
+	_, err = client.Ping(context.Background())
+	assert.IsType(&PingError{}, err)
+	assert.Contains(err.Error(), "giving up after 1 attempts")
+}
+
+func TestReadAccount(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"id": "myaccount", "writableLocations": [{"name": "West US", "databaseAccountEndpoint": "https://myaccount-westus.documents.azure.com:443/"}], "userConsistencyPolicy": {"defaultConsistencyLevel": "Session"}}`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	account, err := client.ReadAccount()
+	assert.Nil(err)
+	assert.Equal("West US", account.WritableLocations[0].Name)
+	assert.Equal("Session", account.UserConsistencyPolicy.DefaultConsistencyLevel)
+}
+
+func TestWarmup(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"id": "myaccount"}`, `{"id": "coll"}`, 500)
+	defer s.Close()
+	cache := NewDNSCache(time.Minute)
+	lookups := []string{}
+	cache.lookup = func(ctx context.Context, host string) ([]string, error) {
+		lookups = append(lookups, host)
+		return []string{host}, nil
+	}
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", DNSCache: cache}, log)
+
+	err := client.Warmup(context.Background(), "dbs/db/colls/coll/")
+	assert.Nil(err)
+	assert.Equal([]string{"127.0.0.1"}, lookups)
+
+	// the cached addresses are reused
+	err = client.Warmup(context.Background())
+	assert.Contains(err.Error(), "giving up after 1 attempts")
+	assert.Len(lookups, 1)
+}
@@ -0,0 +1,132 @@
+package gocosmosdb
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PartitionStats - aggregates the RUs charged per collection and partition key, set it on the Config to find hot
+// partitions before the service throttles them
+//
+//	stats := gocosmosdb.NewPartitionStats(0.1)
+//	client := gocosmosdb.New(url, gocosmosdb.Config{MasterKey: key, PartitionStats: stats}, log)
+//	...
+//	for _, usage := range stats.Report().Hot(2) {
+//		log.Warnf("hot partition %s in %s: %.0f%% of RUs", usage.Key, usage.Collection, usage.Share*100)
+//	}
+type PartitionStats struct {
+	SampleRate float64 // share of requests recorded, the recorded charges are scaled up to estimate the total
+	MaxKeys    int     // partition keys tracked, requests for further keys are only counted as untracked
+	mu         sync.Mutex
+	usage      map[partitionUsageKey]*PartitionKeyUsage
+	untracked  float64
+}
+
+type partitionUsageKey struct {
+	coll, key string
+}
+
+// PartitionKeyUsage - the estimated RUs charged for requests to a partition key
+type PartitionKeyUsage struct {
+	Collection    string
+	Key           string
+	Requests      float64
+	RequestCharge float64
+	Share         float64 // of the RUs charged to the collection
+}
+
+// HotPartitionReport - the partition keys by RUs charged, highest first
+type HotPartitionReport struct {
+	Keys      []PartitionKeyUsage
+	Untracked float64 // RUs charged to keys past MaxKeys
+}
+
+// NewPartitionStats - creates stats recording the given share of requests, 1 records every request
+func NewPartitionStats(sampleRate float64) *PartitionStats {
+	return &PartitionStats{SampleRate: sampleRate, MaxKeys: 10000, usage: map[partitionUsageKey]*PartitionKeyUsage{}}
+}
+
+// record - samples the charge of a request scoped to a partition key
+func (s *PartitionStats) record(r *Request, resp *Response) {
+	key := r.Header.Get(HeaderPartitionKey)
+	if key == "" || s.SampleRate <= 0 || (s.SampleRate < 1 && rand.Float64() >= s.SampleRate) {
+		return
+	}
+	charge, err := resp.GetRUs()
+	if err != nil {
+		return
+	}
+	scale := 1 / s.SampleRate
+	if s.SampleRate > 1 {
+		scale = 1
+	}
+	k := partitionUsageKey{coll: collectionOf(r.URL.Path), key: key}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	usage, ok := s.usage[k]
+	if !ok {
+		if s.MaxKeys > 0 && len(s.usage) >= s.MaxKeys {
+			s.untracked += charge * scale
+			return
+		}
+		usage = &PartitionKeyUsage{Collection: k.coll, Key: k.key}
+		s.usage[k] = usage
+	}
+	usage.Requests += scale
+	usage.RequestCharge += charge * scale
+}
+
+// Report - returns the usage of every partition key recorded so far
+func (s *PartitionStats) Report() *HotPartitionReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	totals := map[string]float64{}
+	for k, usage := range s.usage {
+		totals[k.coll] += usage.RequestCharge
+	}
+	report := &HotPartitionReport{Untracked: s.untracked}
+	for _, usage := range s.usage {
+		u := *usage
+		if total := totals[u.Collection]; total > 0 {
+			u.Share = u.RequestCharge / total
+		}
+		report.Keys = append(report.Keys, u)
+	}
+	sort.Slice(report.Keys, func(i, j int) bool { return report.Keys[i].RequestCharge > report.Keys[j].RequestCharge })
+	return report
+}
+
+// Reset - forgets the usage recorded so far, eg. to report per interval
+func (s *PartitionStats) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.usage = map[partitionUsageKey]*PartitionKeyUsage{}
+	s.untracked = 0
+}
+
+// Hot - returns the keys charged more than factor times the mean RUs of the keys of their collection
+func (r *HotPartitionReport) Hot(factor float64) []PartitionKeyUsage {
+	keys := map[string]int{}
+	for _, usage := range r.Keys {
+		keys[usage.Collection]++
+	}
+	hot := []PartitionKeyUsage{}
+	for _, usage := range r.Keys {
+		// the mean share of a key of the collection is 1 / keys
+		if n := keys[usage.Collection]; n > 1 && usage.Share > factor/float64(n) {
+			hot = append(hot, usage)
+		}
+	}
+	return hot
+}
+
+// collectionOf - returns the collection link of a document path
+func collectionOf(path string) string {
+	path = strings.Trim(path, "/")
+	if i := strings.Index(path, "/docs"); i > -1 {
+		path = path[:i]
+	}
+	return path
+}
@@ -0,0 +1,44 @@
+package gocosmosdb
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartitionStats(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{}`, `{}`, `{}`, `{}`, `{}`)
+	s.SetHeader(HeaderRequestCharge, "10")
+	s.SetStatus(http.StatusCreated)
+	defer s.Close()
+	stats := NewPartitionStats(1)
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", PartitionStats: stats}, log)
+
+	for _, pk := range []string{"hot", "hot", "hot", "cold", "warm"} {
+		_, err := client.client.create("dbs/db/colls/coll/docs", `{"id": "1"}`, nil, PartitionKey(pk))
+		assert.Nil(err)
+	}
+
+	report := stats.Report()
+	assert.Equal(3, len(report.Keys))
+	assert.Equal(`["hot"]`, report.Keys[0].Key)
+	assert.Equal("dbs/db/colls/coll", report.Keys[0].Collection)
+	assert.Equal(30.0, report.Keys[0].RequestCharge)
+	assert.Equal(3.0, report.Keys[0].Requests)
+	assert.InDelta(0.6, report.Keys[0].Share, 0.001)
+
+	hot := report.Hot(1.5)
+	assert.Equal(1, len(hot))
+	assert.Equal(`["hot"]`, hot[0].Key)
+
+	stats.Reset()
+	assert.Equal(0, len(stats.Report().Keys))
+}
+
+func TestCollectionOf(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("dbs/db/colls/coll", collectionOf("/dbs/db/colls/coll/docs/1"))
+	assert.Equal("dbs/db/colls/coll", collectionOf("/dbs/db/colls/coll/docs"))
+}
@@ -0,0 +1,50 @@
+package gocosmosdb
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// StatusRetryWith - the service did not apply a write conflicting with a concurrent one and asks for it again
+const StatusRetryWith = 449
+
+// Idempotent - marks a stored procedure execution as safe to run more than once, eg. because the script returns
+// early when it finds the idempotency key document it writes along its changes, so it is retried after timeouts,
+// server errors and lost connections like any other request. Those leave unknown whether the script ran, so
+// executions without the marker are only retried when the service throttled them or asked for them again with
+// 449 Retry With, in which case it did not run them.
+//
+//	_, err := client.ExecuteStoredProcedure(link, []interface{}{orderID, requestID}, &result, gocosmosdb.Idempotent())
+func Idempotent() CallOption {
+	return func(r *Request) error {
+		r.rIdempotent = true
+		return nil
+	}
+}
+
+// execution - marks the request as a stored procedure execution
+func execution(r *Request) error {
+	r.rExecution = true
+	return nil
+}
+
+type unappliedOnlyKey struct{}
+
+// unappliedOnly - returns a copy of the request only retried when the service did not apply it
+func unappliedOnly(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), unappliedOnlyKey{}, true))
+}
+
+// retryUnapplied - wraps a retry policy to give up on the requests marked by unappliedOnly after failures that
+// leave unknown whether the service applied them
+func retryUnapplied(policy retryablehttp.CheckRetry) retryablehttp.CheckRetry {
+	return func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		if ctx.Value(unappliedOnlyKey{}) == nil || err == nil && ctx.Err() == nil && resp != nil &&
+			(resp.StatusCode < http.StatusBadRequest || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == StatusRetryWith) {
+			return policy(ctx, resp, err)
+		}
+		return false, nil
+	}
+}
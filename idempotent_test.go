@@ -0,0 +1,43 @@
+package gocosmosdb
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecuteStoredProcedureRetries(t *testing.T) {
+	assert := assert.New(t)
+	config := Config{MasterKey: "YXJpZWwNCg==", RetryMax: 2, RetryWaitMin: time.Millisecond, RetryWaitMax: time.Millisecond, RetryThrottled: true}
+	link := "dbs/db/colls/coll/sprocs/transfer"
+
+	// a server error leaves unknown whether the script ran
+	s := ServerFactory(http.StatusServiceUnavailable, `{"moved": 1}`)
+	defer s.Close()
+	var result map[string]int
+	_, err := New(s.URL, config, log).ExecuteStoredProcedure(link, []string{"a"}, &result)
+	assert.NotNil(err)
+
+	s = ServerFactory(http.StatusServiceUnavailable, `{"moved": 1}`)
+	defer s.Close()
+	_, err = New(s.URL, config, log).ExecuteStoredProcedure(link, []string{"a"}, &result, Idempotent())
+	assert.Nil(err)
+	assert.Equal(map[string]int{"moved": 1}, result)
+
+	// a throttled script did not run
+	s = ServerFactory(http.StatusTooManyRequests, `{"moved": 2}`)
+	defer s.Close()
+	_, err = New(s.URL, config, log).ExecuteStoredProcedure(link, []string{"a"}, &result)
+	assert.Nil(err)
+	assert.Equal(map[string]int{"moved": 2}, result)
+
+	// other requests are retried as before
+	s = ServerFactory(http.StatusServiceUnavailable, `{"id": "doc"}`)
+	defer s.Close()
+	var doc Document
+	_, err = New(s.URL, config, log).ReadDocument("dbs/db/colls/coll/docs/doc", &doc)
+	assert.Nil(err)
+	assert.Equal("doc", doc.Id)
+}
@@ -0,0 +1,62 @@
+package gocosmosdb
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Instrumentation - observes every request of a client once it completed, retries included, set it on the Config
+// eg. to a PrometheusCollector
+type Instrumentation interface {
+	ObserveRequest(ctx context.Context, e RequestEvent)
+}
+
+// InstrumentationFunc - adapts a function to the Instrumentation interface
+type InstrumentationFunc func(ctx context.Context, e RequestEvent)
+
+// ObserveRequest - calls the function
+func (f InstrumentationFunc) ObserveRequest(ctx context.Context, e RequestEvent) {
+	f(ctx, e)
+}
+
+// RequestEvent - a completed request, as passed to Instrumentation
+type RequestEvent struct {
+	Method        string
+	ResourceType  string        // eg. "docs", "colls" or "sprocs"
+	StatusCode    int           // 0 when the request failed before a response arrived
+	Duration      time.Duration // from sending the request to its last response, the waits of retries included
+	RequestCharge float64
+	Retries       int   // made with RetryThrottled or a RetryPolicy
+	Err           error // why the request failed before a response arrived
+}
+
+// Throttled - reports whether the service still refused the request for exceeding the provisioned RUs
+func (e RequestEvent) Throttled() bool {
+	return e.StatusCode == http.StatusTooManyRequests
+}
+
+// Status - returns the status code as a label, "error" when the request failed before a response arrived
+func (e RequestEvent) Status() string {
+	if e.StatusCode == 0 {
+		return "error"
+	}
+	return strconv.Itoa(e.StatusCode)
+}
+
+// instrument - passes a completed request to the instrumentation of the client
+func (c *apiClient) instrument(r *Request, resp *http.Response, err error, start time.Time, retries *retryState) {
+	e := RequestEvent{
+		Method:       r.Method,
+		ResourceType: r.rType,
+		Duration:     time.Since(start),
+		Retries:      retries.Retries(),
+		Err:          err,
+	}
+	if resp != nil {
+		e.StatusCode = resp.StatusCode
+		e.RequestCharge = (&Response{Header: resp.Header}).RequestCharge()
+	}
+	c.config.Instrumentation.ObserveRequest(r.ctx(), e)
+}
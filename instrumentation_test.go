@@ -0,0 +1,45 @@
+package gocosmosdb
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstrumentation(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"id": "1"}`, http.StatusNotFound)
+	s.SetHeader(HeaderRequestCharge, "1.5")
+	defer s.Close()
+	var events []RequestEvent
+	observe := InstrumentationFunc(func(ctx context.Context, e RequestEvent) {
+		events = append(events, e)
+	})
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", Instrumentation: observe}, log)
+
+	var doc testDoc
+	_, err := client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+	assert.Nil(err)
+	_, err = client.DeleteDocument("dbs/db/colls/coll/docs/2")
+	assert.NotNil(err)
+	assert.Len(events, 2)
+	assert.Equal("GET", events[0].Method)
+	assert.Equal("docs", events[0].ResourceType)
+	assert.Equal("200", events[0].Status())
+	assert.Equal(1.5, events[0].RequestCharge)
+	assert.True(events[0].Duration > 0)
+	assert.Equal("DELETE", events[1].Method)
+	assert.Equal(http.StatusNotFound, events[1].StatusCode)
+
+	// requests failing before a response are observed with their error
+	s.Close()
+	events = nil
+	_, err = client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+	assert.NotNil(err)
+	assert.Len(events, 1)
+	assert.Equal("error", events[0].Status())
+	assert.NotNil(events[0].Err)
+	assert.False(events[0].Throttled())
+}
@@ -0,0 +1,101 @@
+package gocosmosdb
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+)
+
+// ContentHashField - the document field the hash of its content is stored under with Config.ContentHashes, empty
+// once the document was partially updated
+const ContentHashField = "_hash"
+
+// IntegrityReport - the result of VerifyContentHashes
+type IntegrityReport struct {
+	Scanned    int
+	Verified   int      // documents matching their hash
+	Unhashed   int      // documents without a hash, written without ContentHashes or partially updated since
+	Mismatched []string // the ids of the documents not matching their hash, corrupted or changed by other writers
+}
+
+// hashContent - stores the hash of the content of a document write under ContentHashField, partial updates clear
+// it since the content they leave is unknown
+func (c *apiClient) hashContent(r *Request) error {
+	if !c.config.ContentHashes || r.rType != "docs" || r.Body == nil {
+		return nil
+	}
+	if r.Method != http.MethodPost && r.Method != http.MethodPut && r.Method != http.MethodPatch {
+		return nil
+	}
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	if r.Method == http.MethodPatch {
+		if data, err = stampPatch(data, map[string]interface{}{ContentHashField: ""}); err != nil {
+			return err
+		}
+		r.setBody(data)
+		return nil
+	}
+	if data, err = hashDocument(data); err != nil {
+		return err
+	}
+	r.setBody(data)
+	return nil
+}
+
+// hashDocument - stores the hash of the content of a document under ContentHashField
+func hashDocument(data []byte) ([]byte, error) {
+	hash, err := contentHash(data)
+	if err != nil {
+		return nil, err
+	}
+	return stampDocument(data, map[string]interface{}{ContentHashField: hash})
+}
+
+// VerifyContentHashes - scans the documents of a collection in pages of pageSize, checking the documents written
+// with Config.ContentHashes still match the hash of their content, to detect corruption or changes by writers not
+// hashing their writes. Fields starting with an underscore, like the system properties and AuditField, are not
+// part of the hash.
+//
+//	report, err := client.VerifyContentHashes(ctx, "dbs/{db-id}/colls/{coll-id}/", 1000)
+//	if err == nil && len(report.Mismatched) > 0 {
+//		log.Infof("documents changed outside of the app: %v", report.Mismatched)
+//	}
+func (c *CosmosDB) VerifyContentHashes(ctx context.Context, coll string, pageSize int, opts ...CallOption) (*IntegrityReport, error) {
+	report := &IntegrityReport{}
+	docs := []json.RawMessage{}
+	feed := c.NewDocumentFeed(normalizeLink(coll), pageSize, &docs, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+	err := feed.ForEachPage(func() error {
+		for _, doc := range docs {
+			report.Scanned++
+			var meta struct {
+				Id   string `json:"id"`
+				Hash string `json:"_hash"`
+			}
+			if err := json.Unmarshal(doc, &meta); err != nil {
+				return err
+			}
+			if meta.Hash == "" {
+				report.Unhashed++
+				continue
+			}
+			hash, err := contentHash(doc)
+			if err != nil {
+				return err
+			}
+			if hash != meta.Hash {
+				report.Mismatched = append(report.Mismatched, meta.Id)
+				continue
+			}
+			report.Verified++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
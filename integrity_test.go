@@ -0,0 +1,118 @@
+package gocosmosdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentHashes(t *testing.T) {
+	assert := assert.New(t)
+	var written []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fmt.Fprintf(w, `{"Documents": [%s], "_count": %d}`, strings.Join(written, ","), len(written))
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		written = append(written, string(body))
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+		w.Write(body)
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", ContentHashes: true}, log)
+
+	type order struct {
+		Id    string   `json:"id"`
+		Total float64  `json:"total"`
+		Tags  []string `json:"tags,omitempty"`
+	}
+	_, err := client.CreateDocument("dbs/db/colls/coll/", &order{Id: "a", Total: 1.25, Tags: []string{"x"}})
+	assert.Nil(err)
+	doc := map[string]interface{}{}
+	assert.Nil(json.Unmarshal([]byte(written[0]), &doc))
+	assert.Len(doc[ContentHashField], 64)
+	_, err = client.CreateDocument("dbs/db/colls/coll/", &order{Id: "b", Total: 2})
+	assert.Nil(err)
+	_, err = client.Patch("dbs/db/colls/coll/docs/c", []PatchOperation{{Op: PatchSet, Path: "/total", Value: 3}}, nil)
+	assert.Nil(err)
+	assert.Contains(written[2], `{"op":"set","path":"/_hash","value":""}`)
+
+	// the service adds its system properties and returns numbers formatted its own way, documents changed without hashing
+	// no longer match
+	written[0] = strings.Replace(written[0], "1.25", "1.250", 1)
+	written[0] = strings.Replace(written[0], "{", `{"_rid": "1", "_ts": 1, `, 1)
+	written[1] = strings.Replace(written[1], `"total":2`, `"total":4`, 1)
+	written[2] = `{"id": "c", "total": 3}`
+	report, err := client.VerifyContentHashes(context.Background(), "dbs/db/colls/coll", 100)
+	assert.Nil(err)
+	assert.Equal(&IntegrityReport{Scanned: 3, Verified: 1, Unhashed: 1, Mismatched: []string{"b"}}, report)
+}
+
+func TestContentHashesBatch(t *testing.T) {
+	assert := assert.New(t)
+	var ids []string
+	docs := map[string]map[string]interface{}{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			var page []string
+			for _, id := range ids {
+				data, _ := json.Marshal(docs[id])
+				page = append(page, string(data))
+			}
+			fmt.Fprintf(w, `{"Documents": [%s], "_count": %d}`, strings.Join(page, ","), len(page))
+			return
+		}
+		// applies the creates and the set operations of patches of a batch
+		var ops []struct {
+			OperationType string `json:"operationType"`
+			ID            string `json:"id"`
+			ResourceBody  struct {
+				Id         string           `json:"id"`
+				Operations []PatchOperation `json:"operations"`
+			} `json:"resourceBody"`
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(body, &ops)
+		var raw []struct {
+			ResourceBody map[string]interface{} `json:"resourceBody"`
+		}
+		json.Unmarshal(body, &raw)
+		for i, op := range ops {
+			switch op.OperationType {
+			case BatchCreate:
+				ids = append(ids, op.ResourceBody.Id)
+				docs[op.ResourceBody.Id] = raw[i].ResourceBody
+			case BatchPatch:
+				for _, p := range op.ResourceBody.Operations {
+					docs[op.ID][strings.TrimPrefix(p.Path, "/")] = p.Value
+				}
+			}
+		}
+		fmt.Fprint(w, `[{"statusCode": 200}]`)
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", ContentHashes: true}, log)
+
+	_, err := client.ExecuteBatch(NewTransactionalBatch("dbs/db/colls/coll/", "t1").
+		Create(map[string]interface{}{"id": "a", "total": 1}).
+		Create(map[string]interface{}{"id": "b", "total": 2}))
+	assert.Nil(err)
+	_, err = client.ExecuteBatch(NewTransactionalBatch("dbs/db/colls/coll/", "t1").
+		Patch("b", []PatchOperation{{Op: PatchSet, Path: "/total", Value: 3}}))
+	assert.Nil(err)
+
+	// the patched document is no longer hashed rather than mismatched
+	report, err := client.VerifyContentHashes(context.Background(), "dbs/db/colls/coll", 100)
+	assert.Nil(err)
+	assert.Equal(&IntegrityReport{Scanned: 2, Verified: 1, Unhashed: 1}, report)
+}
@@ -0,0 +1,105 @@
+package gocosmosdb
+
+import "sort"
+
+// Inventory - the databases and collections of an account with their settings, as read by Inventory
+type Inventory struct {
+	Databases []DatabaseInventory
+}
+
+// DatabaseInventory - a database and its collections, ordered by id
+type DatabaseInventory struct {
+	Id          string
+	Throughput  *Throughput // shared by the collections without their own, nil when the database has none
+	Collections []CollectionInventory
+}
+
+// CollectionInventory - the settings of a collection
+type CollectionInventory struct {
+	Id                string
+	PartitionKeyPaths []string
+	IndexingPolicy    IndexingPolicy
+	DefaultTTL        int
+	UniqueKeys        [][]string
+	Throughput        *Throughput // nil when the collection shares the throughput of its database
+}
+
+// Throughput - the throughput of an offer
+type Throughput struct {
+	RUs                    int // the provisioned or, with autoscale, current RU/s
+	AutoscaleMaxThroughput int // 0 for manual throughput
+}
+
+// Inventory - walks the databases and collections of the account into one report of their throughput, indexing
+// policies and TTL settings, for governance and drift detection
+//
+//	inv, err := client.Inventory()
+//	for _, db := range inv.Databases {
+//		for _, coll := range db.Collections {
+//			if coll.DefaultTTL == 0 {
+//				log.Warnf("%s/%s keeps documents forever", db.Id, coll.Id)
+//			}
+//		}
+//	}
+func (c *CosmosDB) Inventory(opts ...CallOption) (*Inventory, error) {
+	offers, err := c.ReadOffers(opts...)
+	if err != nil {
+		return nil, err
+	}
+	// the offers reference their resource by _rid
+	throughput := map[string]*Throughput{}
+	for _, offer := range offers {
+		t := &Throughput{RUs: offer.Content.OfferThroughput}
+		if autoscale := offer.Content.OfferAutopilotSettings; autoscale != nil {
+			t.AutoscaleMaxThroughput = autoscale.MaxThroughput
+		}
+		throughput[offer.OfferResourceId] = t
+	}
+	dbs, err := c.ReadDatabases(opts...)
+	if err != nil {
+		return nil, err
+	}
+	inv := &Inventory{Databases: make([]DatabaseInventory, 0, len(dbs))}
+	for _, db := range dbs {
+		colls, err := c.ReadCollections("dbs/"+db.Id+"/", opts...)
+		if err != nil {
+			return nil, err
+		}
+		d := DatabaseInventory{Id: db.Id, Throughput: throughput[db.Rid], Collections: make([]CollectionInventory, 0, len(colls))}
+		for _, coll := range colls {
+			ci := CollectionInventory{
+				Id:                coll.Id,
+				PartitionKeyPaths: coll.PartitionKeyDef.Paths,
+				IndexingPolicy:    coll.IndexingPolicy,
+				DefaultTTL:        coll.DefaultTTL,
+				Throughput:        throughput[coll.Rid],
+			}
+			if coll.UniqueKeyPolicy != nil {
+				for _, key := range coll.UniqueKeyPolicy.UniqueKeys {
+					ci.UniqueKeys = append(ci.UniqueKeys, key.Paths)
+				}
+			}
+			d.Collections = append(d.Collections, ci)
+		}
+		sort.Slice(d.Collections, func(i, j int) bool { return d.Collections[i].Id < d.Collections[j].Id })
+		inv.Databases = append(inv.Databases, d)
+	}
+	sort.Slice(inv.Databases, func(i, j int) bool { return inv.Databases[i].Id < inv.Databases[j].Id })
+	return inv, nil
+}
+
+// ContainerSpec - returns the spec the collection matches, to diff against the declared specs of ApplyContainerSpec
+func (ci CollectionInventory) ContainerSpec() *ContainerSpec {
+	spec := &ContainerSpec{Id: ci.Id, DefaultTTL: ci.DefaultTTL, UniqueKeys: ci.UniqueKeys}
+	if len(ci.PartitionKeyPaths) > 0 {
+		spec.PartitionKeyPath = ci.PartitionKeyPaths[0]
+	}
+	policy := ci.IndexingPolicy
+	spec.IndexingPolicy = &policy
+	if t := ci.Throughput; t != nil && t.AutoscaleMaxThroughput > 0 {
+		spec.AutoscaleMaxThroughput = t.AutoscaleMaxThroughput
+	} else if t != nil {
+		spec.Throughput = t.RUs
+	}
+	return spec
+}
@@ -0,0 +1,58 @@
+package gocosmosdb
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInventory(t *testing.T) {
+	assert := assert.New(t)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/offers":
+			fmt.Fprint(w, `{"Offers": [
+				{"offerResourceId": "db1==", "content": {"offerThroughput": 1000}},
+				{"offerResourceId": "coll1==", "content": {"offerThroughput": 400, "offerAutopilotSettings": {"maxThroughput": 4000}}}
+			], "_count": 2}`)
+		case "/dbs":
+			fmt.Fprint(w, `{"Databases": [{"id": "shop", "_rid": "db1=="}, {"id": "audit", "_rid": "db2=="}], "_count": 2}`)
+		case "/dbs/shop/colls/":
+			fmt.Fprint(w, `{"DocumentCollections": [
+				{"id": "orders", "_rid": "coll1==", "partitionKey": {"paths": ["/tenant"], "kind": "Hash"}, "defaultTtl": 3600,
+				 "uniqueKeyPolicy": {"uniqueKeys": [{"paths": ["/number"]}]}},
+				{"id": "carts", "_rid": "coll2==", "partitionKey": {"paths": ["/tenant"], "kind": "Hash"}, "indexingPolicy": {"automatic": true, "indexingMode": "consistent"}}
+			], "_count": 2}`)
+		case "/dbs/audit/colls/":
+			fmt.Fprint(w, `{"DocumentCollections": [], "_count": 0}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	inv, err := client.Inventory()
+	assert.Nil(err)
+	assert.Len(inv.Databases, 2)
+	assert.Equal("audit", inv.Databases[0].Id)
+	assert.Nil(inv.Databases[0].Throughput)
+
+	shop := inv.Databases[1]
+	assert.Equal(&Throughput{RUs: 1000}, shop.Throughput)
+	assert.Equal("carts", shop.Collections[0].Id)
+	assert.Nil(shop.Collections[0].Throughput)
+	assert.Equal("consistent", shop.Collections[0].IndexingPolicy.IndexingMode)
+	orders := shop.Collections[1]
+	assert.Equal(3600, orders.DefaultTTL)
+	assert.Equal([][]string{{"/number"}}, orders.UniqueKeys)
+	assert.Equal(&Throughput{RUs: 400, AutoscaleMaxThroughput: 4000}, orders.Throughput)
+
+	spec := orders.ContainerSpec()
+	assert.Equal("/tenant", spec.PartitionKeyPath)
+	assert.Equal(4000, spec.AutoscaleMaxThroughput)
+	assert.Equal(0, spec.Throughput)
+}
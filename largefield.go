@@ -0,0 +1,136 @@
+package gocosmosdb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// LargeFieldRef - prefixes the value left in place of an offloaded field, followed by the key in the store
+const LargeFieldRef = "gocosmosdb-ref:"
+
+// LargeFieldStore - keeps the values of large fields outside of the documents, eg. blobstore.Store on Azure Blob storage
+type LargeFieldStore interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// LargeFields - offloads the string and []byte fields tagged `cosmosdb:"offload"` over Threshold bytes to Store,
+// leaving a reference in the document
+//
+//	type Report struct {
+//		gocosmosdb.Document
+//		Body string `json:"body" cosmosdb:"offload"`
+//	}
+//
+//	fields := &gocosmosdb.LargeFields{Store: blobstore.New(url, "large-fields", config, log), Threshold: 64 * 1024}
+//	stored, err := fields.Offload(ctx, report.Id, &report)
+//	_, err = client.CreateDocument(coll, stored)
+//	...
+//	err = fields.Rehydrate(ctx, &report)
+type LargeFields struct {
+	Store     LargeFieldStore
+	Threshold int
+}
+
+// Offload - returns a copy of the struct doc points to with the large tagged fields moved to the store under
+// id and the field name
+func (l *LargeFields) Offload(ctx context.Context, id string, doc interface{}) (interface{}, error) {
+	v := reflect.ValueOf(doc)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("LargeFields doc must be a pointer to a struct")
+	}
+	cp := reflect.New(v.Elem().Type())
+	cp.Elem().Set(v.Elem())
+	err := eachTaggedField(cp.Elem(), "offload", func(name string, field reflect.Value) error {
+		data := fieldBytes(field)
+		if len(data) <= l.Threshold || strings.HasPrefix(string(data), LargeFieldRef) {
+			return nil
+		}
+		key := id + "/" + name
+		if err := l.Store.Put(ctx, key, data); err != nil {
+			return err
+		}
+		setFieldBytes(field, []byte(LargeFieldRef+key))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cp.Interface(), nil
+}
+
+// Rehydrate - replaces the references in the tagged fields of the struct doc points to with the stored values
+func (l *LargeFields) Rehydrate(ctx context.Context, doc interface{}) error {
+	v := reflect.ValueOf(doc)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return errors.New("LargeFields doc must be a pointer to a struct")
+	}
+	return eachTaggedField(v.Elem(), "offload", func(name string, field reflect.Value) error {
+		ref := string(fieldBytes(field))
+		if !strings.HasPrefix(ref, LargeFieldRef) {
+			return nil
+		}
+		data, err := l.Store.Get(ctx, strings.TrimPrefix(ref, LargeFieldRef))
+		if err != nil {
+			return err
+		}
+		setFieldBytes(field, data)
+		return nil
+	})
+}
+
+// Remove - deletes the stored values referenced by the tagged fields, eg. after deleting the document
+func (l *LargeFields) Remove(ctx context.Context, doc interface{}) error {
+	v := reflect.ValueOf(doc)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return errors.New("LargeFields doc must be a pointer to a struct")
+	}
+	return eachTaggedField(v.Elem(), "offload", func(name string, field reflect.Value) error {
+		ref := string(fieldBytes(field))
+		if !strings.HasPrefix(ref, LargeFieldRef) {
+			return nil
+		}
+		return l.Store.Delete(ctx, strings.TrimPrefix(ref, LargeFieldRef))
+	})
+}
+
+// eachTaggedField - calls fn with the string and []byte fields with the cosmosdb tag, named by their json name
+func eachTaggedField(v reflect.Value, tag string, fn func(name string, field reflect.Value) error) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Tag.Get("cosmosdb") != tag {
+			continue
+		}
+		field := v.Field(i)
+		if field.Kind() != reflect.String && !(field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.Uint8) {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "" {
+			name = f.Name
+		}
+		if err := fn(name, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fieldBytes(field reflect.Value) []byte {
+	if field.Kind() == reflect.String {
+		return []byte(field.String())
+	}
+	return field.Bytes()
+}
+
+func setFieldBytes(field reflect.Value, data []byte) {
+	if field.Kind() == reflect.String {
+		field.SetString(string(data))
+		return
+	}
+	field.SetBytes(data)
+}
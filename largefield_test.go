@@ -0,0 +1,64 @@
+package gocosmosdb
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryFieldStore map[string][]byte
+
+func (m memoryFieldStore) Put(ctx context.Context, key string, data []byte) error {
+	m[key] = data
+	return nil
+}
+
+func (m memoryFieldStore) Get(ctx context.Context, key string) ([]byte, error) {
+	data, ok := m[key]
+	if !ok {
+		return nil, errors.New("no such field")
+	}
+	return data, nil
+}
+
+func (m memoryFieldStore) Delete(ctx context.Context, key string) error {
+	delete(m, key)
+	return nil
+}
+
+func TestLargeFields(t *testing.T) {
+	assert := assert.New(t)
+	type report struct {
+		Document
+		Title  string `json:"title"`
+		Body   string `json:"body" cosmosdb:"offload"`
+		Raw    []byte `json:"raw,omitempty" cosmosdb:"offload"`
+		Footer string `cosmosdb:"offload"`
+	}
+	store := memoryFieldStore{}
+	fields := &LargeFields{Store: store, Threshold: 10}
+	doc := &report{Title: strings.Repeat("t", 20), Body: strings.Repeat("b", 20), Raw: []byte(strings.Repeat("r", 20)), Footer: "small"}
+
+	stored, err := fields.Offload(context.Background(), "1", doc)
+	assert.Nil(err)
+	out := stored.(*report)
+	assert.Equal(LargeFieldRef+"1/body", out.Body)
+	assert.Equal(LargeFieldRef+"1/raw", string(out.Raw))
+	assert.Equal("small", out.Footer)
+	assert.Equal(doc.Title, out.Title)
+	assert.Equal(strings.Repeat("b", 20), doc.Body, "the passed document is left as is")
+	assert.Equal(2, len(store))
+
+	assert.Nil(fields.Rehydrate(context.Background(), out))
+	assert.Equal(doc, out)
+
+	stored, _ = fields.Offload(context.Background(), "1", doc)
+	assert.Nil(fields.Remove(context.Background(), stored))
+	assert.Equal(0, len(store))
+
+	_, err = fields.Offload(context.Background(), "1", *doc)
+	assert.NotNil(err)
+}
@@ -0,0 +1,122 @@
+package gocosmosdb
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ErrLeaseLost - the lease of a partition key range is held by another owner
+var ErrLeaseLost = errors.New("lease of the partition key range is held by another owner")
+
+// Lease - the ownership of a partition key range by a change feed processor instance and its checkpoint
+type Lease struct {
+	PkRange      string `json:"pkRange"`
+	Owner        string `json:"owner"`
+	Continuation string `json:"continuation"`
+}
+
+// LeaseStore - coordinates which processor instance reads which partition key range and keeps their checkpoints
+type LeaseStore interface {
+	// Acquire - takes the lease of the range for owner, returning nil when another owner holds it
+	Acquire(ctx context.Context, pkRange, owner string) (*Lease, error)
+	// Checkpoint - records the continuation of a held lease, failing with ErrLeaseLost once it is taken
+	Checkpoint(ctx context.Context, lease *Lease) error
+	// Release - gives the lease up so another owner can acquire it
+	Release(ctx context.Context, lease *Lease) error
+}
+
+// MemoryLeaseStore - a LeaseStore for processors running in one process, the checkpoints are lost on exit
+type MemoryLeaseStore struct {
+	mu     sync.Mutex
+	leases map[string]Lease
+}
+
+// NewMemoryLeaseStore - creates an empty MemoryLeaseStore
+func NewMemoryLeaseStore() *MemoryLeaseStore {
+	return &MemoryLeaseStore{leases: map[string]Lease{}}
+}
+
+// Acquire - takes the lease of the range when it is free
+func (s *MemoryLeaseStore) Acquire(ctx context.Context, pkRange, owner string) (*Lease, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lease := s.leases[pkRange]
+	if lease.Owner != "" && lease.Owner != owner {
+		return nil, nil
+	}
+	lease.PkRange = pkRange
+	lease.Owner = owner
+	s.leases[pkRange] = lease
+	return &lease, nil
+}
+
+// Checkpoint - records the continuation of a held lease
+func (s *MemoryLeaseStore) Checkpoint(ctx context.Context, lease *Lease) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.leases[lease.PkRange].Owner != lease.Owner {
+		return ErrLeaseLost
+	}
+	s.leases[lease.PkRange] = *lease
+	return nil
+}
+
+// Release - frees the lease keeping its checkpoint
+func (s *MemoryLeaseStore) Release(ctx context.Context, lease *Lease) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current := s.leases[lease.PkRange]
+	if current.Owner != lease.Owner {
+		return ErrLeaseLost
+	}
+	current.Owner = ""
+	s.leases[lease.PkRange] = current
+	return nil
+}
+
+// BalancedLeaseStore - a LeaseStore the processor instances sharing it use to spread the partition key ranges
+// evenly, an instance holding less than its share takes over the lease of an instance holding more than its own
+type BalancedLeaseStore interface {
+	LeaseStore
+	// Leases - returns the leases of the ranges, those free or expired have no owner
+	Leases(ctx context.Context) ([]Lease, error)
+	// Renew - keeps a held lease from expiring, failing with ErrLeaseLost once it is taken
+	Renew(ctx context.Context, lease *Lease) error
+	// Steal - takes the lease of the range for owner from whoever holds it, returning nil when it changed meanwhile
+	Steal(ctx context.Context, pkRange, owner string) (*Lease, error)
+}
+
+// Leases - returns the leases acquired so far
+func (s *MemoryLeaseStore) Leases(ctx context.Context) ([]Lease, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	leases := make([]Lease, 0, len(s.leases))
+	for _, lease := range s.leases {
+		leases = append(leases, lease)
+	}
+	sort.Slice(leases, func(i, j int) bool { return leases[i].PkRange < leases[j].PkRange })
+	return leases, nil
+}
+
+// Renew - checks the lease is still held, leases in memory do not expire
+func (s *MemoryLeaseStore) Renew(ctx context.Context, lease *Lease) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.leases[lease.PkRange].Owner != lease.Owner {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// Steal - takes the lease of the range whoever holds it
+func (s *MemoryLeaseStore) Steal(ctx context.Context, pkRange, owner string) (*Lease, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lease := s.leases[pkRange]
+	lease.PkRange = pkRange
+	lease.Owner = owner
+	s.leases[pkRange] = lease
+	return &lease, nil
+}
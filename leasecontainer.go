@@ -0,0 +1,177 @@
+package gocosmosdb
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultLeaseExpiry - how long a lease of a ContainerLeaseStore is held without being renewed or checkpointed
+var DefaultLeaseExpiry = time.Minute
+
+// ContainerLeaseStore - a BalancedLeaseStore keeping the leases as documents of a lease collection, so processor
+// instances in different processes share the partition key ranges of a collection and resume from the checkpoints
+// of each other. Leases not renewed within the expiry are free for other instances to acquire, every change is a
+// conditional replace so two instances never both take a lease.
+type ContainerLeaseStore struct {
+	db     *CosmosDB
+	coll   string
+	prefix string
+	expiry time.Duration
+	now    func() time.Time
+}
+
+// leaseDocument - a lease as stored in the lease collection
+type leaseDocument struct {
+	Document
+	Lease
+	RenewedAt int64 `json:"renewedAt"` // unix time of the last change by the owner
+}
+
+// NewContainerLeaseStore - creates a lease store in a collection partitioned by /id, the prefix of the lease ids
+// tells apart the processors sharing the collection eg. the name of the collection they read
+//
+//	leases := client.NewContainerLeaseStore("dbs/{db-id}/colls/leases/", "orders-")
+//	processor := client.NewChangeFeedProcessor("dbs/{db-id}/colls/orders/", handle, &gocosmosdb.ChangeFeedProcessorOptions{
+//		Leases: leases,
+//	})
+func (c *CosmosDB) NewContainerLeaseStore(coll, prefix string) *ContainerLeaseStore {
+	return &ContainerLeaseStore{db: c, coll: normalizeLink(coll), prefix: prefix, expiry: DefaultLeaseExpiry, now: time.Now}
+}
+
+// WithExpiry - sets how long a lease is held without being renewed, longer than the RenewInterval of the processors
+func (s *ContainerLeaseStore) WithExpiry(expiry time.Duration) *ContainerLeaseStore {
+	s.expiry = expiry
+	return s
+}
+
+// get - reads the lease of a range, nil when there is none yet
+func (s *ContainerLeaseStore) get(ctx context.Context, pkRange string) (*leaseDocument, error) {
+	doc := &leaseDocument{}
+	id := s.prefix + pkRange
+	if _, err := s.db.ReadDocument(s.coll+"docs/"+id, doc, PartitionKey(id), WithContext(ctx)); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return doc, nil
+}
+
+// expired - reports whether the owner of a lease stopped renewing it
+func (s *ContainerLeaseStore) expired(doc *leaseDocument) bool {
+	return s.now().Sub(time.Unix(doc.RenewedAt, 0)) > s.expiry
+}
+
+// take - makes owner the owner of a lease, nil when it was changed since it was read
+func (s *ContainerLeaseStore) take(ctx context.Context, doc *leaseDocument, pkRange, owner string) (*Lease, error) {
+	doc.PkRange = pkRange
+	doc.Owner = owner
+	doc.RenewedAt = s.now().Unix()
+	var err error
+	if doc.Etag == "" {
+		doc.Id = s.prefix + pkRange
+		_, err = s.db.CreateDocument(s.coll, doc, PartitionKey(doc.Id), WithContext(ctx))
+	} else {
+		_, err = s.db.ReplaceDocument(s.coll+"docs/"+doc.Id, doc, PartitionKey(doc.Id), IfMatch(doc.Etag), WithContext(ctx))
+	}
+	if errors.Is(err, ErrConflict) || errors.Is(err, ErrPreconditionFailed) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	lease := doc.Lease
+	return &lease, nil
+}
+
+// update - changes a held lease, renewing it, and tries again when the owner changed it meanwhile eg. to renew it
+func (s *ContainerLeaseStore) update(ctx context.Context, lease *Lease, change func(doc *leaseDocument)) error {
+	for {
+		doc, err := s.get(ctx, lease.PkRange)
+		if err != nil {
+			return err
+		}
+		if doc == nil || doc.Owner != lease.Owner {
+			return ErrLeaseLost
+		}
+		change(doc)
+		doc.RenewedAt = s.now().Unix()
+		_, err = s.db.ReplaceDocument(s.coll+"docs/"+doc.Id, doc, PartitionKey(doc.Id), IfMatch(doc.Etag), WithContext(ctx))
+		if !errors.Is(err, ErrPreconditionFailed) {
+			return err
+		}
+	}
+}
+
+// Acquire - takes the lease of the range when it is free or expired
+func (s *ContainerLeaseStore) Acquire(ctx context.Context, pkRange, owner string) (*Lease, error) {
+	doc, err := s.get(ctx, pkRange)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		doc = &leaseDocument{}
+	} else if doc.Owner != "" && doc.Owner != owner && !s.expired(doc) {
+		return nil, nil
+	}
+	return s.take(ctx, doc, pkRange, owner)
+}
+
+// Checkpoint - records the continuation of a held lease, renewing it
+func (s *ContainerLeaseStore) Checkpoint(ctx context.Context, lease *Lease) error {
+	continuation := lease.Continuation
+	return s.update(ctx, lease, func(doc *leaseDocument) {
+		doc.Continuation = continuation
+	})
+}
+
+// Renew - keeps a held lease from expiring
+func (s *ContainerLeaseStore) Renew(ctx context.Context, lease *Lease) error {
+	return s.update(ctx, lease, func(doc *leaseDocument) {})
+}
+
+// Release - frees the lease keeping its checkpoint
+func (s *ContainerLeaseStore) Release(ctx context.Context, lease *Lease) error {
+	return s.update(ctx, lease, func(doc *leaseDocument) {
+		doc.Owner = ""
+	})
+}
+
+// Steal - takes the lease of the range from its owner
+func (s *ContainerLeaseStore) Steal(ctx context.Context, pkRange, owner string) (*Lease, error) {
+	doc, err := s.get(ctx, pkRange)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		doc = &leaseDocument{}
+	}
+	return s.take(ctx, doc, pkRange, owner)
+}
+
+// Leases - returns the leases with the prefix of the store, those expired without an owner
+func (s *ContainerLeaseStore) Leases(ctx context.Context) ([]Lease, error) {
+	query := &QueryWithParameters{
+		Query:      "SELECT * FROM c WHERE STARTSWITH(c.id, @prefix)",
+		Parameters: []QueryParameter{{Name: "@prefix", Value: s.prefix}},
+	}
+	var leases []Lease
+	continuation := ""
+	for {
+		var docs []leaseDocument
+		resp, err := s.db.QueryDocumentsWithParameters(s.coll, query, &docs, CrossPartition(), Continuation(continuation), WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		for i := range docs {
+			if s.expired(&docs[i]) {
+				docs[i].Owner = ""
+			}
+			leases = append(leases, docs[i].Lease)
+		}
+		if continuation = resp.Continuation(); continuation == "" {
+			return leases, nil
+		}
+	}
+}
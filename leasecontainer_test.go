@@ -0,0 +1,114 @@
+package gocosmosdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// leaseServer - keeps the documents of a lease collection, with etags checked on replace
+type leaseServer struct {
+	mu   sync.Mutex
+	docs map[string]map[string]interface{}
+	etag int
+}
+
+func (s *leaseServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	body, _ := ioutil.ReadAll(r.Body)
+	id := strings.TrimPrefix(r.URL.Path, "/dbs/db/colls/leases/docs/")
+	reply := func(status int, doc interface{}) {
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(doc)
+	}
+	switch {
+	case r.Method == http.MethodPost && r.Header.Get(HeaderContentType) == "application/query+json":
+		docs := []interface{}{}
+		for _, doc := range s.docs {
+			docs = append(docs, doc)
+		}
+		reply(http.StatusOK, map[string]interface{}{"Documents": docs, "_count": len(docs)})
+	case r.Method == http.MethodPost:
+		doc := map[string]interface{}{}
+		json.Unmarshal(body, &doc)
+		if _, ok := s.docs[doc["id"].(string)]; ok {
+			reply(http.StatusConflict, map[string]string{"code": "Conflict"})
+			return
+		}
+		s.store(doc)
+		reply(http.StatusCreated, doc)
+	case r.Method == http.MethodGet:
+		if doc, ok := s.docs[id]; ok {
+			reply(http.StatusOK, doc)
+			return
+		}
+		reply(http.StatusNotFound, map[string]string{"code": "NotFound"})
+	case r.Method == http.MethodPut:
+		if doc, ok := s.docs[id]; !ok || doc["_etag"] != r.Header.Get(HeaderIfMatch) {
+			reply(http.StatusPreconditionFailed, map[string]string{"code": "PreconditionFailed"})
+			return
+		}
+		doc := map[string]interface{}{}
+		json.Unmarshal(body, &doc)
+		s.store(doc)
+		reply(http.StatusOK, doc)
+	}
+}
+
+func (s *leaseServer) store(doc map[string]interface{}) {
+	s.etag++
+	doc["_etag"] = fmt.Sprintf(`"%d"`, s.etag)
+	s.docs[doc["id"].(string)] = doc
+}
+
+func TestContainerLeaseStore(t *testing.T) {
+	assert := assert.New(t)
+	s := httptest.NewServer(&leaseServer{docs: map[string]map[string]interface{}{}})
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+	ctx := context.Background()
+
+	now := time.Unix(1560000000, 0)
+	leases := client.NewContainerLeaseStore("dbs/db/colls/leases", "orders-").WithExpiry(time.Minute)
+	leases.now = func() time.Time { return now }
+
+	a, err := leases.Acquire(ctx, "0", "a")
+	assert.Nil(err)
+	assert.Equal(&Lease{PkRange: "0", Owner: "a"}, a)
+	a.Continuation = `"42"`
+	assert.Nil(leases.Checkpoint(ctx, a))
+	assert.Nil(leases.Renew(ctx, a))
+
+	// held leases are only acquired once they expire
+	b, err := leases.Acquire(ctx, "0", "b")
+	assert.Nil(err)
+	assert.Nil(b)
+	now = now.Add(2 * time.Minute)
+	all, err := leases.Leases(ctx)
+	assert.Nil(err)
+	assert.Equal([]Lease{{PkRange: "0", Continuation: `"42"`}}, all)
+	b, err = leases.Acquire(ctx, "0", "b")
+	assert.Nil(err)
+	assert.Equal(&Lease{PkRange: "0", Owner: "b", Continuation: `"42"`}, b)
+	assert.Equal(ErrLeaseLost, leases.Checkpoint(ctx, a))
+	assert.Equal(ErrLeaseLost, leases.Renew(ctx, a))
+
+	c, err := leases.Steal(ctx, "0", "c")
+	assert.Nil(err)
+	assert.Equal("c", c.Owner)
+	assert.Equal(ErrLeaseLost, leases.Release(ctx, b))
+	assert.Nil(leases.Release(ctx, c))
+	all, err = leases.Leases(ctx)
+	assert.Nil(err)
+	assert.Equal([]Lease{{PkRange: "0", Continuation: `"42"`}}, all)
+}
@@ -0,0 +1,43 @@
+package gocosmosdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// QueueTimeoutError - a call waited longer than Config.MaxConcurrentWait for one of the MaxConcurrentRequests slots
+type QueueTimeoutError struct {
+	Limit  int
+	Waited time.Duration
+}
+
+func (e *QueueTimeoutError) Error() string {
+	return fmt.Sprintf("gave up on the request after waiting %v for one of %d concurrent requests", e.Waited, e.Limit)
+}
+
+// acquire - takes one of the concurrent request slots of the client, waiting for at most MaxConcurrentWait or the
+// end of the context, the returned release must be called once the request is done
+func (c *apiClient) acquire(ctx context.Context) (func(), error) {
+	release := func() { <-c.slots }
+	select {
+	case c.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+	start := time.Now()
+	var timeout <-chan time.Time
+	if wait := c.config.MaxConcurrentWait; wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case c.slots <- struct{}{}:
+		return release, nil
+	case <-timeout:
+		return nil, &QueueTimeoutError{Limit: cap(c.slots), Waited: time.Since(start)}
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
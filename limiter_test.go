@@ -0,0 +1,50 @@
+package gocosmosdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxConcurrentRequests(t *testing.T) {
+	assert := assert.New(t)
+	started, unblock := make(chan struct{}, 1), make(chan struct{})
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-unblock
+		w.Write([]byte(`{"id": "1"}`))
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", MaxConcurrentRequests: 1, MaxConcurrentWait: 20 * time.Millisecond}, log)
+
+	done := make(chan error)
+	go func() {
+		var doc Document
+		_, err := client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+		done <- err
+	}()
+	<-started
+
+	var doc Document
+	_, err := client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+	var timeout *QueueTimeoutError
+	assert.True(errors.As(err, &timeout))
+	assert.Equal(1, timeout.Limit)
+	assert.True(timeout.Waited >= 20*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.ReadDocument("dbs/db/colls/coll/docs/1", &doc, WithContext(ctx))
+	assert.Equal(context.Canceled, err)
+
+	close(unblock)
+	assert.Nil(<-done)
+	_, err = client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+	assert.Nil(err)
+	assert.Equal("1", doc.Id)
+}
@@ -0,0 +1,112 @@
+package gocosmosdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// MissingFields - fills missing with the paths of the struct fields the result is read into which the response
+// lacked, telling documents written before a field was added apart from those holding its zero value
+//
+//	var missing []string
+//	_, err := client.ReadDocument(link, &order, gocosmosdb.MissingFields(&missing))
+//	// missing is eg. ["shipping.carrier"] for orders written before the carrier was recorded
+//
+// Fields of the documents of a query are reported under their index eg. "Documents[3].shipping.carrier". Fields
+// tagged omitempty, written without a value when empty, fields decoded by their own UnmarshalJSON and the fields
+// of maps are not checked.
+func MissingFields(missing *[]string) CallOption {
+	return func(r *Request) error {
+		r.rMissing = missing
+		return nil
+	}
+}
+
+var jsonUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// readMissing - reads a response into data like readJson, recording the fields it lacked
+func readMissing(reader io.Reader, data interface{}, missing *[]string) error {
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	if err = readJson(bytes.NewReader(body), data); err != nil {
+		return err
+	}
+	*missing = (*missing)[:0]
+	missingFields(reflect.ValueOf(data), body, "", missing)
+	return nil
+}
+
+// missingFields - appends the paths of the fields of v absent from its JSON
+func missingFields(v reflect.Value, data json.RawMessage, path string, missing *[]string) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) || reflect.PtrTo(v.Type()).Implements(jsonUnmarshaler) {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		obj := map[string]json.RawMessage{}
+		if json.Unmarshal(data, &obj) != nil {
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			tag := f.Tag.Get("json")
+			name := strings.Split(tag, ",")[0]
+			switch {
+			case name == "-" || f.PkgPath != "" && !f.Anonymous:
+				continue
+			case f.Anonymous && name == "":
+				// the fields of embedded structs are read from the same object
+				missingFields(v.Field(i), data, path, missing)
+				continue
+			case name == "":
+				name = f.Name
+			}
+			field := name
+			if path != "" {
+				field = path + "." + name
+			}
+			value, ok := lookupField(obj, name)
+			if !ok {
+				if !strings.Contains(tag, ",omitempty") {
+					*missing = append(*missing, field)
+				}
+				continue
+			}
+			missingFields(v.Field(i), value, field, missing)
+		}
+	case reflect.Slice, reflect.Array:
+		items := []json.RawMessage{}
+		if v.Type().Elem().Kind() == reflect.Uint8 || json.Unmarshal(data, &items) != nil {
+			return
+		}
+		for i := 0; i < len(items) && i < v.Len(); i++ {
+			missingFields(v.Index(i), items[i], path+"["+strconv.Itoa(i)+"]", missing)
+		}
+	}
+}
+
+// lookupField - finds the value of a field the way encoding/json does, preferring an exact match of its name
+func lookupField(obj map[string]json.RawMessage, name string) (json.RawMessage, bool) {
+	if value, ok := obj[name]; ok {
+		return value, true
+	}
+	for key, value := range obj {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,55 @@
+package gocosmosdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type shipping struct {
+	Carrier string `json:"carrier"`
+	Days    int    `json:"days"`
+}
+
+type missingOrder struct {
+	Document
+	Total     float64     `json:"total"`
+	Note      string      `json:"note,omitempty"`
+	Shipping  shipping    `json:"shipping"`
+	Lines     []shipping  `json:"lines"`
+	Placed    time.Time   `json:"placed"`
+	Status    string      // matched case insensitively
+	Ignored   string      `json:"-"`
+	internal  string
+	Discounts *[]shipping `json:"discounts"`
+}
+
+func TestMissingFields(t *testing.T) {
+	assert := assert.New(t)
+	doc := `{"id": "o1", "total": 0, "shipping": {"days": 2}, "lines": [{"carrier": "ups", "days": 1}, {"days": 3}],
+		"placed": "2019-06-01T00:00:00Z", "status": "open", "discounts": null}`
+	s := ServerFactory(doc, `{"Documents": [`+doc+`, {"id": "o2"}], "_count": 2}`, doc)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	missing := []string{"stale"}
+	order := missingOrder{}
+	_, err := client.ReadDocument("dbs/db/colls/coll/docs/o1", &order, MissingFields(&missing))
+	assert.Nil(err)
+	assert.Equal("open", order.Status)
+	assert.Equal([]string{"shipping.carrier", "lines[1].carrier"}, missing)
+
+	var orders []missingOrder
+	_, err = client.QueryDocuments("dbs/db/colls/coll/", "SELECT * FROM c", &orders, MissingFields(&missing))
+	assert.Nil(err)
+	assert.Contains(missing, "Documents[0].shipping.carrier")
+	assert.Contains(missing, "Documents[1].total")
+	assert.NotContains(missing, "Documents[0].total")
+
+	// without the option nothing is recorded
+	missing = nil
+	_, err = client.ReadDocument("dbs/db/colls/coll/docs/o1", &order)
+	assert.Nil(err)
+	assert.Nil(missing)
+}
@@ -0,0 +1,85 @@
+package gocosmosdb
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// NamedOffer - an offer with the ids of the database and collection it provisions throughput for
+type NamedOffer struct {
+	Offer
+	Database   string // empty when the resource of the offer no longer exists
+	Collection string // empty for the throughput a database shares with its collections
+}
+
+// ReadNamedOffers - reads every offer of the account joined to the ids of its database and collection, ordered by
+// database then collection, for throughput audits in one call
+//
+//	offers, err := client.ReadNamedOffers()
+//	for _, o := range offers {
+//		fmt.Println(o.Database, o.Collection, o.Content.OfferThroughput)
+//	}
+func (c *CosmosDB) ReadNamedOffers(opts ...CallOption) ([]NamedOffer, error) {
+	offers, err := c.ReadOffers(opts...)
+	if err != nil {
+		return nil, err
+	}
+	dbs, err := c.ReadDatabases(opts...)
+	if err != nil {
+		return nil, err
+	}
+	// the offers reference their resource by _rid
+	names := map[string]NamedOffer{}
+	for _, db := range dbs {
+		names[db.Rid] = NamedOffer{Database: db.Id}
+		colls, err := c.ReadCollections("dbs/"+db.Id+"/", opts...)
+		if err != nil {
+			return nil, err
+		}
+		for _, coll := range colls {
+			names[coll.Rid] = NamedOffer{Database: db.Id, Collection: coll.Id}
+		}
+	}
+	named := make([]NamedOffer, 0, len(offers))
+	for _, offer := range offers {
+		n := names[offer.OfferResourceId]
+		n.Offer = offer
+		named = append(named, n)
+	}
+	sort.SliceStable(named, func(i, j int) bool {
+		if named[i].Database != named[j].Database {
+			return named[i].Database < named[j].Database
+		}
+		return named[i].Collection < named[j].Collection
+	})
+	return named, nil
+}
+
+// ReplaceThroughput - changes the throughput of an offer keeping its mode, the RU/s of manual throughput or the
+// maximum of autoscale throughput, which is sent along the offer in the autopilot settings header
+//
+//	offer, err := client.ReplaceThroughput("offers/"+offer.Rid, gocosmosdb.Throughput{AutoscaleMaxThroughput: 20000})
+func (c *CosmosDB) ReplaceThroughput(link string, t Throughput, opts ...CallOption) (*Offer, error) {
+	if (t.RUs > 0) == (t.AutoscaleMaxThroughput > 0) {
+		return nil, errors.New("throughput takes either RUs or AutoscaleMaxThroughput")
+	}
+	offer := &Offer{}
+	if _, err := c.client.read(link, offer, opts...); err != nil {
+		return nil, err
+	}
+	autoscale := offer.Content.OfferAutopilotSettings
+	switch {
+	case t.RUs > 0 && autoscale != nil:
+		return nil, fmt.Errorf("offer %s uses autoscale throughput, it cannot change to manual throughput", offer.Id)
+	case t.AutoscaleMaxThroughput > 0 && autoscale == nil:
+		return nil, fmt.Errorf("offer %s uses manual throughput, it cannot change to autoscale throughput", offer.Id)
+	case t.RUs > 0:
+		offer.Content.OfferThroughput = t.RUs
+		return c.ReplaceOffer(link, offer, opts...)
+	}
+	autoscale.MaxThroughput = t.AutoscaleMaxThroughput
+	// the service derives the current throughput from the autoscale maximum
+	offer.Content.OfferThroughput = 0
+	return c.ReplaceOffer(link, offer, append(opts, AutoscaleThroughput(t.AutoscaleMaxThroughput))...)
+}
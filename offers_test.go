@@ -0,0 +1,82 @@
+package gocosmosdb
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadNamedOffers(t *testing.T) {
+	assert := assert.New(t)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/offers":
+			fmt.Fprint(w, `{"Offers": [
+				{"id": "o2", "offerResourceId": "coll1==", "content": {"offerThroughput": 400}},
+				{"id": "o1", "offerResourceId": "db1==", "content": {"offerThroughput": 1000}},
+				{"id": "o3", "offerResourceId": "gone==", "content": {"offerThroughput": 400}}
+			], "_count": 3}`)
+		case "/dbs":
+			fmt.Fprint(w, `{"Databases": [{"id": "shop", "_rid": "db1=="}], "_count": 1}`)
+		case "/dbs/shop/colls/":
+			fmt.Fprint(w, `{"DocumentCollections": [{"id": "orders", "_rid": "coll1=="}, {"id": "carts", "_rid": "coll2=="}], "_count": 2}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	offers, err := client.ReadNamedOffers()
+	assert.Nil(err)
+	assert.Len(offers, 3)
+	assert.Equal("", offers[0].Database)
+	assert.Equal("o3", offers[0].Id)
+	assert.Equal([]string{"shop", ""}, []string{offers[1].Database, offers[1].Collection})
+	assert.Equal(1000, offers[1].Content.OfferThroughput)
+	assert.Equal([]string{"shop", "orders"}, []string{offers[2].Database, offers[2].Collection})
+}
+
+func TestReplaceThroughput(t *testing.T) {
+	assert := assert.New(t)
+	var body, autopilot string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/offers/auto/":
+			fmt.Fprint(w, `{"id": "auto", "_rid": "auto", "content": {"offerThroughput": 400, "offerAutopilotSettings": {"maxThroughput": 4000}}}`)
+		case r.Method == http.MethodGet:
+			fmt.Fprint(w, `{"id": "manual", "_rid": "manual", "content": {"offerThroughput": 400}}`)
+		default:
+			b, _ := ioutil.ReadAll(r.Body)
+			body, autopilot = string(b), r.Header.Get(HeaderOfferAutopilotSettings)
+			w.Write(b)
+		}
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	offer, err := client.ReplaceThroughput("offers/auto/", Throughput{AutoscaleMaxThroughput: 20000})
+	assert.Nil(err)
+	assert.Equal(20000, offer.Content.OfferAutopilotSettings.MaxThroughput)
+	assert.Equal(`{"maxThroughput":20000}`, autopilot)
+	assert.Contains(body, `"offerAutopilotSettings":{"maxThroughput":20000}`)
+	assert.NotContains(body, "offerThroughput")
+
+	offer, err = client.ReplaceThroughput("offers/manual/", Throughput{RUs: 1000})
+	assert.Nil(err)
+	assert.Equal(1000, offer.Content.OfferThroughput)
+	assert.Empty(autopilot)
+
+	_, err = client.ReplaceThroughput("offers/manual/", Throughput{AutoscaleMaxThroughput: 20000})
+	assert.EqualError(err, "offer manual uses manual throughput, it cannot change to autoscale throughput")
+	_, err = client.ReplaceThroughput("offers/auto/", Throughput{RUs: 1000})
+	assert.EqualError(err, "offer auto uses autoscale throughput, it cannot change to manual throughput")
+	_, err = client.ReplaceThroughput("offers/auto/", Throughput{AutoscaleMaxThroughput: 500})
+	assert.EqualError(err, "autoscale max throughput must be at least 1000 RU/s, got 500")
+	_, err = client.ReplaceThroughput("offers/auto/", Throughput{})
+	assert.NotNil(err)
+}
@@ -0,0 +1,49 @@
+package gocosmosdb
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// WithOldDocument - reads the document a replace or delete changes into old, for audit trails or to invalidate
+// caches by the values it had. The service does not return the previous image, so the document is read first and
+// the write made on the condition that it did not change since, unless IfMatch was passed, failing with
+// ErrPreconditionFailed when another write came in between. The read is charged like any other.
+//
+//	var old Order
+//	_, err := client.ReplaceDocument(link, &order, gocosmosdb.PartitionKey(order.TenantId), gocosmosdb.WithOldDocument(&old))
+//	if err == nil && old.Status != order.Status {
+//		cache.Delete(old.Status)
+//	}
+func WithOldDocument(old interface{}) CallOption {
+	return func(r *Request) error {
+		r.rOld = old
+		return nil
+	}
+}
+
+// readOld - reads the document a replace or delete with WithOldDocument changes, conditioning the write on it
+func (c *apiClient) readOld(r *Request, link string) error {
+	if r.rOld == nil || r.rType != "docs" || (r.Method != http.MethodPut && r.Method != http.MethodDelete) {
+		return nil
+	}
+	sameKey := func(read *Request) error {
+		if pk, ok := r.Header[HeaderPartitionKey]; ok {
+			read.Header[HeaderPartitionKey] = pk
+		}
+		return nil
+	}
+	var old json.RawMessage
+	resp, err := c.read(link, &old, sameKey, WithContext(r.ctx()))
+	if errors.Is(err, ErrNotFound) && r.Method == http.MethodDelete && r.rIgnoreNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if r.Header.Get(HeaderIfMatch) == "" {
+		r.Header.Set(HeaderIfMatch, resp.ETag())
+	}
+	return json.Unmarshal(old, r.rOld)
+}
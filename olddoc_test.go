@@ -0,0 +1,53 @@
+package gocosmosdb
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithOldDocument(t *testing.T) {
+	assert := assert.New(t)
+	var requests []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, fmt.Sprintf("%s %s %s", r.Method, r.Header.Get(HeaderPartitionKey), r.Header.Get(HeaderIfMatch)))
+		switch {
+		case r.URL.Path == "/dbs/db/colls/coll/docs/gone":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"code": "NotFound"}`)
+		case r.Method == http.MethodGet:
+			w.Header().Set(HeaderETag, `"e1"`)
+			fmt.Fprint(w, `{"id": "1", "status": "open"}`)
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			fmt.Fprint(w, `{"id": "1", "status": "shipped"}`)
+		}
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	type order struct {
+		Id     string `json:"id"`
+		Status string `json:"status"`
+	}
+	var old order
+	_, err := client.ReplaceDocument("dbs/db/colls/coll/docs/1", &order{Id: "1", Status: "shipped"}, PartitionKey("t1"), WithOldDocument(&old))
+	assert.Nil(err)
+	assert.Equal(order{Id: "1", Status: "open"}, old)
+	_, err = client.DeleteDocument("dbs/db/colls/coll/docs/1", PartitionKey("t1"), IfMatch(`"e0"`), WithOldDocument(&old))
+	assert.Nil(err)
+	assert.Equal([]string{`GET ["t1"] `, `PUT ["t1"] "e1"`, `GET ["t1"] `, `DELETE ["t1"] "e0"`}, requests)
+
+	// a missing document fails unless its delete ignores it
+	requests = nil
+	_, err = client.ReplaceDocument("dbs/db/colls/coll/docs/gone", &order{}, WithOldDocument(&old))
+	assert.True(errors.Is(err, ErrNotFound))
+	_, err = client.DeleteDocument("dbs/db/colls/coll/docs/gone", WithIgnoreNotFound(), WithOldDocument(&old))
+	assert.Nil(err)
+	assert.Equal([]string{"GET  ", "GET  ", "DELETE  "}, requests)
+}
@@ -3,7 +3,9 @@ package gocosmosdb
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strconv"
+	"strings"
 )
 
 // Consistency type to define consistency levels
@@ -16,13 +18,29 @@ const (
 	// Bounded consistency level
 	Bounded Consistency = "bounded"
 
+	// BoundedStaleness consistency level, the name the SDKs of other languages send for Bounded
+	BoundedStaleness Consistency = "boundedstaleness"
+
 	// Session consistency level
 	Session Consistency = "session"
 
 	// Eventual consistency level
 	Eventual Consistency = "eventual"
+
+	// ConsistentPrefix consistency level
+	ConsistentPrefix Consistency = "consistentprefix"
 )
 
+// consistencyLevels - the levels a call may ask for, matched regardless of case
+var consistencyLevels = map[Consistency]bool{
+	Strong:           true,
+	Bounded:          true,
+	BoundedStaleness: true,
+	Session:          true,
+	ConsistentPrefix: true,
+	Eventual:         true,
+}
+
 // CallOption function
 type CallOption func(r *Request) error
 
@@ -83,9 +101,23 @@ func Continuation(continuation string) CallOption {
 	}
 }
 
-// ConsistencyLevel - override for read options against documents and attachments. The valid values are: Strong, Bounded, Session, or Eventual (in order of strongest to weakest). The override must be the same or weaker than the account�s configured consistency level.
+// WithContinuationTokenLimitKB - caps the size of returned continuation tokens so they fit in cookies or stateless page cursors
+func WithContinuationTokenLimitKB(kb int) CallOption {
+	return func(r *Request) error {
+		if kb < 1 {
+			return fmt.Errorf("continuation token limit must be at least 1KB, got %d", kb)
+		}
+		r.Header.Set(HeaderResponseContinuationTokenLimit, strconv.Itoa(kb))
+		return nil
+	}
+}
+
+// ConsistencyLevel - override for read options against documents and attachments. The valid values are: Strong, Bounded, Session, ConsistentPrefix or Eventual (in order of strongest to weakest). The override must be the same or weaker than the account's configured consistency level, eg. to downgrade latency sensitive reads to Eventual.
 func ConsistencyLevel(consistency Consistency) CallOption {
 	return func(r *Request) error {
+		if !consistencyLevels[Consistency(strings.ToLower(string(consistency)))] {
+			return fmt.Errorf("unknown consistency level %q", consistency)
+		}
 		r.Header.Set(HeaderConsistencyLevel, string(consistency))
 		return nil
 	}
@@ -107,6 +139,19 @@ func CrossPartition() CallOption {
 	}
 }
 
+// SinglePartition - restricts a query to the partition of the key, overriding the fan out across partitions the
+// client does with a PartitionKeyStructField or CrossPartitionQueries
+func SinglePartition(partitionKey interface{}) CallOption {
+	pk := PartitionKey(partitionKey)
+	return func(r *Request) error {
+		if err := pk(r); err != nil {
+			return err
+		}
+		r.Header.Del(HeaderCrossPartition)
+		return nil
+	}
+}
+
 // IfMatch - used to make operation conditional for optimistic concurrency. The value should be the etag value of the resource.
 // (applicable only on PUT and DELETE)
 func IfMatch(eTag string) CallOption {
@@ -142,14 +187,40 @@ func ChangeFeed() CallOption {
 	}
 }
 
-// ThroughputRUs - adds throughput headers for container creation
+// ThroughputRUs - provisions manual throughput for database or container creation
 func ThroughputRUs(rus int) CallOption {
 	return func(r *Request) error {
+		if err := r.throughput("ThroughputRUs"); err != nil {
+			return err
+		}
 		r.Header.Set(HeaderOfferThroughput, strconv.Itoa(rus))
 		return nil
 	}
 }
 
+// AutoscaleThroughput - provisions autoscale throughput for database or container creation and offer replaces,
+// scaling between a tenth of maxRUs and maxRUs
+func AutoscaleThroughput(maxRUs int) CallOption {
+	return func(r *Request) error {
+		if maxRUs < 1000 {
+			return fmt.Errorf("autoscale max throughput must be at least 1000 RU/s, got %d", maxRUs)
+		}
+		if err := r.throughput("AutoscaleThroughput"); err != nil {
+			return err
+		}
+		r.Header.Set(HeaderOfferAutopilotSettings, fmt.Sprintf(`{"maxThroughput":%d}`, maxRUs))
+		return nil
+	}
+}
+
+// NoThroughput - provisions no throughput for container creation, for containers sharing the database throughput
+// or in serverless accounts, this is the default when no throughput option is passed
+func NoThroughput() CallOption {
+	return func(r *Request) error {
+		return r.throughput("NoThroughput")
+	}
+}
+
 // PartitionKeyRangeID - adds the partition key range header
 func PartitionKeyRangeID(id int) CallOption {
 	return func(r *Request) error {
@@ -182,6 +253,39 @@ func EnablePopulateQueryMetrics() CallOption {
 	}
 }
 
+// EnablePopulateIndexMetrics - returns the indexes a query used and those it could have, read them with
+// Response.GetIndexMetrics. Only meant to tune indexing policies, the metrics cost RUs of their own.
+func EnablePopulateIndexMetrics() CallOption {
+	return func(r *Request) error {
+		r.Header.Set(HeaderPopulateIndexMetrics, "True")
+		return nil
+	}
+}
+
+// EnableScriptLogging - returns what a stored procedure logs with console.log, read it with Response.ScriptLog
+func EnableScriptLogging() CallOption {
+	return func(r *Request) error {
+		r.Header.Set(HeaderScriptEnableLogging, "true")
+		return nil
+	}
+}
+
+// PreTriggers - runs the pre triggers of the collection with the ids before the write, which fails with them
+func PreTriggers(ids ...string) CallOption {
+	return func(r *Request) error {
+		r.Header.Set(HeaderPreTriggerInclude, strings.Join(ids, ","))
+		return nil
+	}
+}
+
+// PostTriggers - runs the post triggers of the collection with the ids after the write, in its transaction
+func PostTriggers(ids ...string) CallOption {
+	return func(r *Request) error {
+		r.Header.Set(HeaderPostTriggerInclude, strings.Join(ids, ","))
+		return nil
+	}
+}
+
 // WithContext - adds a context to the request
 func WithContext(ctx context.Context) CallOption {
 	return func(r *Request) error {
@@ -197,3 +301,33 @@ func QueryVersion() CallOption {
 		return nil
 	}
 }
+
+// WithResponse - populates resp with the status code and headers of the response, failed calls included, giving
+// access to the continuation, item count, charge and activity id of operations that only return the decoded
+// resources
+//
+//	var resp gocosmosdb.Response
+//	dbs, err := client.QueryDatabases("", gocosmosdb.Limit(10), gocosmosdb.WithResponse(&resp))
+//	next := resp.Continuation()
+func WithResponse(resp *Response) CallOption {
+	return func(r *Request) error {
+		r.rResponse = resp
+		return nil
+	}
+}
+
+// WithIgnoreNotFound - treats a delete of a resource that does not exist as a success
+func WithIgnoreNotFound() CallOption {
+	return func(r *Request) error {
+		r.rIgnoreNotFound = true
+		return nil
+	}
+}
+
+// IndexingDirective - includes or excludes a written document from the index of the collection, Include or Exclude
+func IndexingDirective(directive string) CallOption {
+	return func(r *Request) error {
+		r.Header.Set(HeaderIndexingDirective, directive)
+		return nil
+	}
+}
@@ -27,9 +27,13 @@ func TestCallOptions(t *testing.T) {
 	opts = append(opts, EnableQueryScan())
 	opts = append(opts, EnableParallelizeCrossPartitionQuery())
 	opts = append(opts, EnablePopulateQueryMetrics())
+	opts = append(opts, EnablePopulateIndexMetrics())
 	ctx := context.WithValue(context.Background(), "foo", "bar")
 	opts = append(opts, WithContext(ctx))
 	opts = append(opts, QueryVersion())
+	opts = append(opts, WithContinuationTokenLimitKB(2))
+	opts = append(opts, PreTriggers("validate", "stamp"))
+	opts = append(opts, PostTriggers("audit"))
 
 	link := "http://localhost:8080"
 	req, err := http.NewRequest("POST", link, nil)
@@ -59,6 +63,116 @@ func TestCallOptions(t *testing.T) {
 	assert.Equal("true", r.Header.Get(HeaderEnableScan))
 	assert.Equal("true", r.Header.Get(HeaderParalelizeCrossPartition))
 	assert.Equal("true", r.Header.Get(HeaderPopulateQueryMetrics))
+	assert.Equal("True", r.Header.Get(HeaderPopulateIndexMetrics))
 	assert.Equal(ctx, r.rContext)
 	assert.Equal("1.4", r.Header.Get(HeaderQueryVersion))
+	assert.Equal("2", r.Header.Get(HeaderResponseContinuationTokenLimit))
+	assert.Equal("validate,stamp", r.Header.Get(HeaderPreTriggerInclude))
+	assert.Equal("audit", r.Header.Get(HeaderPostTriggerInclude))
+
+	assert.NotNil(WithContinuationTokenLimitKB(0)(r))
+}
+
+func TestThroughputOptions(t *testing.T) {
+	assert := assert.New(t)
+	request := func() *Request {
+		req, err := http.NewRequest("POST", "http://localhost:8080", nil)
+		assert.Nil(err)
+		return ResourceRequest("/dbs/db/colls", req)
+	}
+
+	r := request()
+	assert.Nil(AutoscaleThroughput(4000)(r))
+	assert.Equal("", r.Header.Get(HeaderOfferThroughput))
+	assert.Equal(`{"maxThroughput":4000}`, r.Header.Get(HeaderOfferAutopilotSettings))
+	assert.Contains(ThroughputRUs(400)(r).Error(), "ThroughputRUs cannot be combined with AutoscaleThroughput")
+	assert.Contains(NoThroughput()(r).Error(), "NoThroughput cannot be combined with AutoscaleThroughput")
+
+	r = request()
+	assert.Nil(ThroughputRUs(400)(r))
+	assert.Equal("400", r.Header.Get(HeaderOfferThroughput))
+	assert.Equal("", r.Header.Get(HeaderOfferAutopilotSettings))
+
+	r = request()
+	assert.Nil(NoThroughput()(r))
+	assert.Equal("", r.Header.Get(HeaderOfferThroughput))
+	assert.Equal("", r.Header.Get(HeaderOfferAutopilotSettings))
+	assert.Contains(ThroughputRUs(400)(r).Error(), "ThroughputRUs cannot be combined with NoThroughput")
+
+	assert.Contains(AutoscaleThroughput(400)(request()).Error(), "at least 1000 RU/s")
+}
+
+func TestCreateCollectionThroughput(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"id": "coll-id"}`, `{"id": "coll-id"}`)
+	s.SetStatus(http.StatusCreated)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	_, err := client.CreateCollection("dbs/db/", `{"id": "coll-id"}`)
+	assert.Nil(err)
+	assert.Equal("", s.Header.Get(HeaderOfferThroughput))
+	assert.Equal("", s.Header.Get(HeaderOfferAutopilotSettings))
+
+	_, err = client.CreateCollection("dbs/db/", `{"id": "coll-id"}`, AutoscaleThroughput(4000))
+	assert.Nil(err)
+	assert.Equal(`{"maxThroughput":4000}`, s.Header.Get(HeaderOfferAutopilotSettings))
+}
+
+func TestCreateDatabaseSharedThroughput(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"id": "db-id"}`, `{"id": "coll-id"}`)
+	s.SetStatus(http.StatusCreated)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	_, err := client.CreateDatabase(`{"id": "db-id"}`, AutoscaleThroughput(4000))
+	assert.Nil(err)
+	assert.Equal(`{"maxThroughput":4000}`, s.Header.Get(HeaderOfferAutopilotSettings))
+
+	_, err = client.CreateCollection("dbs/db-id/", `{"id": "coll-id"}`, NoThroughput())
+	assert.Nil(err)
+	assert.Equal("", s.Header.Get(HeaderOfferThroughput))
+	assert.Equal("", s.Header.Get(HeaderOfferAutopilotSettings))
+
+	_, err = client.CreateCollection("dbs/db-id/", `{"id": "coll-id"}`, NoThroughput(), ThroughputRUs(400))
+	assert.Contains(err.Error(), "cannot be combined")
+}
+
+func TestCrossPartitionQueries(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"Documents": [], "_count": 0}`, `{"Documents": [], "_count": 0}`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", CrossPartitionQueries: true}, log)
+
+	var docs []Document
+	_, err := client.QueryDocuments("dbs/db/colls/coll/", "SELECT * FROM root r", &docs)
+	assert.Nil(err)
+	assert.Equal("true", s.Header.Get(HeaderCrossPartition))
+	assert.Equal(SupportedAPIVersion, s.Header.Get(HeaderVersion))
+
+	_, err = client.QueryDocuments("dbs/db/colls/coll/", "SELECT * FROM root r", &docs, SinglePartition("tenant-1"))
+	assert.Nil(err)
+	assert.Equal("", s.Header.Get(HeaderCrossPartition))
+	assert.Equal(`["tenant-1"]`, s.Header.Get(HeaderPartitionKey))
+}
+
+func TestConsistencyLevelOverride(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"id": "1"}`, `{"Documents": [], "_count": 0}`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	var doc Document
+	_, err := client.ReadDocument("dbs/db/colls/coll/docs/1", &doc, ConsistencyLevel(Eventual))
+	assert.Nil(err)
+	assert.Equal("eventual", s.Header.Get(HeaderConsistencyLevel))
+
+	var docs []Document
+	_, err = client.QueryDocuments("dbs/db/colls/coll/", "SELECT * FROM root r", &docs, ConsistencyLevel("BoundedStaleness"))
+	assert.Nil(err)
+	assert.Equal("BoundedStaleness", s.Header.Get(HeaderConsistencyLevel))
+
+	_, err = client.ReadDocument("dbs/db/colls/coll/docs/1", &doc, ConsistencyLevel("linearizable"))
+	assert.EqualError(err, `unknown consistency level "linearizable"`)
 }
@@ -1,6 +1,9 @@
 package gocosmosdb
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // NewPagableQuery - Creates a pagable query that populates the passed docs interface
 func (c *CosmosDB) NewPagableQuery(coll string, query *QueryWithParameters, limit int, docs interface{}, opts ...CallOption) *PagableQuery {
@@ -15,6 +18,22 @@ func (c *CosmosDB) NewPagableQuery(coll string, query *QueryWithParameters, limi
 	}
 }
 
+// NewDocumentFeed - Creates a pagable read of the documents of a collection through its read feed, cheaper than
+// paging a SELECT * query
+//
+//	feed := client.NewDocumentFeed(coll, 100, &docs)
+//	for !feed.Done() {
+//		if err := feed.Next(); err != nil {
+//			return err
+//		}
+//		... docs has the page
+//	}
+func (c *CosmosDB) NewDocumentFeed(coll string, limit int, docs interface{}, opts ...CallOption) *PagableQuery {
+	q := c.NewPagableQuery(coll, nil, limit, docs, opts...)
+	q.feed = true
+	return q
+}
+
 func (q *PagableQuery) doQuery(coll string, query *QueryWithParameters, docs interface{}, opts ...CallOption) (*Response, error) {
 	data := struct {
 		Documents interface{} `json:"Documents,omitempty"`
@@ -23,6 +42,9 @@ func (q *PagableQuery) doQuery(coll string, query *QueryWithParameters, docs int
 	if query != nil {
 		return q.client.client.queryWithParameters(coll+"docs/", query, &data, opts...)
 	}
+	if q.feed {
+		return q.client.client.read(coll+"docs/", &data, opts...)
+	}
 	return nil, errors.New("QueryWithParameters cannot be nil")
 }
 
@@ -37,11 +59,16 @@ func (q *PagableQuery) Next() error {
 			return err
 		}
 		q.offset = q.offset + 1
+		q.token = resp.Continuation()
 		if resp.Continuation() != "" {
 			q.continuation = Continuation(resp.Continuation())
 		} else {
 			q.done = true
 		}
+		q.record(resp)
+		if err = q.charge(resp); err != nil {
+			return err
+		}
 	}
 	if q.offset == 0 {
 		opts := append(q.opts, q.limit)
@@ -50,17 +77,136 @@ func (q *PagableQuery) Next() error {
 			return err
 		}
 		q.offset = q.offset + 1
+		q.token = resp.Continuation()
 		if resp.Continuation() != "" {
 			q.continuation = Continuation(resp.Continuation())
 		} else {
 			q.done = true
 		}
+		q.record(resp)
+		if err = q.charge(resp); err != nil {
+			return err
+		}
 		q.sessionToken = SessionToken(resp.SessionToken())
 	}
 	return nil
 }
 
+// PageInfo - the metadata of a page read by a PagableQuery, eg. to show what a page cost or to decide whether to
+// prefetch the next one
+type PageInfo struct {
+	Number        int // of the page among those read, from 1
+	ItemCount     int // -1 when the service did not say
+	RequestCharge float64
+	HasMore       bool   // whether the service returned a continuation, the next page may still be empty
+	Endpoint      string // the endpoint that served the page, the regional one of a routing policy
+}
+
+// Page - returns the metadata of the last page read by Next
+//
+//	err := feed.ForEachPage(func() error {
+//		page := feed.Page()
+//		log.Infof("page %d: %d items for %.2f RUs from %s", page.Number, page.ItemCount, page.RequestCharge, page.Endpoint)
+//		return nil
+//	})
+func (q *PagableQuery) Page() PageInfo {
+	return q.page
+}
+
+// record - keeps the metadata of a page read
+func (q *PagableQuery) record(resp *Response) {
+	q.page = PageInfo{
+		Number:        q.page.Number + 1,
+		ItemCount:     resp.ItemCount(),
+		RequestCharge: resp.RequestCharge(),
+		HasMore:       !q.done,
+		Endpoint:      resp.Endpoint,
+	}
+}
+
+// WithMaxRUs - caps the request charge the query may consume across all of its pages,
+// Next returns a *RUCapError once the cap is exceeded
+func (q *PagableQuery) WithMaxRUs(cap float64) *PagableQuery {
+	q.maxRUs = cap
+	return q
+}
+
+// RequestCharge - returns the request charge consumed by the pages read so far
+func (q *PagableQuery) RequestCharge() float64 {
+	return q.requestCharge
+}
+
+// charge - adds the request charge of a page and stops the query once over the cap
+func (q *PagableQuery) charge(resp *Response) error {
+	if rus, err := resp.GetRUs(); err == nil {
+		q.requestCharge += rus
+	}
+	if q.maxRUs > 0 && q.requestCharge > q.maxRUs {
+		q.done = true
+		return &RUCapError{Cap: q.maxRUs, RequestCharge: q.requestCharge}
+	}
+	return nil
+}
+
+// RUCapError - returned when a query consumes more request units than its cap
+type RUCapError struct {
+	Cap           float64
+	RequestCharge float64
+}
+
+// Implement Error function
+func (e *RUCapError) Error() string {
+	return fmt.Sprintf("query request charge %.2f exceeded cap of %.2f RUs", e.RequestCharge, e.Cap)
+}
+
 // Done - returns true if no more pages are available
 func (q *PagableQuery) Done() bool {
 	return q.done
 }
+
+// Continuation - returns the continuation of the next page, "" before the first page and after the last, it can be
+// handed out to resume the query later with Resume
+func (q *PagableQuery) Continuation() string {
+	return q.token
+}
+
+// Resume - continues the query from a continuation returned by Continuation or QueryPage
+func (q *PagableQuery) Resume(continuation string) *PagableQuery {
+	if continuation != "" {
+		q.token = continuation
+		q.continuation = Continuation(continuation)
+		q.offset = 1
+		q.done = false
+	}
+	return q
+}
+
+// ForEachPage - reads the remaining pages into the passed docs interface calling fn after each, stopping at the
+// first error of a read or of fn
+//
+//	err := client.NewPagableQuery(coll, query, 100, &docs).ForEachPage(func() error {
+//		return export(docs)
+//	})
+func (q *PagableQuery) ForEachPage(fn func() error) error {
+	for !q.done {
+		if err := q.Next(); err != nil {
+			return err
+		}
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// QueryPage - reads one page of a query from a continuation, "" for the first page, returning the continuation of
+// the next page, "" after the last. Handy to page stateless APIs, which hand the continuation to their callers.
+//
+//	next, err := client.QueryPage(coll, query, r.URL.Query().Get("page"), 50, &docs)
+func (c *CosmosDB) QueryPage(coll string, query *QueryWithParameters, continuation string, limit int, docs interface{}, opts ...CallOption) (string, error) {
+	resp, err := c.QueryDocumentsWithParameters(coll, query, docs, append(opts, Limit(limit), Continuation(continuation))...)
+	if err != nil {
+		return "", err
+	}
+	return resp.Continuation(), nil
+}
@@ -1,6 +1,9 @@
 package gocosmosdb
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -59,3 +62,66 @@ func TestPagable(t *testing.T) {
 	pg.Next()
 	assert.Equal("SalesOrder2", docs[0].Id)
 }
+
+func TestPagableWithMaxRUs(t *testing.T) {
+	assert := assert.New(t)
+	resp := `{"_rid": "d9RzAJRFKgw=", "Documents": [{"id": "SalesOrder1"}], "_count": 1}`
+	s := ServerFactory(resp, resp)
+	s.SetHeader(HeaderContinuation, "testContinuation")
+	s.SetHeader(HeaderRequestCharge, "6.5")
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+	docs := []testDoc{}
+	query := &QueryWithParameters{Query: "SELECT * FROM root r"}
+	pg := client.NewPagableQuery("dbs/d9RzAA==/colls/d9RzAJRFKgw=", query, 1, &docs).WithMaxRUs(10)
+	assert.Nil(pg.Next())
+	assert.Equal(6.5, pg.RequestCharge())
+	assert.Equal(PageInfo{Number: 1, ItemCount: -1, RequestCharge: 6.5, HasMore: true, Endpoint: s.URL}, pg.Page())
+	err := pg.Next()
+	assert.Equal(&RUCapError{Cap: 10, RequestCharge: 13}, err)
+	assert.True(pg.Done())
+	assert.Equal(2, pg.Page().Number)
+}
+
+func TestPagableContinuation(t *testing.T) {
+	assert := assert.New(t)
+	var continuations []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		continuation := r.Header.Get(HeaderContinuation)
+		continuations = append(continuations, continuation)
+		switch continuation {
+		case "":
+			w.Header().Set(HeaderContinuation, "page2")
+			fmt.Fprint(w, `{"Documents": [{"id": "SalesOrder1"}], "_count": 1}`)
+		case "page2":
+			w.Header().Set(HeaderContinuation, "page3")
+			fmt.Fprint(w, `{"Documents": [{"id": "SalesOrder2"}], "_count": 1}`)
+		default:
+			fmt.Fprint(w, `{"Documents": [{"id": "SalesOrder3"}], "_count": 1}`)
+		}
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+	coll := "dbs/d9RzAA==/colls/d9RzAJRFKgw=/"
+	query := &QueryWithParameters{Query: "SELECT * FROM root r"}
+
+	docs := []testDoc{}
+	next, err := client.QueryPage(coll, query, "", 1, &docs)
+	assert.Nil(err)
+	assert.Equal("page2", next)
+	assert.Equal("SalesOrder1", docs[0].Id)
+
+	// resume where the stateless page left off
+	var ids []string
+	pg := client.NewPagableQuery(coll, query, 1, &docs).Resume(next)
+	assert.Equal("page2", pg.Continuation())
+	err = pg.ForEachPage(func() error {
+		ids = append(ids, docs[0].Id)
+		return nil
+	})
+	assert.Nil(err)
+	assert.Equal([]string{"SalesOrder2", "SalesOrder3"}, ids)
+	assert.Equal("", pg.Continuation())
+	assert.True(pg.Done())
+	assert.Equal([]string{"", "page2", "page3"}, continuations)
+}
@@ -0,0 +1,160 @@
+package gocosmosdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// MaxPatchOperations - the most operations the service applies in one partial update
+const MaxPatchOperations = 10
+
+// the operations of a partial update
+const (
+	PatchAdd       = "add"     // adds a property or inserts into an array, replacing an existing property
+	PatchSet       = "set"     // sets a property, adding it when missing
+	PatchReplace   = "replace" // replaces an existing property, failing when it is missing
+	PatchRemove    = "remove"  // removes a property or an element of an array
+	PatchIncrement = "incr"    // increments a number by the value, negative values decrement
+	PatchMove      = "move"    // moves the property at From to Path
+)
+
+// PatchOperation - an operation of a partial update of a document, Path is a JSON pointer eg. "/address/city"
+type PatchOperation struct {
+	Op    string
+	Path  string
+	Value interface{}
+	From  string
+}
+
+// MarshalJSON - writes the operation, the value is left out of those taking none but written when nil otherwise
+// so properties can be set to null
+func (o PatchOperation) MarshalJSON() ([]byte, error) {
+	if o.Op == PatchRemove || o.Op == PatchMove {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+			From string `json:"from,omitempty"`
+		}{o.Op, o.Path, o.From})
+	}
+	return json.Marshal(struct {
+		Op    string      `json:"op"`
+		Path  string      `json:"path"`
+		Value interface{} `json:"value"`
+	}{o.Op, o.Path, o.Value})
+}
+
+// PatchBuilder - builds the operations of a partial update in order
+//
+//	ops := gocosmosdb.NewPatch().
+//		Set("/status", "shipped").
+//		Increment("/version", 1).
+//		Remove("/cart").
+//		Operations()
+//	_, err := client.Patch("dbs/{db-id}/colls/{coll-id}/docs/{doc-id}", ops, &order, gocosmosdb.PartitionKey(tenant))
+type PatchBuilder struct {
+	ops []PatchOperation
+}
+
+// NewPatch - starts a partial update
+func NewPatch() *PatchBuilder {
+	return &PatchBuilder{}
+}
+
+func (p *PatchBuilder) add(op PatchOperation) *PatchBuilder {
+	p.ops = append(p.ops, op)
+	return p
+}
+
+// Add - adds a property, or inserts into an array at an index or at its end with "-" eg. "/tags/-"
+func (p *PatchBuilder) Add(path string, value interface{}) *PatchBuilder {
+	return p.add(PatchOperation{Op: PatchAdd, Path: path, Value: value})
+}
+
+// Set - sets a property, adding it when missing
+func (p *PatchBuilder) Set(path string, value interface{}) *PatchBuilder {
+	return p.add(PatchOperation{Op: PatchSet, Path: path, Value: value})
+}
+
+// Replace - replaces an existing property
+func (p *PatchBuilder) Replace(path string, value interface{}) *PatchBuilder {
+	return p.add(PatchOperation{Op: PatchReplace, Path: path, Value: value})
+}
+
+// Remove - removes a property
+func (p *PatchBuilder) Remove(path string) *PatchBuilder {
+	return p.add(PatchOperation{Op: PatchRemove, Path: path})
+}
+
+// Increment - increments a number property by an int or float, negative values decrement
+func (p *PatchBuilder) Increment(path string, by interface{}) *PatchBuilder {
+	return p.add(PatchOperation{Op: PatchIncrement, Path: path, Value: by})
+}
+
+// Move - moves a property from one path to another
+func (p *PatchBuilder) Move(from, path string) *PatchBuilder {
+	return p.add(PatchOperation{Op: PatchMove, Path: path, From: from})
+}
+
+// Operations - returns the operations added so far
+func (p *PatchBuilder) Operations() []PatchOperation {
+	return p.ops
+}
+
+// patchBody - the body of a partial update
+type patchBody struct {
+	Condition  string           `json:"condition,omitempty"`
+	Operations []PatchOperation `json:"operations"`
+}
+
+// PatchCondition - applies a partial update only when the document matches a filter predicate eg.
+// "FROM c WHERE c.status = 'open'", failing with ErrPreconditionFailed otherwise, to compare and set on any field
+//
+//	_, err := client.Patch(link, gocosmosdb.NewPatch().Set("/status", "shipped").Operations(), &order,
+//		gocosmosdb.PatchCondition("FROM c WHERE c.status = 'paid'"))
+//	if errors.Is(err, gocosmosdb.ErrPreconditionFailed) {
+//		// the order is not paid
+//	}
+func PatchCondition(predicate string) CallOption {
+	return func(r *Request) error {
+		r.rPatchCondition = predicate
+		return nil
+	}
+}
+
+// Patch - Updates part of a document with up to MaxPatchOperations operations applied in order, atomically, and
+// returns the updated document. Partitioned collections need the PartitionKey option.
+//
+//	_, err := client.Patch("dbs/{db-id}/colls/{coll-id}/docs/{doc-id}", gocosmosdb.NewPatch().Set("/status", "shipped").Operations(), &doc)
+func (c *CosmosDB) Patch(link string, ops []PatchOperation, ret interface{}, opts ...CallOption) (*Response, error) {
+	if len(ops) == 0 || len(ops) > MaxPatchOperations {
+		return nil, fmt.Errorf("a partial update takes 1 to %d operations, got %d", MaxPatchOperations, len(ops))
+	}
+	return c.client.patch(link, ops, ret, opts...)
+}
+
+// patch - sends a partial update, keeping the partitioned API version like batches do
+func (c *apiClient) patch(link string, ops []PatchOperation, ret interface{}, opts ...CallOption) (*Response, error) {
+	req, err := http.NewRequest(http.MethodPatch, path(c.uri, link), nil)
+	if err != nil {
+		return nil, err
+	}
+	r := ResourceRequest(link, req)
+	if err = c.apply(r, opts); err != nil {
+		return nil, err
+	}
+	// the body carries the condition set by the options
+	data, err := json.Marshal(patchBody{Condition: r.rPatchCondition, Operations: ops})
+	if err != nil {
+		return nil, err
+	}
+	r.setBody(data)
+	if err = c.stamp(r); err != nil {
+		return nil, err
+	}
+	if err = c.hashContent(r); err != nil {
+		return nil, err
+	}
+	r.Header.Set(HeaderContentType, "application/json_patch+json")
+	return c.do(r, expectOK, ret)
+}
@@ -0,0 +1,74 @@
+package gocosmosdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatch(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"id": "order-1", "status": "shipped"}`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	ops := NewPatch().
+		Add("/tags/-", "express").
+		Set("/status", "shipped").
+		Set("/cart", nil).
+		Replace("/total", 42.5).
+		Remove("/coupon").
+		Increment("/version", 1).
+		Move("/draft", "/final").
+		Operations()
+	var doc map[string]interface{}
+	_, err := client.Patch("dbs/db/colls/coll/docs/order-1", ops, &doc, PartitionKey("tenant-1"))
+	assert.Nil(err)
+	assert.Equal("shipped", doc["status"])
+	assert.Equal("application/json_patch+json", s.Header.Get(HeaderContentType))
+	assert.Equal(`["tenant-1"]`, s.Header.Get(HeaderPartitionKey))
+	assert.JSONEq(`{"operations": [
+		{"op": "add", "path": "/tags/-", "value": "express"},
+		{"op": "set", "path": "/status", "value": "shipped"},
+		{"op": "set", "path": "/cart", "value": null},
+		{"op": "replace", "path": "/total", "value": 42.5},
+		{"op": "remove", "path": "/coupon"},
+		{"op": "incr", "path": "/version", "value": 1},
+		{"op": "move", "path": "/final", "from": "/draft"}
+	]}`, s.Body)
+
+	_, err = client.Patch("dbs/db/colls/coll/docs/order-1", nil, &doc)
+	assert.EqualError(err, "a partial update takes 1 to 10 operations, got 0")
+}
+
+func TestPatchWithAudit(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"id": "order-1"}`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", Audit: ContextAudit}, log)
+
+	ctx := WithActor(context.Background(), "ariel")
+	_, err := client.PatchCtx(ctx, "dbs/db/colls/coll/docs/order-1", NewPatch().Increment("/version", 9007199254740993).Operations(), nil)
+	assert.Nil(err)
+	assert.JSONEq(`{"operations": [
+		{"op": "incr", "path": "/version", "value": 9007199254740993},
+		{"op": "set", "path": "/_audit", "value": {"actor": "ariel"}}
+	]}`, s.Body)
+}
+
+func TestPatchCondition(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"code": "PreconditionFailed", "message": "One of the specified pre-condition is not met."}`)
+	s.SetStatus(http.StatusPreconditionFailed)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	var doc map[string]interface{}
+	_, err := client.Patch("dbs/db/colls/coll/docs/order-1", NewPatch().Set("/status", "shipped").Operations(), &doc,
+		PatchCondition("FROM c WHERE c.status = 'paid'"))
+	assert.True(errors.Is(err, ErrPreconditionFailed))
+	assert.JSONEq(`{"condition": "FROM c WHERE c.status = 'paid'", "operations": [{"op": "set", "path": "/status", "value": "shipped"}]}`, s.Body)
+}
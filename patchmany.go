@@ -0,0 +1,100 @@
+package gocosmosdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// PatchTarget - a document to update with PatchMany
+type PatchTarget struct {
+	ID           string
+	PartitionKey interface{}
+}
+
+// PatchResult - the outcome of the update of a document by PatchMany
+type PatchResult struct {
+	PatchTarget
+	StatusCode    int     // of its batch operation, 0 when its batch failed as a whole
+	RequestCharge float64 // of its batch operation
+	Err           error   // nil when the document was updated
+}
+
+// PatchMany - applies the same partial update to many documents, eg. to backfill a field or roll out a flag, with a
+// transactional batch per partition key of up to MaxBatchOperations documents. A document failing its update, eg.
+// one that does not exist, is reported in its result and the batch it rolled back is run again without it. The
+// results are in the order of the targets, the error is only for operations that cannot be sent.
+//
+//	results, err := client.PatchMany(coll, targets, gocosmosdb.NewPatch().Set("/beta", true).Operations())
+//	for _, r := range results {
+//		if r.Err != nil {
+//			log.Warnf("%s not updated: %v", r.ID, r.Err)
+//		}
+//	}
+func (c *CosmosDB) PatchMany(coll string, targets []PatchTarget, ops []PatchOperation, opts ...CallOption) ([]PatchResult, error) {
+	if len(ops) == 0 || len(ops) > MaxPatchOperations {
+		return nil, fmt.Errorf("a partial update takes 1 to %d operations, got %d", MaxPatchOperations, len(ops))
+	}
+	results := make([]PatchResult, len(targets))
+	// the indexes of the targets per partition key, in the order the keys are first seen
+	var keys []string
+	groups := map[string][]int{}
+	for i, target := range targets {
+		results[i].PatchTarget = target
+		// the key as sent, so 1 and "1" are different partitions
+		data, err := json.Marshal(target.PartitionKey)
+		if err != nil {
+			return nil, err
+		}
+		key := string(data)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+	for _, key := range keys {
+		group := groups[key]
+		for len(group) > 0 {
+			n := len(group)
+			if n > MaxBatchOperations {
+				n = MaxBatchOperations
+			}
+			c.patchBatch(coll, targets, group[:n], ops, results, opts)
+			group = group[n:]
+		}
+	}
+	return results, nil
+}
+
+// patchBatch - patches the targets at the indexes in a batch, running it again without the target failing it
+func (c *CosmosDB) patchBatch(coll string, targets []PatchTarget, indexes []int, ops []PatchOperation, results []PatchResult, opts []CallOption) {
+	for len(indexes) > 0 {
+		b := NewTransactionalBatch(coll, targets[indexes[0]].PartitionKey)
+		for _, i := range indexes {
+			b.Patch(targets[i].ID, ops)
+		}
+		batchResults, err := c.ExecuteBatch(b, opts...)
+		var batchErr *BatchError
+		if errors.As(err, &batchErr) && len(batchResults) == len(indexes) {
+			failed := indexes[batchErr.Index]
+			results[failed].StatusCode = batchErr.StatusCode
+			results[failed].RequestCharge = batchErr.Results[batchErr.Index].RequestCharge
+			results[failed].Err = &RequestError{StatusCode: batchErr.StatusCode, Message: http.StatusText(batchErr.StatusCode)}
+			indexes = append(indexes[:batchErr.Index:batchErr.Index], indexes[batchErr.Index+1:]...)
+			continue
+		}
+		if (err == nil || batchErr != nil) && len(batchResults) != len(indexes) {
+			err = fmt.Errorf("a batch of %d operations returned %d results", len(indexes), len(batchResults))
+		}
+		for n, i := range indexes {
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+			results[i].StatusCode = batchResults[n].StatusCode
+			results[i].RequestCharge = batchResults[n].RequestCharge
+		}
+		return
+	}
+}
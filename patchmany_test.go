@@ -0,0 +1,102 @@
+package gocosmosdb
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatchMany(t *testing.T) {
+	assert := assert.New(t)
+	var mu sync.Mutex
+	var batches []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ops []BatchOperation
+		json.NewDecoder(r.Body).Decode(&ops)
+		mu.Lock()
+		batch := r.Header.Get(HeaderPartitionKey)
+		for _, op := range ops {
+			batch += " " + op.ID
+		}
+		batches = append(batches, batch)
+		mu.Unlock()
+		// documents named missing fail their batch
+		results := make([]BatchResult, len(ops))
+		failed := -1
+		for i, op := range ops {
+			results[i] = BatchResult{StatusCode: http.StatusOK, RequestCharge: 10}
+			if op.ID == "missing" && failed < 0 {
+				failed = i
+			}
+		}
+		if failed >= 0 {
+			for i := range results {
+				results[i] = BatchResult{StatusCode: http.StatusFailedDependency}
+			}
+			results[failed] = BatchResult{StatusCode: http.StatusNotFound, RequestCharge: 1}
+			w.WriteHeader(http.StatusMultiStatus)
+		}
+		json.NewEncoder(w).Encode(results)
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	targets := []PatchTarget{
+		{ID: "a", PartitionKey: "tenant-1"},
+		{ID: "b", PartitionKey: "tenant-2"},
+		{ID: "missing", PartitionKey: "tenant-1"},
+		{ID: "c", PartitionKey: "tenant-1"},
+	}
+	results, err := client.PatchMany("dbs/db/colls/coll", targets, NewPatch().Set("/beta", true).Operations())
+	assert.Nil(err)
+	assert.Equal([]string{
+		`["tenant-1"] a missing c`,
+		`["tenant-1"] a c`,
+		`["tenant-2"] b`,
+	}, batches)
+	assert.Len(results, 4)
+	for i, target := range targets {
+		assert.Equal(target, results[i].PatchTarget)
+	}
+	assert.Nil(results[0].Err)
+	assert.Equal(http.StatusOK, results[0].StatusCode)
+	assert.Equal(10.0, results[1].RequestCharge)
+	assert.True(errors.Is(results[2].Err, ErrNotFound))
+	assert.Equal(http.StatusNotFound, results[2].StatusCode)
+	assert.Equal(1.0, results[2].RequestCharge)
+	assert.Nil(results[3].Err)
+
+	_, err = client.PatchMany("dbs/db/colls/coll", targets, nil)
+	assert.EqualError(err, "a partial update takes 1 to 10 operations, got 0")
+
+	// keys printing alike are different partitions
+	batches = nil
+	_, err = client.PatchMany("dbs/db/colls/coll", []PatchTarget{{ID: "x", PartitionKey: 1}, {ID: "y", PartitionKey: "1"}},
+		NewPatch().Set("/beta", true).Operations())
+	assert.Nil(err)
+	assert.Equal([]string{`[1] x`, `["1"] y`}, batches)
+}
+
+func TestPatchManyChunks(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`[]`, `[]`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	targets := make([]PatchTarget, MaxBatchOperations+1)
+	for i := range targets {
+		targets[i] = PatchTarget{ID: "doc", PartitionKey: "tenant-1"}
+	}
+	// the server answers with no results, which fails the batches as a whole
+	results, err := client.PatchMany("dbs/db/colls/coll", targets, NewPatch().Remove("/legacy").Operations())
+	assert.Nil(err)
+	assert.Len(results, MaxBatchOperations+1)
+	assert.EqualError(results[0].Err, "a batch of 100 operations returned 0 results")
+	assert.EqualError(results[MaxBatchOperations].Err, "a batch of 1 operations returned 0 results")
+	assert.JSONEq(`[{"operationType": "Patch", "id": "doc", "resourceBody": {"operations": [{"op": "remove", "path": "/legacy"}]}}]`, s.Body)
+}
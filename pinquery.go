@@ -0,0 +1,90 @@
+package gocosmosdb
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// queryAlias - the collection alias of a query eg. r of "FROM root r" or c of "FROM c"
+	queryAlias = regexp.MustCompile(`(?i)\bFROM\s+(\w+)(?:\s+(?:AS\s+)?(\w+))?`)
+	// queryWhere - the filter of a query up to its ordering, grouping or paging
+	queryWhere = regexp.MustCompile(`(?is)\bWHERE\b(.*?)(?:\bORDER\s+BY\b|\bGROUP\s+BY\b|\bOFFSET\b|$)`)
+	// unpinnable - keywords making a filter match documents of other partition keys than the one it compares
+	unpinnable = regexp.MustCompile(`(?i)\b(OR|NOT|JOIN|SELECT|EXISTS|IN)\b`)
+	// conjunction - splits a filter into the conditions it joins with AND
+	conjunction = regexp.MustCompile(`(?i)\bAND\b`)
+)
+
+// pinnedQuery - a call option pinning a fanned out query to the partition its filter compares the partition key
+// of the collection with, or with QueryPlans to the partition key range its plan reads, a key passed to the call
+// or a query of a single partition are left as they are
+func (c *apiClient) pinnedQuery(link string, query *QueryWithParameters) CallOption {
+	return func(r *Request) error {
+		if r.rType != "docs" || r.Header.Get(HeaderCrossPartition) == "" || r.Header.Get(HeaderPartitionKey) != "" ||
+			r.Header.Get(HeaderPartitionKeyRangeID) != "" {
+			return nil
+		}
+		if pk, ok := partitionKeyOf(query, c.partitionKeyPath(link)); ok {
+			return SinglePartition(pk)(r)
+		}
+		if c.config.QueryPlans {
+			return c.plannedQuery(r, link, query)
+		}
+		return nil
+	}
+}
+
+// partitionKeyPath - the partition key of the collection of a link, from its defaults or the config
+func (c *apiClient) partitionKeyPath(link string) string {
+	if d, ok := c.collectionDefaults(collectionOf(link)); ok && d.PartitionKeyPath != "" {
+		return d.PartitionKeyPath
+	}
+	return c.config.PartitionKeyPath
+}
+
+// partitionKeyOf - returns the partition key a query filters on with equality, the conditions of the filter all
+// joined by AND so it only matches documents of that key eg. "WHERE c.tenantId = @tenant AND c.open = true"
+func partitionKeyOf(query *QueryWithParameters, path string) (interface{}, bool) {
+	if query == nil || path == "" || strings.ContainsAny(path, `[]"'`) {
+		return nil, false
+	}
+	alias := queryAlias.FindStringSubmatch(query.Query)
+	where := queryWhere.FindStringSubmatch(query.Query)
+	if alias == nil || where == nil || unpinnable.MatchString(query.Query[strings.Index(query.Query, alias[0]):]) {
+		return nil, false
+	}
+	name := alias[1]
+	if alias[2] != "" && !strings.EqualFold(alias[2], "WHERE") {
+		name = alias[2]
+	}
+	property := regexp.QuoteMeta(name + strings.Replace(path, "/", ".", -1))
+	value := `(@\w+|'[^'\\]*'|"[^"\\]*"|-?\d+(?:\.\d+)?|true|false)`
+	equal := regexp.MustCompile(`^(?:` + property + `\s*=\s*` + value + `|` + value + `\s*=\s*` + property + `)$`)
+	var matches [][]string
+	for _, term := range conjunction.Split(where[1], -1) {
+		if m := equal.FindStringSubmatch(strings.Trim(term, " \t\r\n()")); m != nil {
+			matches = append(matches, m)
+		}
+	}
+	if len(matches) != 1 {
+		return nil, false
+	}
+	literal := matches[0][1] + matches[0][2]
+	switch {
+	case strings.HasPrefix(literal, "@"):
+		for _, p := range query.Parameters {
+			if p.Name == literal {
+				return p.Value, true
+			}
+		}
+		return nil, false
+	case strings.HasPrefix(literal, "'") || strings.HasPrefix(literal, `"`):
+		return literal[1 : len(literal)-1], true
+	case literal == "true" || literal == "false":
+		return literal == "true", true
+	}
+	n, err := strconv.ParseFloat(literal, 64)
+	return n, err == nil
+}
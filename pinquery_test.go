@@ -0,0 +1,78 @@
+package gocosmosdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartitionKeyOf(t *testing.T) {
+	assert := assert.New(t)
+	params := []QueryParameter{{Name: "@tenant", Value: "t1"}, {Name: "@open", Value: true}}
+	pinned := map[string]interface{}{
+		"SELECT * FROM c WHERE c.tenantId = @tenant":                                    "t1",
+		"SELECT * FROM root r WHERE r.open = @open AND r.tenantId = 't2' ORDER BY r.ts": "t2",
+		"SELECT * FROM orders o WHERE (o.tenantId = 42) AND STARTSWITH(o.id, 'a')":      42.0,
+		`SELECT VALUE COUNT(1) FROM c WHERE "t3" = c.tenantId`:                          "t3",
+		"select * from c where c.tenantId = true offset 0 limit 10":                     true,
+	}
+	for query, pk := range pinned {
+		got, ok := partitionKeyOf(&QueryWithParameters{Query: query, Parameters: params}, "/tenantId")
+		assert.True(ok, query)
+		assert.Equal(pk, got, query)
+	}
+	fannedOut := []string{
+		"SELECT * FROM c",
+		"SELECT * FROM c WHERE c.tenantId = @missing",
+		"SELECT * FROM c WHERE c.tenantId = 't1' OR c.tenantId = 't2'",
+		"SELECT * FROM c WHERE NOT c.tenantId = 't1'",
+		"SELECT * FROM c WHERE c.tenantId != 't1'",
+		"SELECT * FROM c WHERE c.tenantId >= 't1'",
+		"SELECT * FROM c WHERE c.tenantId IN ('t1', 't2')",
+		"SELECT * FROM c WHERE c.tenantId = 't1' AND c.tenantId = 't2'",
+		"SELECT * FROM c WHERE c.tenantIds = 't1'",
+		"SELECT * FROM c WHERE c.owner.tenantId = 't1'",
+		"SELECT * FROM c WHERE c.tenantId = c.parentId",
+		"SELECT * FROM c JOIN t IN c.tags WHERE c.tenantId = 't1'",
+	}
+	for _, query := range fannedOut {
+		_, ok := partitionKeyOf(&QueryWithParameters{Query: query, Parameters: params}, "/tenantId")
+		assert.False(ok, query)
+	}
+
+	pk, ok := partitionKeyOf(&QueryWithParameters{Query: "SELECT * FROM c WHERE c.owner.tenantId = 't1'"}, "/owner/tenantId")
+	assert.True(ok)
+	assert.Equal("t1", pk)
+}
+
+func TestQueryPinnedToPartitionKey(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"Documents": []}`, `{"Documents": []}`, `{"Documents": []}`, `{"Documents": []}`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", CrossPartitionQueries: true, PartitionKeyPath: "/tenantId"}, log)
+	var docs []Document
+
+	_, err := client.QueryDocumentsWithParameters("dbs/db/colls/coll/", &QueryWithParameters{
+		Query:      "SELECT * FROM c WHERE c.tenantId = @tenant",
+		Parameters: []QueryParameter{{Name: "@tenant", Value: "t1"}},
+	}, &docs)
+	assert.Nil(err)
+	assert.Equal(`["t1"]`, s.Header.Get(HeaderPartitionKey))
+	assert.Empty(s.Header.Get(HeaderCrossPartition))
+
+	_, err = client.QueryDocuments("dbs/db/colls/coll/", "SELECT * FROM c WHERE c.tenantId = 't1' OR c.vip = true", &docs)
+	assert.Nil(err)
+	assert.Empty(s.Header.Get(HeaderPartitionKey))
+	assert.Equal("true", s.Header.Get(HeaderCrossPartition))
+
+	// a key passed to the call wins
+	_, err = client.QueryDocuments("dbs/db/colls/coll/", "SELECT * FROM c WHERE c.tenantId = 't1'", &docs, PartitionKey("t2"))
+	assert.Nil(err)
+	assert.Equal(`["t2"]`, s.Header.Get(HeaderPartitionKey))
+
+	// the partition key path of the collection defaults wins over that of the config
+	client.SetCollectionDefaults("dbs/db/colls/coll/", CollectionDefaults{PartitionKeyPath: "/region"})
+	_, err = client.QueryDocuments("dbs/db/colls/coll/", "SELECT * FROM c WHERE c.tenantId = 't1'", &docs)
+	assert.Nil(err)
+	assert.Empty(s.Header.Get(HeaderPartitionKey))
+}
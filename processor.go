@@ -0,0 +1,374 @@
+package gocosmosdb
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ChangeFeedHandler - processes a page of changes of a partition key range, a failing handler is retried
+type ChangeFeedHandler func(ctx context.Context, pkRange string, docs []json.RawMessage) error
+
+// DeadLetterFunc - receives the changes of the collection coll the handler kept failing on, the processor moves
+// past them once it returns nil
+type DeadLetterFunc func(ctx context.Context, coll, pkRange string, docs []json.RawMessage, err error) error
+
+// ChangeFeedProcessorOptions - tunes a ChangeFeedProcessor, zero values use the defaults
+type ChangeFeedProcessorOptions struct {
+	Start           []CallOption  // where ranges without a checkpoint start, eg. StartFromNow()
+	MaxItemCount    int           // changes per page
+	PollInterval    time.Duration // wait when a range has no new changes, DefaultChangeFeedPollInterval by default
+	MaxPollInterval time.Duration // the poll interval doubles up to this while a range stays empty
+	MaxAttempts     int           // handler attempts per page before dead-lettering, defaults to 3
+	RetryWait       time.Duration // wait between handler attempts, defaults to a second
+	DeadLetter      DeadLetterFunc
+	Owner           string        // names this instance to the other instances sharing Leases, random by default
+	Leases          LeaseStore    // a MemoryLeaseStore by default
+	AcquireInterval time.Duration // how often a BalancedLeaseStore is checked for leases to take, defaults to 13s
+	RenewInterval   time.Duration // how often the leases of a BalancedLeaseStore are renewed, defaults to 17s
+	OnLeaseAcquired func(pkRange string)
+	OnLeaseLost     func(pkRange string, err error) // err is nil when the lease was released on shutdown
+}
+
+// ChangeFeedProcessor - reads the change feed of every partition key range of a collection, handing the changes
+// to a handler and checkpointing each range once its changes are handled
+type ChangeFeedProcessor struct {
+	db          *CosmosDB
+	coll        string
+	handler     ChangeFeedHandler
+	opts        ChangeFeedProcessorOptions
+	mu          sync.Mutex
+	checkpoints map[string]string
+	running     map[string]bool // the ranges processed by this instance
+}
+
+// NewChangeFeedProcessor - creates a processor for the collection, without a DeadLetter a page the handler keeps
+// failing on is retried until it succeeds, stalling its partition key range
+//
+//	processor := client.NewChangeFeedProcessor("dbs/{db-id}/colls/{coll-id}/", handle, &gocosmosdb.ChangeFeedProcessorOptions{
+//		DeadLetter: client.DeadLetterCollection("dbs/{db-id}/colls/{dead-letter-coll-id}/"),
+//	})
+//	err := processor.Run(ctx)
+func (c *CosmosDB) NewChangeFeedProcessor(coll string, handler ChangeFeedHandler, opts *ChangeFeedProcessorOptions) *ChangeFeedProcessor {
+	p := &ChangeFeedProcessor{db: c, coll: coll, handler: handler, checkpoints: map[string]string{}, running: map[string]bool{}}
+	if opts != nil {
+		p.opts = *opts
+	}
+	if p.opts.PollInterval <= 0 {
+		p.opts.PollInterval = DefaultChangeFeedPollInterval
+	}
+	if p.opts.MaxAttempts <= 0 {
+		p.opts.MaxAttempts = 3
+	}
+	if p.opts.RetryWait <= 0 {
+		p.opts.RetryWait = time.Second
+	}
+	if p.opts.Owner == "" {
+		p.opts.Owner = genId()
+	}
+	if p.opts.Leases == nil {
+		p.opts.Leases = NewMemoryLeaseStore()
+	}
+	if p.opts.AcquireInterval <= 0 {
+		p.opts.AcquireInterval = 13 * time.Second
+	}
+	if p.opts.RenewInterval <= 0 {
+		p.opts.RenewInterval = 17 * time.Second
+	}
+	return p
+}
+
+// Run - processes the changes of every partition key range it acquires the lease of until ctx is done or a range
+// fails. With a BalancedLeaseStore the instances sharing it keep checking for leases to take, expired ones or those
+// of an instance holding more than its share, so the ranges spread evenly as instances come and go, and a range
+// whose lease is taken over is stopped at its next renewal. Once ctx is done the pages being handled are finished
+// and checkpointed, and the leases released for other instances to take over, a shutdown through ctx returns nil.
+func (p *ChangeFeedProcessor) Run(ctx context.Context) error {
+	ranges, err := p.db.QueryPartitionKeyRanges(p.coll, "", WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+	store, balanced := p.opts.Leases.(BalancedLeaseStore)
+	if !balanced {
+		for _, pkRange := range ranges {
+			wg.Add(1)
+			go func(id string) {
+				defer wg.Done()
+				lease, err := p.opts.Leases.Acquire(ctx, id, p.opts.Owner)
+				if err == nil && lease != nil {
+					err = p.run(ctx, lease, nil)
+				}
+				if err != nil {
+					fail(err)
+				}
+			}(pkRange.Id)
+		}
+		wg.Wait()
+		return firstErr
+	}
+
+	ids := make([]string, len(ranges))
+	for i, pkRange := range ranges {
+		ids[i] = pkRange.Id
+	}
+	ticker := time.NewTicker(p.opts.AcquireInterval)
+	defer ticker.Stop()
+	for {
+		leases, err := p.balance(ctx, store, ids)
+		if err != nil && ctx.Err() == nil {
+			fail(err)
+		}
+		for _, lease := range leases {
+			wg.Add(1)
+			go func(lease *Lease) {
+				defer wg.Done()
+				if err := p.run(ctx, lease, store); err != nil {
+					fail(err)
+				}
+			}(lease)
+		}
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return firstErr
+		case <-ticker.C:
+		}
+	}
+}
+
+// balance - acquires the free leases up to the share of this instance, or else takes one lease of the instance
+// holding the most over its share, one per round so the instances settle without taking leases back and forth
+func (p *ChangeFeedProcessor) balance(ctx context.Context, store BalancedLeaseStore, ids []string) ([]*Lease, error) {
+	leases, err := store.Leases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	running := make(map[string]bool, len(p.running))
+	for id := range p.running {
+		running[id] = true
+	}
+	p.mu.Unlock()
+
+	known := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		known[id] = true
+	}
+	// the ranges held by each instance, leases this instance holds but no longer runs eg. after a restart are free
+	held := map[string]bool{}
+	owners := map[string][]string{p.opts.Owner: nil}
+	for _, lease := range leases {
+		if lease.Owner == "" || lease.Owner == p.opts.Owner || !known[lease.PkRange] {
+			continue
+		}
+		held[lease.PkRange] = true
+		owners[lease.Owner] = append(owners[lease.Owner], lease.PkRange)
+	}
+	share := (len(ids) + len(owners) - 1) / len(owners)
+	mine := len(running)
+
+	var acquired []*Lease
+	for _, id := range ids {
+		if mine >= share {
+			return acquired, nil
+		}
+		if held[id] || running[id] {
+			continue
+		}
+		lease, err := store.Acquire(ctx, id, p.opts.Owner)
+		if err != nil {
+			return acquired, err
+		}
+		if lease != nil {
+			acquired = append(acquired, lease)
+			mine++
+		}
+	}
+	if mine >= share {
+		return acquired, nil
+	}
+	victim := ""
+	for owner, ranges := range owners {
+		if owner == p.opts.Owner || len(ranges) <= share {
+			continue
+		}
+		if victim == "" || len(ranges) > len(owners[victim]) || len(ranges) == len(owners[victim]) && owner < victim {
+			victim = owner
+		}
+	}
+	if victim == "" {
+		return acquired, nil
+	}
+	sort.Strings(owners[victim])
+	lease, err := store.Steal(ctx, owners[victim][0], p.opts.Owner)
+	if err != nil {
+		return acquired, err
+	}
+	if lease != nil {
+		acquired = append(acquired, lease)
+	}
+	return acquired, nil
+}
+
+// Checkpoints - returns the continuation each partition key range has been handled up to
+func (p *ChangeFeedProcessor) Checkpoints() map[string]string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	checkpoints := make(map[string]string, len(p.checkpoints))
+	for k, v := range p.checkpoints {
+		checkpoints[k] = v
+	}
+	return checkpoints
+}
+
+// run - processes a partition key range while holding its lease, renewing it with a balanced store, and hands
+// the lease back on shutdown
+func (p *ChangeFeedProcessor) run(ctx context.Context, lease *Lease, store BalancedLeaseStore) error {
+	p.mu.Lock()
+	p.running[lease.PkRange] = true
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		delete(p.running, lease.PkRange)
+		p.mu.Unlock()
+	}()
+	if p.opts.OnLeaseAcquired != nil {
+		p.opts.OnLeaseAcquired(lease.PkRange)
+	}
+
+	rangeCtx, stop := context.WithCancel(ctx)
+	renewed := make(chan error, 1)
+	if store != nil {
+		go func() {
+			renewed <- p.renew(rangeCtx, store, lease, stop)
+		}()
+	} else {
+		renewed <- nil
+	}
+	err := p.process(rangeCtx, lease)
+	stop()
+	if renewErr := <-renewed; renewErr == ErrLeaseLost {
+		err = renewErr
+	}
+	if err == ErrLeaseLost {
+		p.lost(lease.PkRange, err)
+		return nil
+	}
+	if ctx.Err() != nil {
+		err = nil
+	}
+	if releaseErr := p.opts.Leases.Release(context.Background(), lease); releaseErr != nil && err == nil {
+		err = releaseErr
+	}
+	p.lost(lease.PkRange, nil)
+	return err
+}
+
+// renew - renews a lease until ctx is done, stopping the range once the lease is taken over. Failing renewals are
+// tried again, the lease expiring meanwhile is found lost at the next one.
+func (p *ChangeFeedProcessor) renew(ctx context.Context, store BalancedLeaseStore, lease *Lease, stop func()) error {
+	ticker := time.NewTicker(p.opts.RenewInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if err := store.Renew(ctx, lease); err == ErrLeaseLost {
+			stop()
+			return err
+		}
+	}
+}
+
+// lost - notifies the lease of the range is no longer held
+func (p *ChangeFeedProcessor) lost(pkRange string, err error) {
+	if p.opts.OnLeaseLost != nil {
+		p.opts.OnLeaseLost(pkRange, err)
+	}
+}
+
+// process - polls a partition key range from the checkpoint of its lease
+func (p *ChangeFeedProcessor) process(ctx context.Context, lease *Lease) error {
+	opts := p.opts.Start
+	if lease.Continuation != "" {
+		opts = []CallOption{IfNoneMatch(lease.Continuation)}
+	}
+	reader := p.db.NewChangeFeedReader(p.coll, lease.PkRange, opts...).
+		WithMaxItemCount(p.opts.MaxItemCount).
+		WithPollInterval(p.opts.PollInterval, p.opts.MaxPollInterval)
+	return reader.Poll(ctx, func(docs []json.RawMessage) error {
+		if err := p.handle(ctx, lease.PkRange, docs); err != nil {
+			return err
+		}
+		// record handled pages even when shutting down
+		lease.Continuation = reader.Continuation()
+		if err := p.opts.Leases.Checkpoint(context.Background(), lease); err != nil {
+			return err
+		}
+		p.mu.Lock()
+		p.checkpoints[lease.PkRange] = lease.Continuation
+		p.mu.Unlock()
+		return nil
+	})
+}
+
+// handle - runs the handler on a page, dead-lettering the page once the attempts are used up
+func (p *ChangeFeedProcessor) handle(ctx context.Context, pkRange string, docs []json.RawMessage) error {
+	for attempt := 1; ; attempt++ {
+		err := p.handler(ctx, pkRange, docs)
+		if err == nil {
+			return nil
+		}
+		if attempt >= p.opts.MaxAttempts && p.opts.DeadLetter != nil {
+			return p.opts.DeadLetter(ctx, p.coll, pkRange, docs, err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(p.opts.RetryWait):
+		}
+	}
+}
+
+// DeadLetter - a change the processor could not handle, as written by DeadLetterCollection
+type DeadLetter struct {
+	Document
+	Collection string          `json:"collection"` // the collection the change was read from
+	PkRange    string          `json:"pkRange"`
+	Error      string          `json:"error"`
+	FailedAt   int64           `json:"failedAt"`
+	Change     json.RawMessage `json:"change"`
+}
+
+// DeadLetterCollection - returns a DeadLetterFunc writing each failing change as a DeadLetter document to
+// deadLetters, which must be partitioned by /id
+func (c *CosmosDB) DeadLetterCollection(deadLetters string) DeadLetterFunc {
+	return func(ctx context.Context, coll, pkRange string, docs []json.RawMessage, err error) error {
+		for _, doc := range docs {
+			dl := &DeadLetter{Collection: coll, PkRange: pkRange, Error: err.Error(), FailedAt: time.Now().Unix(), Change: doc}
+			dl.Id = genId()
+			if _, err := c.client.create(deadLetters+"docs/", dl, nil, PartitionKey(dl.Id), WithContext(ctx)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
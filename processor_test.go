@@ -0,0 +1,236 @@
+package gocosmosdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testPartitionKeyRange = `{"PartitionKeyRanges": [{"id": "0", "minInclusive": "", "maxExclusive": "FF"}], "_count": 1}`
+
+func TestChangeFeedProcessor(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(testPartitionKeyRange,
+		`{"Documents": [{"id": "SalesOrder1"}, {"id": "SalesOrder2"}], "_count": 2}`,
+		`{"Documents": [], "_count": 0}`)
+	s.SetHeader(HeaderETag, `"42"`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	handled := 0
+	processor := client.NewChangeFeedProcessor("dbs/db/colls/coll/", func(ctx context.Context, pkRange string, docs []json.RawMessage) error {
+		handled += len(docs)
+		return nil
+	}, &ChangeFeedProcessorOptions{PollInterval: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Nil(processor.Run(ctx))
+	assert.Equal(2, handled)
+	assert.Equal(map[string]string{"0": `"42"`}, processor.Checkpoints())
+}
+
+func TestChangeFeedProcessorDeadLetter(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(testPartitionKeyRange,
+		`{"Documents": [{"id": "SalesOrder1"}, {"id": "SalesOrder2"}], "_count": 2}`,
+		`{"Documents": [], "_count": 0}`)
+	s.SetHeader(HeaderETag, `"42"`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	attempts := 0
+	var dead []json.RawMessage
+	processor := client.NewChangeFeedProcessor("dbs/db/colls/coll/", func(ctx context.Context, pkRange string, docs []json.RawMessage) error {
+		attempts++
+		return errors.New("poison")
+	}, &ChangeFeedProcessorOptions{
+		PollInterval: time.Minute,
+		MaxAttempts:  2,
+		RetryWait:    time.Millisecond,
+		DeadLetter: func(ctx context.Context, coll, pkRange string, docs []json.RawMessage, err error) error {
+			assert.Equal("dbs/db/colls/coll/", coll)
+			assert.Equal("poison", err.Error())
+			dead = append(dead, docs...)
+			return nil
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Nil(processor.Run(ctx))
+	assert.Equal(2, attempts)
+	assert.Equal(2, len(dead))
+	assert.Equal(`"42"`, processor.Checkpoints()["0"])
+}
+
+func TestDeadLetterCollection(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{}`)
+	s.SetStatus(http.StatusCreated)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	deadLetter := client.DeadLetterCollection("dbs/db/colls/deadletters/")
+	err := deadLetter(context.Background(), "dbs/db/colls/orders/", "0", []json.RawMessage{json.RawMessage(`{"id":"SalesOrder1"}`)}, errors.New("poison"))
+	assert.Nil(err)
+	var dl DeadLetter
+	assert.Nil(json.Unmarshal([]byte(s.Body), &dl))
+	assert.Equal("poison", dl.Error)
+	assert.Equal("dbs/db/colls/orders/", dl.Collection)
+	assert.Equal(`{"id":"SalesOrder1"}`, string(dl.Change))
+	assert.Equal(`["`+dl.Id+`"]`, s.Header.Get(HeaderPartitionKey))
+}
+
+func TestChangeFeedProcessorLeases(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"PartitionKeyRanges": [{"id": "0"}, {"id": "1"}], "_count": 2}`,
+		`{"Documents": [{"id": "SalesOrder1"}], "_count": 1}`,
+		`{"Documents": [], "_count": 0}`)
+	s.SetHeader(HeaderETag, `"42"`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	// another instance holds range 1
+	leases := NewMemoryLeaseStore()
+	_, err := leases.Acquire(context.Background(), "1", "other")
+	assert.Nil(err)
+
+	var mu sync.Mutex
+	events := []string{}
+	event := func(e string) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, e)
+	}
+	processor := client.NewChangeFeedProcessor("dbs/db/colls/coll/", func(ctx context.Context, pkRange string, docs []json.RawMessage) error {
+		return nil
+	}, &ChangeFeedProcessorOptions{
+		PollInterval:    time.Minute,
+		Owner:           "this",
+		Leases:          leases,
+		OnLeaseAcquired: func(pkRange string) { event("acquired " + pkRange) },
+		OnLeaseLost: func(pkRange string, err error) {
+			assert.Nil(err)
+			event("released " + pkRange)
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Nil(processor.Run(ctx))
+	assert.Equal([]string{"acquired 0", "released 0"}, events)
+
+	// the released lease keeps its checkpoint for the next owner
+	lease, err := leases.Acquire(context.Background(), "0", "next")
+	assert.Nil(err)
+	assert.Equal(`"42"`, lease.Continuation)
+	lease, err = leases.Acquire(context.Background(), "1", "next")
+	assert.Nil(err)
+	assert.Nil(lease)
+}
+
+func TestMemoryLeaseStore(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	leases := NewMemoryLeaseStore()
+
+	lease, err := leases.Acquire(ctx, "0", "a")
+	assert.Nil(err)
+	lease.Continuation = `"1"`
+	assert.Nil(leases.Checkpoint(ctx, lease))
+	assert.Nil(leases.Release(ctx, lease))
+
+	taken, err := leases.Acquire(ctx, "0", "b")
+	assert.Nil(err)
+	assert.Equal(`"1"`, taken.Continuation)
+	assert.Equal(ErrLeaseLost, leases.Checkpoint(ctx, lease))
+	assert.Equal(ErrLeaseLost, leases.Release(ctx, lease))
+}
+
+func TestChangeFeedProcessorBalancesLeases(t *testing.T) {
+	assert := assert.New(t)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/pkranges/") {
+			fmt.Fprint(w, `{"PartitionKeyRanges": [{"id": "0"}, {"id": "1"}, {"id": "2"}, {"id": "3"}], "_count": 4}`)
+			return
+		}
+		fmt.Fprint(w, `{"Documents": [], "_count": 0}`)
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	leases := NewMemoryLeaseStore()
+	var mu sync.Mutex
+	lost := map[string][]string{}
+	start := func(ctx context.Context, owner string) chan error {
+		processor := client.NewChangeFeedProcessor("dbs/db/colls/coll/", func(ctx context.Context, pkRange string, docs []json.RawMessage) error {
+			return nil
+		}, &ChangeFeedProcessorOptions{
+			PollInterval:    time.Minute,
+			Owner:           owner,
+			Leases:          leases,
+			AcquireInterval: 5 * time.Millisecond,
+			RenewInterval:   5 * time.Millisecond,
+			OnLeaseLost: func(pkRange string, err error) {
+				if err == ErrLeaseLost {
+					mu.Lock()
+					lost[owner] = append(lost[owner], pkRange)
+					mu.Unlock()
+				}
+			},
+		})
+		done := make(chan error, 1)
+		go func() {
+			done <- processor.Run(ctx)
+		}()
+		return done
+	}
+	owners := func() map[string]int {
+		all, _ := leases.Leases(context.Background())
+		counts := map[string]int{}
+		for _, lease := range all {
+			counts[lease.Owner]++
+		}
+		return counts
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a := start(ctx, "a")
+	assert.True(eventually(func() bool { return owners()["a"] == 4 }))
+	b := start(ctx, "b")
+	assert.True(eventually(func() bool {
+		counts := owners()
+		return counts["a"] == 2 && counts["b"] == 2
+	}))
+	// a stops the ranges taken over at their next renewal
+	assert.True(eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(lost["a"]) == 2
+	}))
+
+	cancel()
+	assert.Nil(<-a)
+	assert.Nil(<-b)
+	assert.Equal(map[string]int{"": 4}, owners())
+}
+
+// eventually - polls the condition for up to a second
+func eventually(condition func() bool) bool {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if condition() {
+			return true
+		}
+	}
+	return condition()
+}
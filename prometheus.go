@@ -0,0 +1,185 @@
+package gocosmosdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultPrometheusBuckets - the upper bounds in seconds of the request duration histogram of a PrometheusCollector
+var DefaultPrometheusBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// PrometheusCollector - an Instrumentation keeping request counts, durations, RUs, throttles, retries and errors,
+// served in the Prometheus text format so it needs no Prometheus client library. Metrics are labelled by method
+// and resource type, and counts and errors also by status.
+//
+//	metrics := gocosmosdb.NewPrometheusCollector("cosmosdb")
+//	client := gocosmosdb.New(url, gocosmosdb.Config{MasterKey: key, Instrumentation: metrics}, log)
+//	http.Handle("/metrics", metrics)
+type PrometheusCollector struct {
+	namespace string
+	buckets   []float64
+	mu        sync.Mutex
+	requests  map[[3]string]float64 // by method, resource type and status
+	errors    map[string]float64    // by status
+	charges   map[[2]string]float64 // by method and resource type, as are the rest
+	throttled map[[2]string]float64
+	retries   map[[2]string]float64
+	durations map[[2]string]*histogram
+}
+
+type histogram struct {
+	counts []float64 // per bucket, cumulative when written
+	count  float64
+	sum    float64
+}
+
+// NewPrometheusCollector - creates a collector of metrics named with the namespace eg. "cosmosdb_requests_total"
+func NewPrometheusCollector(namespace string) *PrometheusCollector {
+	return &PrometheusCollector{
+		namespace: namespace,
+		buckets:   DefaultPrometheusBuckets,
+		requests:  map[[3]string]float64{},
+		errors:    map[string]float64{},
+		charges:   map[[2]string]float64{},
+		throttled: map[[2]string]float64{},
+		retries:   map[[2]string]float64{},
+		durations: map[[2]string]*histogram{},
+	}
+}
+
+// ObserveRequest - records a completed request
+func (p *PrometheusCollector) ObserveRequest(ctx context.Context, e RequestEvent) {
+	op := [2]string{e.Method, e.ResourceType}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests[[3]string{e.Method, e.ResourceType, e.Status()}]++
+	if e.StatusCode == 0 || e.StatusCode >= http.StatusBadRequest {
+		p.errors[e.Status()]++
+	}
+	p.charges[op] += e.RequestCharge
+	if e.Throttled() {
+		p.throttled[op]++
+	}
+	p.retries[op] += float64(e.Retries)
+	h, ok := p.durations[op]
+	if !ok {
+		h = &histogram{counts: make([]float64, len(p.buckets))}
+		p.durations[op] = h
+	}
+	seconds := e.Duration.Seconds()
+	for i, le := range p.buckets {
+		if seconds <= le {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// ServeHTTP - writes the metrics in the Prometheus text exposition format
+func (p *PrometheusCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(HeaderContentType, "text/plain; version=0.0.4")
+	p.WriteTo(w)
+}
+
+// WriteTo - writes the metrics in the Prometheus text exposition format
+func (p *PrometheusCollector) WriteTo(w io.Writer) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var b strings.Builder
+	name := func(metric string) string {
+		return p.namespace + "_" + metric
+	}
+
+	promHeader(&b, name("requests_total"), "counter", "Requests completed, by method, resource type and status.")
+	for _, k := range sortedKeys3(p.requests) {
+		fmt.Fprintf(&b, "%s{method=%q,resource=%q,status=%q} %s\n", name("requests_total"), k[0], k[1], k[2], promValue(p.requests[k]))
+	}
+	promHeader(&b, name("errors_total"), "counter", "Requests failed, by status, error for those failing before a response.")
+	statuses := make([]string, 0, len(p.errors))
+	for status := range p.errors {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		fmt.Fprintf(&b, "%s{status=%q} %s\n", name("errors_total"), status, promValue(p.errors[status]))
+	}
+	for _, counter := range []struct {
+		metric, help string
+		values       map[[2]string]float64
+	}{
+		{"request_charge_total", "Request units charged.", p.charges},
+		{"throttled_total", "Requests still throttled after any retries.", p.throttled},
+		{"retries_total", "Retries made.", p.retries},
+	} {
+		promHeader(&b, name(counter.metric), "counter", counter.help)
+		for _, k := range sortedKeys2(counter.values) {
+			fmt.Fprintf(&b, "%s{method=%q,resource=%q} %s\n", name(counter.metric), k[0], k[1], promValue(counter.values[k]))
+		}
+	}
+
+	metric := name("request_duration_seconds")
+	promHeader(&b, metric, "histogram", "Request durations, retries included.")
+	keys := make([][2]string, 0, len(p.durations))
+	for k := range p.durations {
+		keys = append(keys, k)
+	}
+	sortPairs(keys)
+	for _, k := range keys {
+		h := p.durations[k]
+		labels := fmt.Sprintf("method=%q,resource=%q", k[0], k[1])
+		for i, le := range p.buckets {
+			fmt.Fprintf(&b, "%s_bucket{%s,le=%q} %s\n", metric, labels, promValue(le), promValue(h.counts[i]))
+		}
+		fmt.Fprintf(&b, "%s_bucket{%s,le=\"+Inf\"} %s\n", metric, labels, promValue(h.count))
+		fmt.Fprintf(&b, "%s_sum{%s} %s\n", metric, labels, promValue(h.sum))
+		fmt.Fprintf(&b, "%s_count{%s} %s\n", metric, labels, promValue(h.count))
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// promHeader - writes the help and type lines of a metric
+func promHeader(b *strings.Builder, metric, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", metric, help, metric, kind)
+}
+
+// promValue - formats a sample value the shortest way
+func promValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys3(m map[[3]string]float64) [][3]string {
+	keys := make([][3]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return strings.Join(keys[i][:], "\x00") < strings.Join(keys[j][:], "\x00")
+	})
+	return keys
+}
+
+func sortedKeys2(m map[[2]string]float64) [][2]string {
+	keys := make([][2]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sortPairs(keys)
+	return keys
+}
+
+func sortPairs(keys [][2]string) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] == keys[j][0] {
+			return keys[i][1] < keys[j][1]
+		}
+		return keys[i][0] < keys[j][0]
+	})
+}
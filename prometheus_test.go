@@ -0,0 +1,46 @@
+package gocosmosdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusCollector(t *testing.T) {
+	assert := assert.New(t)
+	metrics := NewPrometheusCollector("cosmosdb")
+	ctx := context.Background()
+	metrics.ObserveRequest(ctx, RequestEvent{Method: "GET", ResourceType: "docs", StatusCode: 200, Duration: 20 * time.Millisecond, RequestCharge: 1})
+	metrics.ObserveRequest(ctx, RequestEvent{Method: "GET", ResourceType: "docs", StatusCode: 429, Duration: 3 * time.Second, RequestCharge: 0.5, Retries: 2})
+	metrics.ObserveRequest(ctx, RequestEvent{Method: "POST", ResourceType: "docs", Err: errors.New("connection refused")})
+
+	w := httptest.NewRecorder()
+	metrics.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal("text/plain; version=0.0.4", w.Header().Get(HeaderContentType))
+	out := w.Body.String()
+	for _, line := range []string{
+		"# TYPE cosmosdb_requests_total counter",
+		`cosmosdb_requests_total{method="GET",resource="docs",status="200"} 1`,
+		`cosmosdb_requests_total{method="GET",resource="docs",status="429"} 1`,
+		`cosmosdb_requests_total{method="POST",resource="docs",status="error"} 1`,
+		`cosmosdb_errors_total{status="429"} 1`,
+		`cosmosdb_errors_total{status="error"} 1`,
+		`cosmosdb_request_charge_total{method="GET",resource="docs"} 1.5`,
+		`cosmosdb_throttled_total{method="GET",resource="docs"} 1`,
+		`cosmosdb_retries_total{method="GET",resource="docs"} 2`,
+		"# TYPE cosmosdb_request_duration_seconds histogram",
+		`cosmosdb_request_duration_seconds_bucket{method="GET",resource="docs",le="0.025"} 1`,
+		`cosmosdb_request_duration_seconds_bucket{method="GET",resource="docs",le="5"} 2`,
+		`cosmosdb_request_duration_seconds_bucket{method="GET",resource="docs",le="+Inf"} 2`,
+		`cosmosdb_request_duration_seconds_sum{method="GET",resource="docs"} 3.02`,
+		`cosmosdb_request_duration_seconds_count{method="GET",resource="docs"} 2`,
+	} {
+		assert.Contains(out, line+"\n")
+	}
+	assert.NotContains(out, `cosmosdb_errors_total{status="200"}`)
+}
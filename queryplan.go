@@ -0,0 +1,140 @@
+package gocosmosdb
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// SupportedQueryFeatures - the query features the client declares when asking for a query plan, the service
+// refuses to plan queries using others
+const SupportedQueryFeatures = "Aggregate, CompositeAggregate, Distinct, MultipleOrderBy, OffsetAndLimit, OrderBy, Top, GroupBy"
+
+// QueryPlan - how the service executes a query across the partitions of a collection
+type QueryPlan struct {
+	Version     int          `json:"partitionedQueryExecutionInfoVersion"`
+	QueryInfo   QueryInfo    `json:"queryInfo"`
+	QueryRanges []QueryRange `json:"queryRanges"` // the effective partition keys the query reads
+}
+
+// QueryInfo - what the client has to do with the results of the partitions of a query
+type QueryInfo struct {
+	DistinctType       string   `json:"distinctType"` // None, Ordered or Unordered
+	Top                *int     `json:"top"`
+	Offset             *int     `json:"offset"`
+	Limit              *int     `json:"limit"`
+	OrderBy            []string `json:"orderBy"` // Ascending or Descending per expression
+	OrderByExpressions []string `json:"orderByExpressions"`
+	GroupByExpressions []string `json:"groupByExpressions"`
+	Aggregates         []string `json:"aggregates"` // eg. Count, Sum
+	RewrittenQuery     string   `json:"rewrittenQuery"`
+	HasSelectValue     bool     `json:"hasSelectValue"`
+}
+
+// QueryRange - a range of effective partition keys read by a query
+type QueryRange struct {
+	Min            string `json:"min"`
+	Max            string `json:"max"`
+	IsMinInclusive bool   `json:"isMinInclusive"`
+	IsMaxInclusive bool   `json:"isMaxInclusive"`
+}
+
+// GetQueryPlan - asks the service how it executes a query on a collection, without running it
+//
+//	plan, err := client.GetQueryPlan("dbs/{db-id}/colls/{coll-id}/", &gocosmosdb.QueryWithParameters{Query: "SELECT * FROM c WHERE c.pk = 't1'"})
+//	if plan.SinglePartition() {
+//		...
+//	}
+func (c *CosmosDB) GetQueryPlan(coll string, query *QueryWithParameters, opts ...CallOption) (*QueryPlan, error) {
+	if query == nil {
+		return nil, errors.New("QueryWithParameters cannot be nil")
+	}
+	plan := &QueryPlan{}
+	if _, err := c.client.queryPlan(normalizeLink(coll)+"docs/", query, plan, opts...); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// SinglePartition - reports whether the query only reads one partition key, so it runs pinned to it without
+// fanning out
+func (p *QueryPlan) SinglePartition() bool {
+	return len(p.QueryRanges) == 1 && p.QueryRanges[0].Min == p.QueryRanges[0].Max
+}
+
+// NeedsMerge - reports whether the results of the partitions of the query have to be merged by the client eg.
+// ordered or aggregated, rather than appended to each other
+func (p *QueryPlan) NeedsMerge() bool {
+	q := p.QueryInfo
+	return len(q.OrderBy) > 0 || len(q.Aggregates) > 0 || len(q.GroupByExpressions) > 0 ||
+		q.DistinctType != "" && q.DistinctType != "None" || q.Top != nil || q.Offset != nil || q.Limit != nil
+}
+
+// Ranges - returns the partition key ranges holding the documents the query reads, those a fan out has to query
+func (p *QueryPlan) Ranges(ranges []PartitionKeyRange) []PartitionKeyRange {
+	sorted := make([]PartitionKeyRange, len(ranges))
+	copy(sorted, ranges)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].MinInclusive < sorted[j].MinInclusive })
+	var read []PartitionKeyRange
+	for _, r := range sorted {
+		for _, q := range p.QueryRanges {
+			if q.Min < r.MaxInclusive && (r.MinInclusive < q.Max || q.IsMaxInclusive && r.MinInclusive == q.Max) {
+				read = append(read, r)
+				break
+			}
+		}
+	}
+	return read
+}
+
+// plannedQuery - pins a query to the partition key range its plan reads when it only reads one, fanning it out
+// otherwise
+func (c *apiClient) plannedQuery(r *Request, link string, query *QueryWithParameters) error {
+	coll := normalizeLink(collectionOf(link))
+	var opts []CallOption
+	if r.rContext != nil {
+		opts = append(opts, WithContext(r.rContext))
+	}
+	plan := &QueryPlan{}
+	if _, err := c.queryPlan(coll+"docs/", query, plan, opts...); err != nil {
+		return err
+	}
+	data := struct {
+		PartitionKeyRanges []PartitionKeyRange `json:"PartitionKeyRanges,omitempty"`
+	}{}
+	if _, err := c.read(coll+"pkranges/", &data, opts...); err != nil {
+		return err
+	}
+	ranges := plan.Ranges(data.PartitionKeyRanges)
+	if len(ranges) != 1 {
+		return nil
+	}
+	id, err := strconv.Atoi(ranges[0].Id)
+	if err != nil {
+		return err
+	}
+	return PartitionKeyRangeID(id)(r)
+}
+
+// queryPlan - posts a query asking for its plan
+func (c *apiClient) queryPlan(link string, query *QueryWithParameters, ret interface{}, opts ...CallOption) (*Response, error) {
+	q, err := stringify(query)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBuffer(q)
+	req, err := http.NewRequest(http.MethodPost, path(c.uri, link), buf)
+	if err != nil {
+		return nil, err
+	}
+	r := ResourceRequest(link, req)
+	if err = c.apply(r, append([]CallOption{CrossPartition(), QueryVersion()}, opts...)); err != nil {
+		return nil, err
+	}
+	r.QueryHeaders(buf.Len())
+	r.Header.Set(HeaderIsQueryPlan, "True")
+	r.Header.Set(HeaderSupportedQueryFeatures, SupportedQueryFeatures)
+	return c.do(r, expectOK, ret)
+}
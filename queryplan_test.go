@@ -0,0 +1,70 @@
+package gocosmosdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetQueryPlan(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{
+		"partitionedQueryExecutionInfoVersion": 2,
+		"queryInfo": {"distinctType": "None", "top": null, "orderBy": ["Descending"], "orderByExpressions": ["c.ts"], "aggregates": []},
+		"queryRanges": [{"min": "", "max": "FF", "isMinInclusive": true, "isMaxInclusive": false}]
+	}`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	plan, err := client.GetQueryPlan("dbs/db/colls/coll", &QueryWithParameters{Query: "SELECT * FROM c ORDER BY c.ts DESC"})
+	assert.Nil(err)
+	assert.Equal(2, plan.Version)
+	assert.Equal([]string{"c.ts"}, plan.QueryInfo.OrderByExpressions)
+	assert.False(plan.SinglePartition())
+	assert.True(plan.NeedsMerge())
+	assert.Equal("True", s.Header.Get(HeaderIsQueryPlan))
+	assert.Equal("true", s.Header.Get(HeaderCrossPartition))
+	assert.Equal(SupportedQueryFeatures, s.Header.Get(HeaderSupportedQueryFeatures))
+	assert.Equal("application/query+json", s.Header.Get(HeaderContentType))
+	assert.JSONEq(`{"query": "SELECT * FROM c ORDER BY c.ts DESC", "parameters": null}`, s.Body)
+}
+
+func TestQueryPlanRanges(t *testing.T) {
+	assert := assert.New(t)
+	ranges := []PartitionKeyRange{
+		{Resource: Resource{Id: "2"}, MinInclusive: "80", MaxInclusive: "FF"},
+		{Resource: Resource{Id: "0"}, MinInclusive: "", MaxInclusive: "40"},
+		{Resource: Resource{Id: "1"}, MinInclusive: "40", MaxInclusive: "80"},
+	}
+	point := &QueryPlan{QueryRanges: []QueryRange{{Min: "5A", Max: "5A", IsMinInclusive: true, IsMaxInclusive: true}}}
+	assert.True(point.SinglePartition())
+	assert.False(point.NeedsMerge())
+	assert.Equal(ranges[2:], point.Ranges(ranges))
+
+	full := &QueryPlan{QueryRanges: []QueryRange{{Min: "", Max: "FF", IsMinInclusive: true}}}
+	assert.Equal([]PartitionKeyRange{ranges[1], ranges[2], ranges[0]}, full.Ranges(ranges))
+
+	// the end of a range is exclusive
+	edge := &QueryPlan{QueryRanges: []QueryRange{{Min: "10", Max: "40", IsMinInclusive: true}}}
+	assert.Equal(ranges[1:2], edge.Ranges(ranges))
+}
+
+func TestQueryPlans(t *testing.T) {
+	assert := assert.New(t)
+	point := `{"queryInfo": {}, "queryRanges": [{"min": "5A", "max": "5A", "isMinInclusive": true, "isMaxInclusive": true}]}`
+	full := `{"queryInfo": {}, "queryRanges": [{"min": "", "max": "FF", "isMinInclusive": true, "isMaxInclusive": false}]}`
+	s := ServerFactory(point, testPartitionKeyRanges, `{"Documents": []}`, full, testPartitionKeyRanges, `{"Documents": []}`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", CrossPartitionQueries: true, PartitionKeyPath: "/tenant", QueryPlans: true}, log)
+
+	// the filter cannot be pinned by the client but the plan reads a single range
+	docs := []testDoc{}
+	_, err := client.QueryDocuments("dbs/db/colls/coll/", "SELECT * FROM c WHERE c.tenant IN ('t1')", &docs)
+	assert.Nil(err)
+	assert.Equal("1", s.Header.Get(HeaderPartitionKeyRangeID))
+	assert.Equal("true", s.Header.Get(HeaderCrossPartition))
+
+	_, err = client.QueryDocuments("dbs/db/colls/coll/", "SELECT * FROM c WHERE c.tenant IN ('t1', 't2')", &docs)
+	assert.Nil(err)
+	assert.Equal("", s.Header.Get(HeaderPartitionKeyRangeID))
+}
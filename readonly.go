@@ -0,0 +1,57 @@
+package gocosmosdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ReadOnlyError - returned by clients in ReadOnly mode for calls that would write, eg. creates, replaces, deletes
+// and stored procedure executions
+type ReadOnlyError struct {
+	Operation string // method and path eg. "DELETE /dbs/db/colls/coll/docs/1"
+}
+
+// Implement Error function
+func (e *ReadOnlyError) Error() string {
+	return fmt.Sprintf("%s refused, the client is read only", e.Operation)
+}
+
+// guardWrite - refuses the requests of a ReadOnly client that would write, reads and queries pass
+func (c *apiClient) guardWrite(r *Request) error {
+	if !c.config.ReadOnly || !writes(r) || r.Header.Get(HeaderIsBatchRequest) == "true" {
+		// batches are checked by their operations, see guardBatch
+		return nil
+	}
+	return &ReadOnlyError{Operation: r.Method + " " + r.URL.Path}
+}
+
+// writes - reports whether a request may write, reads and queries do not
+func writes(r *Request) bool {
+	switch {
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		return false
+	case r.Method == http.MethodPost && r.Header.Get(HeaderIsQuery) == "true":
+		return false
+	}
+	return true
+}
+
+// guardBatch - refuses the batches of a ReadOnly client with other operations than reads
+func (c *apiClient) guardBatch(link string, data []byte) error {
+	if !c.config.ReadOnly {
+		return nil
+	}
+	var ops []struct {
+		OperationType string `json:"operationType"`
+	}
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return err
+	}
+	for _, op := range ops {
+		if op.OperationType != BatchRead {
+			return &ReadOnlyError{Operation: op.OperationType + " in a batch on " + link}
+		}
+	}
+	return nil
+}
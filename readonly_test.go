@@ -0,0 +1,36 @@
+package gocosmosdb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnly(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"id": "1"}`, `{"Documents": [], "_count": 0}`, `[{"statusCode": 200}]`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", ReadOnly: true}, log)
+
+	var doc testDoc
+	_, err := client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+	assert.Nil(err)
+	docs := []testDoc{}
+	_, err = client.QueryDocuments("dbs/db/colls/coll/", "SELECT * FROM c", &docs)
+	assert.Nil(err)
+	_, err = client.ExecuteBatch(NewTransactionalBatch("dbs/db/colls/coll/", "pk").Read("1"))
+	assert.Nil(err)
+
+	var readOnly *ReadOnlyError
+	_, err = client.DeleteDocument("dbs/db/colls/coll/docs/1")
+	assert.True(errors.As(err, &readOnly))
+	assert.Equal("DELETE /dbs/db/colls/coll/docs/1", readOnly.Operation)
+	_, err = client.CreateDocument("dbs/db/colls/coll/", &doc)
+	assert.True(errors.As(err, &readOnly))
+	_, err = client.ExecuteStoredProcedure("dbs/db/colls/coll/sprocs/sp", nil, nil)
+	assert.True(errors.As(err, &readOnly))
+	_, err = client.ExecuteBatch(NewTransactionalBatch("dbs/db/colls/coll/", "pk").Read("1").Delete("2"))
+	assert.True(errors.As(err, &readOnly))
+	assert.Equal("Delete in a batch on dbs/db/colls/coll/docs/", readOnly.Operation)
+}
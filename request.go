@@ -1,50 +1,153 @@
 package gocosmosdb
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
 )
 
-// RequestError
+// RequestError - the error of a call the service failed, match it with errors.Is against ErrNotFound and the
+// other errors below, or helpers like IsNotFound, and read its details with errors.As
+//
+//	var reqErr *gocosmosdb.RequestError
+//	if errors.As(err, &reqErr) {
+//		log.Infof("%d %s, activity %s", reqErr.StatusCode, reqErr.Code, reqErr.ActivityID)
+//	}
 type RequestError struct {
 	Code       string        `json:"code"`
 	StatusCode int           `json:"statusCode"`
 	Message    string        `json:"message"`
 	RId        string        `json:"rId"`
-	RType      string        `json:"rType`
+	RType      string        `json:"rType"`
 	Request    *http.Request `json:"request"`
+	Retries    int           `json:"-"` // retries made before giving up, with RetryThrottled or a RetryPolicy
+	ActivityID string        `json:"-"` // the id of the operation on the service, quote it in support requests
+	RetryAfter time.Duration `json:"-"` // the wait a throttled call was asked for before retrying, 0 otherwise
+	SubStatus  int           `json:"-"` // the x-ms-substatus of the response, eg. SubStatusPartitionKeyRangeGone, 0 without
 }
 
 // Implement Error function
 func (e RequestError) Error() string {
+	if e.Retries > 0 {
+		return fmt.Sprintf("%v, %v (after %d retries)", e.Code, e.Message, e.Retries)
+	}
 	return fmt.Sprintf("%v, %v", e.Code, e.Message)
 }
 
+var (
+	// ErrNotFound - the resource does not exist
+	ErrNotFound = errors.New("resource not found")
+
+	// ErrConflict - a resource with the same id already exists
+	ErrConflict = errors.New("resource already exists")
+
+	// ErrPreconditionFailed - the resource changed since the etag passed with IfMatch was read
+	ErrPreconditionFailed = errors.New("resource precondition failed")
+
+	// ErrTooManyRequests - the request rate exceeded the provisioned throughput
+	ErrTooManyRequests = errors.New("request rate is too large")
+)
+
+var statusErrors = map[int]error{
+	http.StatusNotFound:           ErrNotFound,
+	http.StatusConflict:           ErrConflict,
+	http.StatusPreconditionFailed: ErrPreconditionFailed,
+	http.StatusTooManyRequests:    ErrTooManyRequests,
+}
+
+// Is - matches the semantic error of the status code, so callers can check results with errors.Is
+//	if errors.Is(err, gocosmosdb.ErrConflict) {
+//		// the document was already created
+//	}
+func (e RequestError) Is(target error) bool {
+	return target != nil && statusErrors[e.StatusCode] == target
+}
+
+// IsNotFound - reports whether the call failed as the resource does not exist
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsConflict - reports whether the call failed as a resource with the same id already exists
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsPreconditionFailed - reports whether the call failed as the resource changed since the etag passed was read
+func IsPreconditionFailed(err error) bool {
+	return errors.Is(err, ErrPreconditionFailed)
+}
+
+// IsThrottled - reports whether the call failed as the request rate exceeded the provisioned throughput
+func IsThrottled(err error) bool {
+	return errors.Is(err, ErrTooManyRequests)
+}
+
+// The substatus codes of failed calls, their meaning depends on the status code
+const (
+	SubStatusPartitionKeyMismatch    = 1001 // 400, the partition key passed is not that of the document
+	SubStatusReadSessionNotAvailable = 1002 // 404, the replica has not caught up with the session token yet
+	SubStatusOwnerResourceNotFound   = 1003 // 404, the database or collection of the resource does not exist
+	SubStatusNameCacheStale          = 1000 // 410, the collection was recreated with the same name
+	SubStatusPartitionKeyRangeGone   = 1002 // 410, the partition split or merged
+	SubStatusCompletingSplit         = 1007 // 410, the partition is splitting
+	SubStatusCompletingMigration     = 1008 // 410, the partition is moving
+	SubStatusRUBudgetExceeded        = 3200 // 429, the RU budget of the request rate is used up
+)
+
+// IsPartitionGone - reports whether the call failed as the partition it was sent to split or moved, which calls
+// retried once the partition key ranges are read afresh get past, unlike other failures
+func IsPartitionGone(err error) bool {
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) || reqErr.StatusCode != http.StatusGone {
+		return false
+	}
+	switch reqErr.SubStatus {
+	case SubStatusNameCacheStale, SubStatusPartitionKeyRangeGone, SubStatusCompletingSplit, SubStatusCompletingMigration:
+		return true
+	}
+	return false
+}
+
 // Resource Request
 type Request struct {
-	rLink    string
-	rId      string
-	rType    string
-	rContext context.Context
+	rLink           string
+	rId             string
+	rType           string
+	rContext        context.Context
+	rResponse       *Response
+	rThroughput     string // the throughput option applied, they are mutually exclusive
+	rIgnoreNotFound bool
+	rTag            string // the operation tag routing the request, see OperationTag
+	rCostCenter     string
+	rNonCritical    bool     // may be refused by the RUBudget of the client
+	rPriority       Priority // the order the Governor of the client admits it in while throttled
+	rPatchCondition string   // the filter predicate a partial update applies under
+	rExecution      bool     // a stored procedure execution, only retried when it did not run unless rIdempotent
+	rIdempotent     bool
+	rMissing        *[]string   // filled with the fields of the result the response lacked, see MissingFields
+	rSchema         *Schema     // upgrades the documents read, see WithSchema
+	rOld            interface{} // filled with the document a replace or delete changes, see WithOldDocument
 	*http.Request
 }
 
 // Return new resource request with type and id
 func ResourceRequest(link string, req *http.Request) *Request {
 	rLink, rId, rType := parse(link)
-	return &Request{rLink, rId, rType, nil, req}
+	return &Request{rLink: rLink, rId: rId, rType: rType, Request: req}
 }
 
 // Add 3 default headers to *Request
 // "x-ms-date", "x-ms-version", "authorization"
 func (req *Request) DefaultHeaders(mKey string) (err error) {
-	req.Header.Add(HeaderXDate, time.Now().UTC().Format("Mon, 02 Jan 2006 15:04:05 GMT"))
-	req.Header.Add(HeaderVersion, SupportedAPIVersion)
-	req.Header.Add(HeaderUserAgent, UserAgent)
+	req.baseHeaders()
 
 	// Auth
 	parts := req.Method + "\n" +
@@ -66,6 +169,36 @@ func (req *Request) DefaultHeaders(mKey string) (err error) {
 	return
 }
 
+// setBody - replaces the request body keeping the content length in sync
+func (req *Request) setBody(data []byte) {
+	req.Body = ioutil.NopCloser(bytes.NewReader(data))
+	req.ContentLength = int64(len(data))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(data)), nil
+	}
+}
+
+// ResourceTokenHeaders - adds the default headers with a resource token as the authorization
+func (req *Request) ResourceTokenHeaders(token string) {
+	req.baseHeaders()
+	req.Header.Add(HeaderAuth, url.QueryEscape(token))
+}
+
+// baseHeaders - adds the date, version and user agent headers every request carries
+func (req *Request) baseHeaders() {
+	req.Header.Add(HeaderXDate, time.Now().UTC().Format("Mon, 02 Jan 2006 15:04:05 GMT"))
+	req.Header.Add(HeaderVersion, SupportedAPIVersion)
+	req.Header.Add(HeaderUserAgent, UserAgent)
+}
+
+// ctx - returns the context passed with WithContext or the background context
+func (req *Request) ctx() context.Context {
+	if req.rContext != nil {
+		return req.rContext
+	}
+	return context.Background()
+}
+
 // Add headers for query request
 func (req *Request) QueryHeaders(len int) {
 	req.Header.Add(HeaderContentType, "application/query+json")
@@ -102,6 +235,11 @@ func parse(link string) (rLink, rId, rType string) {
 	parts := strings.Split(link, "/")
 	l := len(parts)
 
+	// the database account itself has no type or link
+	if l < 3 {
+		return
+	}
+
 	//spew.Dump(parts)
 	if strings.Index(parts[2], "==") > -1 { // use this logic if it's a _self link
 		if l%2 == 0 {
@@ -114,7 +252,13 @@ func parse(link string) (rLink, rId, rType string) {
 			rType = parts[l-2]
 		}
 	} else { // use this logic if it's a constructed uri using ids
-		if l == 3 && parts[1] == "dbs" {
+		if parts[1] == "offers" { // offers are signed with their lowercased _rid
+			if l == 4 {
+				rLink = strings.ToLower(parts[2])
+				rId = rLink
+			}
+			rType = parts[1]
+		} else if l == 3 && parts[1] == "dbs" {
 			rLink = ""
 			rId = ""
 			rType = parts[1]
@@ -143,3 +287,12 @@ func parse(link string) (rLink, rId, rType string) {
 
 	return
 }
+
+// throughput - records the throughput option applied to the request, failing when another was already applied
+func (r *Request) throughput(option string) error {
+	if r.rThroughput != "" && r.rThroughput != option {
+		return fmt.Errorf("%s cannot be combined with %s", option, r.rThroughput)
+	}
+	r.rThroughput = option
+	return nil
+}
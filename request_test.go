@@ -42,11 +42,18 @@ func TestDefaultHeaders(t *testing.T) {
 func TestParseLink(t *testing.T) {
 	assert := assert.New(t)
 
-	// /dbs	Feed of databases under a database account - 1 - 3
-	link := "/dbs"
+	// the database account
+	link := ""
 	rLink, rId, rType := parse(link)
 	assert.Equal("", rLink)
 	assert.Equal("", rId)
+	assert.Equal("", rType)
+
+	// /dbs	Feed of databases under a database account - 1 - 3
+	link = "/dbs"
+	rLink, rId, rType = parse(link)
+	assert.Equal("", rLink)
+	assert.Equal("", rId)
 	assert.Equal("dbs", rType)
 
 	// /dbs/{dbName}	Database with an id matching the value {dbName} - 2 - 4
@@ -174,4 +181,18 @@ func TestParseLink(t *testing.T) {
 	assert.Equal("dbs/mydb/users/mycoll/permissions/mydoc", rLink)
 	assert.Equal("mydoc", rId)
 	assert.Equal("permissions", rType)
+
+	// /offers	Feed of offers of the account
+	link = "/offers"
+	rLink, rId, rType = parse(link)
+	assert.Equal("", rLink)
+	assert.Equal("", rId)
+	assert.Equal("offers", rType)
+
+	// /offers/{_rid-offer}	Offer with a resource id matching the value {_rid-offer}
+	link = "/offers/Bp5Y"
+	rLink, rId, rType = parse(link)
+	assert.Equal("bp5y", rLink)
+	assert.Equal("bp5y", rId)
+	assert.Equal("offers", rType)
 }
@@ -0,0 +1,75 @@
+package gocosmosdb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/intwinelabs/logger"
+)
+
+// DefaultTokenRefresh - how long a resource token is used before the broker is asked for a new one,
+// tokens are valid for one hour by default
+const DefaultTokenRefresh = 50 * time.Minute
+
+// TokenBroker - issues the permission, carrying a resource token, for an end user
+type TokenBroker interface {
+	Permission(ctx context.Context, userID string) (*Permission, error)
+}
+
+// PermissionBroker - a TokenBroker that reads a named permission of each user with a master key client
+type PermissionBroker struct {
+	Client       *CosmosDB
+	Database     string // eg. "dbs/{db-id}"
+	PermissionID string // the permission id shared by all users
+}
+
+// Permission - reads the users permission, which returns a freshly issued resource token
+func (b *PermissionBroker) Permission(ctx context.Context, userID string) (*Permission, error) {
+	return b.Client.ReadPermission(b.Database+"/users/"+userID+"/permissions/"+b.PermissionID, WithContext(ctx))
+}
+
+// NewUserClient - creates a client authorized with the resource token the broker issues for the user,
+// every document operation is scoped to the resource partition key of the users permission
+//
+//	broker := &gocosmosdb.PermissionBroker{Client: master, Database: "dbs/{db-id}", PermissionID: "read-own"}
+//	client := gocosmosdb.NewUserClient(url, gocosmosdb.Config{}, broker, "{user-id}", log)
+func NewUserClient(url string, config Config, broker TokenBroker, userID string, log *logger.Logger) *CosmosDB {
+	c := New(url, config, log)
+	refresh := config.TokenRefresh
+	if refresh == 0 {
+		refresh = DefaultTokenRefresh
+	}
+	c.client.tokens = &userTokens{broker: broker, userID: userID, refresh: refresh}
+	return c
+}
+
+// userTokens - caches the permission of a user until it is due for refresh
+type userTokens struct {
+	broker     TokenBroker
+	userID     string
+	refresh    time.Duration
+	mu         sync.Mutex
+	permission *Permission
+	fetched    time.Time
+}
+
+// get - returns the cached permission, asking the broker for a new one once it is due
+func (u *userTokens) get(ctx context.Context) (*Permission, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.permission != nil && time.Since(u.fetched) < u.refresh {
+		return u.permission, nil
+	}
+	perm, err := u.broker.Permission(ctx, u.userID)
+	if err != nil {
+		return nil, err
+	}
+	if perm == nil || perm.Token == "" {
+		return nil, errors.New("token broker returned no resource token for user " + u.userID)
+	}
+	u.permission = perm
+	u.fetched = time.Now()
+	return perm, nil
+}
@@ -0,0 +1,63 @@
+package gocosmosdb
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testBroker struct {
+	calls int
+}
+
+func (b *testBroker) Permission(ctx context.Context, userID string) (*Permission, error) {
+	b.calls++
+	return &Permission{
+		Token:                "type=resource&ver=1&sig=" + userID,
+		ResourcePartitionKey: []interface{}{userID},
+	}, nil
+}
+
+func TestNewUserClient(t *testing.T) {
+	assert := assert.New(t)
+	doc := `{"id": "SalesOrder1", "ponumber": "PO18009186470"}`
+	s := ServerFactory(doc, doc)
+	defer s.Close()
+	broker := &testBroker{}
+	client := NewUserClient(s.URL, Config{}, broker, "user1", log)
+
+	for i := 0; i < 2; i++ {
+		d := testDoc{}
+		_, err := client.ReadDocument("dbs/db/colls/coll/docs/SalesOrder1", &d)
+		assert.Nil(err)
+		assert.Equal("SalesOrder1", d.Id)
+	}
+	assert.Equal(1, broker.calls)
+	assert.Equal(url.QueryEscape("type=resource&ver=1&sig=user1"), s.Header.Get(HeaderAuth))
+	assert.Equal("[\"user1\"]", s.Header.Get(HeaderPartitionKey))
+}
+
+func TestReadPermission(t *testing.T) {
+	assert := assert.New(t)
+	resp := `{
+		"id": "read-own",
+		"permissionMode": "All",
+		"resource": "dbs/volcanodb/colls/volcano1",
+		"resourcePartitionKey": ["user1"],
+		"_token": "type=resource&ver=1&sig=m32/6Pq4gLJ1bPhnBjFpQA==;",
+		"_rid": "Sl8fAG8cXgBn6Ju2GqNsAA==",
+		"_ts": 1449604760,
+		"_self": "dbs\/Sl8fAA==\/users\/Sl8fAG8cXgA=\/permissions\/Sl8fAG8cXgBn6Ju2GqNsAA==\/",
+		"_etag": "\"00000e00-0000-0000-0000-566736980000\""
+	}`
+	s := ServerFactory(resp)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+	broker := &PermissionBroker{Client: client, Database: "dbs/volcanodb", PermissionID: "read-own"}
+	perm, err := broker.Permission(context.Background(), "user1")
+	assert.Nil(err)
+	assert.Equal("type=resource&ver=1&sig=m32/6Pq4gLJ1bPhnBjFpQA==;", perm.Token)
+	assert.Equal([]interface{}{"user1"}, perm.ResourcePartitionKey)
+}
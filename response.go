@@ -1,14 +1,22 @@
 package gocosmosdb
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"math"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 )
 
+// Response - the metadata of the response to a call, returned by the calls or populated with WithResponse, its
+// methods read the headers most often needed
 type Response struct {
-	Header http.Header
+	Header     http.Header
+	StatusCode int
+	Endpoint   string // the endpoint that served the call eg. "https://{account}-westus.documents.azure.com:443"
 }
 
 // Continuation - returns continuation token for paged request.
@@ -17,12 +25,49 @@ func (r *Response) Continuation() string {
 	return r.Header.Get(HeaderContinuation)
 }
 
+// ItemCount - returns the number of items in a query or read-feed page, -1 when the header is missing
+func (r *Response) ItemCount() int {
+	count, err := strconv.Atoi(r.Header.Get(HeaderItemCount))
+	if err != nil {
+		return -1
+	}
+	return count
+}
+
+// ETag - returns the etag of the resource, for change feed reads the position to continue from
+func (r *Response) ETag() string {
+	return r.Header.Get(HeaderETag)
+}
+
+// ActivityID - returns the id of the operation on the service, quote it in support requests
+func (r *Response) ActivityID() string {
+	return r.Header.Get(HeaderActivityID)
+}
+
+// RequestCharge - returns the RUs charged for the call, 0 when the header is missing, see GetRUs to tell it apart
+func (r *Response) RequestCharge() float64 {
+	charge, err := r.GetRUs()
+	if err != nil {
+		return 0
+	}
+	return charge
+}
+
 // SessionToken - returns session token for session consistent request.
 // Pass this value to next request to maintain session consistency documents.
 func (r *Response) SessionToken() string {
 	return r.Header.Get(HeaderSessionToken)
 }
 
+// ScriptLog - returns what a stored procedure run with EnableScriptLogging logged with console.log
+func (r *Response) ScriptLog() string {
+	log := r.Header.Get(HeaderScriptLogResults)
+	if decoded, err := url.PathUnescape(log); err == nil {
+		return decoded
+	}
+	return log
+}
+
 // GetRUs - returns a responses RUs
 func (r *Response) GetRUs() (float64, error) {
 	// x-ms-request-charge: 604.42
@@ -98,6 +143,23 @@ func (r *Response) GetQueryMetrics() (*Metrics, error) {
 	return metrics, nil
 }
 
+// GetIndexMetrics - returns the indexes a query run with EnablePopulateIndexMetrics used and those it could have
+func (r *Response) GetIndexMetrics() (*IndexMetrics, error) {
+	encoded := r.Header.Get(HeaderIndexUtilization)
+	if encoded == "" {
+		return nil, fmt.Errorf("no index metrics in response")
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding index metrics header: %v", err)
+	}
+	metrics := &IndexMetrics{}
+	if err = json.Unmarshal(data, metrics); err != nil {
+		return nil, fmt.Errorf("error parsing index metrics header: %v", err)
+	}
+	return metrics, nil
+}
+
 func getMetricKeyVal(metricSlice []string) (string, float64, error) {
 	if len(metricSlice) == 2 {
 		var metric float64
@@ -1,6 +1,7 @@
 package gocosmosdb
 
 import (
+	"encoding/base64"
 	"net/http"
 	"testing"
 
@@ -27,6 +28,59 @@ func TestResponseContinuation(t *testing.T) {
 	assert.Equal("testContinuation", continuation)
 }
 
+func TestResponseScriptLog(t *testing.T) {
+	assert := assert.New(t)
+
+	resp := &Response{Header: http.Header{}}
+	resp.Header.Set(HeaderScriptLogResults, "closing%20o1%3A%201+1")
+	assert.Equal("closing o1: 1+1", resp.ScriptLog())
+	resp.Header.Set(HeaderScriptLogResults, "100%")
+	assert.Equal("100%", resp.ScriptLog())
+}
+
+func TestResponseItemCount(t *testing.T) {
+	assert := assert.New(t)
+
+	resp := &Response{Header: http.Header{}}
+	assert.Equal(-1, resp.ItemCount())
+	resp.Header.Set(HeaderItemCount, "10")
+	assert.Equal(10, resp.ItemCount())
+}
+
+func TestWithResponse(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"_rid": "", "Databases": [{"id": "iot2"}], "_count": 1}`)
+	s.SetHeader(HeaderContinuation, "testContinuation")
+	s.SetHeader(HeaderItemCount, "1")
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	var resp Response
+	dbs, err := client.QueryDatabases("", Limit(1), WithResponse(&resp))
+	assert.Nil(err)
+	assert.Equal("iot2", dbs[0].Id)
+	assert.Equal("testContinuation", resp.Continuation())
+	assert.Equal(1, resp.ItemCount())
+	assert.Equal("1", s.Header.Get(HeaderMaxItemCount))
+}
+
+func TestWithResponseFailed(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(http.StatusNotFound)
+	s.SetHeader(HeaderActivityID, "a1b2")
+	s.SetHeader(HeaderRequestCharge, "1.24")
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	var resp Response
+	_, err := client.ReadDatabase("dbs/db/", WithResponse(&resp))
+	assert.NotNil(err)
+	assert.Equal(http.StatusNotFound, resp.StatusCode)
+	assert.Equal("a1b2", resp.ActivityID())
+	assert.Equal(1.24, resp.RequestCharge())
+	assert.Equal(0.0, (&Response{Header: http.Header{}}).RequestCharge())
+}
+
 func TestResponseSessionToken(t *testing.T) {
 	assert := assert.New(t)
 
@@ -89,3 +143,25 @@ func TestGetRUs(t *testing.T) {
 	assert.Nil(err)
 	assert.Equal(float64(604.42), rus)
 }
+
+func TestGetIndexMetrics(t *testing.T) {
+	assert := assert.New(t)
+	resp := &Response{Header: http.Header{}}
+	_, err := resp.GetIndexMetrics()
+	assert.NotNil(err)
+
+	resp.Header.Set(HeaderIndexUtilization, base64.StdEncoding.EncodeToString([]byte(`{
+		"UtilizedSingleIndexes": [{"FilterExpression": "", "IndexSpec": "/name/?", "FilterPreciseSet": true, "IndexPreciseSet": true, "IndexImpactScore": "High"}],
+		"PotentialSingleIndexes": [],
+		"UtilizedCompositeIndexes": [],
+		"PotentialCompositeIndexes": [{"IndexSpecs": ["/name ASC", "/age ASC"], "IndexPreciseSet": false, "IndexImpactScore": "High"}]
+	}`)))
+	metrics, err := resp.GetIndexMetrics()
+	assert.Nil(err)
+	assert.Equal([]SingleIndexMetric{{IndexSpec: "/name/?", FilterPreciseSet: true, IndexPreciseSet: true, IndexImpactScore: "High"}}, metrics.UtilizedSingleIndexes)
+	assert.Equal([]CompositeIndexMetric{{IndexSpecs: []string{"/name ASC", "/age ASC"}, IndexImpactScore: "High"}}, metrics.PotentialCompositeIndexes)
+
+	resp.Header.Set(HeaderIndexUtilization, "not base64")
+	_, err = resp.GetIndexMetrics()
+	assert.NotNil(err)
+}
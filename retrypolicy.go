@@ -0,0 +1,104 @@
+package gocosmosdb
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// RetryPolicy - decides whether and after how long a failed attempt of a request is retried, it is asked about
+// connection errors and error statuses only. resp is nil when the attempt failed before a response arrived and
+// attempt starts at 1. Set on the Config it replaces RetryThrottled, RetryWaitMin and RetryWaitMax, RetryMax
+// still caps the retries.
+type RetryPolicy interface {
+	ShouldRetry(resp *http.Response, err error, attempt int) (time.Duration, bool)
+}
+
+// RetryPolicyFunc - a function implementing RetryPolicy
+type RetryPolicyFunc func(resp *http.Response, err error, attempt int) (time.Duration, bool)
+
+// ShouldRetry - calls the function
+func (f RetryPolicyFunc) ShouldRetry(resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	return f(resp, err, attempt)
+}
+
+// DefaultRetryPolicy - retries throttled requests after the wait the service asks for, and timeouts, unavailable
+// services and failed connections with exponential backoff between MinWait and MaxWait
+//
+//	policy := &gocosmosdb.DefaultRetryPolicy{MinWait: 50 * time.Millisecond, MaxWait: 2 * time.Second}
+//	client := gocosmosdb.New(url, gocosmosdb.Config{MasterKey: key, RetryMax: 5, RetryPolicy: policy}, log)
+type DefaultRetryPolicy struct {
+	MinWait time.Duration
+	MaxWait time.Duration
+}
+
+// ShouldRetry - implements RetryPolicy
+func (p *DefaultRetryPolicy) ShouldRetry(resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if resp == nil {
+		return p.backoff(attempt), err != nil
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		if wait, ok := retryAfter(resp); ok {
+			return wait, true
+		}
+		return p.backoff(attempt), true
+	case http.StatusRequestTimeout, http.StatusServiceUnavailable:
+		return p.backoff(attempt), true
+	}
+	return 0, false
+}
+
+// backoff - doubles the wait from MinWait with every attempt, up to MaxWait
+func (p *DefaultRetryPolicy) backoff(attempt int) time.Duration {
+	wait := float64(p.MinWait) * math.Pow(2, float64(attempt-1))
+	if p.MaxWait > 0 && wait > float64(p.MaxWait) {
+		return p.MaxWait
+	}
+	return time.Duration(wait)
+}
+
+// checkRetryPolicy - adapts a retry policy to the http client, sleeping the wait of the policy itself as the
+// backoff of the http client does not see the request of failed connections
+func checkRetryPolicy(policy RetryPolicy, retryMax int, onBackoff BackoffFunc) retryablehttp.CheckRetry {
+	return func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		if err == nil && resp.StatusCode < http.StatusBadRequest {
+			return false, nil
+		}
+		state := retryStateFrom(ctx)
+		attempt := state.Retries() + 1
+		if attempt > retryMax {
+			return false, err
+		}
+		wait, retry := policy.ShouldRetry(resp, err, attempt)
+		if !retry {
+			return false, err
+		}
+		if onBackoff != nil {
+			onBackoff(ctx, backoffEvent(attempt, wait, resp))
+		}
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(wait):
+		}
+		if state != nil {
+			state.mu.Lock()
+			state.retries++
+			state.waited += wait
+			state.mu.Unlock()
+		}
+		return true, nil
+	}
+}
+
+// noBackoff - the http client does not wait again after checkRetryPolicy waited
+func noBackoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	return 0
+}
@@ -0,0 +1,88 @@
+package gocosmosdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(http.StatusServiceUnavailable, http.StatusServiceUnavailable, `{"id": "1"}`)
+	defer s.Close()
+
+	var attempts []int
+	var events []BackoffEvent
+	policy := RetryPolicyFunc(func(resp *http.Response, err error, attempt int) (time.Duration, bool) {
+		attempts = append(attempts, attempt)
+		return time.Millisecond, resp != nil && resp.StatusCode == http.StatusServiceUnavailable
+	})
+	client := New(s.URL, Config{
+		MasterKey:   "YXJpZWwNCg==",
+		RetryMax:    3,
+		RetryPolicy: policy,
+		OnBackoff: func(ctx context.Context, e BackoffEvent) {
+			events = append(events, e)
+		},
+	}, log)
+
+	var doc Document
+	_, err := client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+	assert.Nil(err)
+	assert.Equal("1", doc.Id)
+	assert.Equal([]int{1, 2}, attempts)
+	assert.Equal([]BackoffEvent{
+		{Operation: "GET /dbs/db/colls/coll/docs/1", Attempt: 1, Wait: time.Millisecond, Reason: BackoffServerError, StatusCode: http.StatusServiceUnavailable},
+		{Operation: "GET /dbs/db/colls/coll/docs/1", Attempt: 2, Wait: time.Millisecond, Reason: BackoffServerError, StatusCode: http.StatusServiceUnavailable},
+	}, events)
+}
+
+func TestRetryPolicyExhausted(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusTooManyRequests, `{"id": "1"}`)
+	s.SetHeader(HeaderRetryAfterMs, "1")
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", RetryMax: 2, RetryPolicy: &DefaultRetryPolicy{}}, log)
+
+	var doc Document
+	_, err := client.ReadDocument("dbs/db/colls/coll/docs/1", &doc)
+	assert.True(errors.Is(err, ErrTooManyRequests))
+	var reqErr *RequestError
+	assert.True(errors.As(err, &reqErr))
+	assert.Equal(2, reqErr.Retries)
+}
+
+func TestDefaultRetryPolicy(t *testing.T) {
+	assert := assert.New(t)
+	policy := &DefaultRetryPolicy{MinWait: 10 * time.Millisecond, MaxWait: 50 * time.Millisecond}
+	status := func(code int) *http.Response {
+		return &http.Response{StatusCode: code, Header: http.Header{}}
+	}
+
+	throttled := status(http.StatusTooManyRequests)
+	throttled.Header.Set(HeaderRetryAfterMs, "200")
+	wait, retry := policy.ShouldRetry(throttled, nil, 1)
+	assert.True(retry)
+	assert.Equal(200*time.Millisecond, wait)
+
+	wait, retry = policy.ShouldRetry(status(http.StatusTooManyRequests), nil, 2)
+	assert.True(retry)
+	assert.Equal(20*time.Millisecond, wait)
+
+	wait, retry = policy.ShouldRetry(status(http.StatusRequestTimeout), nil, 4)
+	assert.True(retry)
+	assert.Equal(50*time.Millisecond, wait)
+
+	wait, retry = policy.ShouldRetry(nil, errors.New("connection refused"), 1)
+	assert.True(retry)
+	assert.Equal(10*time.Millisecond, wait)
+
+	_, retry = policy.ShouldRetry(status(http.StatusBadRequest), nil, 1)
+	assert.False(retry)
+	_, retry = policy.ShouldRetry(status(http.StatusNotFound), nil, 1)
+	assert.False(retry)
+}
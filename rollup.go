@@ -0,0 +1,169 @@
+package gocosmosdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// RollupEvent - what a changed document adds to the aggregate of its key and time
+type RollupEvent struct {
+	Key    string
+	Time   time.Time
+	Values map[string]float64 // summed per name
+}
+
+// RollupFunc - maps a changed document to the events it contributes, none to skip it
+type RollupFunc func(change json.RawMessage) ([]RollupEvent, error)
+
+// RollupDocument - the pre-aggregated count and sums of a key in a time bucket
+type RollupDocument struct {
+	Document
+	Key    string             `json:"key"`
+	Bucket time.Time          `json:"bucket"`
+	Count  int64              `json:"count"`
+	Sums   map[string]float64 `json:"sums"`
+	// the last change applied per partition key range of the source, replayed changes are skipped
+	Positions map[string]int64 `json:"positions"`
+}
+
+// Rollup - maintains RollupDocuments in a collection partitioned by /key from the changes of a source collection
+type Rollup struct {
+	db      *CosmosDB
+	coll    string
+	bucket  time.Duration
+	extract RollupFunc
+}
+
+// NewRollup - creates a rollup into coll, aggregating per key and bucket, eg. time.Hour, maintained by running
+// Handle as the handler of a change feed processor on the source collection. Each change is counted once, so
+// the source should be append only, eg. events, as an updated document is counted again.
+//
+//	rollup := client.NewRollup("dbs/{db-id}/colls/{rollup-id}/", time.Hour, func(change json.RawMessage) ([]gocosmosdb.RollupEvent, error) {
+//		var sale Sale
+//		err := json.Unmarshal(change, &sale)
+//		return []gocosmosdb.RollupEvent{{Key: sale.Store, Time: sale.At, Values: map[string]float64{"amount": sale.Amount}}}, err
+//	})
+//	err := client.NewChangeFeedProcessor("dbs/{db-id}/colls/{sales-id}/", rollup.Handle, nil).Run(ctx)
+func (c *CosmosDB) NewRollup(coll string, bucket time.Duration, extract RollupFunc) *Rollup {
+	return &Rollup{db: c, coll: coll, bucket: bucket, extract: extract}
+}
+
+// RollupID - returns the id of the RollupDocument of a key and bucket
+func RollupID(key string, bucket time.Time) string {
+	return fmt.Sprintf("%s:%d", url.PathEscape(key), bucket.Unix())
+}
+
+// Handle - aggregates a page of source changes and merges it into the rollup documents, it is a ChangeFeedHandler
+func (r *Rollup) Handle(ctx context.Context, pkRange string, docs []json.RawMessage) error {
+	deltas := map[string]*RollupDocument{}
+	order := []string{}
+	for _, doc := range docs {
+		var change struct {
+			Lsn int64 `json:"_lsn"`
+		}
+		if err := json.Unmarshal(doc, &change); err != nil {
+			return err
+		}
+		events, err := r.extract(doc)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			bucket := event.Time.UTC().Truncate(r.bucket)
+			id := RollupID(event.Key, bucket)
+			delta, ok := deltas[id]
+			if !ok {
+				delta = &RollupDocument{Key: event.Key, Bucket: bucket, Sums: map[string]float64{}, Positions: map[string]int64{}}
+				delta.Id = id
+				deltas[id] = delta
+				order = append(order, id)
+			}
+			delta.Count++
+			for name, v := range event.Values {
+				delta.Sums[name] += v
+			}
+			if change.Lsn > delta.Positions[pkRange] {
+				delta.Positions[pkRange] = change.Lsn
+			}
+		}
+	}
+	for _, id := range order {
+		if err := r.merge(ctx, pkRange, deltas[id]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// merge - adds a delta to its rollup document, retrying when the document changes or is created meanwhile
+func (r *Rollup) merge(ctx context.Context, pkRange string, delta *RollupDocument) error {
+	for {
+		var current RollupDocument
+		_, err := r.db.client.read(r.coll+"docs/"+delta.Id, &current, PartitionKey(delta.Key), WithContext(ctx))
+		if errors.Is(err, ErrNotFound) {
+			_, err = r.db.client.create(r.coll+"docs/", delta, nil, PartitionKey(delta.Key), WithContext(ctx))
+			if errors.Is(err, ErrConflict) {
+				continue
+			}
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		// the delta was merged before a replay of the page
+		if delta.Positions[pkRange] > 0 && delta.Positions[pkRange] <= current.Positions[pkRange] {
+			return nil
+		}
+		current.Count += delta.Count
+		if current.Sums == nil {
+			current.Sums = map[string]float64{}
+		}
+		for name, v := range delta.Sums {
+			current.Sums[name] += v
+		}
+		if current.Positions == nil {
+			current.Positions = map[string]int64{}
+		}
+		if delta.Positions[pkRange] > 0 {
+			current.Positions[pkRange] = delta.Positions[pkRange]
+		}
+		data, err := stringify(current)
+		if err != nil {
+			return err
+		}
+		_, err = r.db.client.method("PUT", current.Self, expectOK, nil, bytes.NewBuffer(data),
+			IfMatch(current.Etag), PartitionKey(delta.Key), WithContext(ctx))
+		if errors.Is(err, ErrPreconditionFailed) {
+			continue
+		}
+		return err
+	}
+}
+
+// QueryRollups - reads the rollup documents of a key between from and to, the buckets starting before to included
+//
+//	docs, err := rollup.QueryRollups("store-1", time.Now().Add(-24*time.Hour), time.Now())
+func (r *Rollup) QueryRollups(key string, from, to time.Time, opts ...CallOption) ([]RollupDocument, error) {
+	docs := []RollupDocument{}
+	// the bounds are bucket starts, stored buckets compare as strings only with those formatted alike
+	end := to.UTC().Truncate(r.bucket)
+	if end.Before(to) {
+		end = end.Add(r.bucket)
+	}
+	query := &QueryWithParameters{
+		Query: "SELECT * FROM root r WHERE r.key = @key AND r.bucket >= @from AND r.bucket < @to",
+		Parameters: []QueryParameter{
+			{Name: "@key", Value: key},
+			{Name: "@from", Value: from.UTC().Truncate(r.bucket)},
+			{Name: "@to", Value: end},
+		},
+	}
+	opts = append(opts[:len(opts):len(opts)], PartitionKey(key))
+	err := r.db.QueryAll(r.coll, query, &docs, &DrainLimits{}, opts...)
+	return docs, err
+}
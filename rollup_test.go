@@ -0,0 +1,96 @@
+package gocosmosdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollup(t *testing.T) {
+	assert := assert.New(t)
+	var stored *RollupDocument
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			if stored == nil {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"code": "NotFound"}`)
+				return
+			}
+			json.NewEncoder(w).Encode(stored)
+		case http.MethodPost:
+			stored = &RollupDocument{}
+			json.NewDecoder(r.Body).Decode(stored)
+			stored.Self = "dbs/db/colls/rollups/docs/" + stored.Id
+			stored.Etag = "1"
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{}`)
+		case http.MethodPut:
+			stored = &RollupDocument{}
+			json.NewDecoder(r.Body).Decode(stored)
+			fmt.Fprint(w, `{}`)
+		}
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	rollup := client.NewRollup("dbs/db/colls/rollups/", time.Hour, func(change json.RawMessage) ([]RollupEvent, error) {
+		var sale struct {
+			Store  string    `json:"store"`
+			At     time.Time `json:"at"`
+			Amount float64   `json:"amount"`
+		}
+		err := json.Unmarshal(change, &sale)
+		return []RollupEvent{{Key: sale.Store, Time: sale.At, Values: map[string]float64{"amount": sale.Amount}}}, err
+	})
+	page := []json.RawMessage{
+		json.RawMessage(`{"store": "s1", "at": "2019-06-01T12:10:00Z", "amount": 2.5, "_lsn": 10}`),
+		json.RawMessage(`{"store": "s1", "at": "2019-06-01T12:40:00Z", "amount": 1.5, "_lsn": 11}`),
+	}
+	assert.Nil(rollup.Handle(context.Background(), "0", page))
+	assert.Equal(RollupID("s1", time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)), stored.Id)
+	assert.Equal(int64(2), stored.Count)
+	assert.Equal(4.0, stored.Sums["amount"])
+
+	// a replayed page is not counted twice
+	assert.Nil(rollup.Handle(context.Background(), "0", page))
+	assert.Equal(int64(2), stored.Count)
+
+	assert.Nil(rollup.Handle(context.Background(), "0", []json.RawMessage{
+		json.RawMessage(`{"store": "s1", "at": "2019-06-01T12:50:00Z", "amount": 1, "_lsn": 12}`),
+	}))
+	assert.Equal(int64(3), stored.Count)
+	assert.Equal(5.0, stored.Sums["amount"])
+	assert.Equal(int64(12), stored.Positions["0"])
+}
+
+func TestRollupID(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("a%2Fb:1559390400", RollupID("a/b", time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)))
+}
+
+func TestQueryRollups(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"Documents": [], "_count": 0}`, `{"Documents": [], "_count": 0}`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+	rollup := client.NewRollup("dbs/db/colls/rollups/", time.Hour, nil)
+
+	// the bucket starting in the same second as to is still read
+	from := time.Date(2019, 6, 1, 8, 30, 0, 0, time.UTC)
+	to := time.Date(2019, 6, 1, 10, 0, 0, 500000000, time.UTC)
+	_, err := rollup.QueryRollups("store-1", from, to)
+	assert.Nil(err)
+	assert.Contains(s.Body, `{"name":"@from","value":"2019-06-01T08:00:00Z"}`)
+	assert.Contains(s.Body, `{"name":"@to","value":"2019-06-01T11:00:00Z"}`)
+
+	_, err = rollup.QueryRollups("store-1", from, to.Truncate(time.Second))
+	assert.Nil(err)
+	assert.Contains(s.Body, `{"name":"@to","value":"2019-06-01T10:00:00Z"}`)
+}
@@ -0,0 +1,87 @@
+package gocosmosdb
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// RoutingPolicy - how reads tagged with an operation tag are served, eg. analytical reads from a secondary region
+// with eventual consistency away from the latency critical reads
+type RoutingPolicy struct {
+	Endpoint         string      // regional endpoint the reads go to, see RegionEndpoint, empty keeps the client endpoint
+	ConsistencyLevel Consistency // consistency of the reads when the call sets none, empty keeps the account default
+}
+
+// routingPolicies - the policies registered per operation tag
+type routingPolicies struct {
+	mu       sync.RWMutex
+	policies map[string]RoutingPolicy
+}
+
+// OperationTag - tags the request, reads with a tag that has a routing policy are served according to it,
+// writes are never rerouted
+func OperationTag(tag string) CallOption {
+	return func(r *Request) error {
+		r.rTag = tag
+		return nil
+	}
+}
+
+// SetRoutingPolicy - registers the policy of an operation tag, replacing any registered before
+//
+//	endpoint, err := client.RegionEndpoint("West US")
+//	client.SetRoutingPolicy("analytics", gocosmosdb.RoutingPolicy{Endpoint: endpoint, ConsistencyLevel: gocosmosdb.Eventual})
+//	_, err = client.QueryDocuments(coll, query, &docs, gocosmosdb.OperationTag("analytics"))
+func (c *CosmosDB) SetRoutingPolicy(tag string, policy RoutingPolicy) {
+	c.client.routes.mu.Lock()
+	defer c.client.routes.mu.Unlock()
+	c.client.routes.policies[tag] = policy
+}
+
+// RegionEndpoint - returns the endpoint of a readable region of the database account eg. "West US"
+func (c *CosmosDB) RegionEndpoint(region string, opts ...CallOption) (string, error) {
+	account, err := c.ReadAccount(opts...)
+	if err != nil {
+		return "", err
+	}
+	for _, location := range account.ReadableLocations {
+		if location.Name == region {
+			return location.DatabaseAccountEndpoint, nil
+		}
+	}
+	return "", fmt.Errorf("%s is not a readable region of the database account", region)
+}
+
+// route - applies the routing policy of the operation tag of a read
+func (c *apiClient) route(r *Request) error {
+	if r.rTag == "" || c.routes == nil {
+		return nil
+	}
+	if r.Method != http.MethodGet && r.Header.Get(HeaderIsQuery) != "true" {
+		return nil
+	}
+	c.routes.mu.RLock()
+	policy, ok := c.routes.policies[r.rTag]
+	c.routes.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	if policy.ConsistencyLevel != "" && r.Header.Get(HeaderConsistencyLevel) == "" {
+		r.Header.Set(HeaderConsistencyLevel, string(policy.ConsistencyLevel))
+	}
+	if policy.Endpoint != "" {
+		if c.config.StrictEmulator {
+			return &EmulatorError{Feature: "routing reads to another region"}
+		}
+		endpoint, err := url.Parse(policy.Endpoint)
+		if err != nil {
+			return fmt.Errorf("routing policy %s: %s", r.rTag, err)
+		}
+		r.URL.Scheme = endpoint.Scheme
+		r.URL.Host = endpoint.Host
+		r.Host = ""
+	}
+	return nil
+}
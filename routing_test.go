@@ -0,0 +1,52 @@
+package gocosmosdb
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutingPolicy(t *testing.T) {
+	assert := assert.New(t)
+	primary := ServerFactory(`{"id": "1"}`, `{"id": "2"}`)
+	primary.SetStatus(http.StatusCreated)
+	defer primary.Close()
+	region := ServerFactory(`{"id": "1"}`, `{"Documents": [], "_count": 0}`)
+	defer region.Close()
+	client := New(primary.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+	client.SetRoutingPolicy("analytics", RoutingPolicy{Endpoint: region.URL, ConsistencyLevel: Eventual})
+
+	var doc Document
+	_, err := client.ReadDocument("dbs/db/colls/coll/docs/1", &doc, OperationTag("analytics"))
+	assert.Nil(err)
+	assert.Equal("eventual", region.Header.Get(HeaderConsistencyLevel))
+
+	var docs []Document
+	_, err = client.QueryDocuments("dbs/db/colls/coll/", "SELECT * FROM root r", &docs, OperationTag("analytics"), ConsistencyLevel(Session))
+	assert.Nil(err)
+	assert.Equal("session", region.Header.Get(HeaderConsistencyLevel))
+
+	// writes and untagged reads stay on the client endpoint
+	_, err = client.CreateDocument("dbs/db/colls/coll/", &Document{Resource: Resource{Id: "1"}}, OperationTag("analytics"))
+	assert.Nil(err)
+	assert.Empty(primary.Header.Get(HeaderConsistencyLevel))
+	primary.SetStatus(http.StatusOK)
+	_, err = client.ReadDocument("dbs/db/colls/coll/docs/2", &doc, OperationTag("reporting"))
+	assert.Nil(err)
+	assert.Equal("2", doc.Id)
+}
+
+func TestRegionEndpoint(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"readableLocations": [{"name": "East US", "databaseAccountEndpoint": "https://acct-eastus.documents.azure.com:443/"}, {"name": "West US", "databaseAccountEndpoint": "https://acct-westus.documents.azure.com:443/"}]}`, `{}`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	endpoint, err := client.RegionEndpoint("West US")
+	assert.Nil(err)
+	assert.Equal("https://acct-westus.documents.azure.com:443/", endpoint)
+
+	_, err = client.RegionEndpoint("North Europe")
+	assert.Contains(err.Error(), "not a readable region")
+}
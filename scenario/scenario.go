@@ -0,0 +1,282 @@
+// Package scenario describes integration tests as given containers and documents, when operations, then checks
+// of their outcome, request charge and latency, and runs them against the fake or an emulator.
+//
+//	scenario.New("place order").
+//		GivenContainer("dbs/shop/", &gocosmosdb.ContainerSpec{Id: "orders", PartitionKeyPath: "/tenant"}).
+//		GivenDocuments("dbs/shop/colls/orders/", map[string]interface{}{"id": "cart", "tenant": "t1"}).
+//		When("create the order", func(ctx context.Context, c *gocosmosdb.CosmosDB) error {
+//			_, err := c.CreateDocumentCtx(ctx, "dbs/shop/colls/orders/", &order)
+//			return err
+//		}).
+//		Then(scenario.Succeeds(), scenario.MaxRequestCharge(10), scenario.MaxLatency(time.Second)).
+//		Run(t, scenario.Fake(s))
+package scenario
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/intwinelabs/gocosmosdb"
+	"github.com/intwinelabs/gocosmosdb/fake"
+	"github.com/intwinelabs/logger"
+)
+
+// TestingT - the part of *testing.T a scenario reports to
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	FailNow()
+}
+
+// Target - where a scenario runs, it sets up the given containers and hands out the client the steps use
+type Target interface {
+	Client() *gocosmosdb.CosmosDB
+	Container(db string, spec *gocosmosdb.ContainerSpec) error
+}
+
+// fakeTarget - runs scenarios against a fake server, which needs no containers created
+type fakeTarget struct {
+	s      *fake.Server
+	client *gocosmosdb.CosmosDB
+}
+
+// Fake - runs scenarios against a fake server, partitioning its collections like the given containers
+func Fake(s *fake.Server) Target {
+	return &fakeTarget{s: s, client: gocosmosdb.New(s.URL, gocosmosdb.Config{MasterKey: fake.MasterKey}, logger.New())}
+}
+
+func (f *fakeTarget) Client() *gocosmosdb.CosmosDB {
+	return f.client
+}
+
+func (f *fakeTarget) Container(db string, spec *gocosmosdb.ContainerSpec) error {
+	f.s.SetPartitionKey(strings.Trim(db, "/")+"/colls/"+spec.Id, spec.PartitionKeyPath)
+	return nil
+}
+
+// emulatorTarget - runs scenarios against the emulator, or any account the client connects to
+type emulatorTarget struct {
+	client *gocosmosdb.CosmosDB
+}
+
+// Emulator - runs scenarios with a client of the emulator, applying the given containers with ApplyContainerSpec.
+// The documents of earlier runs stay in the containers, given documents are upserted over them.
+//
+//	client := gocosmosdb.New(gocosmosdb.EmulatorURI, gocosmosdb.Config{MasterKey: gocosmosdb.EmulatorMasterKey}, log)
+//	s.Run(t, scenario.Emulator(client))
+func Emulator(client *gocosmosdb.CosmosDB) Target {
+	return &emulatorTarget{client: client}
+}
+
+func (e *emulatorTarget) Client() *gocosmosdb.CosmosDB {
+	return e.client
+}
+
+func (e *emulatorTarget) Container(db string, spec *gocosmosdb.ContainerSpec) error {
+	_, err := e.client.ApplyContainerSpec(db, spec)
+	return err
+}
+
+// StepFunc - the operations of a step, made with the client and the context so their charge is measured
+type StepFunc func(ctx context.Context, c *gocosmosdb.CosmosDB) error
+
+// Result - the outcome of a step, as seen by its checks
+type Result struct {
+	Step          string
+	Err           error
+	RequestCharge float64 // RUs of the calls made with the context of the step
+	Calls         int
+	Latency       time.Duration
+	Client        *gocosmosdb.CosmosDB
+}
+
+// Check - returns why the result of a step is not as expected, nil when it is
+type Check func(r *Result) error
+
+// Scenario - the containers and documents a test starts from and the steps it runs
+type Scenario struct {
+	name       string
+	containers []container
+	documents  []documents
+	steps      []step
+}
+
+type container struct {
+	db   string
+	spec *gocosmosdb.ContainerSpec
+}
+
+type documents struct {
+	coll string
+	docs []interface{}
+}
+
+type step struct {
+	name   string
+	fn     StepFunc
+	checks []Check
+}
+
+// New - starts a scenario, the name prefixes what it reports
+func New(name string) *Scenario {
+	return &Scenario{name: name}
+}
+
+// GivenContainer - sets up a container in a database before the steps run
+func (s *Scenario) GivenContainer(db string, spec *gocosmosdb.ContainerSpec) *Scenario {
+	s.containers = append(s.containers, container{db: db, spec: spec})
+	return s
+}
+
+// GivenDocuments - upserts documents into a given container before the steps run
+func (s *Scenario) GivenDocuments(coll string, docs ...interface{}) *Scenario {
+	s.documents = append(s.documents, documents{coll: coll, docs: docs})
+	return s
+}
+
+// When - adds a step, run in the order added
+func (s *Scenario) When(name string, fn StepFunc) *Scenario {
+	s.steps = append(s.steps, step{name: name, fn: fn})
+	return s
+}
+
+// Then - adds checks of the last step added
+func (s *Scenario) Then(checks ...Check) *Scenario {
+	if len(s.steps) > 0 {
+		last := &s.steps[len(s.steps)-1]
+		last.checks = append(last.checks, checks...)
+	}
+	return s
+}
+
+// Run - sets up the given containers and documents on the target, stopping the test when that fails, then runs
+// the steps reporting every failed check
+func (s *Scenario) Run(t TestingT, target Target) {
+	t.Helper()
+	if err := s.setup(target); err != nil {
+		t.Errorf("%s: given: %v", s.name, err)
+		t.FailNow()
+	}
+	client := target.Client()
+	for _, st := range s.steps {
+		ctx := gocosmosdb.WithRequestCharge(context.Background())
+		start := time.Now()
+		err := st.fn(ctx, client)
+		r := &Result{Step: st.name, Err: err, Latency: time.Since(start), Client: client}
+		if charge, ok := gocosmosdb.RequestChargeFromContext(ctx); ok {
+			r.RequestCharge, r.Calls = charge.Total(), charge.Calls()
+		}
+		for _, check := range st.checks {
+			if err := check(r); err != nil {
+				t.Errorf("%s: %s: %v", s.name, st.name, err)
+			}
+		}
+	}
+}
+
+// setup - creates the given containers and upserts the given documents, a batch per partition key
+func (s *Scenario) setup(target Target) error {
+	paths := map[string]string{}
+	for _, c := range s.containers {
+		if err := target.Container(c.db, c.spec); err != nil {
+			return err
+		}
+		paths[strings.Trim(c.db, "/")+"/colls/"+c.spec.Id] = c.spec.PartitionKeyPath
+	}
+	for _, d := range s.documents {
+		path, ok := paths[strings.Trim(d.coll, "/")]
+		if !ok {
+			return fmt.Errorf("documents given for %s, which is not a given container", d.coll)
+		}
+		// a batch per partition key of up to MaxBatchOperations documents, in the order the keys are first seen
+		var batches []*gocosmosdb.TransactionalBatch
+		current := map[string]*gocosmosdb.TransactionalBatch{}
+		for _, doc := range d.docs {
+			pk, err := partitionKey(doc, path)
+			if err != nil {
+				return err
+			}
+			key := fmt.Sprint(pk)
+			b, ok := current[key]
+			if !ok || len(b.Operations()) == gocosmosdb.MaxBatchOperations {
+				b = gocosmosdb.NewTransactionalBatch(d.coll, pk)
+				current[key] = b
+				batches = append(batches, b)
+			}
+			b.Upsert(doc)
+		}
+		for _, b := range batches {
+			if _, err := target.Client().ExecuteBatch(b); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// partitionKey - returns the value of a document at a partition key path eg. "/address/city"
+func partitionKey(doc interface{}, path string) (interface{}, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err = json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	for _, field := range strings.Split(strings.Trim(path, "/"), "/") {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("document has no partition key at %s", path)
+		}
+		value = obj[field]
+	}
+	if value == nil {
+		return nil, fmt.Errorf("document has no partition key at %s", path)
+	}
+	return value, nil
+}
+
+// Succeeds - checks the step returned no error
+func Succeeds() Check {
+	return func(r *Result) error {
+		if r.Err != nil {
+			return fmt.Errorf("failed: %v", r.Err)
+		}
+		return nil
+	}
+}
+
+// FailsWith - checks the step returned an error matching target with errors.Is, eg. gocosmosdb.ErrConflict
+func FailsWith(target error) Check {
+	return func(r *Result) error {
+		if !errors.Is(r.Err, target) {
+			return fmt.Errorf("expected to fail with %v, got %v", target, r.Err)
+		}
+		return nil
+	}
+}
+
+// MaxRequestCharge - checks the calls of the step were charged at most rus RUs
+func MaxRequestCharge(rus float64) Check {
+	return func(r *Result) error {
+		if r.RequestCharge > rus {
+			return fmt.Errorf("charged %.2f RUs over %d calls, expected at most %.2f", r.RequestCharge, r.Calls, rus)
+		}
+		return nil
+	}
+}
+
+// MaxLatency - checks the step took at most d
+func MaxLatency(d time.Duration) Check {
+	return func(r *Result) error {
+		if r.Latency > d {
+			return fmt.Errorf("took %v, expected at most %v", r.Latency, d)
+		}
+		return nil
+	}
+}
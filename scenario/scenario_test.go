@@ -0,0 +1,82 @@
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/intwinelabs/gocosmosdb"
+	"github.com/intwinelabs/gocosmosdb/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder - records what a scenario reports instead of failing the test
+type recorder struct {
+	errors []string
+	failed bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) FailNow() {
+	r.failed = true
+}
+
+func TestScenario(t *testing.T) {
+	assert := assert.New(t)
+	s := fake.NewServer()
+	defer s.Close()
+	coll := "dbs/shop/colls/orders/"
+
+	var rec recorder
+	New("checkout").
+		GivenContainer("dbs/shop/", &gocosmosdb.ContainerSpec{Id: "orders", PartitionKeyPath: "/tenant"}).
+		GivenDocuments(coll,
+			map[string]interface{}{"id": "cart", "tenant": "t1"},
+			map[string]interface{}{"id": "other", "tenant": "t2"},
+		).
+		When("create the order", func(ctx context.Context, c *gocosmosdb.CosmosDB) error {
+			_, err := c.ExecuteBatchCtx(ctx, gocosmosdb.NewTransactionalBatch(coll, "t1").
+				Create(map[string]interface{}{"id": "order", "tenant": "t1"}).
+				Delete("cart"))
+			return err
+		}).
+		Then(Succeeds(), MaxRequestCharge(10), MaxLatency(time.Second)).
+		When("create it again", func(ctx context.Context, c *gocosmosdb.CosmosDB) error {
+			_, err := c.ExecuteBatchCtx(ctx, gocosmosdb.NewTransactionalBatch(coll, "t1").
+				Create(map[string]interface{}{"id": "order", "tenant": "t1"}))
+			return err
+		}).
+		Then(FailsWith(gocosmosdb.ErrConflict), MaxRequestCharge(0.5)).
+		Run(&rec, Fake(s))
+
+	assert.False(rec.failed)
+	assert.Equal([]string{"checkout: create it again: charged 1.00 RUs over 1 calls, expected at most 0.50"}, rec.errors)
+	assert.Len(s.Documents(coll), 2)
+}
+
+func TestScenarioGiven(t *testing.T) {
+	assert := assert.New(t)
+	s := fake.NewServer()
+	defer s.Close()
+
+	var rec recorder
+	New("unknown").
+		GivenDocuments("dbs/shop/colls/orders/", map[string]interface{}{"id": "cart"}).
+		Run(&rec, Fake(s))
+	assert.True(rec.failed)
+	assert.Contains(rec.errors[0], "not a given container")
+
+	rec = recorder{}
+	New("no key").
+		GivenContainer("dbs/shop/", &gocosmosdb.ContainerSpec{Id: "orders", PartitionKeyPath: "/tenant"}).
+		GivenDocuments("dbs/shop/colls/orders/", map[string]interface{}{"id": "cart"}).
+		Run(&rec, Fake(s))
+	assert.True(rec.failed)
+	assert.Contains(rec.errors[0], "no partition key at /tenant")
+}
@@ -0,0 +1,202 @@
+package gocosmosdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// SchemaVersionField - the field holding the schema version of a document, documents without it are version 0
+const SchemaVersionField = "schemaVersion"
+
+// MaxWriteBacks - the write backs of a schema in flight at once, documents upgraded while they are all busy are
+// not written back and are upgraded again when next read
+const MaxWriteBacks = 8
+
+// Upgrade - migrates a document from a schema version to the next one, numbers are json.Number so they are
+// written back as read
+type Upgrade func(doc map[string]interface{}) error
+
+// Schema - the schema version of the documents of a collection and the upgrades bringing older documents to it,
+// applied to the documents read with WithSchema so they migrate as they are read instead of all at once
+//
+//	schema := gocosmosdb.NewSchema(2).
+//		Register(0, func(doc map[string]interface{}) error {
+//			doc["tags"] = []interface{}{}
+//			return nil
+//		}).
+//		Register(1, func(doc map[string]interface{}) error {
+//			doc["fullName"] = fmt.Sprint(doc["first"], " ", doc["last"])
+//			return nil
+//		})
+//	_, err := client.ReadDocument(link, &user, gocosmosdb.WithSchema(schema))
+type Schema struct {
+	version   int
+	upgrades  map[int]Upgrade
+	writeBack bool
+	writers   chan struct{}
+	onError   func(err error)
+}
+
+// NewSchema - creates the schema of documents at version
+func NewSchema(version int) *Schema {
+	return &Schema{version: version, upgrades: map[int]Upgrade{}}
+}
+
+// Register - sets the upgrade of the documents at schema version from to the version after it
+func (s *Schema) Register(from int, upgrade Upgrade) *Schema {
+	s.upgrades[from] = upgrade
+	return s
+}
+
+// WithWriteBack - replaces the documents upgraded on read in the background with a background priority, on the
+// condition that they did not change since, so each is only upgraded once. The documents are written to the
+// partition key of the read, or that found at the PartitionKeyPath of the collection for queries across
+// partitions, up to MaxWriteBacks at once. onError, when not nil, is called with the errors of the writes.
+func (s *Schema) WithWriteBack(onError func(err error)) *Schema {
+	s.writeBack = true
+	s.writers = make(chan struct{}, MaxWriteBacks)
+	s.onError = onError
+	return s
+}
+
+// Version - returns the schema version documents are upgraded to
+func (s *Schema) Version() int {
+	return s.version
+}
+
+// Upgrade - applies the upgrades from the schema version of the document to the current one, reporting whether
+// it changed. Documents of later versions, written by newer code, are left as they are.
+func (s *Schema) Upgrade(doc json.RawMessage) (json.RawMessage, bool, error) {
+	fields := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(doc))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, false, err
+	}
+	version := 0
+	if v, ok := fields[SchemaVersionField]; ok {
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, false, fmt.Errorf("%s %v is not a number", SchemaVersionField, v)
+		}
+		i, err := n.Int64()
+		if err != nil {
+			return nil, false, fmt.Errorf("%s %v is not an integer", SchemaVersionField, v)
+		}
+		version = int(i)
+	}
+	if version >= s.version {
+		return doc, false, nil
+	}
+	for ; version < s.version; version++ {
+		upgrade, ok := s.upgrades[version]
+		if !ok {
+			return nil, false, fmt.Errorf("no upgrade registered from schema version %d", version)
+		}
+		if err := upgrade(fields); err != nil {
+			return nil, false, err
+		}
+	}
+	fields[SchemaVersionField] = s.version
+	upgraded, err := json.Marshal(fields)
+	return upgraded, err == nil, err
+}
+
+// WithSchema - upgrades the documents read, a document or the documents of a query or read feed page, to the
+// current version of the schema before they are decoded
+func WithSchema(schema *Schema) CallOption {
+	return func(r *Request) error {
+		r.rSchema = schema
+		return nil
+	}
+}
+
+// upgrade - returns the body of a response with its documents upgraded to the schema of the request
+func (c *apiClient) upgrade(r *Request, reader io.Reader) (io.Reader, error) {
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	page := map[string]json.RawMessage{}
+	if err = json.Unmarshal(body, &page); err != nil {
+		return nil, err
+	}
+	docs, ok := page["Documents"]
+	if !ok {
+		doc, err := c.upgradeDocument(r, body)
+		return bytes.NewReader(doc), err
+	}
+	var items []json.RawMessage
+	if err = json.Unmarshal(docs, &items); err != nil {
+		return nil, err
+	}
+	for i := range items {
+		if items[i], err = c.upgradeDocument(r, items[i]); err != nil {
+			return nil, err
+		}
+	}
+	if page["Documents"], err = json.Marshal(items); err != nil {
+		return nil, err
+	}
+	body, err = json.Marshal(page)
+	return bytes.NewReader(body), err
+}
+
+// upgradeDocument - upgrades a document read, writing it back when the schema says so
+func (c *apiClient) upgradeDocument(r *Request, doc json.RawMessage) (json.RawMessage, error) {
+	upgraded, changed, err := r.rSchema.Upgrade(doc)
+	if err != nil || !changed || !r.rSchema.writeBack {
+		return upgraded, err
+	}
+	select {
+	case r.rSchema.writers <- struct{}{}:
+	default:
+		return upgraded, nil
+	}
+	pk := r.Header[HeaderPartitionKey]
+	go func() {
+		defer func() { <-r.rSchema.writers }()
+		if err := c.writeBack(upgraded, pk, r.URL.Path); err != nil && !errors.Is(err, ErrPreconditionFailed) && r.rSchema.onError != nil {
+			r.rSchema.onError(err)
+		}
+	}()
+	return upgraded, nil
+}
+
+// writeBack - replaces a document with its upgrade unless it changed since it was read
+func (c *apiClient) writeBack(doc json.RawMessage, pk []string, link string) error {
+	var meta struct {
+		Self string `json:"_self"`
+		Etag string `json:"_etag"`
+	}
+	if err := json.Unmarshal(doc, &meta); err != nil {
+		return err
+	}
+	if meta.Self == "" || meta.Etag == "" {
+		return errors.New("upgraded document has no _self or _etag to write it back")
+	}
+	partitionKey := func(r *Request) error {
+		r.Header[HeaderPartitionKey] = pk
+		return nil
+	}
+	if pk == nil {
+		path := c.partitionKeyPath(link)
+		if path == "" {
+			return errors.New("upgraded document read across partitions needs the PartitionKeyPath of its collection to write it back")
+		}
+		value, err := valueAtPath(doc, path)
+		if err != nil {
+			return err
+		}
+		partitionKey = PartitionKey(value)
+	}
+	_, err := c.method(http.MethodPut, meta.Self, expectOK, nil, bytes.NewBuffer(doc),
+		IfMatch(meta.Etag), partitionKey, WithPriority(PriorityBackground), WithContext(context.Background()))
+	return err
+}
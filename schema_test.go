@@ -0,0 +1,142 @@
+package gocosmosdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testSchema() *Schema {
+	return NewSchema(2).
+		Register(0, func(doc map[string]interface{}) error {
+			doc["tags"] = []interface{}{}
+			return nil
+		}).
+		Register(1, func(doc map[string]interface{}) error {
+			doc["fullName"] = fmt.Sprint(doc["first"], " ", doc["last"])
+			return nil
+		})
+}
+
+func TestSchemaUpgrade(t *testing.T) {
+	assert := assert.New(t)
+	schema := testSchema()
+	assert.Equal(2, schema.Version())
+
+	doc, changed, err := schema.Upgrade(json.RawMessage(`{"id": "u1", "first": "Ada", "last": "Lovelace", "born": 18151210123456789}`))
+	assert.Nil(err)
+	assert.True(changed)
+	assert.JSONEq(`{"id": "u1", "first": "Ada", "last": "Lovelace", "born": 18151210123456789, "tags": [], "fullName": "Ada Lovelace", "schemaVersion": 2}`, string(doc))
+
+	doc, changed, err = schema.Upgrade(json.RawMessage(`{"id": "u1", "schemaVersion": 1, "first": "Ada", "last": "L"}`))
+	assert.Nil(err)
+	assert.True(changed)
+	assert.JSONEq(`{"id": "u1", "first": "Ada", "last": "L", "fullName": "Ada L", "schemaVersion": 2}`, string(doc))
+
+	// documents of the current or a later version are left as they are
+	current := json.RawMessage(`{"id": "u1", "schemaVersion": 3}`)
+	doc, changed, err = schema.Upgrade(current)
+	assert.Nil(err)
+	assert.False(changed)
+	assert.Equal(current, doc)
+
+	_, _, err = NewSchema(1).Upgrade(json.RawMessage(`{"id": "u1"}`))
+	assert.EqualError(err, "no upgrade registered from schema version 0")
+	_, _, err = schema.Upgrade(json.RawMessage(`{"id": "u1", "schemaVersion": "1"}`))
+	assert.EqualError(err, "schemaVersion 1 is not a number")
+	failed := errors.New("failed")
+	_, _, err = NewSchema(1).Register(0, func(map[string]interface{}) error { return failed }).Upgrade(json.RawMessage(`{}`))
+	assert.Equal(failed, err)
+}
+
+type schemaUser struct {
+	Document
+	FullName      string   `json:"fullName"`
+	Tags          []string `json:"tags"`
+	SchemaVersion int      `json:"schemaVersion"`
+}
+
+func TestWithSchema(t *testing.T) {
+	assert := assert.New(t)
+	var (
+		mu      sync.Mutex
+		written = map[string]string{}
+	)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `{"id": "u1", "_self": "dbs/db/colls/users/docs/u1/", "_etag": "1", "tenant": "t1", "first": "Ada", "last": "L"}`)
+		case http.MethodPost:
+			fmt.Fprint(w, `{"Documents": [
+				{"id": "u1", "_self": "dbs/db/colls/users/docs/u1/", "_etag": "1", "tenant": "t1", "first": "Ada", "last": "L"},
+				{"id": "u2", "_self": "dbs/db/colls/users/docs/u2/", "_etag": "2", "tenant": "t2", "fullName": "Bo B", "schemaVersion": 2}
+			], "_count": 2}`)
+		case http.MethodPut:
+			body, _ := ioutil.ReadAll(r.Body)
+			mu.Lock()
+			written[r.URL.Path] = r.Header.Get(HeaderPartitionKey) + " " + r.Header.Get(HeaderIfMatch) + " " + string(body)
+			mu.Unlock()
+			w.Write(body)
+		}
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", PartitionKeyPath: "/tenant"}, log)
+	writes := func() map[string]string {
+		mu.Lock()
+		defer mu.Unlock()
+		copied := map[string]string{}
+		for k, v := range written {
+			copied[k] = v
+		}
+		return copied
+	}
+
+	// without write back the documents are only upgraded as read
+	user := schemaUser{}
+	_, err := client.ReadDocument("dbs/db/colls/users/docs/u1", &user, WithSchema(testSchema()), PartitionKey("t1"))
+	assert.Nil(err)
+	assert.Equal(schemaUser{Document: user.Document, FullName: "Ada L", Tags: []string{}, SchemaVersion: 2}, user)
+
+	schema := testSchema().WithWriteBack(func(err error) { t.Error(err) })
+	user = schemaUser{}
+	_, err = client.ReadDocument("dbs/db/colls/users/docs/u1", &user, WithSchema(schema), PartitionKey("t1"))
+	assert.Nil(err)
+	assert.Equal("Ada L", user.FullName)
+	assert.True(eventually(func() bool { return len(writes()) == 1 }))
+	assert.Contains(writes()["/dbs/db/colls/users/docs/u1/"], `["t1"] 1 {`)
+	assert.Contains(writes()["/dbs/db/colls/users/docs/u1/"], `"schemaVersion":2`)
+
+	// documents read across partitions are written to the key at the partition key path
+	mu.Lock()
+	written = map[string]string{}
+	mu.Unlock()
+	var users []schemaUser
+	_, err = client.QueryDocuments("dbs/db/colls/users/", "SELECT * FROM c", &users, WithSchema(schema))
+	assert.Nil(err)
+	assert.Equal("Ada L", users[0].FullName)
+	assert.Equal("Bo B", users[1].FullName)
+	assert.True(eventually(func() bool { return len(writes()) == 1 }))
+	assert.Contains(writes()["/dbs/db/colls/users/docs/u1/"], `["t1"] 1 {`)
+
+	// documents upgraded while the write backs are all busy are not written back
+	mu.Lock()
+	written = map[string]string{}
+	mu.Unlock()
+	assert.True(eventually(func() bool { return len(schema.writers) == 0 }))
+	for i := 0; i < MaxWriteBacks; i++ {
+		schema.writers <- struct{}{}
+	}
+	_, err = client.ReadDocument("dbs/db/colls/users/docs/u1", &user, WithSchema(schema), PartitionKey("t1"))
+	assert.Nil(err)
+	assert.Equal("Ada L", user.FullName)
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(writes())
+}
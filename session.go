@@ -0,0 +1,108 @@
+package gocosmosdb
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// sessionTokens - the latest session token seen per partition key range of each collection, so reads after a
+// write of the same client see it with Session consistency
+type sessionTokens struct {
+	mu     sync.Mutex
+	tokens map[string]map[string]string // collection link -> partition key range id -> token
+}
+
+// lsn - the global logical sequence number of a session token, the part after its version eg. 12 of "-1#12", of
+// the vector token of a multi-region account "1#12#1=20#2=7" or of "12"
+func lsn(token string) int64 {
+	if parts := strings.Split(token, "#"); len(parts) > 1 {
+		token = parts[1]
+	}
+	n, _ := strconv.ParseInt(token, 10, 64)
+	return n
+}
+
+// capture - merges the session token header of a response eg. "0:-1#12,1:-1#30", keeping the newest token per
+// partition key range
+func (s *sessionTokens) capture(coll, header string) {
+	if header == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ranges, ok := s.tokens[coll]
+	if !ok {
+		ranges = map[string]string{}
+		s.tokens[coll] = ranges
+	}
+	for _, part := range strings.Split(header, ",") {
+		i := strings.Index(part, ":")
+		if i < 0 {
+			continue
+		}
+		pkRange, token := strings.TrimSpace(part[:i]), part[i+1:]
+		if current, ok := ranges[pkRange]; !ok || lsn(token) > lsn(current) {
+			ranges[pkRange] = token
+		}
+	}
+}
+
+// header - returns the session tokens of a collection to send, of one partition key range when pkRange is set
+func (s *sessionTokens) header(coll, pkRange string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ranges := s.tokens[coll]
+	if pkRange != "" {
+		if token, ok := ranges[pkRange]; ok {
+			return pkRange + ":" + token
+		}
+		return ""
+	}
+	parts := make([]string, 0, len(ranges))
+	for id, token := range ranges {
+		parts = append(parts, id+":"+token)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// sessionCollection - returns the collection link of a document request, empty for other resources
+func sessionCollection(r *Request) string {
+	if r.rType != "docs" {
+		return ""
+	}
+	return collectionOf(r.URL.Path)
+}
+
+// replaySession - sends the session tokens captured for the collection with reads and queries setting none
+func (c *apiClient) replaySession(r *Request) {
+	coll := sessionCollection(r)
+	if coll == "" || r.Header.Get(HeaderSessionToken) != "" {
+		return
+	}
+	if r.Method != http.MethodGet && r.Header.Get(HeaderIsQuery) == "" {
+		return
+	}
+	if token := c.sessions.header(coll, r.Header.Get(HeaderPartitionKeyRangeID)); token != "" {
+		r.Header.Set(HeaderSessionToken, token)
+	}
+}
+
+// captureSession - keeps the session token of a document response
+func (c *apiClient) captureSession(r *Request, resp *http.Response) {
+	if coll := sessionCollection(r); coll != "" && resp.StatusCode < http.StatusBadRequest {
+		c.sessions.capture(coll, resp.Header.Get(HeaderSessionToken))
+	}
+}
+
+// SessionToken - returns the session tokens captured for a collection with Config.SessionTokens, to hand to
+// another client eg. of a later request of the same user with the SessionToken option
+func (c *CosmosDB) SessionToken(coll string) string {
+	if c.client.sessions == nil {
+		return ""
+	}
+	return c.client.sessions.header(collectionKey(coll), "")
+}
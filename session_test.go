@@ -0,0 +1,65 @@
+package gocosmosdb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionTokens(t *testing.T) {
+	assert := assert.New(t)
+	writes := []string{"0:-1#5", "0:-1#3,1:-1#9"}
+	var replayed []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			replayed = append(replayed, r.Header.Get(HeaderSessionToken))
+			w.Write([]byte(`{"id": "1"}`))
+			return
+		}
+		w.Header().Set(HeaderSessionToken, writes[0])
+		writes = writes[1:]
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": "1"}`))
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", SessionTokens: true}, log)
+	coll := "dbs/db/colls/coll/"
+
+	var doc Document
+	_, err := client.ReadDocument(coll+"docs/1", &doc)
+	assert.Nil(err)
+	_, err = client.CreateDocument(coll, &Document{Resource: Resource{Id: "1"}})
+	assert.Nil(err)
+	_, err = client.ReadDocument(coll+"docs/1", &doc)
+	assert.Nil(err)
+	_, err = client.CreateDocument(coll, &Document{Resource: Resource{Id: "2"}})
+	assert.Nil(err)
+	_, err = client.ReadDocument(coll+"docs/1", &doc)
+	assert.Nil(err)
+	_, err = client.ReadDocument(coll+"docs/1", &doc, PartitionKeyRangeID(1))
+	assert.Nil(err)
+	_, err = client.ReadDocument(coll+"docs/1", &doc, SessionToken("0:-1#1"))
+	assert.Nil(err)
+	_, err = client.ReadDocument("dbs/db/colls/other/docs/1", &doc)
+	assert.Nil(err)
+
+	// the older token of range 0 of the second write does not replace the first
+	assert.Equal([]string{"", "0:-1#5", "0:-1#5,1:-1#9", "1:-1#9", "0:-1#1", ""}, replayed)
+	assert.Equal("0:-1#5,1:-1#9", client.SessionToken("/dbs/db/colls/coll"))
+}
+
+func TestSessionTokensVector(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(int64(12), lsn("-1#12"))
+	assert.Equal(int64(12), lsn("12"))
+	assert.Equal(int64(100), lsn("1#100#1=20#2=7"))
+
+	sessions := &sessionTokens{tokens: map[string]map[string]string{}}
+	sessions.capture("dbs/db/colls/coll", "0:1#100#1=20")
+	sessions.capture("dbs/db/colls/coll", "0:1#90#1=30")
+	assert.Equal("0:1#100#1=20", sessions.header("dbs/db/colls/coll", ""))
+	sessions.capture("dbs/db/colls/coll", "0:1#120#1=25,1:1#7#1=3")
+	assert.Equal("0:1#120#1=25,1:1#7#1=3", sessions.header("dbs/db/colls/coll", ""))
+}
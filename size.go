@@ -0,0 +1,116 @@
+package gocosmosdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+const (
+	// MaxDocumentSize - the largest document the service stores, in bytes
+	MaxDocumentSize = 2 * 1024 * 1024
+
+	// MaxDocumentDepth - the deepest nesting of objects and arrays the service accepts
+	MaxDocumentDepth = 128
+)
+
+// DocumentLimitError - a document exceeds a service limit, caught before sending it
+type DocumentLimitError struct {
+	Limit string // "size" or "depth"
+	Value int
+	Max   int
+}
+
+// Implement Error function
+func (e *DocumentLimitError) Error() string {
+	if e.Limit == "depth" {
+		return fmt.Sprintf("document nesting depth %d exceeds the limit of %d", e.Value, e.Max)
+	}
+	return fmt.Sprintf("document size %d bytes exceeds the limit of %d bytes", e.Value, e.Max)
+}
+
+// EstimateSize - returns the size in bytes the document is sent with
+//
+//	size, err := gocosmosdb.EstimateSize(&doc)
+func EstimateSize(doc interface{}) (int, error) {
+	data, err := stringify(doc)
+	if err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// ValidateDocument - checks the document against the size and nesting depth limits of the service, returning
+// a *DocumentLimitError for a document the service would reject
+//
+//	if err := gocosmosdb.ValidateDocument(&doc); err != nil {
+//		...
+//	}
+func ValidateDocument(doc interface{}) error {
+	data, err := stringify(doc)
+	if err != nil {
+		return err
+	}
+	return validateDocument(data)
+}
+
+// validateDocument - checks the limits of an encoded document
+func validateDocument(data []byte) error {
+	if len(data) > MaxDocumentSize {
+		return &DocumentLimitError{Limit: "size", Value: len(data), Max: MaxDocumentSize}
+	}
+	depth, err := documentDepth(data)
+	if err != nil {
+		return err
+	}
+	if depth > MaxDocumentDepth {
+		return &DocumentLimitError{Limit: "depth", Value: depth, Max: MaxDocumentDepth}
+	}
+	return nil
+}
+
+// documentDepth - returns the deepest nesting of objects and arrays in an encoded document
+func documentDepth(data []byte) (int, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	depth, max := 0, 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return max, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			if depth++; depth > max {
+				max = depth
+			}
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+}
+
+// validate - checks documents written with a client configured to ValidateDocuments or for a DryRun
+func (c *apiClient) validate(r *Request) error {
+	if !c.config.ValidateDocuments && c.config.DryRun == nil || r.rType != "docs" || r.GetBody == nil {
+		return nil
+	}
+	if r.Method != http.MethodPost && r.Method != http.MethodPut {
+		return nil
+	}
+	body, err := r.GetBody()
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	return validateDocument(data)
+}
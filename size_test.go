@@ -0,0 +1,46 @@
+package gocosmosdb
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateSize(t *testing.T) {
+	assert := assert.New(t)
+	size, err := EstimateSize(map[string]string{"id": "1"})
+	assert.Nil(err)
+	assert.Equal(len(`{"id":"1"}`), size)
+}
+
+func TestValidateDocument(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(ValidateDocument(`{"id": "1", "a": [{"b": []}]}`))
+
+	err := ValidateDocument(`{"id": "1", "a": "` + strings.Repeat("x", MaxDocumentSize) + `"}`)
+	assert.IsType(&DocumentLimitError{}, err)
+	assert.Equal("size", err.(*DocumentLimitError).Limit)
+
+	deep := strings.Repeat(`{"a":`, MaxDocumentDepth) + "1" + strings.Repeat("}", MaxDocumentDepth)
+	assert.Nil(ValidateDocument(deep))
+	err = ValidateDocument(`[` + deep + `]`)
+	assert.IsType(&DocumentLimitError{}, err)
+	assert.Equal("document nesting depth 129 exceeds the limit of 128", err.Error())
+}
+
+func TestValidateDocumentsConfig(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{}`, `{"Documents": [], "_count": 0}`)
+	s.SetStatus(http.StatusCreated)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", ValidateDocuments: true}, log)
+
+	deep := strings.Repeat(`[`, MaxDocumentDepth+1) + strings.Repeat("]", MaxDocumentDepth+1)
+	_, err := client.client.create("dbs/db/colls/coll/docs", `{"id": "1", "a": `+deep+`}`, nil)
+	assert.IsType(&DocumentLimitError{}, err)
+
+	_, err = client.client.create("dbs/db/colls/coll/docs", `{"id": "1"}`, nil)
+	assert.Nil(err)
+}
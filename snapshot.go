@@ -0,0 +1,82 @@
+package gocosmosdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// Snapshot - the documents of a collection as of the end of an ExportSnapshot
+type Snapshot struct {
+	Documents    []json.RawMessage
+	Changes      int    // changes read from the change feed after the export and applied to it
+	Continuation string // the change feed position of the snapshot, pass it to ReadChangeFeed to follow the changes since
+}
+
+// ExportSnapshot - exports the documents of a collection without pausing its writes, in pages of pageSize, then
+// applies the changes made during the export from the change feed so the documents are as of one point, the end
+// of the export, rather than a mix of the versions written while it read. The change feed does not record deletes,
+// so documents deleted during the export may still be in the snapshot; collections relying on snapshots should
+// delete by setting a ttl, or a deleted flag, first.
+//
+//	snapshot, err := client.ExportSnapshot(ctx, "dbs/{db-id}/colls/{coll-id}/", 1000)
+//	... back up snapshot.Documents, then follow the changes made since
+//	page, err := client.ReadChangeFeed(ctx, "dbs/{db-id}/colls/{coll-id}/", snapshot.Continuation)
+func (c *CosmosDB) ExportSnapshot(ctx context.Context, coll string, pageSize int, opts ...CallOption) (*Snapshot, error) {
+	coll = normalizeLink(coll)
+	// mark the position of the change feed before reading anything, changes from there on are applied after
+	start, err := c.ReadChangeFeed(ctx, coll, "", append(opts[:len(opts):len(opts)], StartFromNow())...)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &Snapshot{Documents: []json.RawMessage{}}
+	index := map[string]int{}
+	docs := []json.RawMessage{}
+	feed := c.NewDocumentFeed(coll, pageSize, &docs, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+	err = feed.ForEachPage(func() error {
+		return snapshot.apply(index, docs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the feed is read up to where it has no new changes, the changes read also hold those made to documents
+	// after they were exported
+	snapshot.Continuation = start.Continuation
+	for {
+		page, err := c.ReadChangeFeed(ctx, coll, snapshot.Continuation, opts...)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Continuation = page.Continuation
+		if len(page.Documents) == 0 {
+			return snapshot, nil
+		}
+		if err = snapshot.apply(index, page.Documents); err != nil {
+			return nil, err
+		}
+		snapshot.Changes += len(page.Documents)
+	}
+}
+
+// apply - adds documents to the snapshot, replacing the versions it has by their _rid
+func (s *Snapshot) apply(index map[string]int, docs []json.RawMessage) error {
+	for _, doc := range docs {
+		var meta struct {
+			Rid string `json:"_rid"`
+		}
+		if err := json.Unmarshal(doc, &meta); err != nil {
+			return err
+		}
+		if meta.Rid == "" {
+			return errors.New("snapshot document has no _rid")
+		}
+		if i, ok := index[meta.Rid]; ok {
+			s.Documents[i] = doc
+			continue
+		}
+		index[meta.Rid] = len(s.Documents)
+		s.Documents = append(s.Documents, doc)
+	}
+	return nil
+}
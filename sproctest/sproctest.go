@@ -0,0 +1,105 @@
+// Package sproctest runs stored procedures in scratch collections of the emulator, so their JavaScript is unit
+// tested from Go tests against fixture documents.
+//
+//	sandbox := sproctest.New(gocosmosdb.New(gocosmosdb.EmulatorURI, gocosmosdb.Config{MasterKey: gocosmosdb.EmulatorMasterKey}, log), "dbs/sproctest/")
+//	result, err := sandbox.Run(ctx, &sproctest.Run{
+//		Sproc:        bulkClose,
+//		PartitionKey: "t1",
+//		Fixtures:     []interface{}{map[string]interface{}{"id": "o1", "pk": "t1", "status": "open"}},
+//		Params:       []interface{}{"o1"},
+//	})
+//	assert.JSONEq(t, `{"closed": 1}`, string(result.Body))
+//	assert.Contains(t, result.Log, "closing o1")
+package sproctest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/intwinelabs/gocosmosdb"
+)
+
+// DefaultPartitionKeyPath - the partition key of the scratch collections when the sandbox sets none
+const DefaultPartitionKeyPath = "/pk"
+
+// Sandbox - runs stored procedures in scratch collections of a database, which is created when missing
+type Sandbox struct {
+	client           *gocosmosdb.CosmosDB
+	db               string
+	partitionKeyPath string
+}
+
+// New - creates a sandbox with a client of the emulator, or any account the tests may create collections in
+func New(client *gocosmosdb.CosmosDB, db string) *Sandbox {
+	return &Sandbox{client: client, db: strings.TrimSuffix(db, "/") + "/", partitionKeyPath: DefaultPartitionKeyPath}
+}
+
+// WithPartitionKeyPath - sets the partition key of the scratch collections, the fixtures carry it
+func (s *Sandbox) WithPartitionKeyPath(path string) *Sandbox {
+	s.partitionKeyPath = path
+	return s
+}
+
+// Run - a run of a stored procedure
+type Run struct {
+	Sproc        string        // the JavaScript of the procedure eg. "function main(id) { ... }"
+	PartitionKey interface{}   // of the fixtures and the run, procedures only see one logical partition
+	Fixtures     []interface{} // documents created before the run
+	Params       []interface{} // the arguments of the procedure
+}
+
+// Result - what a run of a stored procedure returned
+type Result struct {
+	Body          json.RawMessage // as set with getContext().getResponse().setBody
+	Log           string          // as logged with console.log
+	RequestCharge float64
+	Documents     []json.RawMessage // the documents of the collection after the run
+}
+
+// Run - creates a scratch collection with the fixtures and the procedure, runs it and returns its result, the
+// collection is deleted once done. A procedure that throws fails the run with the error of the service.
+func (s *Sandbox) Run(ctx context.Context, run *Run) (*Result, error) {
+	if _, err := s.client.CreateDatabaseCtx(ctx, map[string]string{"id": dbID(s.db)}); err != nil && !errors.Is(err, gocosmosdb.ErrConflict) {
+		return nil, err
+	}
+	spec := &gocosmosdb.ContainerSpec{Id: "sproctest-" + uuid.New().String(), PartitionKeyPath: s.partitionKeyPath}
+	if _, err := s.client.ApplyContainerSpec(s.db, spec, gocosmosdb.WithContext(ctx)); err != nil {
+		return nil, err
+	}
+	coll := s.db + "colls/" + spec.Id + "/"
+	defer s.client.DeleteCollection(coll, gocosmosdb.WithContext(context.Background()))
+
+	pk := gocosmosdb.PartitionKey(run.PartitionKey)
+	for _, doc := range run.Fixtures {
+		if _, err := s.client.Upsert(coll+"docs/", doc, nil, pk, gocosmosdb.WithContext(ctx)); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := s.client.CreateStoredProcedureCtx(ctx, coll, &gocosmosdb.Sproc{Resource: gocosmosdb.Resource{Id: "sproc"}, Body: run.Sproc}); err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	params := run.Params
+	if params == nil {
+		params = []interface{}{}
+	}
+	resp, err := s.client.ExecuteStoredProcedureCtx(ctx, coll+"sprocs/sproc", params, &result.Body, pk, gocosmosdb.EnableScriptLogging())
+	if err != nil {
+		return nil, err
+	}
+	result.Log = resp.ScriptLog()
+	result.RequestCharge, _ = resp.GetRUs()
+	if _, err = s.client.ReadDocumentsCtx(ctx, coll, &result.Documents, pk); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// dbID - the id of the database of a link eg. "dbs/sproctest/"
+func dbID(db string) string {
+	return strings.Trim(strings.TrimPrefix(strings.TrimPrefix(db, "/"), "dbs/"), "/")
+}
@@ -0,0 +1,91 @@
+package sproctest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/intwinelabs/gocosmosdb"
+	"github.com/intwinelabs/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+// emulator - serves the requests of a run, executing a procedure that echoes its parameters
+type emulator struct {
+	mu       sync.Mutex
+	requests []string
+	docs     []string
+}
+
+func (e *emulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	body, _ := ioutil.ReadAll(r.Body)
+	path := strings.Trim(r.URL.Path, "/")
+	parts := strings.Split(path, "/")
+	// the ids are left out of the recorded requests
+	kinds := []string{}
+	for i := 0; i < len(parts); i += 2 {
+		kinds = append(kinds, parts[i])
+	}
+	e.requests = append(e.requests, r.Method+" "+strings.Join(kinds, "/"))
+	switch {
+	case r.Method == http.MethodPost && path == "dbs":
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"code": "Conflict", "message": "exists"}`)
+	case r.Method == http.MethodGet && len(parts) == 4:
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"code": "NotFound", "message": "missing"}`)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/docs"):
+		e.docs = append(e.docs, string(body))
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/sprocs/sproc"):
+		w.Header().Set(gocosmosdb.HeaderScriptLogResults, "echo%20"+strings.Trim(string(body), "[]\""))
+		w.Header().Set(gocosmosdb.HeaderRequestCharge, "3.5")
+		fmt.Fprintf(w, `{"params": %s}`, body)
+	case r.Method == http.MethodGet && strings.HasSuffix(path, "/docs"):
+		fmt.Fprintf(w, `{"Documents": [%s], "_count": %d}`, strings.Join(e.docs, ","), len(e.docs))
+	case r.Method == http.MethodDelete:
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	}
+}
+
+func TestRun(t *testing.T) {
+	assert := assert.New(t)
+	e := &emulator{}
+	s := httptest.NewServer(e)
+	defer s.Close()
+	client := gocosmosdb.New(s.URL, gocosmosdb.Config{MasterKey: "YXJpZWwNCg=="}, logger.New())
+
+	result, err := New(client, "dbs/sproctest").Run(context.Background(), &Run{
+		Sproc:        "function main(id) { console.log('echo ' + id); getContext().getResponse().setBody({params: [id]}) }",
+		PartitionKey: "t1",
+		Fixtures:     []interface{}{map[string]string{"id": "o1", "pk": "t1"}},
+		Params:       []interface{}{"o1"},
+	})
+	assert.Nil(err)
+	assert.JSONEq(`{"params": ["o1"]}`, string(result.Body))
+	assert.Equal("echo o1", result.Log)
+	assert.Equal(3.5, result.RequestCharge)
+	assert.Equal([]json.RawMessage{json.RawMessage(`{"id":"o1","pk":"t1"}`)}, result.Documents)
+	assert.Equal([]string{
+		"POST dbs",
+		"GET dbs/colls",
+		"POST dbs/colls",
+		"POST dbs/colls/docs",
+		"POST dbs/colls/sprocs",
+		"POST dbs/colls/sprocs",
+		"GET dbs/colls/docs",
+		"DELETE dbs/colls",
+	}, e.requests)
+}
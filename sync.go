@@ -0,0 +1,209 @@
+package gocosmosdb
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// SyncOptions - what SyncCollections does with the differences it finds
+type SyncOptions struct {
+	Apply    bool // upsert the documents missing from or different in the target
+	Delete   bool // with Apply, also delete the documents only in the target
+	PageSize int  // documents read per page, 100 when 0
+}
+
+// SyncDifference - a document that differs between the source and the target of a sync
+type SyncDifference struct {
+	Id           string
+	PartitionKey interface{}
+}
+
+// SyncReport - the differences found by SyncCollections
+type SyncReport struct {
+	Compared int              // documents of the source
+	Missing  []SyncDifference // in the source only
+	Changed  []SyncDifference // in both, with different content
+	Extra    []SyncDifference // in the target only
+	Applied  int              // upserts and deletes made to the target
+}
+
+// InSync - reports whether the target held the same documents as the source
+func (r *SyncReport) InSync() bool {
+	return len(r.Missing) == 0 && len(r.Changed) == 0 && len(r.Extra) == 0
+}
+
+// SyncCollections - compares the documents of two collections by id and partition key, and by the hash of their
+// content without the system properties, which differ between collections, reporting and optionally applying the
+// differences to the target. Useful to validate a disaster recovery copy or to promote the data of an
+// environment. The target is read first, keeping only the hashes of its documents in memory, then the source is
+// read page by page.
+//
+//	report, err := client.SyncCollections(ctx, "dbs/prod/colls/orders/", "dbs/dr/colls/orders/", nil)
+//	if err == nil && !report.InSync() {
+//		log.Infof("dr copy of orders: %d missing, %d changed, %d extra", len(report.Missing), len(report.Changed), len(report.Extra))
+//	}
+func (c *CosmosDB) SyncCollections(ctx context.Context, source, target string, options *SyncOptions, opts ...CallOption) (*SyncReport, error) {
+	if options == nil {
+		options = &SyncOptions{}
+	}
+	pageSize := options.PageSize
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+	source, target = normalizeLink(source), normalizeLink(target)
+	opts = append(opts[:len(opts):len(opts)], WithContext(ctx))
+	coll, err := c.ReadCollection(target, opts...)
+	if err != nil {
+		return nil, err
+	}
+	path := ""
+	if len(coll.PartitionKeyDef.Paths) > 0 {
+		path = coll.PartitionKeyDef.Paths[0]
+	}
+
+	type entry struct {
+		SyncDifference
+		hash string
+	}
+	key := func(doc json.RawMessage) (string, SyncDifference, error) {
+		var meta struct {
+			Id string `json:"id"`
+		}
+		if err := json.Unmarshal(doc, &meta); err != nil {
+			return "", SyncDifference{}, err
+		}
+		diff := SyncDifference{Id: meta.Id}
+		if path != "" {
+			// documents without the partition key are stored under none
+			diff.PartitionKey, _ = valueAtPath(doc, path)
+		}
+		return fmt.Sprintf("%v|%s", diff.PartitionKey, diff.Id), diff, nil
+	}
+	targets := map[string]entry{}
+	docs := []json.RawMessage{}
+	err = c.NewDocumentFeed(target, pageSize, &docs, opts...).ForEachPage(func() error {
+		for _, doc := range docs {
+			k, diff, err := key(doc)
+			if err != nil {
+				return err
+			}
+			hash, err := contentHash(doc)
+			if err != nil {
+				return err
+			}
+			targets[k] = entry{diff, hash}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SyncReport{}
+	err = c.NewDocumentFeed(source, pageSize, &docs, opts...).ForEachPage(func() error {
+		for _, doc := range docs {
+			report.Compared++
+			k, diff, err := key(doc)
+			if err != nil {
+				return err
+			}
+			hash, err := contentHash(doc)
+			if err != nil {
+				return err
+			}
+			existing, ok := targets[k]
+			delete(targets, k)
+			switch {
+			case !ok:
+				report.Missing = append(report.Missing, diff)
+			case existing.hash != hash:
+				report.Changed = append(report.Changed, diff)
+			default:
+				continue
+			}
+			if options.Apply {
+				if err = c.syncDocument(target, doc, diff, opts); err != nil {
+					return err
+				}
+				report.Applied++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, extra := range targets {
+		report.Extra = append(report.Extra, extra.SyncDifference)
+	}
+	sort.Slice(report.Extra, func(i, j int) bool { return report.Extra[i].Id < report.Extra[j].Id })
+	for _, extra := range report.Extra {
+		if options.Apply && options.Delete {
+			if _, err = c.DeleteDocument(target+"docs/"+extra.Id, append(opts, PartitionKey(extra.PartitionKey))...); err != nil {
+				return report, err
+			}
+			report.Applied++
+		}
+	}
+	return report, nil
+}
+
+// syncDocument - upserts a document of the source of a sync to its target, without its system properties
+func (c *CosmosDB) syncDocument(target string, doc json.RawMessage, diff SyncDifference, opts []CallOption) error {
+	fields, err := userFields(doc)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.method(http.MethodPost, target+"docs/", expectUpserted, nil, bytes.NewBuffer(data),
+		append(opts, Upsert(), PartitionKey(diff.PartitionKey))...)
+	return err
+}
+
+// userFields - decodes a document without its system properties, which start with an underscore, keeping its
+// numbers as written
+func userFields(doc json.RawMessage) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(doc))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+	for k := range fields {
+		if len(k) > 0 && k[0] == '_' {
+			delete(fields, k)
+		}
+	}
+	return fields, nil
+}
+
+// contentHash - returns the SHA-256 of the content of a document, its fields but those starting with an underscore
+// like the system properties in the order of their names, so it is the same for copies of the document in any
+// collection. Numbers are hashed as the doubles the service stores them as, so 1.50 written hashes like the 1.5
+// read back.
+func contentHash(doc json.RawMessage) (string, error) {
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(doc, &fields); err != nil {
+		return "", err
+	}
+	for k := range fields {
+		if len(k) > 0 && k[0] == '_' {
+			delete(fields, k)
+		}
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
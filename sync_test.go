@@ -0,0 +1,64 @@
+package gocosmosdb
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncCollections(t *testing.T) {
+	assert := assert.New(t)
+	var writes []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		switch r.Method + " " + r.URL.Path {
+		case "GET /dbs/db/colls/dst/":
+			fmt.Fprint(w, `{"id": "dst", "partitionKey": {"paths": ["/pk"], "kind": "Hash"}}`)
+		case "GET /dbs/db/colls/src/docs/":
+			fmt.Fprint(w, `{"Documents": [
+				{"id": "same", "pk": "a", "n": 1.50, "_rid": "1", "_etag": "1"},
+				{"id": "changed", "pk": "a", "n": 2, "_rid": "2"},
+				{"id": "missing", "pk": "b", "_rid": "3"}
+			], "_count": 3}`)
+		case "GET /dbs/db/colls/dst/docs/":
+			fmt.Fprint(w, `{"Documents": [
+				{"_etag": "9", "n": 1.50, "pk": "a", "id": "same", "_rid": "7"},
+				{"id": "changed", "pk": "a", "n": 3, "_rid": "8"},
+				{"id": "extra", "pk": "c", "_rid": "9"}
+			], "_count": 3}`)
+		case "POST /dbs/db/colls/dst/docs/":
+			writes = append(writes, fmt.Sprintf("upsert %s %s", r.Header.Get(HeaderPartitionKey), body))
+			fmt.Fprint(w, string(body))
+		case "DELETE /dbs/db/colls/dst/docs/extra":
+			writes = append(writes, "delete "+r.Header.Get(HeaderPartitionKey))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	report, err := client.SyncCollections(context.Background(), "dbs/db/colls/src", "dbs/db/colls/dst", nil)
+	assert.Nil(err)
+	assert.False(report.InSync())
+	assert.Equal(3, report.Compared)
+	assert.Equal([]SyncDifference{{Id: "missing", PartitionKey: "b"}}, report.Missing)
+	assert.Equal([]SyncDifference{{Id: "changed", PartitionKey: "a"}}, report.Changed)
+	assert.Equal([]SyncDifference{{Id: "extra", PartitionKey: "c"}}, report.Extra)
+	assert.Empty(writes)
+
+	report, err = client.SyncCollections(context.Background(), "dbs/db/colls/src", "dbs/db/colls/dst", &SyncOptions{Apply: true, Delete: true})
+	assert.Nil(err)
+	assert.Equal(3, report.Applied)
+	assert.Equal([]string{
+		`upsert ["a"] {"id":"changed","n":2,"pk":"a"}`,
+		`upsert ["b"] {"id":"missing","pk":"b"}`,
+		`delete ["c"]`,
+	}, writes)
+}
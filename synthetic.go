@@ -0,0 +1,104 @@
+package gocosmosdb
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SyntheticKey - spreads the documents of a base value, eg. a tenant, over Buckets partition keys of the form
+// base + Separator + bucket, eg. "tenant-1_3", so a large or busy base does not pile onto one partition
+type SyntheticKey struct {
+	Buckets   int
+	Separator string // "_" by default
+}
+
+func (k SyntheticKey) separator() string {
+	if k.Separator == "" {
+		return "_"
+	}
+	return k.Separator
+}
+
+// Compose - returns the partition key of a bucket of base
+func (k SyntheticKey) Compose(base string, bucket int) string {
+	return base + k.separator() + strconv.Itoa(bucket)
+}
+
+// Split - returns the base and bucket of a partition key
+func (k SyntheticKey) Split(key string) (string, int, error) {
+	i := strings.LastIndex(key, k.separator())
+	if i < 0 {
+		return "", 0, fmt.Errorf("%q is not a synthetic partition key", key)
+	}
+	bucket, err := strconv.Atoi(key[i+len(k.separator()):])
+	if err != nil || bucket < 0 || bucket >= k.buckets() {
+		return "", 0, fmt.Errorf("%q is not a synthetic partition key", key)
+	}
+	return key[:i], bucket, nil
+}
+
+// For - returns the partition key of the document with id, hashing the id so a document always lands in the same
+// bucket and can be read knowing its base and id
+//
+//	doc.PartitionKey = key.For(doc.TenantId, doc.Id)
+func (k SyntheticKey) For(base, id string) string {
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	return k.Compose(base, int(h.Sum32()%uint32(k.buckets())))
+}
+
+// All - returns the partition keys of every bucket of base
+func (k SyntheticKey) All(base string) []string {
+	keys := make([]string, k.buckets())
+	for i := range keys {
+		keys[i] = k.Compose(base, i)
+	}
+	return keys
+}
+
+func (k SyntheticKey) buckets() int {
+	if k.Buckets < 1 {
+		return 1
+	}
+	return k.Buckets
+}
+
+// QuerySyntheticKey - runs the query against every bucket of base at once, as single partition queries, appending
+// the documents to the slice docs points to in bucket order
+//
+//	var orders []Order
+//	err := client.QuerySyntheticKey(coll, key, "tenant-1", &gocosmosdb.QueryWithParameters{Query: "SELECT * FROM root r"}, &orders)
+func (c *CosmosDB) QuerySyntheticKey(coll string, key SyntheticKey, base string, query *QueryWithParameters, docs interface{}, opts ...CallOption) error {
+	out := reflect.ValueOf(docs)
+	if out.Kind() != reflect.Ptr || out.Elem().Kind() != reflect.Slice {
+		return errors.New("QuerySyntheticKey docs must be a pointer to a slice")
+	}
+	if query == nil {
+		return errors.New("QueryWithParameters cannot be nil")
+	}
+	keys := key.All(base)
+	pages := make([]reflect.Value, len(keys))
+	errs := make([]error, len(keys))
+	var wg sync.WaitGroup
+	for i, pk := range keys {
+		wg.Add(1)
+		go func(i int, pk string) {
+			defer wg.Done()
+			pages[i] = reflect.New(out.Elem().Type())
+			errs[i] = c.QueryAll(coll, query, pages[i].Interface(), &DrainLimits{}, append(append([]CallOption{}, opts...), PartitionKey(pk))...)
+		}(i, pk)
+	}
+	wg.Wait()
+	for i := range keys {
+		if errs[i] != nil {
+			return errs[i]
+		}
+		out.Elem().Set(reflect.AppendSlice(out.Elem(), pages[i].Elem()))
+	}
+	return nil
+}
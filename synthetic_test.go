@@ -0,0 +1,44 @@
+package gocosmosdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyntheticKey(t *testing.T) {
+	assert := assert.New(t)
+	key := SyntheticKey{Buckets: 4}
+	assert.Equal("tenant_1_3", key.Compose("tenant_1", 3))
+	assert.Equal([]string{"t_0", "t_1", "t_2", "t_3"}, key.All("t"))
+
+	base, bucket, err := key.Split("tenant_1_3")
+	assert.Nil(err)
+	assert.Equal("tenant_1", base)
+	assert.Equal(3, bucket)
+	_, _, err = key.Split("tenant_1_9")
+	assert.NotNil(err)
+	_, _, err = key.Split("tenant")
+	assert.NotNil(err)
+
+	pk := key.For("tenant", "order-1")
+	assert.Equal(pk, key.For("tenant", "order-1"))
+	assert.Contains(key.All("tenant"), pk)
+
+	assert.Equal("t-0", SyntheticKey{Separator: "-"}.For("t", "x"))
+}
+
+func TestQuerySyntheticKey(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"Documents": [{"id": "1"}], "_count": 1}`, `{"Documents": [{"id": "1"}], "_count": 1}`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	docs := []testDoc{}
+	err := client.QuerySyntheticKey("dbs/db/colls/coll/", SyntheticKey{Buckets: 2}, "tenant", &QueryWithParameters{Query: "SELECT * FROM root r"}, &docs)
+	assert.Nil(err)
+	assert.Equal(2, len(docs))
+
+	assert.NotNil(client.QuerySyntheticKey("dbs/db/colls/coll/", SyntheticKey{Buckets: 2}, "tenant", nil, &docs))
+	assert.NotNil(client.QuerySyntheticKey("dbs/db/colls/coll/", SyntheticKey{Buckets: 2}, "tenant", &QueryWithParameters{}, docs))
+}
@@ -0,0 +1,143 @@
+// Package tables implements the CosmosDB Table API, entities addressed by partition and row key,
+// on the auth and transport of the SQL API client.
+package tables
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/intwinelabs/gocosmosdb"
+	"github.com/intwinelabs/logger"
+)
+
+const (
+	// APIVersion - the Table service version spoken by the client
+	APIVersion = "2019-02-02"
+
+	// accept - minimal metadata returns the odata.etag of each entity in query results
+	accept = "application/json;odata=minimalmetadata"
+)
+
+// Client - a Table API client for a single account
+type Client struct {
+	uri        string
+	account    string
+	config     gocosmosdb.Config
+	httpClient *retryablehttp.Client
+	logger     *logger.Logger
+}
+
+// New - creates a Table API client, the account name is the first label of the url host
+//
+//	tbl := tables.New("https://{account}.table.cosmos.azure.com", gocosmosdb.Config{MasterKey: key}, log)
+func New(uri string, config gocosmosdb.Config, log *logger.Logger) *Client {
+	account := ""
+	if u, err := url.Parse(uri); err == nil {
+		account = strings.Split(u.Hostname(), ".")[0]
+	}
+	return &Client{
+		uri:        strings.TrimSuffix(uri, "/"),
+		account:    account,
+		config:     config,
+		httpClient: gocosmosdb.NewHTTPClient(config),
+		logger:     log,
+	}
+}
+
+// Error - a failed Table API request
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+// Implement Error function
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d %v, %v", e.StatusCode, e.Code, e.Message)
+}
+
+// authorize - signs the request with SharedKeyLite, the date and the canonicalized resource
+func (c *Client) authorize(req *http.Request) error {
+	date := time.Now().UTC().Format(http.TimeFormat)
+	req.Header.Set("X-Ms-Date", date)
+	resource := "/" + c.account + req.URL.EscapedPath()
+	if comp := req.URL.Query().Get("comp"); comp != "" {
+		resource += "?comp=" + comp
+	}
+	sig, err := gocosmosdb.Sign(date+"\n"+resource, c.config.MasterKey)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "SharedKeyLite "+c.account+":"+sig)
+	return nil
+}
+
+// do - sends a signed request and decodes the response body into ret
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, headers map[string]string, body, ret interface{}) (http.Header, error) {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+	uri := c.uri + "/" + path
+	if len(query) > 0 {
+		uri += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, uri, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Ms-Version", APIVersion)
+	req.Header.Set("User-Agent", gocosmosdb.UserAgent)
+	req.Header.Set("Accept", accept)
+	req.Header.Set("DataServiceVersion", "3.0;NetFx")
+	req.Header.Set("MaxDataServiceVersion", "3.0;NetFx")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if err = c.authorize(req); err != nil {
+		return nil, err
+	}
+	if c.config.Debug && c.logger != nil {
+		c.logger.Infof("Table Request: %s %s", method, uri)
+	}
+	rr, err := retryablehttp.FromRequest(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("error creating retryable request: %s", err)
+	}
+	resp, err := c.httpClient.Do(rr)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		odata := struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message struct {
+					Value string `json:"value"`
+				} `json:"message"`
+			} `json:"odata.error"`
+		}{}
+		json.NewDecoder(resp.Body).Decode(&odata)
+		return resp.Header, &Error{StatusCode: resp.StatusCode, Code: odata.Error.Code, Message: odata.Error.Message.Value}
+	}
+	if ret != nil && resp.StatusCode != http.StatusNoContent {
+		if err = json.NewDecoder(resp.Body).Decode(ret); err != nil {
+			return resp.Header, err
+		}
+	}
+	return resp.Header, nil
+}
@@ -0,0 +1,68 @@
+package tables
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/intwinelabs/gocosmosdb"
+	"github.com/intwinelabs/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+var log = logger.New()
+
+func TestFilter(t *testing.T) {
+	assert := assert.New(t)
+	f := Eq("PartitionKey", "o'brien").And(Not(Gt("Age", int64(30)).Or(Le("Active", true))))
+	assert.Equal("(PartitionKey eq 'o''brien') and (not ((Age gt 30L) or (Active le true)))", f.String())
+	ts := time.Date(2019, 2, 13, 1, 17, 27, 0, time.UTC)
+	assert.Equal("Timestamp ge datetime'2019-02-13T01:17:27Z'", Ge("Timestamp", ts).String())
+}
+
+func TestQueryEntities(t *testing.T) {
+	assert := assert.New(t)
+	var req *http.Request
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = r
+		w.Header().Set("X-Ms-Continuation-NextPartitionKey", "1!8!c2Vuc29ycw--")
+		w.Header().Set("X-Ms-Continuation-NextRowKey", "1!4!MTA-")
+		fmt.Fprintln(w, `{"value": [{"odata.etag": "W/\"1\"", "PartitionKey": "sensors", "RowKey": "1", "Reading": 31}]}`)
+	}))
+	defer s.Close()
+	tbl := New(strings.Replace(s.URL, "127.0.0.1", "myaccount.localhost", 1), gocosmosdb.Config{MasterKey: "YXJpZWwNCg=="}, log)
+	tbl.uri = s.URL
+
+	opts := &QueryOptions{Filter: Eq("PartitionKey", "sensors").String(), Top: 1}
+	entities, next, err := tbl.QueryEntities(context.Background(), "readings", opts)
+	assert.Nil(err)
+	assert.Equal(1, len(entities))
+	assert.Equal("sensors", entities[0].PartitionKey())
+	assert.Equal("W/\"1\"", entities[0].ETag())
+	assert.Equal(&Continuation{NextPartitionKey: "1!8!c2Vuc29ycw--", NextRowKey: "1!4!MTA-"}, next)
+
+	assert.Equal("/readings()", req.URL.Path)
+	assert.Equal("PartitionKey eq 'sensors'", req.URL.Query().Get("$filter"))
+	sig, _ := gocosmosdb.Sign(req.Header.Get("X-Ms-Date")+"\n/myaccount/readings()", "YXJpZWwNCg==")
+	assert.Equal("SharedKeyLite myaccount:"+sig, req.Header.Get("Authorization"))
+}
+
+func TestGetEntityNotFound(t *testing.T) {
+	assert := assert.New(t)
+	var path string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintln(w, `{"odata.error": {"code": "ResourceNotFound", "message": {"lang": "en-US", "value": "The specified resource does not exist."}}}`)
+	}))
+	defer s.Close()
+	tbl := New(s.URL, gocosmosdb.Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	_, err := tbl.GetEntity(context.Background(), "readings", "sensors", "it's")
+	assert.Equal(&Error{StatusCode: 404, Code: "ResourceNotFound", Message: "The specified resource does not exist."}, err)
+	assert.Equal("/readings(PartitionKey=%27sensors%27,RowKey=%27it%27%27s%27)", path)
+}
@@ -0,0 +1,172 @@
+package tables
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Entity - a table entity, PartitionKey and RowKey identify it within the table
+type Entity map[string]interface{}
+
+// PartitionKey - returns the partition key of the entity
+func (e Entity) PartitionKey() string {
+	pk, _ := e["PartitionKey"].(string)
+	return pk
+}
+
+// RowKey - returns the row key of the entity
+func (e Entity) RowKey() string {
+	rk, _ := e["RowKey"].(string)
+	return rk
+}
+
+// ETag - returns the etag of the entity, used for optimistic concurrency
+func (e Entity) ETag() string {
+	etag, _ := e["odata.etag"].(string)
+	return etag
+}
+
+// Table - a table in the account
+type Table struct {
+	TableName string `json:"TableName"`
+}
+
+// QueryOptions - the OData options of an entity query
+type QueryOptions struct {
+	Filter string   // eg. Eq("PartitionKey", "pk").And(Gt("Age", 30)).String()
+	Select []string // the properties to return, all when empty
+	Top    int      // the page size, the service default when 0
+	// Continuation - the continuation returned by the previous page
+	Continuation *Continuation
+}
+
+// Continuation - the keys of the next entity of a paged query
+type Continuation struct {
+	NextPartitionKey string
+	NextRowKey       string
+}
+
+// CreateTable - creates a table
+func (c *Client) CreateTable(ctx context.Context, name string) error {
+	_, err := c.do(ctx, http.MethodPost, "Tables", nil, map[string]string{"Prefer": "return-no-content"}, Table{name}, nil)
+	return err
+}
+
+// DeleteTable - deletes a table
+func (c *Client) DeleteTable(ctx context.Context, name string) error {
+	_, err := c.do(ctx, http.MethodDelete, "Tables("+quote(name)+")", nil, nil, nil, nil)
+	return err
+}
+
+// QueryTables - lists the tables of the account
+func (c *Client) QueryTables(ctx context.Context) ([]Table, error) {
+	data := struct {
+		Value []Table `json:"value"`
+	}{}
+	_, err := c.do(ctx, http.MethodGet, "Tables", nil, nil, nil, &data)
+	return data.Value, err
+}
+
+// InsertEntity - inserts an entity, failing with 409 if it exists
+func (c *Client) InsertEntity(ctx context.Context, table string, entity Entity) error {
+	_, err := c.do(ctx, http.MethodPost, table, nil, map[string]string{"Prefer": "return-no-content"}, entity, nil)
+	return err
+}
+
+// GetEntity - reads an entity by partition and row key
+func (c *Client) GetEntity(ctx context.Context, table, pk, rk string) (Entity, error) {
+	entity := Entity{}
+	_, err := c.do(ctx, http.MethodGet, entityPath(table, pk, rk), nil, nil, nil, &entity)
+	if err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// UpdateEntity - replaces an existing entity, matching etag or any version when etag is "*"
+func (c *Client) UpdateEntity(ctx context.Context, table string, entity Entity, etag string) error {
+	return c.write(ctx, http.MethodPut, table, entity, etag)
+}
+
+// MergeEntity - merges the properties into an existing entity, matching etag or any version when etag is "*"
+func (c *Client) MergeEntity(ctx context.Context, table string, entity Entity, etag string) error {
+	return c.write(ctx, "MERGE", table, entity, etag)
+}
+
+// InsertOrReplaceEntity - inserts the entity or replaces it if it exists
+func (c *Client) InsertOrReplaceEntity(ctx context.Context, table string, entity Entity) error {
+	return c.write(ctx, http.MethodPut, table, entity, "")
+}
+
+// InsertOrMergeEntity - inserts the entity or merges its properties into it if it exists
+func (c *Client) InsertOrMergeEntity(ctx context.Context, table string, entity Entity) error {
+	return c.write(ctx, "MERGE", table, entity, "")
+}
+
+// DeleteEntity - deletes an entity, matching etag or any version when etag is "*"
+func (c *Client) DeleteEntity(ctx context.Context, table, pk, rk, etag string) error {
+	if etag == "" {
+		etag = "*"
+	}
+	_, err := c.do(ctx, http.MethodDelete, entityPath(table, pk, rk), nil, map[string]string{"If-Match": etag}, nil, nil)
+	return err
+}
+
+// QueryEntities - returns a page of the entities matching the options and the continuation of the next page,
+// the continuation is nil on the last page
+//
+//	opts := &tables.QueryOptions{Filter: tables.Eq("PartitionKey", "sensors").And(tables.Gt("Reading", 30)).String()}
+//	entities, next, err := tbl.QueryEntities(ctx, "readings", opts)
+func (c *Client) QueryEntities(ctx context.Context, table string, opts *QueryOptions) ([]Entity, *Continuation, error) {
+	query := url.Values{}
+	if opts != nil {
+		if opts.Filter != "" {
+			query.Set("$filter", opts.Filter)
+		}
+		if len(opts.Select) > 0 {
+			query.Set("$select", strings.Join(opts.Select, ","))
+		}
+		if opts.Top > 0 {
+			query.Set("$top", strconv.Itoa(opts.Top))
+		}
+		if opts.Continuation != nil {
+			query.Set("NextPartitionKey", opts.Continuation.NextPartitionKey)
+			query.Set("NextRowKey", opts.Continuation.NextRowKey)
+		}
+	}
+	data := struct {
+		Value []Entity `json:"value"`
+	}{}
+	header, err := c.do(ctx, http.MethodGet, table+"()", query, nil, nil, &data)
+	if err != nil {
+		return nil, nil, err
+	}
+	var next *Continuation
+	if npk := header.Get("X-Ms-Continuation-Nextpartitionkey"); npk != "" {
+		next = &Continuation{NextPartitionKey: npk, NextRowKey: header.Get("X-Ms-Continuation-Nextrowkey")}
+	}
+	return data.Value, next, nil
+}
+
+// write - sends an entity to its key addressed path, conditional on etag when set
+func (c *Client) write(ctx context.Context, method, table string, entity Entity, etag string) error {
+	headers := map[string]string{}
+	if etag != "" {
+		headers["If-Match"] = etag
+	}
+	_, err := c.do(ctx, method, entityPath(table, entity.PartitionKey(), entity.RowKey()), nil, headers, entity, nil)
+	return err
+}
+
+// entityPath - the path addressing a single entity
+func entityPath(table, pk, rk string) string {
+	return table + "(PartitionKey=" + quote(pk) + ",RowKey=" + quote(rk) + ")"
+}
+
+// quote - quotes a key for use in a path, doubling single quotes
+func quote(key string) string {
+	return url.PathEscape("'" + strings.Replace(key, "'", "''", -1) + "'")
+}
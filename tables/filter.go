@@ -0,0 +1,80 @@
+package tables
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Filter - an OData filter expression
+type Filter string
+
+// String - returns the filter as the $filter query value
+func (f Filter) String() string {
+	return string(f)
+}
+
+// And - joins two filters, both must match
+func (f Filter) And(other Filter) Filter {
+	return Filter("(" + string(f) + ") and (" + string(other) + ")")
+}
+
+// Or - joins two filters, either must match
+func (f Filter) Or(other Filter) Filter {
+	return Filter("(" + string(f) + ") or (" + string(other) + ")")
+}
+
+// Not - negates a filter
+func Not(f Filter) Filter {
+	return Filter("not (" + string(f) + ")")
+}
+
+// Eq - property equals value
+func Eq(property string, value interface{}) Filter {
+	return compare(property, "eq", value)
+}
+
+// Ne - property does not equal value
+func Ne(property string, value interface{}) Filter {
+	return compare(property, "ne", value)
+}
+
+// Gt - property is greater than value
+func Gt(property string, value interface{}) Filter {
+	return compare(property, "gt", value)
+}
+
+// Ge - property is greater than or equal to value
+func Ge(property string, value interface{}) Filter {
+	return compare(property, "ge", value)
+}
+
+// Lt - property is less than value
+func Lt(property string, value interface{}) Filter {
+	return compare(property, "lt", value)
+}
+
+// Le - property is less than or equal to value
+func Le(property string, value interface{}) Filter {
+	return compare(property, "le", value)
+}
+
+func compare(property, op string, value interface{}) Filter {
+	return Filter(property + " " + op + " " + literal(value))
+}
+
+// literal - formats a value as an OData literal
+func literal(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return "'" + strings.Replace(v, "'", "''", -1) + "'"
+	case time.Time:
+		return "datetime'" + v.UTC().Format(time.RFC3339Nano) + "'"
+	case int64:
+		return fmt.Sprintf("%dL", v)
+	case bool:
+		return fmt.Sprintf("%t", v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
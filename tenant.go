@@ -0,0 +1,120 @@
+package gocosmosdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNoTenant - returned by a TenantScopedClient when the context carries no tenant
+	ErrNoTenant = errors.New("no tenant in context, use WithTenant")
+
+	// ErrTenantMismatch - returned by a TenantScopedClient when a document belongs to another tenant
+	ErrTenantMismatch = errors.New("document does not belong to the context tenant")
+)
+
+// TenantScopedClient - confines every operation to the partition of the tenant carried by the context.
+// The collection must be partitioned on the tenant field.
+type TenantScopedClient struct {
+	client      *CosmosDB
+	tenantField string
+}
+
+// NewTenantScopedClient - wraps a client, tenantField is the json field holding the tenant id eg. "tenantId"
+//
+//	tenants := gocosmosdb.NewTenantScopedClient(client, "tenantId")
+//	_, err := tenants.ReadDocument(gocosmosdb.WithTenant(ctx, "contoso"), link, &doc)
+func NewTenantScopedClient(c *CosmosDB, tenantField string) *TenantScopedClient {
+	return &TenantScopedClient{client: c, tenantField: tenantField}
+}
+
+// scope - returns the options pinning a request to the context tenant
+func (t *TenantScopedClient) scope(ctx context.Context, opts []CallOption) ([]CallOption, string, error) {
+	tenant, ok := TenantFromContext(ctx)
+	if !ok || tenant == "" {
+		return nil, "", ErrNoTenant
+	}
+	opts = append(opts, WithContext(ctx), SinglePartition(tenant))
+	return opts, tenant, nil
+}
+
+// check - rejects documents whose tenant field is not the context tenant
+func (t *TenantScopedClient) check(doc interface{}, tenant string) error {
+	data, err := stringify(doc)
+	if err != nil {
+		return err
+	}
+	fields := map[string]interface{}{}
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if fields[t.tenantField] != tenant {
+		return fmt.Errorf("%v: %s is %v", ErrTenantMismatch, t.tenantField, fields[t.tenantField])
+	}
+	return nil
+}
+
+// ReadDocument - reads a document from the context tenants partition
+func (t *TenantScopedClient) ReadDocument(ctx context.Context, link string, doc interface{}, opts ...CallOption) (*Response, error) {
+	opts, _, err := t.scope(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return t.client.ReadDocument(link, doc, opts...)
+}
+
+// QueryDocuments - runs the query against the context tenants partition only
+func (t *TenantScopedClient) QueryDocuments(ctx context.Context, coll string, query *QueryWithParameters, docs interface{}, opts ...CallOption) (*Response, error) {
+	opts, _, err := t.scope(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return t.client.QueryDocumentsWithParameters(coll, query, docs, opts...)
+}
+
+// CreateDocument - creates a document after checking it belongs to the context tenant
+func (t *TenantScopedClient) CreateDocument(ctx context.Context, coll string, doc interface{}, opts ...CallOption) (*Response, error) {
+	opts, tenant, err := t.scope(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err = t.check(doc, tenant); err != nil {
+		return nil, err
+	}
+	return t.client.CreateDocument(coll, doc, opts...)
+}
+
+// UpsertDocument - upserts a document after checking it belongs to the context tenant
+func (t *TenantScopedClient) UpsertDocument(ctx context.Context, coll string, doc interface{}, opts ...CallOption) (*Response, error) {
+	opts, tenant, err := t.scope(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err = t.check(doc, tenant); err != nil {
+		return nil, err
+	}
+	return t.client.UpsertDocument(coll, doc, opts...)
+}
+
+// ReplaceDocument - replaces a document after checking it belongs to the context tenant
+func (t *TenantScopedClient) ReplaceDocument(ctx context.Context, link string, doc interface{}, opts ...CallOption) (*Response, error) {
+	opts, tenant, err := t.scope(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err = t.check(doc, tenant); err != nil {
+		return nil, err
+	}
+	return t.client.ReplaceDocument(link, doc, opts...)
+}
+
+// DeleteDocument - deletes a document from the context tenants partition
+func (t *TenantScopedClient) DeleteDocument(ctx context.Context, link string, opts ...CallOption) (*Response, error) {
+	opts, _, err := t.scope(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return t.client.DeleteDocument(link, opts...)
+}
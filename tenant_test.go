@@ -0,0 +1,57 @@
+package gocosmosdb
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tenantDoc struct {
+	Document
+	TenantId string `json:"tenantId"`
+}
+
+func TestTenantScopedClientNoTenant(t *testing.T) {
+	assert := assert.New(t)
+	client := New("url", Config{MasterKey: "YXJpZWwNCg=="}, log)
+	tenants := NewTenantScopedClient(client, "tenantId")
+	_, err := tenants.ReadDocument(context.Background(), "dbs/db/colls/coll/docs/doc", &tenantDoc{})
+	assert.Equal(ErrNoTenant, err)
+}
+
+func TestTenantScopedClientCreateDocument(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"id": "order1", "tenantId": "contoso"}`)
+	s.SetStatus(http.StatusCreated)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", PartitionKeyStructField: "TenantId"}, log)
+	tenants := NewTenantScopedClient(client, "tenantId")
+	ctx := WithTenant(context.Background(), "contoso")
+
+	_, err := tenants.CreateDocument(ctx, "dbs/db/colls/coll/", &tenantDoc{TenantId: "fabrikam"})
+	assert.Contains(err.Error(), ErrTenantMismatch.Error())
+
+	doc := tenantDoc{TenantId: "contoso"}
+	_, err = tenants.CreateDocument(ctx, "dbs/db/colls/coll/", &doc)
+	assert.Nil(err)
+	assert.Equal("[\"contoso\"]", s.Header.Get(HeaderPartitionKey))
+}
+
+func TestTenantScopedClientQueryDocuments(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"Documents": [{"id": "order1", "tenantId": "contoso"}], "_count": 1}`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg==", PartitionKeyStructField: "TenantId"}, log)
+	tenants := NewTenantScopedClient(client, "tenantId")
+	ctx := WithTenant(context.Background(), "contoso")
+
+	docs := []tenantDoc{}
+	query := &QueryWithParameters{Query: "SELECT * FROM root r"}
+	_, err := tenants.QueryDocuments(ctx, "dbs/db/colls/coll/", query, &docs, CrossPartition())
+	assert.Nil(err)
+	assert.Equal("contoso", docs[0].TenantId)
+	assert.Equal("[\"contoso\"]", s.Header.Get(HeaderPartitionKey))
+	assert.Equal("", s.Header.Get(HeaderCrossPartition))
+}
@@ -0,0 +1,170 @@
+package gocosmosdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Sample - a value of a series at a time
+type Sample struct {
+	Time  time.Time   `json:"t"`
+	Value interface{} `json:"v"`
+}
+
+// TimeSeriesBucket - the samples of a series in an interval, an interval with more samples than fit a bucket
+// continues in the next part
+type TimeSeriesBucket struct {
+	Document
+	Series  string    `json:"series"`
+	Start   time.Time `json:"start"`
+	Part    int       `json:"part"`
+	Samples []Sample  `json:"samples"`
+}
+
+// TimeSeries - writes the samples of series into TimeSeriesBuckets in a collection partitioned by /series
+type TimeSeries struct {
+	db         *CosmosDB
+	coll       string
+	interval   time.Duration
+	maxSamples int
+	mu         sync.Mutex
+	parts      map[string]int // the part of each series and interval last written
+}
+
+// NewTimeSeries - creates a time series writer bucketing samples per interval, a bucket holds at most maxSamples
+// before rolling over to a new part
+//
+//	ts := client.NewTimeSeries("dbs/{db-id}/colls/{metrics-id}/", time.Hour, 1000)
+//	err := ts.Append(ctx, "sensor-1", gocosmosdb.Sample{Time: time.Now(), Value: 21.5})
+func (c *CosmosDB) NewTimeSeries(coll string, interval time.Duration, maxSamples int) *TimeSeries {
+	return &TimeSeries{db: c, coll: coll, interval: interval, maxSamples: maxSamples, parts: map[string]int{}}
+}
+
+// TimeSeriesBucketID - returns the id of a part of the bucket of a series starting at start
+func TimeSeriesBucketID(series string, start time.Time, part int) string {
+	return fmt.Sprintf("%s:%d:%d", url.PathEscape(series), start.Unix(), part)
+}
+
+// Append - adds the samples to the buckets of their intervals with partial updates appending to their sample
+// arrays, so concurrent writers of a series do not lose samples. An update is conditional on the bucket having
+// room for its samples, a bucket may therefore roll over up to MaxPatchOperations samples short of maxSamples.
+func (ts *TimeSeries) Append(ctx context.Context, series string, samples ...Sample) error {
+	intervals := map[time.Time][]Sample{}
+	starts := []time.Time{}
+	for _, sample := range samples {
+		start := sample.Time.UTC().Truncate(ts.interval)
+		if _, ok := intervals[start]; !ok {
+			starts = append(starts, start)
+		}
+		intervals[start] = append(intervals[start], sample)
+	}
+	for _, start := range starts {
+		if err := ts.append(ctx, series, start, intervals[start]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// append - writes the samples of one interval, rolling over to the next part when a bucket is full
+func (ts *TimeSeries) append(ctx context.Context, series string, start time.Time, samples []Sample) error {
+	key := series + "|" + start.String()
+	ts.mu.Lock()
+	part := ts.parts[key]
+	ts.mu.Unlock()
+	opts := []CallOption{PartitionKey(series), WithContext(ctx)}
+	for len(samples) > 0 {
+		id := TimeSeriesBucketID(series, start, part)
+		n := ts.fit(0, len(samples))
+		if n > MaxPatchOperations {
+			n = MaxPatchOperations
+		}
+		patch := NewPatch()
+		for _, sample := range samples[:n] {
+			patch.Add("/samples/-", sample)
+		}
+		appendOpts := opts
+		if ts.maxSamples > 0 {
+			appendOpts = append(opts[:len(opts):len(opts)], PatchCondition(fmt.Sprintf("FROM c WHERE ARRAY_LENGTH(c.samples) <= %d", ts.maxSamples-n)))
+		}
+		_, err := ts.db.client.patch(ts.coll+"docs/"+id, patch.Operations(), nil, appendOpts...)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			n = ts.fit(0, len(samples))
+			bucket := TimeSeriesBucket{Series: series, Start: start, Part: part, Samples: samples[:n]}
+			bucket.Id = id
+			_, err = ts.db.client.create(ts.coll+"docs/", &bucket, nil, opts...)
+			if errors.Is(err, ErrConflict) {
+				// created by another writer, append to it instead
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		case errors.Is(err, ErrPreconditionFailed):
+			// the bucket is full
+			part++
+			continue
+		case err != nil:
+			return err
+		}
+		samples = samples[n:]
+	}
+	ts.mu.Lock()
+	ts.parts[key] = part
+	ts.mu.Unlock()
+	return nil
+}
+
+// fit - returns how many of n samples fit a bucket holding held samples
+func (ts *TimeSeries) fit(held, n int) int {
+	if ts.maxSamples <= 0 {
+		return n
+	}
+	if free := ts.maxSamples - held; free < n {
+		if free < 0 {
+			return 0
+		}
+		return free
+	}
+	return n
+}
+
+// Range - reads the samples of a series from from up to to, in time order, across all the buckets they are in
+//
+//	samples, err := ts.Range(ctx, "sensor-1", time.Now().Add(-24*time.Hour), time.Now())
+func (ts *TimeSeries) Range(ctx context.Context, series string, from, to time.Time) ([]Sample, error) {
+	buckets := []TimeSeriesBucket{}
+	// the bounds are bucket starts, stored starts compare as strings only with those formatted alike
+	end := to.UTC().Truncate(ts.interval)
+	if end.Before(to) {
+		end = end.Add(ts.interval)
+	}
+	query := &QueryWithParameters{
+		Query: "SELECT * FROM root r WHERE r.series = @series AND r.start >= @from AND r.start < @to",
+		Parameters: []QueryParameter{
+			{Name: "@series", Value: series},
+			{Name: "@from", Value: from.UTC().Truncate(ts.interval)},
+			{Name: "@to", Value: end},
+		},
+	}
+	err := ts.db.QueryAll(ts.coll, query, &buckets, &DrainLimits{}, PartitionKey(series), WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	samples := []Sample{}
+	for _, bucket := range buckets {
+		for _, sample := range bucket.Samples {
+			if !sample.Time.Before(from) && sample.Time.Before(to) {
+				samples = append(samples, sample)
+			}
+		}
+	}
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].Time.Before(samples[j].Time) })
+	return samples, nil
+}
@@ -0,0 +1,107 @@
+package gocosmosdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeSeriesAppend(t *testing.T) {
+	assert := assert.New(t)
+	buckets := map[string]*TimeSeriesBucket{}
+	methods := []string{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		methods = append(methods, r.Method)
+		switch r.Method {
+		case http.MethodPatch:
+			bucket, ok := buckets[parts[len(parts)-1]]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"code": "NotFound"}`)
+				return
+			}
+			var body struct {
+				Condition  string `json:"condition"`
+				Operations []struct {
+					Op    string `json:"op"`
+					Path  string `json:"path"`
+					Value Sample `json:"value"`
+				} `json:"operations"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			var room int
+			fmt.Sscanf(body.Condition, "FROM c WHERE ARRAY_LENGTH(c.samples) <= %d", &room)
+			if len(bucket.Samples) > room {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				fmt.Fprint(w, `{"code": "PreconditionFailed"}`)
+				return
+			}
+			for _, op := range body.Operations {
+				assert.Equal(PatchAdd, op.Op)
+				assert.Equal("/samples/-", op.Path)
+				bucket.Samples = append(bucket.Samples, op.Value)
+			}
+			fmt.Fprint(w, `{}`)
+		case http.MethodPost:
+			bucket := &TimeSeriesBucket{}
+			json.NewDecoder(r.Body).Decode(bucket)
+			buckets[bucket.Id] = bucket
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{}`)
+		}
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	ts := client.NewTimeSeries("dbs/db/colls/metrics/", time.Hour, 2)
+	at := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	err := ts.Append(context.Background(), "sensor-1",
+		Sample{Time: at.Add(time.Minute), Value: 1.0},
+		Sample{Time: at.Add(2 * time.Minute), Value: 2.0},
+		Sample{Time: at.Add(3 * time.Minute), Value: 3.0},
+		Sample{Time: at.Add(time.Hour), Value: 4.0})
+	assert.Nil(err)
+	assert.Equal(3, len(buckets))
+	assert.Equal(2, len(buckets[TimeSeriesBucketID("sensor-1", at, 0)].Samples))
+	assert.Equal(1, len(buckets[TimeSeriesBucketID("sensor-1", at, 1)].Samples))
+	assert.Equal(1, len(buckets[TimeSeriesBucketID("sensor-1", at.Add(time.Hour), 0)].Samples))
+
+	// appends continue in the part that has room
+	assert.Nil(ts.Append(context.Background(), "sensor-1", Sample{Time: at.Add(4 * time.Minute), Value: 5.0}))
+	assert.Equal(2, len(buckets[TimeSeriesBucketID("sensor-1", at, 1)].Samples))
+	assert.Equal([]string{"PATCH", "POST", "PATCH", "PATCH", "POST", "PATCH", "POST", "PATCH"}, methods)
+}
+
+func TestTimeSeriesRange(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"Documents": [
+		{"series": "sensor-1", "part": 1, "samples": [{"t": "2019-06-01T12:03:00Z", "v": 3}]},
+		{"series": "sensor-1", "part": 0, "samples": [{"t": "2019-06-01T12:01:00Z", "v": 1}, {"t": "2019-06-01T12:02:00Z", "v": 2}]}
+	], "_count": 2}`, `{"Documents": [], "_count": 0}`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	ts := client.NewTimeSeries("dbs/db/colls/metrics/", time.Hour, 2)
+	at := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	samples, err := ts.Range(context.Background(), "sensor-1", at.Add(90*time.Second), at.Add(time.Hour))
+	assert.Nil(err)
+	assert.Equal(2, len(samples))
+	assert.Equal(2.0, samples[0].Value)
+	assert.Equal(3.0, samples[1].Value)
+	assert.Equal(`["sensor-1"]`, s.Header.Get(HeaderPartitionKey))
+	assert.Contains(s.Body, `{"name":"@to","value":"2019-06-01T13:00:00Z"}`)
+
+	// the bucket starting in the same second as to is still read
+	_, err = ts.Range(context.Background(), "sensor-1", at, at.Add(time.Hour+time.Millisecond))
+	assert.Nil(err)
+	assert.Contains(s.Body, `{"name":"@from","value":"2019-06-01T12:00:00Z"}`)
+	assert.Contains(s.Body, `{"name":"@to","value":"2019-06-01T14:00:00Z"}`)
+}
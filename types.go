@@ -29,17 +29,50 @@ type IndexingPolicy struct {
 		Indexes []struct {
 			DataType  string `json:"dataType,omitempty"`
 			Kind      string `json:"kind,omitempty"`
-			Precision int    `json:"precision,omitempty`
+			Precision int    `json:"precision,omitempty"`
 		} `json:"indexes,omitempty"`
 		Path string `json:"path,omitempty"`
 	} `json:"includedPaths,omitempty"`
+	ExcludedPaths []struct {
+		Path string `json:"path,omitempty"`
+	} `json:"excludedPaths,omitempty"`
 	IndexingMode string `json:"indexingMode,omitempty"`
 }
 
+// Unique key policy
+type UniqueKeyPolicy struct {
+	UniqueKeys []UniqueKey `json:"uniqueKeys"`
+}
+
+// UniqueKey - a set of paths whose values must be unique within a logical partition
+type UniqueKey struct {
+	Paths []string `json:"paths"`
+}
+
 // Partition Key
 type PartitionKeyDef struct {
-	Kind  string   `json:"kind"`
-	Paths []string `json:"paths"`
+	Kind    string   `json:"kind"`
+	Paths   []string `json:"paths"`
+	Version int      `json:"version,omitempty"` // of the hash, 2 hashes the whole value and 1 or none its first 100 bytes
+}
+
+// Account - the database account properties
+type Account struct {
+	Resource
+	Media                        string     `json:"media,omitempty"`
+	Addresses                    string     `json:"addresses,omitempty"`
+	WritableLocations            []Location `json:"writableLocations,omitempty"`
+	ReadableLocations            []Location `json:"readableLocations,omitempty"`
+	EnableMultipleWriteLocations bool       `json:"enableMultipleWriteLocations,omitempty"`
+	UserConsistencyPolicy        struct {
+		DefaultConsistencyLevel string `json:"defaultConsistencyLevel,omitempty"`
+	} `json:"userConsistencyPolicy,omitempty"`
+}
+
+// Location - a region of the database account
+type Location struct {
+	Name                    string `json:"name"`
+	DatabaseAccountEndpoint string `json:"databaseAccountEndpoint"`
 }
 
 // Database
@@ -52,13 +85,30 @@ type Database struct {
 // Collection
 type Collection struct {
 	Resource
-	IndexingPolicy  IndexingPolicy  `json:"indexingPolicy,omitempty"`
-	PartitionKeyDef PartitionKeyDef `json:"partitionKey,omitempty"`
-	Docs            string          `json:"_docs,omitempty"`
-	Udf             string          `json:"_udfs,omitempty"`
-	Sporcs          string          `json:"_sporcs,omitempty"`
-	Triggers        string          `json:"_triggers,omitempty"`
-	Conflicts       string          `json:"_conflicts,omitempty"`
+	IndexingPolicy  IndexingPolicy   `json:"indexingPolicy,omitempty"`
+	PartitionKeyDef PartitionKeyDef  `json:"partitionKey,omitempty"`
+	DefaultTTL      int              `json:"defaultTtl,omitempty"`
+	UniqueKeyPolicy *UniqueKeyPolicy `json:"uniqueKeyPolicy,omitempty"`
+	Docs            string           `json:"_docs,omitempty"`
+	Udf             string           `json:"_udfs,omitempty"`
+	Sporcs          string           `json:"_sporcs,omitempty"`
+	Triggers        string           `json:"_triggers,omitempty"`
+	Conflicts       string           `json:"_conflicts,omitempty"`
+}
+
+// Offer - the throughput provisioned for a database or collection
+type Offer struct {
+	Resource
+	OfferVersion    string `json:"offerVersion,omitempty"`
+	OfferType       string `json:"offerType,omitempty"`
+	ResourceLink    string `json:"resource,omitempty"`
+	OfferResourceId string `json:"offerResourceId,omitempty"`
+	Content         struct {
+		OfferThroughput        int `json:"offerThroughput,omitempty"`
+		OfferAutopilotSettings *struct {
+			MaxThroughput int `json:"maxThroughput"`
+		} `json:"offerAutopilotSettings,omitempty"`
+	} `json:"content"`
 }
 
 // QueryWithParameters
@@ -84,6 +134,21 @@ type Expirable struct {
 	TTL int64 `json:"ttl"`
 }
 
+// User
+type User struct {
+	Resource
+	Permissions string `json:"_permissions,omitempty"`
+}
+
+// Permission
+type Permission struct {
+	Resource
+	PermissionMode       string        `json:"permissionMode,omitempty"`
+	ResourceLink         string        `json:"resource,omitempty"`
+	ResourcePartitionKey []interface{} `json:"resourcePartitionKey,omitempty"`
+	Token                string        `json:"_token,omitempty"`
+}
+
 // Stored Procedure
 type Sproc struct {
 	Resource
@@ -96,6 +161,28 @@ type UDF struct {
 	Body string `json:"body,omitempty"`
 }
 
+// Trigger types, pre triggers run before the write and post triggers after it in its transaction
+const (
+	TriggerTypePre  = "Pre"
+	TriggerTypePost = "Post"
+)
+
+// Trigger operations, the writes a trigger runs on
+const (
+	TriggerOperationAll     = "All"
+	TriggerOperationCreate  = "Create"
+	TriggerOperationReplace = "Replace"
+	TriggerOperationDelete  = "Delete"
+)
+
+// Trigger
+type Trigger struct {
+	Resource
+	Body             string `json:"body,omitempty"`
+	TriggerType      string `json:"triggerType,omitempty"`
+	TriggerOperation string `json:"triggerOperation,omitempty"`
+}
+
 // Metrics
 type Metrics struct {
 	TotalExecutionTimeInMs         float64 `json:"totalExecutionTimeInMs,omitempty"`
@@ -116,23 +203,64 @@ type Metrics struct {
 	RequestCharge                  float64 `json:"requestCharge,omitempty"`
 }
 
+// IndexMetrics - the indexes a query used and the indexes that would have helped it, returned by
+// Response.GetIndexMetrics
+type IndexMetrics struct {
+	UtilizedSingleIndexes     []SingleIndexMetric    `json:"UtilizedSingleIndexes"`
+	PotentialSingleIndexes    []SingleIndexMetric    `json:"PotentialSingleIndexes"`
+	UtilizedCompositeIndexes  []CompositeIndexMetric `json:"UtilizedCompositeIndexes"`
+	PotentialCompositeIndexes []CompositeIndexMetric `json:"PotentialCompositeIndexes"`
+}
+
+// SingleIndexMetric - a range index path of IndexMetrics eg. "/name/?"
+type SingleIndexMetric struct {
+	FilterExpression string `json:"FilterExpression"`
+	IndexSpec        string `json:"IndexSpec"`
+	FilterPreciseSet bool   `json:"FilterPreciseSet"`
+	IndexPreciseSet  bool   `json:"IndexPreciseSet"`
+	IndexImpactScore string `json:"IndexImpactScore"` // High or Low
+}
+
+// CompositeIndexMetric - a composite index of IndexMetrics eg. ["/name ASC", "/age ASC"]
+type CompositeIndexMetric struct {
+	IndexSpecs       []string `json:"IndexSpecs"`
+	IndexPreciseSet  bool     `json:"IndexPreciseSet"`
+	IndexImpactScore string   `json:"IndexImpactScore"` // High or Low
+}
+
+// Partition key range statuses
+const (
+	PartitionKeyRangeOnline  = "online"
+	PartitionKeyRangeOffline = "offline"
+)
+
 // PartitionKeyRange partition key range model
 type PartitionKeyRange struct {
 	Resource
-	MinInclusive string `json:"minInclusive,omitempty"`
-	MaxInclusive string `json:"maxExclusive,omitempty"`
+	MinInclusive       string   `json:"minInclusive,omitempty"`
+	MaxInclusive       string   `json:"maxExclusive,omitempty"` // exclusive, despite the name
+	RidPrefix          int      `json:"ridPrefix,omitempty"`
+	ThroughputFraction float64  `json:"throughputFraction,omitempty"`
+	Status             string   `json:"status,omitempty"`
+	Parents            []string `json:"parents,omitempty"` // ids of the ranges split into this one, oldest first
+	Lsn                int64    `json:"_lsn,omitempty"`
 }
 
 // PagableQuery
 type PagableQuery struct {
-	client       *CosmosDB
-	coll         string
-	query        *QueryWithParameters
-	sessionToken CallOption
-	continuation CallOption
-	limit        CallOption
-	offset       int64
-	docs         interface{}
-	opts         []CallOption
-	done         bool
+	client        *CosmosDB
+	coll          string
+	query         *QueryWithParameters
+	sessionToken  CallOption
+	continuation  CallOption
+	token         string // the continuation of the next page
+	limit         CallOption
+	offset        int64
+	docs          interface{}
+	opts          []CallOption
+	done          bool
+	feed          bool // reads the document feed instead of querying
+	maxRUs        float64
+	requestCharge float64
+	page          PageInfo // the metadata of the last page read
 }
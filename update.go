@@ -0,0 +1,42 @@
+package gocosmosdb
+
+import "errors"
+
+// DefaultUpdateAttempts - how many times Update reads and mutates a document changed concurrently before failing
+// with ErrPreconditionFailed
+var DefaultUpdateAttempts = 5
+
+// Update - reads the document at link into a T, applies mutate and replaces the document on the condition it did
+// not change since it was read, reading and mutating it again when it did. It returns the replaced document, or
+// the error of mutate, which should only change the document it is passed. pk is nil for collections without a
+// partition key.
+//
+//	order, err := gocosmosdb.Update(client, "dbs/{db-id}/colls/orders/docs/{doc-id}", tenantId, func(o *Order) error {
+//		if o.Status != "open" {
+//			return ErrClosed
+//		}
+//		o.Items = append(o.Items, item)
+//		return nil
+//	})
+func Update[T any](c *CosmosDB, link string, pk interface{}, mutate func(doc *T) error, opts ...CallOption) (*T, error) {
+	if pk != nil {
+		opts = append(opts[:len(opts):len(opts)], PartitionKey(pk))
+	}
+	for attempt := 1; ; attempt++ {
+		doc := new(T)
+		resp, err := c.ReadDocument(link, doc, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if err = mutate(doc); err != nil {
+			return nil, err
+		}
+		_, err = c.ReplaceDocument(link, doc, append(opts[:len(opts):len(opts)], IfMatch(resp.ETag()))...)
+		if err == nil {
+			return doc, nil
+		}
+		if !errors.Is(err, ErrPreconditionFailed) || attempt >= DefaultUpdateAttempts {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package gocosmosdb
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdate(t *testing.T) {
+	assert := assert.New(t)
+	version := 1
+	stored := `{"id": "o1", "total": 10}`
+	var ifMatch []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := fmt.Sprintf(`"%d"`, version)
+		if r.Method == http.MethodGet {
+			w.Header().Set(HeaderETag, etag)
+			fmt.Fprint(w, stored)
+			// another writer changes the document after the first read
+			if version == 1 {
+				version, stored = 2, `{"id": "o1", "total": 15}`
+			}
+			return
+		}
+		ifMatch = append(ifMatch, r.Header.Get(HeaderIfMatch))
+		if r.Header.Get(HeaderIfMatch) != etag {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			fmt.Fprint(w, `{"code": "PreconditionFailed"}`)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		stored = string(body)
+		w.Write(body)
+	}))
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	type order struct {
+		Id    string `json:"id"`
+		Total int    `json:"total"`
+	}
+	doc, err := Update(client, "dbs/db/colls/orders/docs/o1", "t1", func(o *order) error {
+		o.Total += 5
+		return nil
+	})
+	assert.Nil(err)
+	assert.Equal(&order{Id: "o1", Total: 20}, doc)
+	assert.Equal([]string{`"1"`, `"2"`}, ifMatch)
+
+	closed := errors.New("closed")
+	_, err = Update(client, "dbs/db/colls/orders/docs/o1", "t1", func(o *order) error { return closed })
+	assert.Equal(closed, err)
+
+	// a document changing on every read fails once the attempts run out
+	version = 0
+	_, err = Update(client, "dbs/db/colls/orders/docs/o1", "t1", func(o *order) error {
+		version++
+		return nil
+	})
+	assert.True(errors.Is(err, ErrPreconditionFailed))
+	assert.Len(ifMatch, 2+DefaultUpdateAttempts)
+}
@@ -1,9 +1,12 @@
 package gocosmosdb
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math"
 	"strings"
 	"time"
@@ -27,25 +30,104 @@ func path(url string, args ...string) (link string) {
 	return
 }
 
-// readJson - response to given interface(struct, map, ..)
+// normalizeLink - normalizes a link eg. "/dbs/db/colls/coll" to "dbs/db/colls/coll/", ready for appending
+func normalizeLink(link string) string {
+	link = strings.TrimPrefix(link, "/")
+	if !strings.HasSuffix(link, "/") {
+		link += "/"
+	}
+	return link
+}
+
+// readJson - response to given interface(struct, map, ..), a *json.RawMessage or *[]byte gets the body as read
+// without decoding and owns it
 func readJson(reader io.Reader, data interface{}) error {
+	if raw := rawTarget(data); raw != nil {
+		body, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+		*raw = bytes.TrimSpace(body)
+		return nil
+	}
+	if fast, ok := data.(fastUnmarshaler); ok {
+		body, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+		if fast.unmarshalFast(body) {
+			return nil
+		}
+		return json.Unmarshal(body, &data)
+	}
 	return json.NewDecoder(reader).Decode(&data)
 }
 
-// Stringify query-string as CosmosDB expected
-func querify(query string) string {
-	return fmt.Sprintf(`{ "%s": "%s" }`, "query", query)
+// rawTarget - returns the bytes a result is read into as is, nil for results to decode
+func rawTarget(data interface{}) *[]byte {
+	switch v := data.(type) {
+	case *interface{}:
+		return rawTarget(*v)
+	case *json.RawMessage:
+		return (*[]byte)(v)
+	case *[]byte:
+		return v
+	}
+	return nil
+}
+
+// Stringify query-string as CosmosDB expected, JSON encoded so quotes and control characters survive
+func querify(query string) ([]byte, error) {
+	return json.Marshal(&QueryWithParameters{Query: query, Parameters: []QueryParameter{}})
 }
 
-// Stringify body data
+// Stringify body data, a []byte or json.RawMessage is sent as is and must not be changed until the call returns
 func stringify(body interface{}) (bt []byte, err error) {
 	switch t := body.(type) {
 	case string:
 		bt = []byte(t)
 	case []byte:
 		bt = t
+	case json.RawMessage:
+		bt = t
 	default:
 		bt, err = json.Marshal(t)
 	}
 	return
 }
+
+// resourceID - returns the id of the body of a resource to create
+func resourceID(body interface{}) (string, error) {
+	data, err := stringify(body)
+	if err != nil {
+		return "", err
+	}
+	var meta struct {
+		Id string `json:"id"`
+	}
+	if err = json.Unmarshal(data, &meta); err != nil {
+		return "", err
+	}
+	if meta.Id == "" {
+		return "", errors.New("resource has no id")
+	}
+	return meta.Id, nil
+}
+
+// valueAtPath - returns the value a slash denoted path eg. "/customer/id" points to in a JSON document
+func valueAtPath(doc json.RawMessage, path string) (interface{}, error) {
+	var value interface{}
+	if err := json.Unmarshal(doc, &value); err != nil {
+		return nil, err
+	}
+	for _, part := range strings.Split(strings.TrimPrefix(path, "/"), "/") {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("path %s does not exist in the document", path)
+		}
+		if value, ok = obj[part]; !ok {
+			return nil, fmt.Errorf("path %s does not exist in the document", path)
+		}
+	}
+	return value, nil
+}
@@ -1,6 +1,7 @@
 package gocosmosdb
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -21,3 +22,41 @@ func TestUtils(t *testing.T) {
 	assert.Nil(err)
 	assert.Equal([]byte("foo"), b)
 }
+
+func TestRawBodies(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"id": "1", "n": 1.50}`, `{"id": "2"}`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	// raw results are the body as sent, not re-encoded
+	var raw json.RawMessage
+	_, err := client.ReadDocument("dbs/db/colls/coll/docs/1", &raw)
+	assert.Nil(err)
+	assert.Equal(`{"id": "1", "n": 1.50}`, string(raw))
+
+	body := json.RawMessage(`{"id": "2",  "n": 2.50}`)
+	var created []byte
+	_, err = client.Upsert("dbs/db/colls/coll/docs/", body, &created)
+	assert.Nil(err)
+	assert.Equal(`{"id": "2",  "n": 2.50}`, s.Body)
+	assert.Equal(`{"id": "2"}`, string(created))
+
+	data, err := stringify(body)
+	assert.Nil(err)
+	assert.True(&data[0] == &body[0])
+
+	// raw bodies have no partition key field to read
+	s = ServerFactory(`{"id": "3"}`, `{"id": "3"}`, `{"id": "3"}`)
+	defer s.Close()
+	client = New(s.URL, Config{MasterKey: "YXJpZWwNCg==", PartitionKeyStructField: "Tenant"}, log)
+	body = json.RawMessage(`{"id": "3", "_etag": "1"}`)
+	_, err = client.Upsert("dbs/db/colls/coll/docs/", body, &created, PartitionKey("t1"))
+	assert.Nil(err)
+	_, err = client.UpsertDocument("dbs/db/colls/coll/", body, PartitionKey("t1"))
+	assert.Nil(err)
+	_, err = client.ReplaceDocumentAsync("dbs/db/colls/coll/docs/3", body, PartitionKey("t1"))
+	assert.Nil(err)
+	assert.Equal(`["t1"]`, s.Header.Get(HeaderPartitionKey))
+	assert.Equal("1", s.Header.Get(HeaderIfMatch))
+}
@@ -0,0 +1,106 @@
+package gocosmosdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ViewDocument - a document of a materialized view with its partition key in the view collection
+type ViewDocument struct {
+	PartitionKey interface{}
+	Body         interface{} // must carry an id derived from the source document so replays overwrite it
+}
+
+// ViewFunc - projects a changed source document into the view documents it maintains
+type ViewFunc func(change json.RawMessage) ([]ViewDocument, error)
+
+// MaterializedView - keeps a view collection, eg. the source documents under another partition key, in sync
+// with the changes of a source collection by upserting the projected documents
+type MaterializedView struct {
+	db      *CosmosDB
+	view    string
+	project ViewFunc
+	mu      sync.Mutex
+	applied int64
+	lag     time.Duration
+	synced  time.Time
+}
+
+// NewMaterializedView - creates a view maintained by running Handle as the handler of a change feed processor on
+// the source collection, deleting source documents is only propagated when they expire through a TTL
+//
+//	view := client.NewMaterializedView("dbs/{db-id}/colls/{view-id}/", func(change json.RawMessage) ([]gocosmosdb.ViewDocument, error) {
+//		var order Order
+//		err := json.Unmarshal(change, &order)
+//		return []gocosmosdb.ViewDocument{{PartitionKey: order.CustomerId, Body: order}}, err
+//	})
+//	err := client.NewChangeFeedProcessor("dbs/{db-id}/colls/{source-id}/", view.Handle, nil).Run(ctx)
+func (c *CosmosDB) NewMaterializedView(view string, project ViewFunc) *MaterializedView {
+	return &MaterializedView{db: c, view: view, project: project}
+}
+
+// Handle - projects and upserts a page of source changes, it is a ChangeFeedHandler
+func (v *MaterializedView) Handle(ctx context.Context, pkRange string, docs []json.RawMessage) error {
+	var newest int64
+	for _, doc := range docs {
+		views, err := v.project(doc)
+		if err != nil {
+			return err
+		}
+		for _, view := range views {
+			if err = v.upsert(ctx, view); err != nil {
+				return err
+			}
+		}
+		var change struct {
+			Ts int64 `json:"_ts"`
+		}
+		if json.Unmarshal(doc, &change) == nil && change.Ts > newest {
+			newest = change.Ts
+		}
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.applied += int64(len(docs))
+	v.synced = time.Now()
+	if newest > 0 {
+		v.lag = v.synced.Sub(time.Unix(newest, 0))
+	}
+	return nil
+}
+
+// upsert - writes a view document, the same projection written twice leaves the same document
+func (v *MaterializedView) upsert(ctx context.Context, view ViewDocument) error {
+	data, err := stringify(view.Body)
+	if err != nil {
+		return err
+	}
+	_, err = v.db.client.method(http.MethodPost, v.view+"docs/", expectUpserted, nil, bytes.NewBuffer(data),
+		Upsert(), PartitionKey(view.PartitionKey), WithContext(ctx))
+	return err
+}
+
+// Applied - returns the number of source changes applied to the view
+func (v *MaterializedView) Applied() int64 {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.applied
+}
+
+// Lag - returns how long after the newest source change of the last page the view was updated
+func (v *MaterializedView) Lag() time.Duration {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.lag
+}
+
+// LastSync - returns when the view was last updated, zero before the first page
+func (v *MaterializedView) LastSync() time.Time {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.synced
+}
@@ -0,0 +1,43 @@
+package gocosmosdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaterializedView(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{}`, `{}`)
+	s.SetStatus(http.StatusCreated)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	type order struct {
+		Id       string `json:"id"`
+		Customer string `json:"customer"`
+	}
+	view := client.NewMaterializedView("dbs/db/colls/ordersByCustomer/", func(change json.RawMessage) ([]ViewDocument, error) {
+		var o order
+		err := json.Unmarshal(change, &o)
+		return []ViewDocument{{PartitionKey: o.Customer, Body: o}}, err
+	})
+
+	ts := time.Now().Add(-time.Minute).Unix()
+	changes := []json.RawMessage{
+		json.RawMessage(`{"id": "1", "customer": "c1", "_ts": 1}`),
+		json.RawMessage(`{"id": "2", "customer": "c2", "_ts": ` + fmt.Sprint(ts) + `}`),
+	}
+	assert.Nil(view.Handle(context.Background(), "0", changes))
+	assert.Equal(int64(2), view.Applied())
+	assert.Equal("true", s.Header.Get(HeaderUpsert))
+	assert.Equal(`["c2"]`, s.Header.Get(HeaderPartitionKey))
+	assert.JSONEq(`{"id": "2", "customer": "c2"}`, s.Body)
+	assert.True(view.Lag() >= time.Minute)
+	assert.False(view.LastSync().IsZero())
+}
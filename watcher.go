@@ -0,0 +1,188 @@
+package gocosmosdb
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Resource events, as passed to the callback of a ResourceWatcher
+const (
+	ResourceDeleted   = "deleted"
+	ResourceRecreated = "recreated"
+)
+
+// ResourceEvent - a watched database or collection was deleted or recreated with a new _rid
+type ResourceEvent struct {
+	Link   string
+	Event  string
+	OldRid string
+	NewRid string // empty when deleted
+}
+
+// ResourceWatcher - watches databases and collections by their id based links, dropping the self links and
+// partition key ranges it cached for them and notifying the callback once one is deleted or recreated
+type ResourceWatcher struct {
+	db       *CosmosDB
+	interval time.Duration
+	onChange func(ResourceEvent)
+	mu       sync.Mutex
+	watched  map[string]*watchedResource
+	selfs    map[string]string
+	pkRanges map[string][]PartitionKeyRange
+}
+
+// watchedResource - the last seen state of a watched resource
+type watchedResource struct {
+	rid     string
+	deleted bool
+}
+
+// NewResourceWatcher - creates a watcher checking the watched resources every interval when run, onChange is
+// called from the goroutine checking and may be nil
+//
+//	watcher := client.NewResourceWatcher(time.Minute, func(e gocosmosdb.ResourceEvent) {
+//		log.Infof("%s was %s, re-bootstrapping", e.Link, e.Event)
+//	})
+//	watcher.Watch("dbs/{db-id}/", "dbs/{db-id}/colls/{coll-id}/")
+//	go watcher.Run(ctx)
+func (c *CosmosDB) NewResourceWatcher(interval time.Duration, onChange func(ResourceEvent)) *ResourceWatcher {
+	return &ResourceWatcher{
+		db:       c,
+		interval: interval,
+		onChange: onChange,
+		watched:  map[string]*watchedResource{},
+		selfs:    map[string]string{},
+		pkRanges: map[string][]PartitionKeyRange{},
+	}
+}
+
+// Watch - adds database or collection links to watch, their _rid is recorded on the next check
+func (w *ResourceWatcher) Watch(links ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, link := range links {
+		link = normalizeLink(link)
+		if _, ok := w.watched[link]; !ok {
+			w.watched[link] = &watchedResource{}
+		}
+	}
+}
+
+// Run - checks the watched resources every interval until ctx is done, returning the first failing check
+func (w *ResourceWatcher) Run(ctx context.Context) error {
+	for {
+		if err := w.Check(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(w.interval):
+		}
+	}
+}
+
+// Check - reads each watched resource once, invalidating and notifying deletions and recreations
+func (w *ResourceWatcher) Check(ctx context.Context) error {
+	w.mu.Lock()
+	links := make([]string, 0, len(w.watched))
+	for link := range w.watched {
+		links = append(links, link)
+	}
+	w.mu.Unlock()
+
+	for _, link := range links {
+		var res Resource
+		_, err := w.db.client.read(link, &res, WithContext(ctx))
+		deleted := errors.Is(err, ErrNotFound)
+		if err != nil && !deleted {
+			return err
+		}
+		if event := w.observe(link, res.Rid, deleted); event != nil && w.onChange != nil {
+			w.onChange(*event)
+		}
+	}
+	return nil
+}
+
+// observe - records the state of a resource, returning the event when it changed since the last check
+func (w *ResourceWatcher) observe(link, rid string, deleted bool) *ResourceEvent {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	state := w.watched[link]
+	var event *ResourceEvent
+	switch {
+	case state.rid == "":
+		// first sighting
+	case deleted && !state.deleted:
+		event = &ResourceEvent{Link: link, Event: ResourceDeleted, OldRid: state.rid}
+	case !deleted && (state.deleted || rid != state.rid):
+		event = &ResourceEvent{Link: link, Event: ResourceRecreated, OldRid: state.rid, NewRid: rid}
+	}
+	// keep the last known _rid while deleted to report it on recreation
+	if !deleted {
+		state.rid = rid
+	}
+	state.deleted = deleted
+	if event != nil {
+		w.invalidate(link)
+	}
+	return event
+}
+
+// invalidate - drops what was cached for a resource and the resources under it, the lock must be held
+func (w *ResourceWatcher) invalidate(link string) {
+	for cached := range w.selfs {
+		if strings.HasPrefix(cached, link) {
+			delete(w.selfs, cached)
+		}
+	}
+	for cached := range w.pkRanges {
+		if strings.HasPrefix(cached, link) {
+			delete(w.pkRanges, cached)
+		}
+	}
+}
+
+// SelfLink - returns the _self link of the resource at an id based link, cached until the watcher sees the
+// resource or one it is under deleted or recreated
+func (w *ResourceWatcher) SelfLink(link string, opts ...CallOption) (string, error) {
+	link = normalizeLink(link)
+	w.mu.Lock()
+	self, ok := w.selfs[link]
+	w.mu.Unlock()
+	if ok {
+		return self, nil
+	}
+	var res Resource
+	if _, err := w.db.client.read(link, &res, opts...); err != nil {
+		return "", err
+	}
+	w.mu.Lock()
+	w.selfs[link] = res.Self
+	w.mu.Unlock()
+	return res.Self, nil
+}
+
+// PartitionKeyRanges - returns the partition key ranges of a collection, cached until the watcher sees the
+// collection or its database deleted or recreated
+func (w *ResourceWatcher) PartitionKeyRanges(coll string, opts ...CallOption) ([]PartitionKeyRange, error) {
+	coll = normalizeLink(coll)
+	w.mu.Lock()
+	ranges, ok := w.pkRanges[coll]
+	w.mu.Unlock()
+	if ok {
+		return ranges, nil
+	}
+	ranges, err := w.db.QueryPartitionKeyRanges(coll, "", opts...)
+	if err != nil {
+		return nil, err
+	}
+	w.mu.Lock()
+	w.pkRanges[coll] = ranges
+	w.mu.Unlock()
+	return ranges, nil
+}
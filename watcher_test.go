@@ -0,0 +1,56 @@
+package gocosmosdb
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceWatcher(t *testing.T) {
+	assert := assert.New(t)
+	s := ServerFactory(`{"_rid": "A==", "_self": "dbs/db==/colls/A==/"}`, `{"_rid": "A==", "_self": "dbs/db==/colls/A==/"}`,
+		http.StatusNotFound, `{"_rid": "B==", "_self": "dbs/db==/colls/B==/"}`, `{"_rid": "B==", "_self": "dbs/db==/colls/B==/"}`)
+	defer s.Close()
+	client := New(s.URL, Config{MasterKey: "YXJpZWwNCg=="}, log)
+
+	var events []ResourceEvent
+	watcher := client.NewResourceWatcher(0, func(e ResourceEvent) {
+		events = append(events, e)
+	})
+	watcher.Watch("/dbs/db/colls/users")
+	ctx := context.Background()
+
+	assert.Nil(watcher.Check(ctx))
+	self, err := watcher.SelfLink("dbs/db/colls/users/")
+	assert.Nil(err)
+	assert.Equal("dbs/db==/colls/A==/", self)
+	// served from the cache
+	self, err = watcher.SelfLink("dbs/db/colls/users/")
+	assert.Nil(err)
+	assert.Equal("dbs/db==/colls/A==/", self)
+
+	assert.Nil(watcher.Check(ctx))
+	assert.Equal([]ResourceEvent{{Link: "dbs/db/colls/users/", Event: ResourceDeleted, OldRid: "A=="}}, events)
+
+	assert.Nil(watcher.Check(ctx))
+	assert.Equal(ResourceEvent{Link: "dbs/db/colls/users/", Event: ResourceRecreated, OldRid: "A==", NewRid: "B=="}, events[1])
+	self, err = watcher.SelfLink("dbs/db/colls/users/")
+	assert.Nil(err)
+	assert.Equal("dbs/db==/colls/B==/", self)
+}
+
+func TestResourceWatcherInvalidatesChildren(t *testing.T) {
+	assert := assert.New(t)
+	w := (&CosmosDB{}).NewResourceWatcher(0, nil)
+	w.Watch("dbs/db/")
+	w.selfs["dbs/db/colls/users/"] = "dbs/db==/colls/A==/"
+	w.pkRanges["dbs/db/colls/users/"] = []PartitionKeyRange{{}}
+	w.pkRanges["dbs/other/colls/users/"] = []PartitionKeyRange{{}}
+
+	assert.Nil(w.observe("dbs/db/", "db==", false))
+	assert.NotNil(w.observe("dbs/db/", "db2==", false))
+	assert.Empty(w.selfs)
+	assert.Len(w.pkRanges, 1)
+}